@@ -8,11 +8,13 @@ import (
 	"idrm/api/internal/handler"
 	"idrm/api/internal/svc"
 	"idrm/pkg/middleware"
+	"idrm/pkg/response"
 	"idrm/pkg/telemetry"
 	"idrm/pkg/validator"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
 var configFile = flag.String("f", "etc/api.yaml", "the config file")
@@ -31,6 +33,8 @@ func main() {
 
 	// Initialize validator
 	validator.Init()
+	httpx.SetValidator(validator.NewHttpxValidator()) // 自动验证 httpx.Parse 解析后的请求
+	httpx.SetErrorHandlerCtx(response.ErrorHandler)   // 验证错误统一返回字段错误字典
 	fmt.Println("Validator initialized successfully")
 
 	// Create server
@@ -46,6 +50,7 @@ func main() {
 
 	// Initialize service context
 	ctx := svc.NewServiceContext(c)
+	defer ctx.Close()
 
 	// Register routes
 	handler.RegisterHandlers(server, ctx)
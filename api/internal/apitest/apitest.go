@@ -0,0 +1,151 @@
+// Package apitest API 测试工具：使用测试 ServiceContext 注册全部路由，通过 httptest 发送请求
+//
+//	svcCtx := apitest.NewServiceContext(categorytest.NewMemory())
+//	srv := apitest.NewServer(t, svcCtx)
+//	resp := srv.Do(t, http.MethodGet, "/api/v1/catalog/categories/1", nil)
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"idrm/api/internal/config"
+	"idrm/api/internal/handler"
+	"idrm/api/internal/svc"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/category/categorytest"
+	"idrm/pkg/middleware"
+	"idrm/pkg/response"
+	"idrm/pkg/validator"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"github.com/zeromicro/go-zero/rest/router"
+)
+
+var setupOnce sync.Once
+
+// setup 初始化与 api.go 相同的全局组件（验证器、错误处理）
+func setup() {
+	setupOnce.Do(func() {
+		validator.Init()
+		httpx.SetValidator(validator.NewHttpxValidator())
+		httpx.SetErrorHandlerCtx(response.ErrorHandler)
+	})
+}
+
+// middlewares 全局中间件（顺序与 api.go 保持一致）
+func middlewares() []rest.Middleware {
+	return []rest.Middleware{
+		middleware.Recovery(),
+		middleware.RequestID(),
+		middleware.Trace(),
+		middleware.CORS(),
+		middleware.Logger(),
+	}
+}
+
+// NewServiceContext 创建测试 ServiceContext（使用默认配置，不创建数据源）
+// categoryModel 为 nil 时使用空的内存版实现
+func NewServiceContext(categoryModel category.Model) *svc.ServiceContext {
+	setup()
+
+	var c config.Config
+	if err := conf.FillDefault(&c); err != nil {
+		panic(err)
+	}
+	if categoryModel == nil {
+		categoryModel = categorytest.NewMemory()
+	}
+	return svc.NewServiceContextWithModels(c, categoryModel)
+}
+
+// Server 测试服务
+type Server struct {
+	*httptest.Server
+}
+
+// NewServer 注册全部路由（含全局中间件）并启动 httptest 服务，测试结束时自动关闭
+func NewServer(t testing.TB, svcCtx *svc.ServiceContext) *Server {
+	t.Helper()
+	setup()
+
+	// rest.Server 只在 Start 时绑定路由，这里取出已注册的路由绑定到独立的 router
+	server, err := rest.NewServer(rest.RestConf{Host: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("new rest server: %v", err)
+	}
+	handler.RegisterHandlers(server, svcCtx)
+
+	rt := router.NewRouter()
+	mws := middlewares()
+	for _, route := range server.Routes() {
+		h := route.Handler
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+		if err := rt.Handle(route.Method, route.Path, h); err != nil {
+			t.Fatalf("bind route %s %s: %v", route.Method, route.Path, err)
+		}
+	}
+
+	srv := &Server{Server: httptest.NewServer(rt)}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Response 测试响应
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode 将响应体解析为 JSON
+func (r *Response) Decode(t testing.TB, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode response %q: %v", r.Body, err)
+	}
+}
+
+// Do 发送请求，body 非 nil 时编码为 JSON
+func (s *Server) Do(t testing.TB, method, path string, body interface{}) *Response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encode request: %v", err)
+		}
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: content}
+}
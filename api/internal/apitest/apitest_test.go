@@ -0,0 +1,45 @@
+package apitest
+
+import (
+	"net/http"
+	"testing"
+
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/category/categorytest"
+	"idrm/pkg/response"
+)
+
+func TestServer_CreateCategoryValidation(t *testing.T) {
+	model := categorytest.NewMemory(&category.Category{Name: "数据资源", Code: "DR", Level: 1, Status: category.StatusEnabled})
+	srv := NewServer(t, NewServiceContext(model))
+
+	tests := []struct {
+		name       string
+		body       map[string]interface{}
+		wantStatus int
+		wantField  string
+	}{
+		{"父级不存在", map[string]interface{}{"name": "子类别", "code": "DR_SUB", "parent_id": 99, "level": 2}, http.StatusBadRequest, "parent_id"},
+		{"层级不匹配", map[string]interface{}{"name": "子类别", "code": "DR_SUB", "parent_id": 1, "level": 3}, http.StatusBadRequest, "level"},
+		{"校验通过", map[string]interface{}{"name": "子类别", "code": "DR_SUB", "parent_id": 1, "level": 2}, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := srv.Do(t, http.MethodPost, "/api/v1/catalog/categories", tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", resp.StatusCode, tt.wantStatus, resp.Body)
+			}
+			if tt.wantField == "" {
+				return
+			}
+
+			var httpErr response.HttpError
+			resp.Decode(t, &httpErr)
+			detail, _ := httpErr.Detail.(map[string]interface{})
+			if _, ok := detail[tt.wantField]; !ok {
+				t.Errorf("detail = %v, want error on %s", httpErr.Detail, tt.wantField)
+			}
+		})
+	}
+}
@@ -1,7 +1,14 @@
 package config
 
 import (
+	"idrm/migrations"
+	"idrm/pkg/cache"
 	"idrm/pkg/db"
+	"idrm/pkg/kms"
+	"idrm/pkg/lock"
+	"idrm/pkg/notify"
+	"idrm/pkg/outbox"
+	"idrm/pkg/preview"
 	"idrm/pkg/telemetry"
 
 	"github.com/zeromicro/go-zero/rest"
@@ -18,16 +25,51 @@ type Config struct {
 		// 资源目录数据库
 		ResourceCatalog db.Config
 
-		// 数据视图数据库
-		DataView db.Config
-
 		// 数据理解数据库
 		DataUnderstanding db.Config
+
+		// 启动时自动执行数据库迁移（migrations 目录）
+		AutoMigrate bool `json:",default=false"`
+
+		// 启动时检查实体与表结构是否一致（仅输出告警，不阻止启动）
+		SchemaCheck bool `json:",default=true"`
+
+		// 健康检查间隔（秒），0 表示不检查
+		HealthCheckInterval int `json:",default=30"`
+
+		// 开启 AutoMigrate 时等待数据库可用的最长时间（秒）
+		ConnectTimeout int `json:",default=300"`
 	}
 
+	// 缓存配置（类别查询读穿缓存：进程内 LRU + 可选 Redis）
+	Cache cache.Config
+
+	// 事务发件箱配置（类别变更事件投递到进程内总线或 Kafka）
+	Outbox outbox.Config
+
+	// 通知渠道（Outbox.Publisher 为 bus 时，数据视图结构变化在本服务通知负责人；kafka 时由 consumer 服务通知）
+	Notify notify.Config `json:",optional"`
+
+	// 数据预览（行数上限、语句超时及敏感列脱敏规则）
+	Preview preview.Config
+
+	// 分布式锁配置（业务临界区，如同一类别的并发修改；多实例部署时使用 db 或 redis）
+	Lock lock.Config
+
+	// 密钥管理（加密存储数据源密码），未配置 KeyFile 时无法保存带密码的数据源
+	KMS kms.Config
+
 	// 认证配置
 	Auth struct {
 		AccessSecret string
 		AccessExpire int64
 	}
 }
+
+// Datasources 数据源配置（名称与 migrations 中的数据库目录一致）
+func (c Config) Datasources() map[string]db.Config {
+	return map[string]db.Config{
+		migrations.ResourceCatalog:   c.DB.ResourceCatalog,
+		migrations.DataUnderstanding: c.DB.DataUnderstanding,
+	}
+}
@@ -0,0 +1,139 @@
+package dataview_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/kms"
+	"idrm/pkg/response"
+	"idrm/pkg/testkit"
+)
+
+func TestCreateDataView(t *testing.T) {
+	ctx := context.Background()
+	catalog := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	datasourceModel, err := datasource.NewModel(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataViewModel, err := dataview.NewModel(catalog, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lineageModel, err := lineage.NewModel(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 登记为 MySQL 数据源（保存 SQL 定义不连接源库），表结构从 SQLite 文件库采集
+	if err := datasourceModel.Insert(ctx, &datasource.Datasource{Name: "shop", Type: datasource.TypeMySQL, Host: "127.0.0.1", Port: 3306,
+		DatabaseName: "shop", Options: "{}", Owner: "李四"}); err != nil {
+		t.Fatal(err)
+	}
+	local := &datasource.Datasource{Name: "local", Type: datasource.TypeSQLite, DatabaseName: filepath.Join(t.TempDir(), "shop.db"), Options: "{}"}
+	if err := datasourceModel.Insert(ctx, local); err != nil {
+		t.Fatal(err)
+	}
+	src, err := datasource.Connect(ctx, kms.Disabled, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	testkit.Exec(t, src, "CREATE TABLE customer (id integer PRIMARY KEY, city varchar(50))")
+	testkit.Exec(t, src, "CREATE TABLE orders (id integer PRIMARY KEY, customer_id integer, amount decimal(10,2))")
+	testkit.Exec(t, src, "INSERT INTO customer VALUES (1, '北京'), (2, '上海')")
+	testkit.Exec(t, src, "INSERT INTO orders VALUES (1, 1, 10), (2, 1, 20), (3, 2, 5)")
+	tables, err := harvest.Inspect(ctx, src, harvest.Source{Name: "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dataview.Sync(ctx, dataViewModel, dataview.Source{Name: "shop", Datasource: "shop"}, tables, false); err != nil {
+		t.Fatal(err)
+	}
+
+	svcCtx := apitest.NewServiceContext(nil)
+	svcCtx.DatasourceModel = datasourceModel
+	svcCtx.DataViewModel = dataViewModel
+	svcCtx.LineageModel = lineageModel
+	srv := apitest.NewServer(t, svcCtx)
+
+	query := "SELECT c.id, c.city, SUM(o.amount) AS total FROM customer c JOIN orders o ON o.customer_id = c.id GROUP BY c.id, c.city"
+	var got types.DataViewResp
+	srv.Do(t, http.MethodPost, "/api/v1/data_view/data_views", map[string]string{
+		"datasource": "shop", "schema_name": "main", "name": "customer_total", "sql": query,
+	}).Decode(t, &got)
+	if got.Id == 0 || got.TableType != dataview.TableTypeSQL || got.Owner != "李四" || len(got.Tables) != 2 ||
+		got.Tables[0].DataViewId == 0 || got.Tables[1].DataViewId == 0 {
+		t.Fatalf("created = %+v", got)
+	}
+	wantTotal := types.DataViewSqlColumn{Name: "total", Expr: "SUM(o.amount)",
+		Sources: []types.DataViewSqlColumnRef{{Table: "orders", Column: "amount"}}}
+	if !reflect.DeepEqual(got.Columns[2], wantTotal) {
+		t.Errorf("total column = %+v, want %+v", got.Columns[2], wantTotal)
+	}
+
+	// 直接引用的列沿用源列类型
+	cols, err := dataViewModel.Columns(ctx, got.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols) != 3 {
+		t.Fatalf("columns = %d, want 3", len(cols))
+	}
+	if cols[1].Name != "city" || cols[1].ColumnType != "varchar(50)" || cols[2].Name != "total" || cols[2].DataType != "" {
+		t.Errorf("columns = %+v, %+v", *cols[1], *cols[2])
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		body     map[string]string
+		wantCode int
+	}{
+		{"语法错误", "/parse", map[string]string{"sql": "SELECT id FROM"}, 20002},
+		{"拒绝 DML", "/parse", map[string]string{"sql": "DELETE FROM customer"}, 20002},
+		{"引用未采集的表", "/parse", map[string]string{"datasource": "shop", "schema_name": "main", "sql": "SELECT * FROM payment"}, 20002},
+		{"数据源未注册", "/parse", map[string]string{"datasource": "erp", "sql": "SELECT id FROM customer"}, 30001},
+		{"未引用表", "/parse", map[string]string{"sql": "SELECT 1, NOW()"}, 20002},
+		{"数据源不是 MySQL", "", map[string]string{"datasource": "local", "schema_name": "main", "name": "local_view", "sql": "SELECT id FROM customer"}, 20002},
+		{"名称重复", "", map[string]string{"datasource": "shop", "schema_name": "main", "name": "customer_total", "sql": "SELECT id FROM customer"}, 30002},
+		{"输出列名重复", "", map[string]string{"datasource": "shop", "schema_name": "main", "name": "dup", "sql": "SELECT c.id, o.id FROM customer c, orders o"}, 20002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body response.HttpResponse
+			resp := srv.Do(t, http.MethodPost, "/api/v1/data_view/data_views"+tt.path, tt.body)
+			resp.Decode(t, &body)
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d (%s)", body.Code, tt.wantCode, resp.Body)
+			}
+		})
+	}
+
+	var parsed types.ParseDataViewSqlResp
+	srv.Do(t, http.MethodPost, "/api/v1/data_view/data_views/parse", map[string]string{"sql": "SELECT * FROM main.customer"}).Decode(t, &parsed)
+	want := types.ParseDataViewSqlResp{
+		Tables: []types.DataViewSqlTable{{Schema: "main", Name: "customer"}},
+		Columns: []types.DataViewSqlColumn{{Name: "*", Expr: "customer.*",
+			Sources: []types.DataViewSqlColumnRef{{Schema: "main", Table: "customer", Column: "*"}}, Direct: true}},
+	}
+	if !reflect.DeepEqual(parsed, want) {
+		t.Errorf("parse = %+v, want %+v", parsed, want)
+	}
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
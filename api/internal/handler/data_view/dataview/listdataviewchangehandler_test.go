@@ -0,0 +1,67 @@
+package dataview_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/testkit"
+)
+
+func TestListDataViewChange(t *testing.T) {
+	ctx := context.Background()
+	catalog := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	dataViewModel, err := dataview.NewModel(catalog, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 三次采集：删除列 mobile（破坏性）、新增列 email
+	table := func(cols ...string) []*harvest.Table {
+		tb := &harvest.Table{Schema: "main", Name: "customer", Type: "table"}
+		for i, c := range cols {
+			tb.Columns = append(tb.Columns, harvest.Column{Name: c, Position: i + 1, DataType: "text", ColumnType: "text", Nullable: true})
+		}
+		return []*harvest.Table{tb}
+	}
+	src := dataview.Source{Name: "crm"}
+	for _, cols := range [][]string{{"id", "mobile"}, {"id"}, {"id", "email"}} {
+		if _, err := dataview.Sync(ctx, dataViewModel, src, table(cols...), false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svcCtx := apitest.NewServiceContext(nil)
+	svcCtx.DataViewModel = dataViewModel
+	srv := apitest.NewServer(t, svcCtx)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantTotal int64
+	}{
+		{"全部", "/api/v1/data_view/data_views/changes?source=crm", 2},
+		{"只返回破坏性变化", "/api/v1/data_view/data_views/changes?source=crm&breaking_only=true", 1},
+		{"其他采集源", "/api/v1/data_view/data_views/changes?source=erp", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got types.ListDataViewChangeResp
+			srv.Do(t, http.MethodGet, tt.path, nil).Decode(t, &got)
+			if got.Total != tt.wantTotal || int64(len(got.List)) != tt.wantTotal {
+				t.Errorf("total = %d, len = %d, want %d", got.Total, len(got.List), tt.wantTotal)
+			}
+		})
+	}
+
+	// 原路径已移除
+	if resp := srv.Do(t, http.MethodGet, "/api/v1/catalog/data-views/changes", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("old path status = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,32 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataview
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"idrm/api/internal/logic/data_view/dataview"
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+)
+
+// 编目数据视图
+func PatchDataViewCatalogHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.PatchDataViewCatalogReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := dataview.NewPatchDataViewCatalogLogic(r.Context(), svcCtx)
+		resp, err := l.PatchDataViewCatalog(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
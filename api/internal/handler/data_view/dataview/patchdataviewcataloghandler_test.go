@@ -0,0 +1,92 @@
+package dataview_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/category/categorytest"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/response"
+	"idrm/pkg/testkit"
+)
+
+func TestPatchDataViewCatalog(t *testing.T) {
+	ctx := context.Background()
+	dataViewModel, err := dataview.NewModel(testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := func(cols ...string) []*harvest.Table {
+		tb := &harvest.Table{Schema: "main", Name: "customer", Type: harvest.TypeTable}
+		for i, c := range cols {
+			tb.Columns = append(tb.Columns, harvest.Column{Name: c, Position: i + 1, DataType: "text", ColumnType: "text"})
+		}
+		return []*harvest.Table{tb}
+	}
+	src := dataview.Source{Name: "crm"}
+	report, err := dataview.Sync(ctx, dataViewModel, src, table("id"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := report.Changes[0].DataViewId
+
+	svcCtx := apitest.NewServiceContext(categorytest.NewMemory(&category.Category{Name: "数据资源", Code: "data", Level: 1, Status: 1}))
+	svcCtx.DataViewModel = dataViewModel
+	srv := apitest.NewServer(t, svcCtx)
+	path := "/api/v1/data_view/data_views/" + itoa(id) + "/catalog"
+
+	var got types.DataViewCatalogResp
+	srv.Do(t, http.MethodPatch, path, map[string]interface{}{
+		"category_id": 1, "sensitivity": "confidential", "department": "财务部",
+	}).Decode(t, &got)
+	if got != (types.DataViewCatalogResp{Id: id, CategoryId: 1, Sensitivity: "confidential", Department: "财务部"}) {
+		t.Fatalf("patch resp = %+v", got)
+	}
+	// 只更新出现的字段
+	srv.Do(t, http.MethodPatch, path, map[string]interface{}{"department": ""}).Decode(t, &got)
+	if got.CategoryId != 1 || got.Sensitivity != "confidential" || got.Department != "" {
+		t.Errorf("partial patch resp = %+v", got)
+	}
+
+	// 再次采集（结构变化）保留编目属性
+	if _, err := dataview.Sync(ctx, dataViewModel, src, table("id", "name"), false); err != nil {
+		t.Fatal(err)
+	}
+	view, err := dataViewModel.FindOne(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.CategoryId != 1 || view.Sensitivity != "confidential" {
+		t.Errorf("after sync category = %d, sensitivity = %q, want 1 and confidential", view.CategoryId, view.Sensitivity)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		body     map[string]interface{}
+		wantCode int
+	}{
+		{"类别不存在", path, map[string]interface{}{"category_id": 99}, 20002},
+		{"数据视图不存在", "/api/v1/data_view/data_views/999/catalog", map[string]interface{}{"department": "财务部"}, 30001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body response.HttpResponse
+			srv.Do(t, http.MethodPatch, tt.path, tt.body).Decode(t, &body)
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d (%s)", body.Code, tt.wantCode, body.Msg)
+			}
+		})
+	}
+
+	if resp := srv.Do(t, http.MethodPatch, path, map[string]interface{}{"sensitivity": "top"}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid sensitivity status = %d, want 400", resp.StatusCode)
+	}
+}
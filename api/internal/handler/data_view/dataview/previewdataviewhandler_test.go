@@ -0,0 +1,149 @@
+package dataview_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/kms"
+	"idrm/pkg/response"
+	"idrm/pkg/testkit"
+)
+
+func TestPreviewDataView(t *testing.T) {
+	ctx := context.Background()
+	catalog := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	datasourceModel, err := datasource.NewModel(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataViewModel, err := dataview.NewModel(catalog, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 源为已注册的 SQLite 文件库，采集后写入数据视图
+	d := &datasource.Datasource{Name: "crm", Type: datasource.TypeSQLite, DatabaseName: filepath.Join(t.TempDir(), "crm.db"), Options: "{}"}
+	if err := datasourceModel.Insert(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	src, err := datasource.Connect(ctx, kms.Disabled, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	testkit.Exec(t, src, "CREATE TABLE customer (id integer PRIMARY KEY, real_name text, mobile text)")
+	testkit.Exec(t, src, "INSERT INTO customer VALUES (1, '张三', '13812345678'), (2, '李四', '13987654321'), (3, '王五', NULL)")
+	testkit.Exec(t, src, "CREATE VIEW customer_contact AS SELECT id, mobile AS contact FROM customer")
+	tables, err := harvest.Inspect(ctx, src, harvest.Source{Name: "crm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dataview.Sync(ctx, dataViewModel, dataview.Source{Name: "crm", Datasource: "crm"}, tables, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dataview.Sync(ctx, dataViewModel, dataview.Source{Name: "direct"}, tables, false); err != nil {
+		t.Fatal(err)
+	}
+	// SQL 定义的数据视图：数据源不是 MySQL，不能执行其定义
+	aliased := &dataview.DataView{Datasource: "crm", SchemaName: "main", Table: "customer_alias", TableType: dataview.TableTypeSQL,
+		Indexes: "[]", Status: dataview.StatusActive, HarvestedAt: time.Now()}
+	if _, err := aliased.SetDefinition("SELECT id, mobile AS x, UPPER(real_name) AS n FROM customer ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataViewModel.Save(ctx, aliased, []*dataview.Column{{Name: "id", Position: 1}, {Name: "x", Position: 2}, {Name: "n", Position: 3}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	views, err := dataViewModel.FindBySource(ctx, "crm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contactId int64
+	for _, v := range views {
+		if v.Table == "customer_contact" {
+			contactId = v.Id
+		}
+	}
+	direct, err := dataViewModel.FindBySource(ctx, "direct")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svcCtx := apitest.NewServiceContext(nil)
+	svcCtx.DatasourceModel = datasourceModel
+	svcCtx.DataViewModel = dataViewModel
+	srv := apitest.NewServer(t, svcCtx)
+
+	var got types.PreviewDataViewResp
+	srv.Do(t, http.MethodGet, "/api/v1/data_view/data_views/1/preview?limit=2", nil).Decode(t, &got)
+	want := types.PreviewDataViewResp{
+		Columns: []string{"id", "real_name", "mobile"},
+		Rows:    [][]interface{}{{float64(1), "张*", "138****5678"}, {float64(2), "李*", "139****4321"}},
+		Masked:  []string{"real_name", "mobile"},
+		HasMore: true,
+	}
+	got.ElapsedMs = 0
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("preview = %+v, want %+v", got, want)
+	}
+
+	// 别名引用敏感列时按来源全部脱敏
+	maskedTests := []struct {
+		name string
+		id   int64
+		want types.PreviewDataViewResp
+	}{
+		{"采集的视图", contactId, types.PreviewDataViewResp{
+			Columns: []string{"id", "contact"},
+			Rows:    [][]interface{}{{float64(1), "******"}},
+			Masked:  []string{"contact"},
+			HasMore: true,
+		}},
+	}
+	for _, tt := range maskedTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got types.PreviewDataViewResp
+			srv.Do(t, http.MethodGet, "/api/v1/data_view/data_views/"+strconv.FormatInt(tt.id, 10)+"/preview?limit=1", nil).Decode(t, &got)
+			got.ElapsedMs = 0
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("preview = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{"不存在", "/api/v1/data_view/data_views/9/preview", 30001},
+		{"未引用已注册数据源", "/api/v1/data_view/data_views/" + strconv.FormatInt(direct[0].Id, 10) + "/preview", 30004},
+		{"SQL定义的数据源不是MySQL", "/api/v1/data_view/data_views/" + strconv.FormatInt(aliased.Id, 10) + "/preview", 30004},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body response.HttpResponse
+			resp := srv.Do(t, http.MethodGet, tt.path, nil)
+			resp.Decode(t, &body)
+			if body.Code != tt.wantCode || bytes.Contains(resp.Body, []byte("13812345678")) {
+				t.Errorf("code = %d, want %d (%s)", body.Code, tt.wantCode, resp.Body)
+			}
+		})
+	}
+
+	if resp := srv.Do(t, http.MethodGet, "/api/v1/data_view/data_views/1/preview?limit=5000", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("limit=5000 status = %d, want 400", resp.StatusCode)
+	}
+}
@@ -0,0 +1,86 @@
+package category_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/category/categorytest"
+	"idrm/pkg/db"
+	"idrm/pkg/response"
+)
+
+func TestPatchCategory(t *testing.T) {
+	newModels := map[string]func(t *testing.T) category.Model{
+		"内存": func(t *testing.T) category.Model { return categorytest.NewMemory() },
+		"gorm": func(t *testing.T) category.Model {
+			model, _ := categorytest.NewSQLite(t, db.ORMGorm)
+			return model
+		},
+		"sqlx": func(t *testing.T) category.Model {
+			model, _ := categorytest.NewSQLite(t, db.ORMSqlx)
+			return model
+		},
+	}
+
+	tests := []struct {
+		name       string
+		body       map[string]interface{}
+		wantStatus int
+		want       types.CategoryResp // HTTP 200 时比较
+		wantCode   int                // 业务错误码
+	}{
+		{"禁用类别", map[string]interface{}{"status": 0}, http.StatusOK,
+			types.CategoryResp{Name: "数据资源", Code: "DR", Level: 1, Sort: 3, Description: "描述", Status: 0}, 0},
+		{"清空描述及排序", map[string]interface{}{"description": "", "sort": 0}, http.StatusOK,
+			types.CategoryResp{Name: "数据资源", Code: "DR", Level: 1, Sort: 0, Description: "", Status: 1}, 0},
+		{"空请求不修改", map[string]interface{}{}, http.StatusOK,
+			types.CategoryResp{Name: "数据资源", Code: "DR", Level: 1, Sort: 3, Description: "描述", Status: 1}, 0},
+		{"状态非法", map[string]interface{}{"status": 2}, http.StatusBadRequest, types.CategoryResp{}, 0},
+		{"编码重复", map[string]interface{}{"code": "BR"}, http.StatusOK, types.CategoryResp{}, 30002},
+	}
+
+	for backend, newModel := range newModels {
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				model := newModel(t)
+				ctx := context.Background()
+				data, err := model.Insert(ctx, &category.Category{Name: "数据资源", Code: "DR", Level: 1, Sort: 3, Description: "描述", Status: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := model.Insert(ctx, &category.Category{Name: "业务资源", Code: "BR", Level: 1, Status: 1}); err != nil {
+					t.Fatal(err)
+				}
+
+				srv := apitest.NewServer(t, apitest.NewServiceContext(model))
+				resp := srv.Do(t, http.MethodPatch, "/api/v1/catalog/categories/1", tt.body)
+				if resp.StatusCode != tt.wantStatus {
+					t.Fatalf("status = %d, want %d, body = %s", resp.StatusCode, tt.wantStatus, resp.Body)
+				}
+				if tt.wantStatus != http.StatusOK {
+					return
+				}
+
+				if tt.wantCode != 0 {
+					var body response.HttpResponse
+					resp.Decode(t, &body)
+					if body.Code != tt.wantCode {
+						t.Errorf("code = %d, want %d, body = %s", body.Code, tt.wantCode, resp.Body)
+					}
+					return
+				}
+
+				var got types.CategoryResp
+				resp.Decode(t, &got)
+				tt.want.Id = data.Id
+				if got != tt.want {
+					t.Errorf("resp = %+v, want %+v", got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package datasource_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/pkg/db"
+	"idrm/pkg/kms"
+	"idrm/pkg/response"
+	"idrm/pkg/testkit"
+)
+
+func TestDatasource(t *testing.T) {
+	model, err := datasource.NewModel(testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	km, err := kms.NewLocal(filepath.Join(t.TempDir(), "secret.key"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcCtx := apitest.NewServiceContext(nil)
+	svcCtx.DatasourceModel = model
+	svcCtx.KMS = km
+	srv := apitest.NewServer(t, svcCtx)
+
+	// 源为运维登记的 SQLite 文件库，连通性检查无需外部数据库；mysql 源连接本机未监听的端口
+	local := &datasource.Datasource{Name: "本地库", Type: datasource.TypeSQLite, DatabaseName: filepath.Join(t.TempDir(), "src.db"), Options: "{}"}
+	if err := datasource.SetPassword(context.Background(), km, local, "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Insert(context.Background(), local); err != nil {
+		t.Fatal(err)
+	}
+	resp := srv.Do(t, http.MethodPost, "/api/v1/catalog/datasources", map[string]interface{}{"name": "不可达", "type": "mysql", "host": "127.0.0.1", "port": 1,
+		"database_name": "erp", "username": "reader", "password": "s3cret", "options": map[string]string{"charset": "utf8"}})
+	if bytes.Contains(resp.Body, []byte("s3cret")) {
+		t.Fatalf("create response contains password: %s", resp.Body)
+	}
+	var created types.DatasourceResp
+	resp.Decode(t, &created)
+	if created.Id == 0 || created.Password != "******" {
+		t.Fatalf("create response = %s, want id and redacted password", resp.Body)
+	}
+
+	// 接口不能登记 sqlite 源（库名为服务器上的文件路径）
+	resp = srv.Do(t, http.MethodPost, "/api/v1/catalog/datasources", map[string]interface{}{"name": "文件", "type": "sqlite", "database_name": "/etc/passwd"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("create sqlite status = %d, want 400", resp.StatusCode)
+	}
+
+	list := srv.Do(t, http.MethodGet, "/api/v1/catalog/datasources", nil)
+	if bytes.Contains(list.Body, []byte("s3cret")) {
+		t.Errorf("list response contains password: %s", list.Body)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     interface{}
+		wantCode int  // 业务错误码，0 表示成功
+		wantOK   bool // 连通性检查结果
+	}{
+		{"连通", http.MethodPost, "/api/v1/catalog/datasources/1/test", nil, 0, true},
+		{"不可达", http.MethodPost, "/api/v1/catalog/datasources/2/test?timeout=2", nil, 0, false},
+		{"不存在", http.MethodPost, "/api/v1/catalog/datasources/9/test", nil, 30001, false},
+		{"名称重复", http.MethodPatch, "/api/v1/catalog/datasources/2", map[string]interface{}{"name": "本地库"}, 30002, false},
+		{"参数不支持", http.MethodPatch, "/api/v1/catalog/datasources/2", map[string]interface{}{"options": map[string]string{"timeout": "1"}}, 20002, false},
+		{"字符集不在白名单", http.MethodPatch, "/api/v1/catalog/datasources/2", map[string]interface{}{"options": map[string]string{"charset": "utf8&allowAllFiles=true"}}, 20002, false},
+		{"修改sqlite路径", http.MethodPatch, "/api/v1/catalog/datasources/1", map[string]interface{}{"database_name": "/etc/passwd"}, 20002, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := srv.Do(t, tt.method, tt.path, tt.body)
+			if bytes.Contains(resp.Body, []byte("s3cret")) {
+				t.Errorf("response contains password: %s", resp.Body)
+			}
+			if tt.wantCode != 0 {
+				var body response.HttpResponse
+				resp.Decode(t, &body)
+				if body.Code != tt.wantCode {
+					t.Errorf("code = %d, want %d (%s)", body.Code, tt.wantCode, resp.Body)
+				}
+				return
+			}
+			var got types.TestDatasourceResp
+			resp.Decode(t, &got)
+			if got.Success != tt.wantOK || (!got.Success && got.Message == "") {
+				t.Errorf("test response = %s, want success %v", resp.Body, tt.wantOK)
+			}
+		})
+	}
+
+	// 未配置密钥文件时不能保存密码
+	svcCtx.KMS = kms.Disabled
+	var body response.HttpResponse
+	srv.Do(t, http.MethodPatch, "/api/v1/catalog/datasources/1", map[string]interface{}{"password": "new"}).Decode(t, &body)
+	if body.Code != 30004 {
+		t.Errorf("patch password without key code = %d, want 30004", body.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package lineage_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/kms"
+	"idrm/pkg/response"
+	"idrm/pkg/testkit"
+)
+
+func TestLineageGraph(t *testing.T) {
+	ctx := context.Background()
+	catalog := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	datasourceModel, err := datasource.NewModel(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataViewModel, err := dataview.NewModel(catalog, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lineageModel, err := lineage.NewModel(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 采集源库：表及引用它的视图（登记为 MySQL 数据源以便以 SQL 定义数据视图，表结构从 SQLite 文件库采集）
+	if err := datasourceModel.Insert(ctx, &datasource.Datasource{Name: "shop", Type: datasource.TypeMySQL, Host: "127.0.0.1", Port: 3306,
+		DatabaseName: "shop", Options: "{}"}); err != nil {
+		t.Fatal(err)
+	}
+	src, err := datasource.Connect(ctx, kms.Disabled, &datasource.Datasource{Type: datasource.TypeSQLite, DatabaseName: filepath.Join(t.TempDir(), "shop.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	testkit.Exec(t, src, "CREATE TABLE customer (id integer PRIMARY KEY, city varchar(50))")
+	testkit.Exec(t, src, "CREATE TABLE orders (id integer PRIMARY KEY, customer_id integer, amount decimal(10,2))")
+	testkit.Exec(t, src, "CREATE VIEW big_orders AS SELECT id, amount FROM orders WHERE amount > 100")
+	tables, err := harvest.Inspect(ctx, src, harvest.Source{Name: "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dataview.Sync(ctx, dataViewModel, dataview.Source{Name: "shop", Datasource: "shop"}, tables, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lineage.SyncSource(ctx, lineageModel, dataViewModel, "shop"); err != nil {
+		t.Fatal(err)
+	}
+
+	svcCtx := apitest.NewServiceContext(nil)
+	svcCtx.DatasourceModel = datasourceModel
+	svcCtx.DataViewModel = dataViewModel
+	svcCtx.LineageModel = lineageModel
+	srv := apitest.NewServer(t, svcCtx)
+
+	// 以 SQL 定义的数据视图写入血缘，再登记发布的目录资源及 API
+	var view types.DataViewResp
+	srv.Do(t, http.MethodPost, "/api/v1/data_view/data_views", map[string]string{"datasource": "shop", "schema_name": "main", "name": "city_sales",
+		"sql": "SELECT c.city, SUM(o.amount) AS total FROM customer c JOIN orders o ON o.customer_id = c.id GROUP BY c.city"}).Decode(t, &view)
+
+	var nodes types.ListLineageNodeResp
+	srv.Do(t, http.MethodGet, "/api/v1/catalog/lineage/nodes?type=data_view", nil).Decode(t, &nodes)
+	if nodes.Total != 1 || nodes.List[0].RefId != view.Id || nodes.List[0].Key != "data_view:shop:main.city_sales" {
+		t.Fatalf("data view nodes = %+v", nodes)
+	}
+	dataViewNode := nodes.List[0].Id
+	srv.Do(t, http.MethodGet, "/api/v1/catalog/lineage/nodes?type=table&keyword=orders", nil).Decode(t, &nodes)
+	if nodes.Total != 2 {
+		t.Fatalf("table nodes = %+v, want orders and big_orders", nodes)
+	}
+	var ordersNode int64
+	for _, n := range nodes.List {
+		if n.Name == "main.orders" {
+			ordersNode = n.Id
+		}
+	}
+
+	var resource, api types.LineageNodeResp
+	srv.Do(t, http.MethodPost, "/api/v1/catalog/lineage/nodes", map[string]interface{}{"type": "resource", "key": "R-001", "name": "城市销售额"}).Decode(t, &resource)
+	srv.Do(t, http.MethodPost, "/api/v1/catalog/lineage/nodes", map[string]interface{}{"type": "api", "key": "GET /sales/city", "name": "城市销售额接口"}).Decode(t, &api)
+	if resource.Key != "resource:r-001" || api.Id == 0 {
+		t.Fatalf("resource = %+v, api = %+v", resource, api)
+	}
+	var edge types.LineageEdgeResp
+	srv.Do(t, http.MethodPost, "/api/v1/catalog/lineage/edges", map[string]interface{}{"source_id": dataViewNode, "target_id": resource.Id, "type": "publishes"}).Decode(t, &edge)
+	if edge.Id == 0 || edge.Origin != lineage.OriginManual {
+		t.Fatalf("edge = %+v", edge)
+	}
+	srv.Do(t, http.MethodPost, "/api/v1/catalog/lineage/edges", map[string]interface{}{"source_id": resource.Id, "target_id": api.Id, "type": "serves"}).Decode(t, &edge)
+
+	// API 的上游：资源、数据视图及其引用的表
+	var graph types.LineageGraphResp
+	srv.Do(t, http.MethodGet, "/api/v1/catalog/lineage/"+itoa(api.Id)+"/upstream?depth=10", nil).Decode(t, &graph)
+	g := graph.Graph
+	if !g.Directed || g.Metadata.Root != itoa(api.Id) || g.Metadata.Direction != "upstream" || len(g.Nodes) != 5 || len(g.Edges) != 4 {
+		t.Errorf("upstream graph = %+v", g)
+	}
+	if g.Nodes[0].Id != itoa(api.Id) || g.Nodes[4].Metadata.Distance != 3 {
+		t.Errorf("upstream nodes = %+v", g.Nodes)
+	}
+
+	// 深度 1 只包含表的列及直接引用它的视图
+	srv.Do(t, http.MethodGet, "/api/v1/catalog/lineage/"+itoa(ordersNode)+"/downstream?depth=1", nil).Decode(t, &graph)
+	if len(graph.Graph.Nodes) != 6 || !graph.Graph.Metadata.Truncated {
+		t.Errorf("downstream graph = %+v", graph.Graph)
+	}
+
+	// orders 的影响：视图 big_orders、数据视图 city_sales、目录资源及 API
+	var impact types.LineageImpactResp
+	srv.Do(t, http.MethodGet, "/api/v1/catalog/lineage/"+itoa(ordersNode)+"/impact", nil).Decode(t, &impact)
+	summary := map[string]int{}
+	for _, s := range impact.Summary {
+		summary[s.Type] = s.Count
+	}
+	if len(impact.Affected) != 4 || summary[lineage.NodeTable] != 1 || summary[lineage.NodeDataView] != 1 ||
+		summary[lineage.NodeResource] != 1 || summary[lineage.NodeAPI] != 1 || impact.Columns != 7 {
+		t.Errorf("impact = %+v, columns %d", impact.Affected, impact.Columns)
+	}
+
+	// 参数校验失败
+	if resp := srv.Do(t, http.MethodGet, "/api/v1/catalog/lineage/"+itoa(api.Id)+"/downstream?depth=11", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("depth=11 status = %d, want 400", resp.StatusCode)
+	}
+	resp := srv.Do(t, http.MethodPost, "/api/v1/catalog/lineage/nodes", map[string]interface{}{"type": "table", "key": "t", "name": "t"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("register table node status = %d, want 400", resp.StatusCode)
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     interface{}
+		wantCode int
+	}{
+		{"节点不存在", http.MethodGet, "/lineage/99999/upstream", nil, 30001},
+		{"节点重复", http.MethodPost, "/lineage/nodes", map[string]interface{}{"type": "resource", "key": "r-001", "name": "重复"}, 30002},
+		{"边重复", http.MethodPost, "/lineage/edges", map[string]interface{}{"source_id": resource.Id, "target_id": api.Id, "type": "serves"}, 30002},
+		{"边类型与节点不符", http.MethodPost, "/lineage/edges", map[string]interface{}{"source_id": api.Id, "target_id": resource.Id, "type": "serves"}, 20002},
+		{"边的节点不存在", http.MethodPost, "/lineage/edges", map[string]interface{}{"source_id": resource.Id, "target_id": 99999, "type": "serves"}, 30001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body response.HttpResponse
+			resp := srv.Do(t, tt.method, "/api/v1/catalog"+tt.path, tt.body)
+			resp.Decode(t, &body)
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d (%s)", body.Code, tt.wantCode, resp.Body)
+			}
+		})
+	}
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,109 @@
+package stats_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"idrm/api/internal/apitest"
+	"idrm/api/internal/types"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/stats"
+	"idrm/pkg/db"
+	"idrm/pkg/response"
+	"idrm/pkg/testkit"
+)
+
+func TestCatalogStats(t *testing.T) {
+	ctx := context.Background()
+	model, err := stats.NewModel(testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svcCtx := apitest.NewServiceContext(nil)
+	svcCtx.StatsModel = model
+	srv := apitest.NewServer(t, svcCtx)
+
+	// 无快照时返回业务错误
+	var body response.HttpResponse
+	srv.Do(t, http.MethodGet, "/api/v1/catalog/stats", nil).Decode(t, &body)
+	if body.Code != 30001 {
+		t.Fatalf("code without snapshot = %d, want 30001", body.Code)
+	}
+
+	// 昨天 2 个类别，今天 3 个
+	today := time.Now()
+	categories := []*category.Category{
+		{Id: 1, Name: "数据资源", Level: 1, Status: 1, CreatedAt: today.AddDate(0, 0, -60)},
+		{Id: 2, Name: "库表", ParentId: 1, Level: 2, Status: 1, CreatedAt: today.AddDate(0, 0, -20)},
+		{Id: 3, Name: "接口", ParentId: 1, Level: 2, Status: 0, CreatedAt: today.AddDate(0, 0, -1)},
+	}
+	// 昨天 1 个资源，今天 2 个（另有 1 个已在源中删除）
+	views := []*dataview.DataView{
+		{Id: 1, Status: dataview.StatusActive, CategoryId: 2, Sensitivity: dataview.SensitivityInternal, Department: "财务部", CreatedAt: today.AddDate(0, 0, -10)},
+		{Id: 2, Status: dataview.StatusActive, CreatedAt: today},
+		{Id: 3, Status: dataview.StatusRemoved, CategoryId: 3, CreatedAt: today.AddDate(0, 0, -40)},
+	}
+	for _, snap := range []struct {
+		now        time.Time
+		categories []*category.Category
+		views      []*dataview.DataView
+	}{{today.AddDate(0, 0, -1), categories[:2], views[:1]}, {today, categories, views}} {
+		if err := model.Save(ctx, snap.now.Format(stats.DateLayout), stats.Compute(snap.categories, snap.views, snap.now)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		path string
+		got  interface{}
+		want func(got interface{}) bool
+	}{
+		{"最近快照", "/api/v1/catalog/stats", &types.CatalogStatsResp{}, func(got interface{}) bool {
+			r := got.(*types.CatalogStatsResp)
+			return r.Date == today.Format(stats.DateLayout) && r.Total == 3 && r.Created7d == 1 && r.Created30d == 2 &&
+				len(r.ByStatus) == 2 && len(r.ByLevel) == 2 &&
+				len(r.BySubtree) == 1 && r.BySubtree[0] == types.CatalogStatItem{Key: "1", Label: "数据资源", Value: 3}
+		}},
+		{"资源统计", "/api/v1/catalog/stats", &types.CatalogStatsResp{}, func(got interface{}) bool {
+			r := got.(*types.CatalogStatsResp)
+			return r.ResourceTotal == 2 && r.ResourceCreated7d == 1 && r.ResourceCreated30d == 2 &&
+				len(r.ResourceByStatus) == 2 &&
+				len(r.ResourceBySubtree) == 2 &&
+				r.ResourceBySubtree[0] == types.CatalogStatItem{Key: "0", Label: "未编目", Value: 1} &&
+				r.ResourceBySubtree[1] == types.CatalogStatItem{Key: "1", Label: "数据资源", Value: 1} &&
+				len(r.ResourceBySensitivity) == 2 &&
+				r.ResourceBySensitivity[1] == types.CatalogStatItem{Key: "internal", Label: "内部", Value: 1} &&
+				len(r.ResourceByDepartment) == 2
+		}},
+		{"趋势", "/api/v1/catalog/stats/trend?days=7", &types.CatalogStatsTrendResp{}, func(got interface{}) bool {
+			l := got.(*types.CatalogStatsTrendResp).List
+			return len(l) == 2 && l[0].Total == 2 && l[0].Growth == 0 && l[1].Total == 3 && l[1].Growth == 1 &&
+				l[0].ResourceTotal == 1 && l[1].ResourceTotal == 2 && l[1].ResourceGrowth == 1 && l[1].ResourceCreated7d == 1
+		}},
+		{"趋势仅今天", "/api/v1/catalog/stats/trend?days=1", &types.CatalogStatsTrendResp{}, func(got interface{}) bool {
+			l := got.(*types.CatalogStatsTrendResp).List
+			return len(l) == 1 && l[0].Total == 3
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := srv.Do(t, http.MethodGet, tt.path, nil)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", resp.StatusCode, resp.Body)
+			}
+			resp.Decode(t, tt.got)
+			if !tt.want(tt.got) {
+				t.Errorf("resp = %s", resp.Body)
+			}
+		})
+	}
+
+	if resp := srv.Do(t, http.MethodGet, "/api/v1/catalog/stats/trend?days=0", nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("days=0 status = %d, want 400", resp.StatusCode)
+	}
+}
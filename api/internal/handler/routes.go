@@ -7,7 +7,11 @@ import (
 	"net/http"
 
 	data_viewcategory "idrm/api/internal/handler/data_view/category"
+	data_viewdataview "idrm/api/internal/handler/data_view/dataview"
 	resource_catalogcategory "idrm/api/internal/handler/resource_catalog/category"
+	resource_catalogdatasource "idrm/api/internal/handler/resource_catalog/datasource"
+	resource_cataloglineage "idrm/api/internal/handler/resource_catalog/lineage"
+	resource_catalogstats "idrm/api/internal/handler/resource_catalog/stats"
 	"idrm/api/internal/svc"
 
 	"github.com/zeromicro/go-zero/rest"
@@ -38,6 +42,42 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		rest.WithPrefix("/api/v1/data_view"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 预览数据视图
+				Method:  http.MethodGet,
+				Path:    "/data_views/:id/preview",
+				Handler: data_viewdataview.PreviewDataViewHandler(serverCtx),
+			},
+			{
+				// 数据视图结构变化列表
+				Method:  http.MethodGet,
+				Path:    "/data_views/changes",
+				Handler: data_viewdataview.ListDataViewChangeHandler(serverCtx),
+			},
+			{
+				// 校验数据视图 SQL
+				Method:  http.MethodPost,
+				Path:    "/data_views/parse",
+				Handler: data_viewdataview.ParseDataViewSqlHandler(serverCtx),
+			},
+			{
+				// 以 SQL 定义数据视图
+				Method:  http.MethodPost,
+				Path:    "/data_views",
+				Handler: data_viewdataview.CreateDataViewHandler(serverCtx),
+			},
+			{
+				// 编目数据视图
+				Method:  http.MethodPatch,
+				Path:    "/data_views/:id/catalog",
+				Handler: data_viewdataview.PatchDataViewCatalogHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/data_view"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -58,6 +98,114 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 				Path:    "/categories/:id",
 				Handler: resource_catalogcategory.GetCategoryHandler(serverCtx),
 			},
+			{
+				// 部分更新类别
+				Method:  http.MethodPatch,
+				Path:    "/categories/:id",
+				Handler: resource_catalogcategory.PatchCategoryHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/catalog"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 创建数据源
+				Method:  http.MethodPost,
+				Path:    "/datasources",
+				Handler: resource_catalogdatasource.CreateDatasourceHandler(serverCtx),
+			},
+			{
+				// 数据源列表
+				Method:  http.MethodGet,
+				Path:    "/datasources",
+				Handler: resource_catalogdatasource.ListDatasourceHandler(serverCtx),
+			},
+			{
+				// 获取数据源详情
+				Method:  http.MethodGet,
+				Path:    "/datasources/:id",
+				Handler: resource_catalogdatasource.GetDatasourceHandler(serverCtx),
+			},
+			{
+				// 部分更新数据源
+				Method:  http.MethodPatch,
+				Path:    "/datasources/:id",
+				Handler: resource_catalogdatasource.PatchDatasourceHandler(serverCtx),
+			},
+			{
+				// 删除数据源
+				Method:  http.MethodDelete,
+				Path:    "/datasources/:id",
+				Handler: resource_catalogdatasource.DeleteDatasourceHandler(serverCtx),
+			},
+			{
+				// 测试数据源连通性
+				Method:  http.MethodPost,
+				Path:    "/datasources/:id/test",
+				Handler: resource_catalogdatasource.TestDatasourceHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/catalog"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 血缘节点列表
+				Method:  http.MethodGet,
+				Path:    "/lineage/nodes",
+				Handler: resource_cataloglineage.ListLineageNodeHandler(serverCtx),
+			},
+			{
+				// 登记目录资源或 API 节点
+				Method:  http.MethodPost,
+				Path:    "/lineage/nodes",
+				Handler: resource_cataloglineage.CreateLineageNodeHandler(serverCtx),
+			},
+			{
+				// 登记血缘边
+				Method:  http.MethodPost,
+				Path:    "/lineage/edges",
+				Handler: resource_cataloglineage.CreateLineageEdgeHandler(serverCtx),
+			},
+			{
+				// 上游血缘
+				Method:  http.MethodGet,
+				Path:    "/lineage/:id/upstream",
+				Handler: resource_cataloglineage.GetLineageUpstreamHandler(serverCtx),
+			},
+			{
+				// 下游血缘
+				Method:  http.MethodGet,
+				Path:    "/lineage/:id/downstream",
+				Handler: resource_cataloglineage.GetLineageDownstreamHandler(serverCtx),
+			},
+			{
+				// 影响分析
+				Method:  http.MethodGet,
+				Path:    "/lineage/:id/impact",
+				Handler: resource_cataloglineage.GetLineageImpactHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/v1/catalog"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 最近一次统计快照
+				Method:  http.MethodGet,
+				Path:    "/stats",
+				Handler: resource_catalogstats.GetCatalogStatsHandler(serverCtx),
+			},
+			{
+				// 统计趋势
+				Method:  http.MethodGet,
+				Path:    "/stats/trend",
+				Handler: resource_catalogstats.GetCatalogStatsTrendHandler(serverCtx),
+			},
 		},
 		rest.WithPrefix("/api/v1/catalog"),
 	)
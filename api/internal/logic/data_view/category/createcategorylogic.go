@@ -27,7 +27,7 @@ func NewCreateCategoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Cr
 	}
 }
 
-func (l *CreateCategoryLogic) CreateCategory(req *types.CreateCategoryReq) (resp *types.CategoryResp, err error) {
+func (l *CreateCategoryLogic) CreateCategory(req *types.DataViewCreateCategoryReq) (resp *types.DataViewCategoryResp, err error) {
 	// todo: add your logic here and delete this line
 
 	return
@@ -27,7 +27,7 @@ func NewGetCategoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetCa
 	}
 }
 
-func (l *GetCategoryLogic) GetCategory(req *types.CategoryReq) (resp *types.CategoryResp, err error) {
+func (l *GetCategoryLogic) GetCategory(req *types.DataViewCategoryReq) (resp *types.DataViewCategoryResp, err error) {
 	// todo: add your logic here and delete this line
 
 	return
@@ -27,7 +27,7 @@ func NewListCategoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *List
 	}
 }
 
-func (l *ListCategoryLogic) ListCategory(req *types.ListCategoryReq) (resp *types.ListCategoryResp, err error) {
+func (l *ListCategoryLogic) ListCategory(req *types.DataViewListCategoryReq) (resp *types.DataViewListCategoryResp, err error) {
 	// todo: add your logic here and delete this line
 
 	return
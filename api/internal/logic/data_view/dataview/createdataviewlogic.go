@@ -0,0 +1,180 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataview
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/pkg/errorx"
+	"idrm/pkg/harvest"
+	"idrm/pkg/sqlparse"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateDataViewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 以 SQL 定义数据视图
+func NewCreateDataViewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateDataViewLogic {
+	return &CreateDataViewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateDataViewLogic) CreateDataView(req *types.CreateDataViewReq) (resp *types.DataViewResp, err error) {
+	checked, err := checkSql(l.ctx, l.Logger, l.svcCtx, req.Datasource, req.SchemaName, req.Sql)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := l.columns(checked, req.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := req.Owner
+	if owner == "" {
+		owner = checked.datasource.Owner
+	}
+	// SQL 定义的数据视图不属于采集源（source 为空），不参与采集比较
+	view := &dataview.DataView{
+		Datasource:  req.Datasource,
+		SchemaName:  req.SchemaName,
+		Table:       req.Name,
+		TableType:   dataview.TableTypeSQL,
+		Comment:     req.Comment,
+		Indexes:     "[]",
+		Status:      dataview.StatusActive,
+		Owner:       owner,
+		HarvestedAt: time.Now(),
+	}
+	if _, err := view.SetDefinition(req.Sql); err != nil {
+		return nil, sqlError(err)
+	}
+	view.Fingerprint = fingerprint(view, columns)
+	if err := l.svcCtx.DataViewModel.Save(l.ctx, view, columns, nil); err != nil {
+		if errors.Is(err, dataview.ErrDuplicateName) {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeAlreadyExists, "数据视图名称已存在")
+		}
+		l.Errorf("保存数据视图失败: name=%s, err=%v", req.Name, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	// 写入血缘图（数据视图节点及引用的表和列），失败时只记录日志，数据视图已保存
+	if err := lineage.SyncView(l.ctx, l.svcCtx.LineageModel, l.svcCtx.DataViewModel, view); err != nil {
+		l.Errorf("写入数据视图血缘失败: id=%d, err=%v", view.Id, err)
+	}
+
+	// 重新查询以返回数据库生成的时间
+	saved, err := l.svcCtx.DataViewModel.FindOne(l.ctx, view.Id)
+	if err != nil {
+		l.Errorf("查询数据视图失败: id=%d, err=%v", view.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	return &types.DataViewResp{
+		Id:         saved.Id,
+		Datasource: saved.Datasource,
+		SchemaName: saved.SchemaName,
+		TableName:  saved.Table,
+		TableType:  saved.TableType,
+		Comment:    saved.Comment,
+		Owner:      saved.Owner,
+		Definition: saved.Definition,
+		Tables:     checked.resp.Tables,
+		Columns:    checked.resp.Columns,
+		CreatedAt:  saved.CreatedAt.Format(time.DateTime),
+	}, nil
+}
+
+// columns 按输出列生成数据视图的列：直接引用源列时沿用其类型及注释，
+// 物理表的 * 展开为该表已采集的列；列名重复时返回参数错误
+func (l *CreateDataViewLogic) columns(checked *checkedSql, schema string) ([]*dataview.Column, error) {
+	// 引用的数据视图的列，按 schema.table 索引
+	byTable := make(map[string][]*dataview.Column, len(checked.views))
+	for id, v := range checked.views {
+		cols, err := l.svcCtx.DataViewModel.Columns(l.ctx, id)
+		if err != nil {
+			l.Errorf("查询数据视图的列失败: id=%d, err=%v", id, err)
+			return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+		}
+		byTable[strings.ToLower(v.FullName())] = cols
+	}
+	tableOf := func(ref sqlparse.ColumnRef) []*dataview.Column {
+		if ref.Schema == "" {
+			ref.Schema = schema
+		}
+		return byTable[strings.ToLower(ref.Schema+"."+ref.Table)]
+	}
+
+	var columns []*dataview.Column
+	seen := make(map[string]bool)
+	add := func(c *dataview.Column) error {
+		if seen[strings.ToLower(c.Name)] {
+			return errorx.NewWithMsg(errorx.ErrCodeParamInvalid, "输出列名重复: "+c.Name+"，请使用别名")
+		}
+		seen[strings.ToLower(c.Name)] = true
+		c.Position = len(columns) + 1
+		columns = append(columns, c)
+		return nil
+	}
+
+	for _, out := range checked.result.Columns {
+		if out.Name == "*" && out.Direct && len(out.Sources) == 1 {
+			for _, c := range tableOf(out.Sources[0]) {
+				if err := add(copyColumn(c, c.Name)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		c := &dataview.Column{Name: out.Name, Nullable: true}
+		if out.Direct && len(out.Sources) == 1 {
+			for _, src := range tableOf(out.Sources[0]) {
+				if strings.EqualFold(src.Name, out.Sources[0].Column) {
+					c = copyColumn(src, out.Name)
+					break
+				}
+			}
+		}
+		if err := add(c); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// copyColumn 沿用源列的类型及注释（主键属性不继承）
+func copyColumn(src *dataview.Column, name string) *dataview.Column {
+	return &dataview.Column{
+		Name:       name,
+		DataType:   src.DataType,
+		ColumnType: src.ColumnType,
+		Nullable:   src.Nullable,
+		Comment:    src.Comment,
+	}
+}
+
+// fingerprint 与采集结果相同算法的结构指纹
+func fingerprint(view *dataview.DataView, columns []*dataview.Column) string {
+	t := &harvest.Table{Schema: view.SchemaName, Name: view.Table, Type: view.TableType, Comment: view.Comment,
+		Columns: make([]harvest.Column, len(columns)), Definition: view.Definition}
+	for i, c := range columns {
+		t.Columns[i] = harvest.Column{Name: c.Name, Position: c.Position, DataType: c.DataType, ColumnType: c.ColumnType,
+			Nullable: c.Nullable, Comment: c.Comment}
+	}
+	return t.Fingerprint()
+}
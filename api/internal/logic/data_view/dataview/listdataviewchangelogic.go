@@ -0,0 +1,62 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataview
+
+import (
+	"context"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListDataViewChangeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 数据视图结构变化列表
+func NewListDataViewChangeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListDataViewChangeLogic {
+	return &ListDataViewChangeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListDataViewChangeLogic) ListDataViewChange(req *types.ListDataViewChangeReq) (resp *types.ListDataViewChangeResp, err error) {
+	list, total, err := l.svcCtx.DataViewModel.Changes(l.ctx, dataview.ChangeQuery{
+		ViewId:       req.ViewId,
+		Source:       req.Source,
+		BreakingOnly: req.BreakingOnly,
+		Page:         req.Page,
+		PageSize:     req.PageSize,
+	})
+	if err != nil {
+		l.Errorf("查询数据视图结构变化失败: %v", err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	resp = &types.ListDataViewChangeResp{List: make([]types.DataViewChangeResp, 0, len(list)), Total: total}
+	for _, c := range list {
+		resp.List = append(resp.List, types.DataViewChangeResp{
+			Id:         c.Id,
+			ViewId:     c.ViewId,
+			Source:     c.Source,
+			TableName:  c.Table,
+			ChangeType: c.ChangeType,
+			ColumnName: c.ColumnName,
+			OldValue:   c.OldValue,
+			NewValue:   c.NewValue,
+			Breaking:   c.Breaking,
+			DetectedAt: c.DetectedAt.Format(time.DateTime),
+		})
+	}
+	return resp, nil
+}
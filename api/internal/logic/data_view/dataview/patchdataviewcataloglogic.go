@@ -0,0 +1,74 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataview
+
+import (
+	"context"
+	"errors"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PatchDataViewCatalogLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 编目数据视图
+func NewPatchDataViewCatalogLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PatchDataViewCatalogLogic {
+	return &PatchDataViewCatalogLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PatchDataViewCatalogLogic) PatchDataViewCatalog(req *types.PatchDataViewCatalogReq) (resp *types.DataViewCatalogResp, err error) {
+	view, err := l.svcCtx.DataViewModel.FindOne(l.ctx, req.Id)
+	if errors.Is(err, dataview.ErrNotFound) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeNotFound, "数据视图不存在")
+	}
+	if err != nil {
+		l.Errorf("查询数据视图失败: id=%d, err=%v", req.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	// 只更新请求中出现的字段（指针非 nil），零值同样写入（取消编目、定级）
+	if req.CategoryId != nil {
+		if *req.CategoryId > 0 {
+			if _, err := l.svcCtx.CategoryModel.FindOne(l.ctx, *req.CategoryId); err != nil {
+				if errors.Is(err, category.ErrNotFound) {
+					return nil, errorx.NewWithMsg(errorx.ErrCodeParamInvalid, "类别不存在")
+				}
+				l.Errorf("查询类别失败: id=%d, err=%v", *req.CategoryId, err)
+				return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+			}
+		}
+		view.CategoryId = *req.CategoryId
+	}
+	if req.Sensitivity != nil {
+		view.Sensitivity = *req.Sensitivity
+	}
+	if req.Department != nil {
+		view.Department = *req.Department
+	}
+
+	if err := l.svcCtx.DataViewModel.Save(l.ctx, view, nil, nil); err != nil {
+		l.Errorf("更新数据视图编目失败: id=%d, err=%v", req.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	return &types.DataViewCatalogResp{
+		Id:          view.Id,
+		CategoryId:  view.CategoryId,
+		Sensitivity: view.Sensitivity,
+		Department:  view.Department,
+	}, nil
+}
@@ -0,0 +1,140 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package dataview
+
+import (
+	"context"
+	"errors"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/errorx"
+	"idrm/pkg/kms"
+	"idrm/pkg/preview"
+	"idrm/pkg/sqlparse"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PreviewDataViewLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 预览数据视图
+func NewPreviewDataViewLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PreviewDataViewLogic {
+	return &PreviewDataViewLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PreviewDataViewLogic) PreviewDataView(req *types.PreviewDataViewReq) (resp *types.PreviewDataViewResp, err error) {
+	view, err := l.svcCtx.DataViewModel.FindOne(l.ctx, req.Id)
+	if errors.Is(err, dataview.ErrNotFound) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeNotFound, "数据视图不存在")
+	}
+	if err != nil {
+		l.Errorf("查询数据视图失败: id=%d, err=%v", req.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	if view.Status != dataview.StatusActive {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "数据视图已在源中删除，无法预览")
+	}
+	// 按采集时记录的已注册数据源连接源库，只查询采集到的列；SQL 定义的数据视图执行其定义
+	if view.Datasource == "" {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "数据视图的采集源未引用已注册数据源，无法预览")
+	}
+
+	columns, err := l.svcCtx.DataViewModel.Columns(l.ctx, view.Id)
+	if err != nil {
+		l.Errorf("查询数据视图的列失败: id=%d, err=%v", view.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+
+	d, err := l.svcCtx.DatasourceModel.FindByName(l.ctx, view.Datasource)
+	if errors.Is(err, datasource.ErrNotFound) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeNotFound, "数据源 "+view.Datasource+" 未注册")
+	}
+	if err != nil {
+		l.Errorf("查询数据源失败: name=%s, err=%v", view.Datasource, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	// 执行定义前须通过 preview.CheckSelect，只有 MySQL 有对应的解析器
+	if view.TableType == dataview.TableTypeSQL && d.Type != datasource.TypeMySQL {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "SQL 定义的数据视图只支持 MySQL 数据源，不能预览")
+	}
+	conn, err := datasource.Connect(l.ctx, l.svcCtx.KMS, d)
+	if err != nil {
+		if errors.Is(err, kms.ErrNotConfigured) || errors.Is(err, kms.ErrKeyNotFound) {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "无法解密数据源密码，请检查密钥文件（KMS.KeyFile）")
+		}
+		l.Errorf("连接数据源失败: name=%s, err=%v", d.Name, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeExternal)
+	}
+	defer conn.Close()
+
+	// 按定义解析出的列来源脱敏：别名或表达式引用敏感列时同样脱敏
+	parsed, err := view.ParsedLineage()
+	if err != nil {
+		l.Errorf("解析数据视图血缘失败: id=%d, err=%v", view.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeSystem)
+	}
+	sources := columnSources(parsed)
+
+	var result *preview.Result
+	if view.TableType == dataview.TableTypeSQL {
+		// SQL 定义的数据视图执行其定义（保存时已校验为单条查询）；无法确定列来源时不预览
+		if parsed == nil {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "数据视图定义未能解析，无法确定列来源，不能预览")
+		}
+		result, err = l.svcCtx.Previewer.Query(l.ctx, conn, view.Definition, sources, req.Limit)
+	} else {
+		result, err = l.svcCtx.Previewer.Table(l.ctx, conn, view.SchemaName, view.Table, names, sources, req.Limit)
+	}
+	if err != nil {
+		if errors.Is(err, preview.ErrTimeout) {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "预览查询超时")
+		}
+		if errors.Is(err, preview.ErrNotSelect) {
+			l.Infof("数据视图定义未通过只读查询检查: id=%d, err=%v", view.Id, err)
+			return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "数据视图定义不是允许预览的只读查询（函数须在白名单内，不能含注释及反斜杠）")
+		}
+		// 错误信息可能包含源库连接信息，只记录日志
+		l.Errorf("预览数据视图失败: id=%d, table=%s, err=%v", view.Id, view.FullName(), err)
+		return nil, errorx.NewWithMsg(errorx.ErrCodeExternal, "查询源数据失败")
+	}
+
+	return &types.PreviewDataViewResp{
+		Columns:   result.Columns,
+		Rows:      result.Rows,
+		Masked:    result.Masked,
+		HasMore:   result.HasMore,
+		ElapsedMs: result.Elapsed.Milliseconds(),
+	}, nil
+}
+
+// columnSources 输出列名 → 引用的源列名，未解析定义时为 nil
+func columnSources(parsed *sqlparse.Result) preview.Sources {
+	if parsed == nil {
+		return nil
+	}
+	sources := make(preview.Sources, len(parsed.Columns))
+	for _, c := range parsed.Columns {
+		for _, src := range c.Sources {
+			if src.Column != "*" {
+				sources[c.Name] = append(sources[c.Name], src.Column)
+			}
+		}
+	}
+	return sources
+}
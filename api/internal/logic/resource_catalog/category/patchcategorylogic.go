@@ -0,0 +1,107 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package category
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/category"
+	"idrm/pkg/errorx"
+	"idrm/pkg/lock"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// patchLockWait 等待同一类别其他修改完成的最长时间
+const patchLockWait = 3 * time.Second
+
+type PatchCategoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 部分更新类别
+func NewPatchCategoryLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PatchCategoryLogic {
+	return &PatchCategoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PatchCategoryLogic) PatchCategory(req *types.PatchCategoryReq) (resp *types.CategoryResp, err error) {
+	// 同一类别的修改串行执行（多实例间同样互斥），响应为本次修改后的数据
+	ttl := time.Duration(l.svcCtx.Config.Lock.TTL) * time.Second
+	err = lock.Do(l.ctx, l.svcCtx.Locker, "category:"+strconv.FormatInt(req.Id, 10), ttl, patchLockWait,
+		func(ctx context.Context) error {
+			resp, err = l.patch(ctx, req)
+			return err
+		})
+	if errors.Is(err, lock.ErrNotAcquired) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "类别正在被修改，请稍后重试")
+	}
+	if errors.Is(err, lock.ErrLost) {
+		l.Errorf("类别修改期间锁失效: id=%d", req.Id)
+		return nil, errorx.NewWithCode(errorx.ErrCodeSystem)
+	}
+	return resp, err
+}
+
+func (l *PatchCategoryLogic) patch(ctx context.Context, req *types.PatchCategoryReq) (*types.CategoryResp, error) {
+	if _, err := l.svcCtx.CategoryModel.FindOne(ctx, req.Id); err != nil {
+		if errors.Is(err, category.ErrNotFound) {
+			return nil, errorx.NewWithMsg(404, "类别不存在")
+		}
+		l.Errorf("查询类别失败: id=%d, err=%v", req.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	// 只更新请求中出现的字段（指针非 nil），零值同样写入
+	fields := make(map[string]interface{})
+	if req.Name != nil {
+		fields[category.FieldName] = *req.Name
+	}
+	if req.Code != nil {
+		fields[category.FieldCode] = *req.Code
+	}
+	if req.Sort != nil {
+		fields[category.FieldSort] = *req.Sort
+	}
+	if req.Description != nil {
+		fields[category.FieldDescription] = *req.Description
+	}
+	if req.Status != nil {
+		fields[category.FieldStatus] = *req.Status
+	}
+
+	if err := l.svcCtx.CategoryModel.UpdateFields(ctx, req.Id, fields); err != nil {
+		if errors.Is(err, category.ErrCodeAlreadyExists) {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeAlreadyExists, "类别编码已存在")
+		}
+		l.Errorf("更新类别失败: id=%d, err=%v", req.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	updated, err := l.svcCtx.CategoryModel.FindOne(ctx, req.Id)
+	if err != nil {
+		return nil, errorx.NewWithMsg(404, "类别不存在")
+	}
+
+	return &types.CategoryResp{
+		Id:          updated.Id,
+		Name:        updated.Name,
+		Code:        updated.Code,
+		ParentId:    updated.ParentId,
+		Level:       updated.Level,
+		Sort:        updated.Sort,
+		Description: updated.Description,
+		Status:      updated.Status,
+	}, nil
+}
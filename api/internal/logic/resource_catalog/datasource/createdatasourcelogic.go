@@ -0,0 +1,58 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package datasource
+
+import (
+	"context"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateDatasourceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建数据源
+func NewCreateDatasourceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateDatasourceLogic {
+	return &CreateDatasourceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateDatasourceLogic) CreateDatasource(req *types.CreateDatasourceReq) (resp *types.DatasourceResp, err error) {
+	options := datasource.FormatOptions(req.Options)
+	if _, err := datasource.ParseOptions(options); err != nil {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeParamInvalid, "连接参数错误: "+err.Error())
+	}
+
+	d := &datasource.Datasource{
+		Name:         req.Name,
+		Type:         req.Type,
+		Host:         req.Host,
+		Port:         req.Port,
+		DatabaseName: req.DatabaseName,
+		Username:     req.Username,
+		Options:      options,
+		Owner:        req.Owner,
+		Description:  req.Description,
+	}
+	if err := datasource.SetPassword(l.ctx, l.svcCtx.KMS, d, req.Password); err != nil {
+		return nil, saveError(l.Logger, err)
+	}
+	if err := l.svcCtx.DatasourceModel.Insert(l.ctx, d); err != nil {
+		return nil, saveError(l.Logger, err)
+	}
+
+	// 重新查询以返回数据库生成的时间
+	return NewGetDatasourceLogic(l.ctx, l.svcCtx).GetDatasource(&types.DatasourceReq{Id: d.Id})
+}
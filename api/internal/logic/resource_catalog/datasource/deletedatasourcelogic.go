@@ -0,0 +1,40 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package datasource
+
+import (
+	"context"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteDatasourceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除数据源
+func NewDeleteDatasourceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteDatasourceLogic {
+	return &DeleteDatasourceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteDatasourceLogic) DeleteDatasource(req *types.DatasourceReq) error {
+	if _, err := findDatasource(l.ctx, l.Logger, l.svcCtx, req.Id); err != nil {
+		return err
+	}
+	if err := l.svcCtx.DatasourceModel.Delete(l.ctx, req.Id); err != nil {
+		l.Errorf("删除数据源失败: id=%d, err=%v", req.Id, err)
+		return errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	return nil
+}
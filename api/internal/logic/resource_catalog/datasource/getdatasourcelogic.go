@@ -0,0 +1,96 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package datasource
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/pkg/errorx"
+	"idrm/pkg/kms"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// redactedPassword 已设置密码时响应中的占位
+const redactedPassword = "******"
+
+type GetDatasourceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取数据源详情
+func NewGetDatasourceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetDatasourceLogic {
+	return &GetDatasourceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetDatasourceLogic) GetDatasource(req *types.DatasourceReq) (resp *types.DatasourceResp, err error) {
+	d, err := findDatasource(l.ctx, l.Logger, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toDatasourceResp(d), nil
+}
+
+// findDatasource 查询数据源，不存在或查询失败时返回业务错误
+func findDatasource(ctx context.Context, logger logx.Logger, svcCtx *svc.ServiceContext, id int64) (*datasource.Datasource, error) {
+	d, err := svcCtx.DatasourceModel.FindOne(ctx, id)
+	if errors.Is(err, datasource.ErrNotFound) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeNotFound, "数据源不存在")
+	}
+	if err != nil {
+		logger.Errorf("查询数据源失败: id=%d, err=%v", id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	return d, nil
+}
+
+// toDatasourceResp 转换为响应（不返回密码）
+func toDatasourceResp(d *datasource.Datasource) *types.DatasourceResp {
+	resp := &types.DatasourceResp{
+		Id:           d.Id,
+		Name:         d.Name,
+		Type:         d.Type,
+		Host:         d.Host,
+		Port:         d.Port,
+		DatabaseName: d.DatabaseName,
+		Username:     d.Username,
+		Owner:        d.Owner,
+		Description:  d.Description,
+		CreatedAt:    d.CreatedAt.Format(time.DateTime),
+		UpdatedAt:    d.UpdatedAt.Format(time.DateTime),
+	}
+	if d.Password != "" {
+		resp.Password = redactedPassword
+	}
+	// 已保存的参数均经过校验，解析失败时返回空参数
+	resp.Options, _ = datasource.ParseOptions(d.Options)
+	if resp.Options == nil {
+		resp.Options = map[string]string{}
+	}
+	return resp
+}
+
+// saveError 保存数据源的错误转换为业务错误
+func saveError(logger logx.Logger, err error) error {
+	switch {
+	case errors.Is(err, datasource.ErrDuplicateName):
+		return errorx.NewWithMsg(errorx.ErrCodeAlreadyExists, "数据源名称已存在")
+	case errors.Is(err, kms.ErrNotConfigured):
+		return errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "未配置密钥文件（KMS.KeyFile），无法保存密码")
+	default:
+		logger.Errorf("保存数据源失败: %v", err)
+		return errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+}
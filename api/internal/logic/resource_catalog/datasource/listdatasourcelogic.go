@@ -0,0 +1,43 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package datasource
+
+import (
+	"context"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListDatasourceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 数据源列表
+func NewListDatasourceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListDatasourceLogic {
+	return &ListDatasourceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListDatasourceLogic) ListDatasource(req *types.ListDatasourceReq) (resp *types.ListDatasourceResp, err error) {
+	list, total, err := l.svcCtx.DatasourceModel.List(l.ctx, req.Page, req.PageSize)
+	if err != nil {
+		l.Errorf("查询数据源列表失败: %v", err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	resp = &types.ListDatasourceResp{List: make([]types.DatasourceResp, 0, len(list)), Total: total}
+	for _, d := range list {
+		resp.List = append(resp.List, *toDatasourceResp(d))
+	}
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package datasource
+
+import (
+	"context"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PatchDatasourceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 部分更新数据源
+func NewPatchDatasourceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *PatchDatasourceLogic {
+	return &PatchDatasourceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *PatchDatasourceLogic) PatchDatasource(req *types.PatchDatasourceReq) (resp *types.DatasourceResp, err error) {
+	d, err := findDatasource(l.ctx, l.Logger, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite 的库名为服务器上的文件路径，不能通过接口修改
+	if d.Type == datasource.TypeSQLite && req.DatabaseName != nil {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeParamInvalid, "SQLite 数据源不能通过接口修改库文件路径")
+	}
+
+	// 只修改请求中出现的字段（指针非 nil），零值同样写入
+	if req.Name != nil {
+		d.Name = *req.Name
+	}
+	if req.Type != nil {
+		d.Type = *req.Type
+	}
+	if req.Host != nil {
+		d.Host = *req.Host
+	}
+	if req.Port != nil {
+		d.Port = *req.Port
+	}
+	if req.DatabaseName != nil {
+		d.DatabaseName = *req.DatabaseName
+	}
+	if req.Username != nil {
+		d.Username = *req.Username
+	}
+	if req.Options != nil {
+		options := datasource.FormatOptions(req.Options)
+		if _, err := datasource.ParseOptions(options); err != nil {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeParamInvalid, "连接参数错误: "+err.Error())
+		}
+		d.Options = options
+	}
+	if req.Owner != nil {
+		d.Owner = *req.Owner
+	}
+	if req.Description != nil {
+		d.Description = *req.Description
+	}
+	if req.Password != nil {
+		if err := datasource.SetPassword(l.ctx, l.svcCtx.KMS, d, *req.Password); err != nil {
+			return nil, saveError(l.Logger, err)
+		}
+	}
+
+	if err := l.svcCtx.DatasourceModel.Update(l.ctx, d); err != nil {
+		return nil, saveError(l.Logger, err)
+	}
+	return NewGetDatasourceLogic(l.ctx, l.svcCtx).GetDatasource(&types.DatasourceReq{Id: d.Id})
+}
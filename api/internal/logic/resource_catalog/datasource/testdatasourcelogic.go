@@ -0,0 +1,87 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package datasource
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/pkg/db"
+	"idrm/pkg/errorx"
+	"idrm/pkg/kms"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type TestDatasourceLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 测试数据源连通性
+func NewTestDatasourceLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TestDatasourceLogic {
+	return &TestDatasourceLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *TestDatasourceLogic) TestDatasource(req *types.TestDatasourceReq) (resp *types.TestDatasourceResp, err error) {
+	d, err := findDatasource(l.ctx, l.Logger, l.svcCtx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	password, err := datasource.Password(l.ctx, l.svcCtx.KMS, d)
+	if err != nil {
+		if errors.Is(err, kms.ErrNotConfigured) || errors.Is(err, kms.ErrKeyNotFound) {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeOperationFailed, "无法解密数据源密码，请检查密钥文件（KMS.KeyFile）")
+		}
+		l.Errorf("解密数据源密码失败: id=%d, err=%v", req.Id, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeSystem)
+	}
+	c, err := d.DBConfig(password)
+	if err != nil {
+		return &types.TestDatasourceResp{Message: err.Error()}, nil
+	}
+
+	// 连接失败属于检查结果，不作为错误返回
+	ctx, cancel := context.WithTimeout(l.ctx, time.Duration(req.Timeout)*time.Second)
+	defer cancel()
+	start := time.Now()
+	err = ping(ctx, c)
+	resp = &types.TestDatasourceResp{Success: err == nil, LatencyMs: time.Since(start).Milliseconds(), Message: "ok"}
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		resp.Message = redact(err.Error(), password)
+		l.Infof("数据源连通性检查失败: id=%d, err=%s", req.Id, resp.Message)
+	}
+	return resp, nil
+}
+
+// ping 建立连接并检查连通性，结束后关闭连接
+func ping(ctx context.Context, c db.Config) error {
+	conn, err := db.NewConn(c)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Ping(ctx)
+}
+
+// redact 去除错误信息中的密码（部分驱动的错误信息包含 DSN）
+func redact(msg, password string) string {
+	if password == "" {
+		return msg
+	}
+	return strings.ReplaceAll(msg, password, redactedPassword)
+}
@@ -0,0 +1,77 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package lineage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateLineageEdgeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 登记血缘边
+func NewCreateLineageEdgeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateLineageEdgeLogic {
+	return &CreateLineageEdgeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// edgeEnds 各类边允许的起点、终点节点类型
+var edgeEnds = map[string][2][]string{
+	lineage.EdgeFeeds:     {{lineage.NodeTable, lineage.NodeDataView}, {lineage.NodeTable, lineage.NodeDataView}},
+	lineage.EdgeDerives:   {{lineage.NodeColumn}, {lineage.NodeColumn}},
+	lineage.EdgePublishes: {{lineage.NodeTable, lineage.NodeDataView}, {lineage.NodeResource}},
+	lineage.EdgeServes:    {{lineage.NodeResource}, {lineage.NodeAPI}},
+}
+
+func (l *CreateLineageEdgeLogic) CreateLineageEdge(req *types.CreateLineageEdgeReq) (resp *types.LineageEdgeResp, err error) {
+	if req.SourceId == req.TargetId {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeParamInvalid, "起点与终点不能相同")
+	}
+	source, err := findNode(l.ctx, l.Logger, l.svcCtx, req.SourceId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := findNode(l.ctx, l.Logger, l.svcCtx, req.TargetId)
+	if err != nil {
+		return nil, err
+	}
+	ends := edgeEnds[req.Type]
+	if !slices.Contains(ends[0], source.Type) || !slices.Contains(ends[1], target.Type) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeParamInvalid, req.Type+" 边不能从 "+source.Type+" 指向 "+target.Type)
+	}
+
+	e := &lineage.Edge{SourceId: source.Id, TargetId: target.Id, Type: req.Type, Origin: lineage.OriginManual}
+	if err := l.svcCtx.LineageModel.InsertEdge(l.ctx, e); err != nil {
+		if errors.Is(err, lineage.ErrDuplicateEdge) {
+			return nil, errorx.NewWithMsg(errorx.ErrCodeAlreadyExists, "血缘边已存在")
+		}
+		l.Errorf("登记血缘边失败: %d -> %d, err=%v", e.SourceId, e.TargetId, err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	return &types.LineageEdgeResp{
+		Id:        e.Id,
+		SourceId:  e.SourceId,
+		TargetId:  e.TargetId,
+		Type:      e.Type,
+		Origin:    e.Origin,
+		Expr:      e.Expr,
+		CreatedAt: e.CreatedAt.Format(time.DateTime),
+	}, nil
+}
@@ -0,0 +1,37 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package lineage
+
+import (
+	"context"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/lineage"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetLineageDownstreamLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 下游血缘
+func NewGetLineageDownstreamLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetLineageDownstreamLogic {
+	return &GetLineageDownstreamLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetLineageDownstreamLogic) GetLineageDownstream(req *types.LineageGraphReq) (resp *types.LineageGraphResp, err error) {
+	g, err := traverse(l.ctx, l.Logger, l.svcCtx, req, lineage.Downstream)
+	if err != nil {
+		return nil, err
+	}
+	return &types.LineageGraphResp{Graph: toGraph(g)}, nil
+}
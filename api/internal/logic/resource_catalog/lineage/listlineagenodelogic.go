@@ -0,0 +1,68 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package lineage
+
+import (
+	"context"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListLineageNodeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 血缘节点列表
+func NewListLineageNodeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListLineageNodeLogic {
+	return &ListLineageNodeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListLineageNodeLogic) ListLineageNode(req *types.ListLineageNodeReq) (resp *types.ListLineageNodeResp, err error) {
+	list, total, err := l.svcCtx.LineageModel.ListNodes(l.ctx, lineage.NodeQuery{
+		Type:      req.Type,
+		Namespace: req.Namespace,
+		Keyword:   req.Keyword,
+		Page:      req.Page,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		l.Errorf("查询血缘节点失败: %v", err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	resp = &types.ListLineageNodeResp{List: make([]types.LineageNodeResp, 0, len(list)), Total: total}
+	for _, n := range list {
+		resp.List = append(resp.List, *toLineageNodeResp(n))
+	}
+	return resp, nil
+}
+
+// toLineageNodeResp 转换为响应
+func toLineageNodeResp(n *lineage.Node) *types.LineageNodeResp {
+	return &types.LineageNodeResp{
+		Id:          n.Id,
+		Key:         n.NodeKey,
+		Type:        n.Type,
+		Name:        n.Name,
+		Namespace:   n.Namespace,
+		ParentId:    n.ParentId,
+		RefId:       n.RefId,
+		Status:      n.Status,
+		Description: n.Description,
+		CreatedAt:   n.CreatedAt.Format(time.DateTime),
+		UpdatedAt:   n.UpdatedAt.Format(time.DateTime),
+	}
+}
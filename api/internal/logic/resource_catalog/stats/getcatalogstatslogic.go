@@ -0,0 +1,92 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package stats
+
+import (
+	"context"
+	"errors"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/stats"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetCatalogStatsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 最近一次统计快照
+func NewGetCatalogStatsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetCatalogStatsLogic {
+	return &GetCatalogStatsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetCatalogStatsLogic) GetCatalogStats() (resp *types.CatalogStatsResp, err error) {
+	snapshot, err := l.svcCtx.StatsModel.Latest(l.ctx)
+	if errors.Is(err, stats.ErrNotFound) {
+		return nil, errorx.NewWithMsg(errorx.ErrCodeNotFound, "暂无统计数据，请先执行统计任务")
+	}
+	if err != nil {
+		l.Errorf("查询统计快照失败: %v", err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+
+	// 各维度按快照中的顺序（key 升序）返回，维度无数据时为空数组
+	resp = &types.CatalogStatsResp{
+		ByStatus:              []types.CatalogStatItem{},
+		ByLevel:               []types.CatalogStatItem{},
+		BySubtree:             []types.CatalogStatItem{},
+		ResourceByStatus:      []types.CatalogStatItem{},
+		ResourceBySubtree:     []types.CatalogStatItem{},
+		ResourceBySensitivity: []types.CatalogStatItem{},
+		ResourceByDepartment:  []types.CatalogStatItem{},
+	}
+	for _, s := range snapshot {
+		resp.Date = s.StatDate
+		item := types.CatalogStatItem{Key: s.DimKey, Label: s.DimLabel, Value: s.Value}
+		switch s.Dimension {
+		case stats.DimTotal:
+			resp.Total = s.Value
+		case stats.DimStatus:
+			resp.ByStatus = append(resp.ByStatus, item)
+		case stats.DimLevel:
+			resp.ByLevel = append(resp.ByLevel, item)
+		case stats.DimSubtree:
+			resp.BySubtree = append(resp.BySubtree, item)
+		case stats.DimCreated:
+			switch s.DimKey {
+			case stats.KeyCreated7d:
+				resp.Created7d = s.Value
+			case stats.KeyCreated30d:
+				resp.Created30d = s.Value
+			}
+		case stats.DimResourceTotal:
+			resp.ResourceTotal = s.Value
+		case stats.DimResourceStatus:
+			resp.ResourceByStatus = append(resp.ResourceByStatus, item)
+		case stats.DimResourceSubtree:
+			resp.ResourceBySubtree = append(resp.ResourceBySubtree, item)
+		case stats.DimResourceSensitivity:
+			resp.ResourceBySensitivity = append(resp.ResourceBySensitivity, item)
+		case stats.DimResourceDepartment:
+			resp.ResourceByDepartment = append(resp.ResourceByDepartment, item)
+		case stats.DimResourceCreated:
+			switch s.DimKey {
+			case stats.KeyCreated7d:
+				resp.ResourceCreated7d = s.Value
+			case stats.KeyCreated30d:
+				resp.ResourceCreated30d = s.Value
+			}
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package stats
+
+import (
+	"context"
+	"time"
+
+	"idrm/api/internal/svc"
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/stats"
+	"idrm/pkg/errorx"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetCatalogStatsTrendLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 统计趋势
+func NewGetCatalogStatsTrendLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetCatalogStatsTrendLogic {
+	return &GetCatalogStatsTrendLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetCatalogStatsTrendLogic) GetCatalogStatsTrend(req *types.CatalogStatsTrendReq) (resp *types.CatalogStatsTrendResp, err error) {
+	// 最近 Days 天（含今天）的快照，未执行统计的日期不返回
+	from := time.Now().AddDate(0, 0, 1-req.Days).Format(stats.DateLayout)
+	totals, err := l.svcCtx.StatsModel.Series(l.ctx, stats.DimTotal, "", from)
+	if err != nil {
+		l.Errorf("查询统计趋势失败: %v", err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	created7d, err := l.series(stats.DimCreated, stats.KeyCreated7d, from)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := l.series(stats.DimResourceTotal, "", from)
+	if err != nil {
+		return nil, err
+	}
+	resourceCreated7d, err := l.series(stats.DimResourceCreated, stats.KeyCreated7d, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// 增量为与上一个快照的差值，第一个快照为 0
+	resp = &types.CatalogStatsTrendResp{List: make([]types.CatalogStatsTrendPoint, 0, len(totals))}
+	for i, s := range totals {
+		point := types.CatalogStatsTrendPoint{
+			Date:              s.StatDate,
+			Total:             s.Value,
+			Created7d:         created7d[s.StatDate],
+			ResourceTotal:     resources[s.StatDate],
+			ResourceCreated7d: resourceCreated7d[s.StatDate],
+		}
+		if i > 0 {
+			prev := totals[i-1].StatDate
+			point.Growth = s.Value - totals[i-1].Value
+			point.ResourceGrowth = point.ResourceTotal - resources[prev]
+		}
+		resp.List = append(resp.List, point)
+	}
+	return resp, nil
+}
+
+// series 某维度取值的快照值，按统计日期索引
+func (l *GetCatalogStatsTrendLogic) series(dimension, key, from string) (map[string]int64, error) {
+	list, err := l.svcCtx.StatsModel.Series(l.ctx, dimension, key, from)
+	if err != nil {
+		l.Errorf("查询统计趋势失败: %v", err)
+		return nil, errorx.NewWithCode(errorx.ErrCodeDatabase)
+	}
+	byDate := make(map[string]int64, len(list))
+	for _, s := range list {
+		byDate[s.StatDate] = s.Value
+	}
+	return byDate, nil
+}
@@ -1,75 +1,245 @@
 package svc
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"idrm/api/internal/config"
+	"idrm/migrations"
 	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/model/resource_catalog/stats"
+	"idrm/pkg/cache"
 	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+	"idrm/pkg/db/migrate"
+	"idrm/pkg/db/schemacheck"
+	"idrm/pkg/kms"
+	"idrm/pkg/lock"
+	"idrm/pkg/notify"
+	"idrm/pkg/outbox"
+	"idrm/pkg/preview"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/zeromicro/go-zero/core/logx"
-	"github.com/zeromicro/go-zero/core/stores/sqlx"
-	"gorm.io/gorm"
 )
 
 type ServiceContext struct {
 	Config config.Config
 
-	// Model层（使用接口类型，支持自动ORM选择）
-	CategoryModel category.Model
+	// 数据源管理器（资源目录、数据视图、数据理解）
+	DB *db.Manager
+
+	// Model层（使用接口类型，按配置选择ORM）
+	CategoryModel   category.Model
+	StatsModel      stats.Model      // 统计快照（由 job 服务的 statistics 任务写入）
+	DatasourceModel datasource.Model // 外部数据源（密码为 KMS 加密的密文）
+	DataViewModel   dataview.Model   // 数据视图及结构变化记录（由 job 服务的 sync_data 任务写入）
+	LineageModel    lineage.Model    // 血缘图（采集及 SQL 定义数据视图时写入，目录资源及 API 手工登记）
+
+	// 进程内事件总线（Outbox.Publisher 为 bus 时接收类别变更及数据视图结构变化事件）
+	EventBus *outbox.Bus
+
+	// 分布式锁（按 Lock.Type 创建，默认进程内锁）
+	Locker lock.Locker
+
+	// 密钥管理（加解密数据源密码）
+	KMS kms.KeyManager
+
+	// 数据预览（只读受限查询及脱敏）
+	Previewer *preview.Previewer
+
+	relay *outbox.Relay
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
-	// 1. 初始化 sqlx 连接（作为备用）
-	var sqlConn *sql.DB
-	var sqlxErr error
-	dsn := buildDSN(c.DB.ResourceCatalog)
-	logx.Infof("尝试连接数据库(SQLx): %s:%d/%s", c.DB.ResourceCatalog.Host, c.DB.ResourceCatalog.Port, c.DB.ResourceCatalog.Database)
-	conn := sqlx.NewMysql(dsn)
-	sqlConn, sqlxErr = conn.RawDB()
-	if sqlxErr != nil {
-		logx.Errorf("SQLx RawDB获取失败: %v, DSN: %s", sqlxErr, dsn)
-		sqlConn = nil
-	} else {
-		logx.Info("SQLx 连接成功")
-	}
-
-	// 2. 初始化 gorm 连接（优先）
-	var gormDB *gorm.DB
-	var gormErr error
-	logx.Infof("尝试连接数据库(GORM): %s:%d/%s", c.DB.ResourceCatalog.Host, c.DB.ResourceCatalog.Port, c.DB.ResourceCatalog.Database)
-	gormDB, gormErr = db.InitGorm(c.DB.ResourceCatalog)
-	if gormErr != nil {
-		logx.Errorf("GORM初始化失败: %v", gormErr)
-		gormDB = nil
-	} else {
-		logx.Info("GORM 连接成功")
-	}
-
-	// 如果两个都失败，提前panic
-	if sqlConn == nil && gormDB == nil {
-		panic(fmt.Sprintf("数据库连接失败！SQLx错误: %v, GORM错误: %v", sqlxErr, gormErr))
-	}
-
-	// 3. 使用工厂自动选择ORM（gorm优先，sqlx降级）
-	categoryModel := category.NewModel(sqlConn, gormDB)
-
-	return &ServiceContext{
+	// 1. 创建数据源管理器（不建立网络连接，数据库不可达时服务仍可启动）
+	manager, err := db.NewManager(c.Datasources())
+	if err != nil {
+		panic(fmt.Sprintf("数据源配置错误: %v", err))
+	}
+	conn, err := manager.Conn(migrations.ResourceCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("数据源配置错误: %v", err))
+	}
+	logx.Infof("数据源: %s %s (ORM: %s)", c.DB.ResourceCatalog.Dialect(), c.DB.ResourceCatalog.Addr(),
+		c.DB.ResourceCatalog.ORMFor(category.ModelName))
+
+	// 启动时自动执行数据库迁移（可选，等待数据库可用后执行）
+	if c.DB.AutoMigrate {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.DB.ConnectTimeout)*time.Second)
+		err := manager.WaitReady(ctx, migrations.ResourceCatalog)
+		cancel()
+		if err != nil {
+			panic(fmt.Sprintf("数据库连接失败: %v", err))
+		}
+		if err := autoMigrate(conn); err != nil {
+			panic(fmt.Sprintf("数据库迁移失败: %v", err))
+		}
+	}
+
+	manager.StartHealthCheck(time.Duration(c.DB.HealthCheckInterval) * time.Second)
+
+	// 数据库可用后检查实体与表结构是否一致（仅告警，目前仅支持 MySQL）
+	if c.DB.SchemaCheck && conn.Dialect() == dialect.MySQL {
+		go func() {
+			if err := manager.WaitReady(context.Background(), migrations.ResourceCatalog); err != nil {
+				return
+			}
+			checkSchema(c.DB.ResourceCatalog, conn.DB)
+		}()
+	}
+
+	// 2. 按配置创建Model
+	categoryModel, err := category.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	locker, err := lock.New(c.Lock, conn)
+	if err != nil {
+		panic(fmt.Sprintf("分布式锁配置错误: %v", err))
+	}
+
+	// 写操作同时写入发件箱，缓存装饰器在外层（事务提交后失效）
+	// 多实例时只有持有投递租约的实例投递（Lock.Type 须为 db 或 redis）
+	bus := outbox.NewBus()
+	var relay *outbox.Relay
+	if c.Outbox.Enabled {
+		store := outbox.NewStore(conn)
+		categoryModel = category.NewEventModel(categoryModel, store)
+		if c.Outbox.Relay {
+			publisher, err := outbox.NewPublisher(c.Outbox, bus)
+			if err != nil {
+				panic(fmt.Sprintf("创建发件箱投递目标失败: %v", err))
+			}
+			relay = outbox.NewRelay(store, publisher, c.Outbox, outbox.WithLocker(locker, time.Duration(c.Lock.TTL)*time.Second))
+			relay.Start()
+		}
+	}
+	if c.Cache.Enabled {
+		categoryCache, err := cache.New(category.ModelName, c.Cache)
+		if err != nil {
+			panic(fmt.Sprintf("创建缓存失败: %v", err))
+		}
+		categoryModel = category.NewCachedModel(categoryModel, categoryCache)
+		logx.Infof("类别缓存已启用 (Redis: %t)", c.Cache.Redis.Host != "")
+	}
+
+	statsModel, err := stats.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+
+	datasourceModel, err := datasource.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+
+	dataViewModel, err := dataview.NewModel(conn, nil)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	lineageModel, err := lineage.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	// 数据视图结构变化通知负责人（job 服务写入发件箱，本服务 Relay 投递到进程内总线时生效）
+	notifier, err := notify.New(c.Notify)
+	if err != nil {
+		panic(fmt.Sprintf("通知配置错误: %v", err))
+	}
+	bus.Subscribe(dataview.AggregateType, func(ctx context.Context, msg *outbox.Message) error {
+		if msg.EventType != dataview.EventSchemaChanged {
+			return nil
+		}
+		return dataview.NotifySchemaChanged(ctx, notifier, []byte(msg.Payload))
+	})
+
+	km, err := kms.New(c.KMS)
+	if err != nil {
+		panic(fmt.Sprintf("密钥管理配置错误: %v", err))
+	}
+
+	svcCtx := NewServiceContextWithModels(c, categoryModel)
+	svcCtx.DB = manager
+	svcCtx.StatsModel = statsModel
+	svcCtx.DatasourceModel = datasourceModel
+	svcCtx.DataViewModel = dataViewModel
+	svcCtx.LineageModel = lineageModel
+	svcCtx.EventBus = bus
+	svcCtx.Locker = locker
+	svcCtx.KMS = km
+	svcCtx.relay = relay
+
+	return svcCtx
+}
+
+// NewServiceContextWithModels 使用已创建的Model构建ServiceContext（不创建数据源，测试中注入内存版或 SQLite 实现）
+func NewServiceContextWithModels(c config.Config, categoryModel category.Model) *ServiceContext {
+	previewer, err := preview.New(c.Preview)
+	if err != nil {
+		panic(fmt.Sprintf("数据预览配置错误: %v", err))
+	}
+	svcCtx := &ServiceContext{
 		Config:        c,
 		CategoryModel: categoryModel,
+		EventBus:      outbox.NewBus(),
+		Locker:        lock.NewLocal(),
+		KMS:           kms.Disabled,
+		Previewer:     previewer,
+	}
+
+	// 3. 注册依赖数据访问的请求验证规则
+	registerValidators(svcCtx)
+
+	return svcCtx
+}
+
+// Close 释放资源（停止发件箱投递，关闭所有数据源连接池）
+func (s *ServiceContext) Close() {
+	if s.relay != nil {
+		s.relay.Stop()
+	}
+	if s.DB == nil {
+		return
+	}
+	if err := s.DB.Close(); err != nil {
+		logx.Errorf("关闭数据源失败: %v", err)
+	}
+}
+
+// autoMigrate 对资源目录数据库执行未执行的迁移（Up 持有迁移锁，多个副本同时启动时依次执行）
+func autoMigrate(conn *db.Conn) error {
+	migrator, err := migrate.NewEmbedded(conn.DB, string(conn.Dialect()), migrations.ResourceCatalog)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
 	}
+	logx.Infof("数据库迁移完成: %s, 本次执行 %d 个迁移", migrations.ResourceCatalog, len(applied))
+	return nil
 }
 
-// buildDSN 构建 sqlx 的 DSN
-func buildDSN(cfg db.Config) string {
-	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		cfg.Username,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-		cfg.Charset,
-	)
+// checkSchema 检查资源目录实体与数据库表结构，不一致时输出告警日志
+func checkSchema(cfg db.Config, sqlConn *sql.DB) {
+	report, err := schemacheck.NewChecker(sqlConn, cfg.Namer()).Check(context.Background(), migrations.ResourceCatalog)
+	if err != nil {
+		logx.Errorf("表结构检查失败: %v", err)
+		return
+	}
+	for _, issue := range report.Issues {
+		if issue.Severity == schemacheck.SeverityError {
+			logx.Errorf("表结构不一致: %s", issue)
+		} else {
+			logx.Infof("表结构不一致: %s", issue)
+		}
+	}
+	if report.HasErrors() {
+		logx.Errorf("表结构检查发现 %d 个问题，可执行 go run ./cmd/schemacheck 查看详情", len(report.Issues))
+	}
 }
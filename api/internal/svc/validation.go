@@ -0,0 +1,77 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"idrm/api/internal/types"
+	"idrm/model/resource_catalog/category"
+	"idrm/pkg/validator"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 结构体级验证规则 tag
+const (
+	tagParentExists = "parent_exists"
+	tagParentLevel  = "parent_level"
+)
+
+// registerValidators 注册依赖数据访问的结构体级验证规则
+// 规则由 httpx.Parse 通过 validator.HttpxValidator 自动执行
+func registerValidators(svcCtx *ServiceContext) {
+	mustRegisterTranslation(tagParentExists, "{0}对应的父级类别不存在", "{0} must reference an existing parent category")
+	mustRegisterTranslation(tagParentLevel, "{0}必须等于父级类别层级+1（期望{1}）", "{0} must equal parent level + 1 (expected {1})")
+
+	// 资源目录及数据视图模块的创建类别请求使用同一规则
+	validator.RegisterStructValidation(svcCtx.validateCreateCategory, types.CreateCategoryReq{}, types.DataViewCreateCategoryReq{})
+}
+
+// validateCreateCategory 校验父级类别存在，且层级等于父级层级+1（顶级类别层级为1）
+func (s *ServiceContext) validateCreateCategory(ctx context.Context, sl validator.StructLevel) {
+	var (
+		parentId int64
+		level    int
+	)
+	switch req := sl.Current().Interface().(type) {
+	case types.CreateCategoryReq:
+		parentId, level = req.ParentId, req.Level
+	case types.DataViewCreateCategoryReq:
+		parentId, level = req.ParentId, req.Level
+	default:
+		return
+	}
+
+	if parentId == 0 {
+		if level != 1 {
+			sl.ReportError(level, "level", "Level", tagParentLevel, "1")
+		}
+		return
+	}
+
+	parent, err := s.CategoryModel.FindOne(ctx, parentId)
+	if err != nil {
+		if errors.Is(err, category.ErrNotFound) {
+			sl.ReportError(parentId, "parent_id", "ParentId", tagParentExists, "")
+			return
+		}
+		// 数据库异常不作为参数错误返回，交由后续业务逻辑处理
+		logx.WithContext(ctx).Errorf("验证父级类别失败: parent_id=%d, err=%v", parentId, err)
+		return
+	}
+
+	if expected := parent.Level + 1; level != expected {
+		sl.ReportError(level, "level", "Level", tagParentLevel, strconv.Itoa(expected))
+	}
+}
+
+// mustRegisterTranslation 注册中英文翻译，失败时panic（启动阶段调用）
+func mustRegisterTranslation(tag, zhMsg, enMsg string) {
+	if err := validator.RegisterTranslationLocale(validator.LocaleZh, tag, zhMsg); err != nil {
+		panic(err)
+	}
+	if err := validator.RegisterTranslationLocale(validator.LocaleEn, tag, enMsg); err != nil {
+		panic(err)
+	}
+}
@@ -3,6 +3,47 @@
 
 package types
 
+type CatalogStatItem struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value int64  `json:"value"`
+}
+
+type CatalogStatsResp struct {
+	Date                  string            `json:"date"`
+	Total                 int64             `json:"total"` // 类别总数
+	Created7d             int64             `json:"created_7d"`
+	Created30d            int64             `json:"created_30d"`
+	ByStatus              []CatalogStatItem `json:"by_status"`
+	ByLevel               []CatalogStatItem `json:"by_level"`
+	BySubtree             []CatalogStatItem `json:"by_subtree"`     // 顶级类别子树（含全部下级）的类别数
+	ResourceTotal         int64             `json:"resource_total"` // 资源总数（不含已在源中删除的数据视图）
+	ResourceCreated7d     int64             `json:"resource_created_7d"`
+	ResourceCreated30d    int64             `json:"resource_created_30d"`
+	ResourceByStatus      []CatalogStatItem `json:"resource_by_status"`      // 含已删除
+	ResourceBySubtree     []CatalogStatItem `json:"resource_by_subtree"`     // 所属类别的顶级类别子树，key 0 为未编目
+	ResourceBySensitivity []CatalogStatItem `json:"resource_by_sensitivity"` // key 为空表示未定级
+	ResourceByDepartment  []CatalogStatItem `json:"resource_by_department"`  // key 为空表示未指定
+}
+
+type CatalogStatsTrendPoint struct {
+	Date              string `json:"date"`
+	Total             int64  `json:"total"`
+	Growth            int64  `json:"growth"`
+	Created7d         int64  `json:"created_7d"`
+	ResourceTotal     int64  `json:"resource_total"`
+	ResourceGrowth    int64  `json:"resource_growth"`
+	ResourceCreated7d int64  `json:"resource_created_7d"`
+}
+
+type CatalogStatsTrendReq struct {
+	Days int `form:"days,optional,default=30" validate:"gte=1,lte=366"`
+}
+
+type CatalogStatsTrendResp struct {
+	List []CatalogStatsTrendPoint `json:"list"`
+}
+
 type CategoryReq struct {
 	Id int64 `path:"id"`
 }
@@ -19,12 +60,55 @@ type CategoryResp struct {
 }
 
 type CreateCategoryReq struct {
-	Name        string `json:"name"`
-	Code        string `json:"code"`
-	ParentId    int64  `json:"parent_id,optional"`
-	Level       int    `json:"level,optional,default=1"`
-	Sort        int    `json:"sort,optional,default=0"`
-	Description string `json:"description,optional"`
+	Name        string `json:"name" validate:"required,min=2,max=50"`
+	Code        string `json:"code" validate:"required,catcode,min=2,max=50"`
+	ParentId    int64  `json:"parent_id,optional" validate:"gte=0"`
+	Level       int    `json:"level,optional,default=1" validate:"gte=1,lte=5"`
+	Sort        int    `json:"sort,optional,default=0" validate:"gte=0"`
+	Description string `json:"description,optional" validate:"omitempty,max=200"`
+}
+
+type CreateDataViewReq struct {
+	Datasource string `json:"datasource" validate:"required,max=100"`
+	SchemaName string `json:"schema_name" validate:"required,max=100"`
+	Name       string `json:"name" validate:"required,max=191"`
+	Sql        string `json:"sql" validate:"required,max=65535"`
+	Comment    string `json:"comment,optional" validate:"omitempty,max=1000"`
+	Owner      string `json:"owner,optional" validate:"omitempty,max=100"`
+}
+
+type CreateDatasourceReq struct {
+	Name         string            `json:"name" validate:"required,min=2,max=100"`
+	Type         string            `json:"type" validate:"required,oneof=mysql postgres"`
+	Host         string            `json:"host,optional" validate:"omitempty,max=255"`
+	Port         int               `json:"port,optional" validate:"gte=0,lte=65535"`
+	DatabaseName string            `json:"database_name" validate:"required,max=255"`
+	Username     string            `json:"username,optional" validate:"omitempty,max=100"`
+	Password     string            `json:"password,optional" validate:"omitempty,max=200"`
+	Options      map[string]string `json:"options,optional"`
+	Owner        string            `json:"owner,optional" validate:"omitempty,max=100"`
+	Description  string            `json:"description,optional" validate:"omitempty,max=500"`
+}
+
+type CreateLineageEdgeReq struct {
+	SourceId int64  `json:"source_id" validate:"required,gte=1"`
+	TargetId int64  `json:"target_id" validate:"required,gte=1"`
+	Type     string `json:"type" validate:"required,oneof=feeds derives publishes serves"`
+}
+
+type CreateLineageNodeReq struct {
+	Type        string `json:"type" validate:"required,oneof=resource api"`
+	Key         string `json:"key" validate:"required,max=400"` // 资源编码或接口标识，节点键为 {type}:{key}
+	Name        string `json:"name" validate:"required,max=500"`
+	RefId       int64  `json:"ref_id,optional" validate:"gte=0"`
+	Description string `json:"description,optional" validate:"omitempty,max=500"`
+}
+
+type DataViewCatalogResp struct {
+	Id          int64  `json:"id"`
+	CategoryId  int64  `json:"category_id"`
+	Sensitivity string `json:"sensitivity"`
+	Department  string `json:"department"`
 }
 
 type DataViewCategoryReq struct {
@@ -42,18 +126,31 @@ type DataViewCategoryResp struct {
 	Status      int    `json:"status"`
 }
 
+type DataViewChangeResp struct {
+	Id         int64  `json:"id"`
+	ViewId     int64  `json:"view_id"`
+	Source     string `json:"source"`
+	TableName  string `json:"table_name"` // schema.table
+	ChangeType string `json:"change_type"`
+	ColumnName string `json:"column_name"` // 重命名时为新列名，表级变化为空
+	OldValue   string `json:"old_value"`
+	NewValue   string `json:"new_value"`
+	Breaking   bool   `json:"breaking"`
+	DetectedAt string `json:"detected_at"`
+}
+
 type DataViewCreateCategoryReq struct {
-	Name        string `json:"name"`
-	Code        string `json:"code"`
-	ParentId    int64  `json:"parent_id,optional"`
-	Level       int    `json:"level,optional,default=1"`
-	Sort        int    `json:"sort,optional,default=0"`
-	Description string `json:"description,optional"`
+	Name        string `json:"name" validate:"required,min=2,max=50"`
+	Code        string `json:"code" validate:"required,catcode,min=2,max=50"`
+	ParentId    int64  `json:"parent_id,optional" validate:"gte=0"`
+	Level       int    `json:"level,optional,default=1" validate:"gte=1,lte=5"`
+	Sort        int    `json:"sort,optional,default=0" validate:"gte=0"`
+	Description string `json:"description,optional" validate:"omitempty,max=200"`
 }
 
 type DataViewListCategoryReq struct {
-	Page     int `form:"page,optional,default=1"`
-	PageSize int `form:"page_size,optional,default=10"`
+	Page     int `form:"page,optional,default=1" validate:"gte=1"`
+	PageSize int `form:"page_size,optional,default=10" validate:"gte=1,lte=100"`
 }
 
 type DataViewListCategoryResp struct {
@@ -61,12 +158,268 @@ type DataViewListCategoryResp struct {
 	Total int64                  `json:"total"`
 }
 
+type DataViewResp struct {
+	Id         int64               `json:"id"`
+	Datasource string              `json:"datasource"`
+	SchemaName string              `json:"schema_name"`
+	TableName  string              `json:"table_name"`
+	TableType  string              `json:"table_type"` // table/view/sql
+	Comment    string              `json:"comment"`
+	Owner      string              `json:"owner"`
+	Definition string              `json:"definition"`
+	Tables     []DataViewSqlTable  `json:"tables"`  // 定义引用的表
+	Columns    []DataViewSqlColumn `json:"columns"` // 定义的输出列
+	CreatedAt  string              `json:"created_at"`
+}
+
+type DataViewSqlColumn struct {
+	Name    string                 `json:"name"`
+	Expr    string                 `json:"expr"`
+	Sources []DataViewSqlColumnRef `json:"sources"`
+	Direct  bool                   `json:"direct"` // 表达式为单个列引用
+}
+
+type DataViewSqlColumnRef struct {
+	Schema string `json:"schema"`
+	Table  string `json:"table"`  // 无法确定所属表时为空
+	Column string `json:"column"` // * 表示表的全部列
+}
+
+type DataViewSqlTable struct {
+	Schema     string `json:"schema"` // 未限定时为空
+	Name       string `json:"name"`
+	DataViewId int64  `json:"data_view_id"` // 对应的已采集数据视图，未指定数据源时为 0
+}
+
+type ParseDataViewSqlReq struct {
+	Datasource string `json:"datasource,optional" validate:"omitempty,max=100"`  // 设置时检查引用的表是否为该数据源已采集的表
+	SchemaName string `json:"schema_name,optional" validate:"omitempty,max=100"` // 未限定 schema 的表所在的 schema
+	Sql        string `json:"sql" validate:"required,max=65535"`
+}
+
+type ParseDataViewSqlResp struct {
+	Tables  []DataViewSqlTable  `json:"tables"`
+	Columns []DataViewSqlColumn `json:"columns"`
+}
+
+type PatchCategoryReq struct {
+	Id          int64   `path:"id"`
+	Name        *string `json:"name,optional" validate:"omitempty,min=2,max=50"`
+	Code        *string `json:"code,optional" validate:"omitempty,catcode,min=2,max=50"`
+	Sort        *int    `json:"sort,optional" validate:"omitempty,gte=0"`
+	Description *string `json:"description,optional" validate:"omitempty,max=200"`
+	Status      *int    `json:"status,optional" validate:"omitempty,oneof=0 1"`
+}
+
+type PatchDataViewCatalogReq struct {
+	Id          int64   `path:"id"`
+	CategoryId  *int64  `json:"category_id,optional" validate:"omitempty,gte=0"`
+	Sensitivity *string `json:"sensitivity,optional" validate:"omitempty,oneof=public internal confidential secret"` // 为空表示未定级
+	Department  *string `json:"department,optional" validate:"omitempty,max=100"`
+}
+
+type DatasourceReq struct {
+	Id int64 `path:"id"`
+}
+
+type DatasourceResp struct {
+	Id           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Host         string            `json:"host"`
+	Port         int               `json:"port"`
+	DatabaseName string            `json:"database_name"`
+	Username     string            `json:"username"`
+	Password     string            `json:"password"`
+	Options      map[string]string `json:"options"`
+	Owner        string            `json:"owner"`
+	Description  string            `json:"description"`
+	CreatedAt    string            `json:"created_at"`
+	UpdatedAt    string            `json:"updated_at"`
+}
+
+type PatchDatasourceReq struct {
+	Id           int64             `path:"id"`
+	Name         *string           `json:"name,optional" validate:"omitempty,min=2,max=100"`
+	Type         *string           `json:"type,optional" validate:"omitempty,oneof=mysql postgres"`
+	Host         *string           `json:"host,optional" validate:"omitempty,max=255"`
+	Port         *int              `json:"port,optional" validate:"omitempty,gte=0,lte=65535"`
+	DatabaseName *string           `json:"database_name,optional" validate:"omitempty,min=1,max=255"`
+	Username     *string           `json:"username,optional" validate:"omitempty,max=100"`
+	Password     *string           `json:"password,optional" validate:"omitempty,max=200"`
+	Options      map[string]string `json:"options,optional"` // 传入时整体替换，{} 清空
+	Owner        *string           `json:"owner,optional" validate:"omitempty,max=100"`
+	Description  *string           `json:"description,optional" validate:"omitempty,max=500"`
+}
+
+type PreviewDataViewReq struct {
+	Id    int64 `path:"id"`
+	Limit int   `form:"limit,optional" validate:"gte=0,lte=1000"` // 返回行数，为 0 时使用 Preview.DefaultRows，不超过 Preview.MaxRows
+}
+
+type PreviewDataViewResp struct {
+	Columns   []string        `json:"columns"`
+	Rows      [][]interface{} `json:"rows"`
+	Masked    []string        `json:"masked"`   // 按列名规则脱敏的列
+	HasMore   bool            `json:"has_more"` // 源中还有更多行
+	ElapsedMs int64           `json:"elapsed_ms"`
+}
+
+type LineageEdgeResp struct {
+	Id        int64  `json:"id"`
+	SourceId  int64  `json:"source_id"`
+	TargetId  int64  `json:"target_id"`
+	Type      string `json:"type"`
+	Origin    string `json:"origin"` // harvest/sql/manual
+	Expr      string `json:"expr"`
+	CreatedAt string `json:"created_at"`
+}
+
+type LineageGraph struct {
+	Directed bool               `json:"directed"`
+	Type     string             `json:"type"` // lineage
+	Label    string             `json:"label"`
+	Metadata LineageGraphMeta   `json:"metadata"`
+	Nodes    []LineageGraphNode `json:"nodes"` // 按距离排序，首个为起点
+	Edges    []LineageGraphEdge `json:"edges"`
+}
+
+type LineageGraphEdge struct {
+	Id       string               `json:"id"`
+	Source   string               `json:"source"`
+	Target   string               `json:"target"`
+	Relation string               `json:"relation"` // contains/feeds/derives/publishes/serves
+	Directed bool                 `json:"directed"`
+	Metadata LineageGraphEdgeMeta `json:"metadata"`
+}
+
+type LineageGraphEdgeMeta struct {
+	Origin string `json:"origin"`
+	Expr   string `json:"expr"` // 列级边的表达式，直接引用时为空
+}
+
+type LineageGraphMeta struct {
+	Root      string `json:"root"`
+	Direction string `json:"direction"` // upstream/downstream
+	Depth     int    `json:"depth"`
+	Truncated bool   `json:"truncated"` // 节点数达到上限或超出层数的部分未展开
+}
+
+type LineageGraphNode struct {
+	Id       string               `json:"id"`
+	Label    string               `json:"label"`
+	Metadata LineageGraphNodeMeta `json:"metadata"`
+}
+
+type LineageGraphNodeMeta struct {
+	Type      string `json:"type"`
+	Key       string `json:"key"`
+	Namespace string `json:"namespace"`
+	ParentId  int64  `json:"parent_id"`
+	RefId     int64  `json:"ref_id"`
+	Status    int    `json:"status"`
+	Distance  int    `json:"distance"` // 与起点的距离
+}
+
+type LineageGraphReq struct {
+	Id    int64 `path:"id"`
+	Depth int   `form:"depth,optional,default=3" validate:"gte=1,lte=10"` // 遍历层数
+}
+
+type LineageGraphResp struct {
+	Graph LineageGraph `json:"graph"`
+}
+
+type LineageImpactItem struct {
+	Id        int64  `json:"id"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	RefId     int64  `json:"ref_id"`
+	Status    int    `json:"status"`
+	Distance  int    `json:"distance"`
+	Via       int64  `json:"via"` // 由受影响的列推出时为该列的节点ID
+}
+
+type LineageImpactResp struct {
+	Graph    LineageGraph        `json:"graph"`
+	Affected []LineageImpactItem `json:"affected"` // 按距离排序
+	Columns  int                 `json:"columns"`  // 受影响的列数
+	Summary  []LineageTypeCount  `json:"summary"`  // 受影响的对象按类型计数
+}
+
+type LineageNodeResp struct {
+	Id          int64  `json:"id"`
+	Key         string `json:"key"`
+	Type        string `json:"type"` // table/column/data_view/resource/api
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	ParentId    int64  `json:"parent_id"` // 列所属的表或数据视图节点
+	RefId       int64  `json:"ref_id"`    // 表、数据视图为数据视图ID
+	Status      int    `json:"status"`    // 0已删除 1有效
+	Description string `json:"description"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type LineageTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
 type ListCategoryReq struct {
-	Page     int `form:"page,optional,default=1"`
-	PageSize int `form:"page_size,optional,default=10"`
+	Page     int `form:"page,optional,default=1" validate:"gte=1"`
+	PageSize int `form:"page_size,optional,default=10" validate:"gte=1,lte=100"`
 }
 
 type ListCategoryResp struct {
 	List  []CategoryResp `json:"list"`
 	Total int64          `json:"total"`
 }
+
+type ListDataViewChangeReq struct {
+	ViewId       int64  `form:"view_id,optional" validate:"gte=0"`
+	Source       string `form:"source,optional" validate:"omitempty,max=100"`
+	BreakingOnly bool   `form:"breaking_only,optional"` // 只返回破坏性变化（删除列、重命名列、类型变化、删除表）
+	Page         int    `form:"page,optional,default=1" validate:"gte=1"`
+	PageSize     int    `form:"page_size,optional,default=10" validate:"gte=1,lte=100"`
+}
+
+type ListDataViewChangeResp struct {
+	List  []DataViewChangeResp `json:"list"`
+	Total int64                `json:"total"`
+}
+
+type ListDatasourceReq struct {
+	Page     int `form:"page,optional,default=1" validate:"gte=1"`
+	PageSize int `form:"page_size,optional,default=10" validate:"gte=1,lte=100"`
+}
+
+type ListDatasourceResp struct {
+	List  []DatasourceResp `json:"list"`
+	Total int64            `json:"total"`
+}
+
+type ListLineageNodeReq struct {
+	Type      string `form:"type,optional" validate:"omitempty,oneof=table column data_view resource api"`
+	Namespace string `form:"namespace,optional" validate:"omitempty,max=100"` // 数据源或采集源名称
+	Keyword   string `form:"keyword,optional" validate:"omitempty,max=100"`   // 名称包含
+	Page      int    `form:"page,optional,default=1" validate:"gte=1"`
+	PageSize  int    `form:"page_size,optional,default=10" validate:"gte=1,lte=100"`
+}
+
+type ListLineageNodeResp struct {
+	List  []LineageNodeResp `json:"list"`
+	Total int64             `json:"total"`
+}
+
+type TestDatasourceReq struct {
+	Id      int64 `path:"id"`
+	Timeout int   `form:"timeout,optional,default=5" validate:"gte=1,lte=60"` // 超时（秒）
+}
+
+type TestDatasourceResp struct {
+	Success   bool   `json:"success"`
+	LatencyMs int64  `json:"latency_ms"`
+	Message   string `json:"message"`
+}
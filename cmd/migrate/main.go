@@ -0,0 +1,153 @@
+// migrate 数据库版本化迁移工具
+//
+// 用法:
+//
+//	go run ./cmd/migrate -f api/etc/api.yaml [-db resource_catalog] up
+//	go run ./cmd/migrate -f api/etc/api.yaml [-db resource_catalog] down [N]
+//	go run ./cmd/migrate -f api/etc/api.yaml [-db resource_catalog] goto VERSION
+//	go run ./cmd/migrate -f api/etc/api.yaml [-db resource_catalog] status
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/db/migrate"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+var (
+	configFile = flag.String("f", "api/etc/api.yaml", "the config file")
+	database   = flag.String("db", "", "database to migrate: resource_catalog/data_understanding, empty for all")
+)
+
+// Config 迁移工具配置（复用 API 服务配置文件中的 DB 部分）
+type Config struct {
+	DB struct {
+		ResourceCatalog   db.Config
+		DataUnderstanding db.Config
+	}
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	databases := []struct {
+		name string
+		cfg  db.Config
+	}{
+		{migrations.ResourceCatalog, c.DB.ResourceCatalog},
+		{migrations.DataUnderstanding, c.DB.DataUnderstanding},
+	}
+
+	matched := false
+	for _, d := range databases {
+		if *database != "" && *database != d.name {
+			continue
+		}
+		matched = true
+		if err := run(context.Background(), d.name, d.cfg, flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %v\n", d.name, err)
+			os.Exit(1)
+		}
+	}
+	if !matched {
+		fmt.Fprintf(os.Stderr, "unknown database: %s\n", *database)
+		os.Exit(2)
+	}
+}
+
+// run 对单个数据库执行迁移命令
+func run(ctx context.Context, name string, cfg db.Config, args []string) error {
+	conn, err := cfg.Open()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	migrator, err := migrate.NewEmbedded(conn, string(cfg.Dialect()), name)
+	if err != nil {
+		return err
+	}
+
+	var changed []migrate.Migration
+	switch cmd := args[0]; cmd {
+	case "up":
+		changed, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps: %s", args[1])
+			}
+		}
+		changed, err = migrator.Down(ctx, steps)
+	case "goto":
+		if len(args) < 2 {
+			return fmt.Errorf("goto requires a version")
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			return fmt.Errorf("invalid version: %s", args[1])
+		}
+		changed, err = migrator.Goto(ctx, version)
+	case "status":
+		return printStatus(ctx, name, migrator)
+	default:
+		return fmt.Errorf("unknown command: %s", cmd)
+	}
+
+	for _, m := range changed {
+		fmt.Printf("[%s] %s %06d_%s\n", name, args[0], m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[%s] %s done, %d migration(s)\n", name, args[0], len(changed))
+	return nil
+}
+
+// printStatus 输出迁移状态表
+func printStatus(ctx context.Context, name string, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("[%s]\n", name)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		status, appliedAt := "pending", "-"
+		if s.Applied {
+			status, appliedAt = "applied", s.AppliedAt.Format("2006-01-02 15:04:05")
+			if s.Up == "" {
+				status = "applied (missing file)"
+			} else if s.Modified {
+				status = "applied (modified!)"
+			}
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+	}
+	return w.Flush()
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: migrate [-f config] [-db name] <up|down [N]|goto VERSION|status>\n")
+	flag.PrintDefaults()
+}
@@ -0,0 +1,104 @@
+// schemacheck 检查 Go 实体定义与数据库表结构是否一致
+//
+// 检查项：表是否存在、gorm/sqlx 表名是否一致、列是否缺失、类型是否兼容、索引是否缺失。
+// 存在 error 级别问题时以状态码 1 退出，可用于 CI。
+//
+// 用法:
+//
+//	go run ./cmd/schemacheck -f api/etc/api.yaml [-db resource_catalog]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+	"idrm/pkg/db/schemacheck"
+
+	// 引入 model 包以注册需要检查的实体
+	_ "idrm/model/resource_catalog/category"
+	_ "idrm/model/resource_catalog/datasource"
+	_ "idrm/model/resource_catalog/dataview"
+	_ "idrm/model/resource_catalog/stats"
+	_ "idrm/pkg/job"
+	_ "idrm/pkg/lock"
+	_ "idrm/pkg/outbox"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+var (
+	configFile = flag.String("f", "api/etc/api.yaml", "the config file")
+	database   = flag.String("db", "", "database to check: resource_catalog/data_understanding, empty for all")
+)
+
+// Config 检查工具配置（复用 API 服务配置文件中的 DB 部分）
+type Config struct {
+	DB struct {
+		ResourceCatalog   db.Config
+		DataUnderstanding db.Config
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	var c Config
+	conf.MustLoad(*configFile, &c)
+
+	databases := []struct {
+		name string
+		cfg  db.Config
+	}{
+		{migrations.ResourceCatalog, c.DB.ResourceCatalog},
+		{migrations.DataUnderstanding, c.DB.DataUnderstanding},
+	}
+
+	matched, failed := false, false
+	for _, d := range databases {
+		if *database != "" && *database != d.name {
+			continue
+		}
+		matched = true
+		if len(schemacheck.Entities(d.name)) == 0 {
+			continue
+		}
+		if d.cfg.Dialect() != dialect.MySQL {
+			fmt.Printf("[%s] skipped: schema check supports mysql only (driver: %s)\n", d.name, d.cfg.Dialect())
+			continue
+		}
+
+		report, err := check(context.Background(), d.name, d.cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[%s] %v\n", d.name, err)
+			os.Exit(1)
+		}
+		for _, issue := range report.Issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("[%s] %d issue(s)\n", d.name, len(report.Issues))
+		failed = failed || report.HasErrors()
+	}
+	if !matched {
+		fmt.Fprintf(os.Stderr, "unknown database: %s\n", *database)
+		os.Exit(2)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// check 检查单个数据库
+func check(ctx context.Context, name string, cfg db.Config) (*schemacheck.Report, error) {
+	conn, err := cfg.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return schemacheck.NewChecker(conn, cfg.Namer()).Check(ctx, name)
+}
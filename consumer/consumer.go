@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"idrm/consumer/internal/config"
+	"idrm/consumer/internal/handler"
+	"idrm/pkg/mq"
+	"idrm/pkg/notify"
+	"idrm/pkg/telemetry"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+var configFile = flag.String("f", "etc/consumer.yaml", "the config file")
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+
+	// Initialize Telemetry (Logging, Tracing, Audit)
+	if err := telemetry.Init(c.Telemetry); err != nil {
+		panic(fmt.Sprintf("failed to initialize telemetry: %v", err))
+	}
+	defer telemetry.Close(context.Background())
+
+	notifier, err := notify.New(c.Notify)
+	if err != nil {
+		panic(fmt.Sprintf("通知配置错误: %v", err))
+	}
+
+	// Register topic handlers
+	router := mq.NewRouter()
+	handler.RegisterHandlers(router, notifier)
+
+	// One consumer per Kafka.Consumers entry
+	broker := mq.KafkaBroker{Brokers: c.Kafka.Brokers}
+	group := service.NewServiceGroup()
+	defer group.Stop()
+	for name, item := range c.Kafka.Consumers {
+		consumer, err := mq.NewConsumer(name, item, c.Retry, broker, router)
+		if err != nil {
+			panic(err)
+		}
+		group.Add(consumer)
+	}
+
+	// 收到退出信号时 ServiceGroup 停止各消费者：停止拉取，等待处理中的消息完成并提交位移
+	fmt.Printf("Starting consumer %s with %d consumers...\n", c.Name, len(c.Kafka.Consumers))
+	group.Start()
+}
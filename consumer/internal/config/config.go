@@ -0,0 +1,17 @@
+package config
+
+import (
+	"idrm/pkg/config"
+	"idrm/pkg/notify"
+	"idrm/pkg/telemetry"
+)
+
+type Config struct {
+	config.ConsumerConfig
+
+	// Telemetry配置
+	Telemetry telemetry.Config
+
+	// 通知渠道（数据视图结构变化通知负责人）
+	Notify notify.Config `json:",optional"`
+}
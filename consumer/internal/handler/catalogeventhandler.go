@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"context"
+
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/mq"
+	"idrm/pkg/notify"
+	"idrm/pkg/outbox"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// CatalogEventHandler 处理目录变更事件（outbox 投递）：记录日志，数据视图结构变化通知负责人；
+// 搜索索引、下游同步等订阅方在此扩展
+func CatalogEventHandler(notifier notify.Notifier) mq.HandlerFunc {
+	return func(ctx context.Context, msg *mq.Message) error {
+		eventType := mq.HeaderValue(msg, outbox.HeaderEventType)
+		logx.WithContext(ctx).Infof("收到目录变更事件: id=%s, type=%s, aggregate=%s:%s",
+			mq.HeaderValue(msg, outbox.HeaderEventId),
+			eventType,
+			mq.HeaderValue(msg, outbox.HeaderAggregateType),
+			mq.HeaderValue(msg, outbox.HeaderAggregateId))
+
+		switch eventType {
+		case dataview.EventSchemaChanged:
+			return dataview.NotifySchemaChanged(ctx, notifier, msg.Value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"idrm/pkg/mq"
+	"idrm/pkg/notify"
+)
+
+// CatalogEventsTopic 目录变更事件主题（与 Outbox.Kafka.Topic 默认值一致）
+const CatalogEventsTopic = "idrm.catalog.events"
+
+// RegisterHandlers 注册各 topic 的处理函数
+func RegisterHandlers(router *mq.Router, notifier notify.Notifier) {
+	router.Handle(CatalogEventsTopic, CatalogEventHandler(notifier))
+}
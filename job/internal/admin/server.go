@@ -0,0 +1,196 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"idrm/job/internal/handler"
+	"idrm/job/internal/svc"
+	"idrm/pkg/db/repo"
+	"idrm/pkg/errorx"
+	"idrm/pkg/job"
+	"idrm/pkg/lock"
+	"idrm/pkg/response"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/sysx"
+)
+
+// 执行记录查询条数
+const (
+	defaultLimit = 20
+	maxLimit     = 200
+)
+
+// shutdownTimeout 停止时等待处理中请求的时间
+const shutdownTimeout = 5 * time.Second
+
+// Server 管理接口，实现 go-zero service.Service
+//
+//	GET  /jobs                 已注册的任务及下次执行时间、本实例是否为 leader
+//	POST /jobs/{name}/run      后台执行任务（执行中时返回错误）
+//	GET  /jobs/{name}/runs     最近的执行记录（?limit=20）
+//	GET  /runs/{id}            执行记录
+//	GET  /sources                         元数据采集源
+//	POST /sources/{name}/harvest          同步采集单个源并返回变化（?dry_run=true 只比较不写入）
+type Server struct {
+	svcCtx *svc.ServiceContext
+	server *http.Server
+}
+
+// NewServer 创建管理接口
+func NewServer(addr string, svcCtx *svc.ServiceContext) *Server {
+	s := &Server{svcCtx: svcCtx}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", s.listJobs)
+	mux.HandleFunc("POST /jobs/{name}/run", s.runJob)
+	mux.HandleFunc("GET /jobs/{name}/runs", s.listRuns)
+	mux.HandleFunc("GET /runs/{id}", s.getRun)
+	mux.HandleFunc("GET /sources", s.listSources)
+	mux.HandleFunc("POST /sources/{name}/harvest", s.harvest)
+	s.server = &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Handler HTTP 处理器（测试使用）
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+// Start 启动监听，阻塞直到 Stop
+func (s *Server) Start() {
+	logx.Infof("任务管理接口监听 %s", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logx.Errorf("任务管理接口启动失败: %v", err)
+	}
+}
+
+// Stop 停止监听
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		logx.Errorf("任务管理接口停止失败: %v", err)
+	}
+}
+
+// jobsResp GET /jobs 响应
+type jobsResp struct {
+	Host   string     `json:"host"`
+	Leader bool       `json:"leader"` // 多实例部署时只有 leader 按 cron 调度
+	Jobs   []job.Info `json:"jobs"`
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, jobsResp{
+		Host:   sysx.Hostname(),
+		Leader: s.svcCtx.Scheduler.IsLeader(),
+		Jobs:   s.svcCtx.Scheduler.Jobs(),
+	})
+}
+
+func (s *Server) runJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := s.svcCtx.Scheduler.Trigger(name)
+	switch {
+	case errors.Is(err, job.ErrUnknownJob):
+		response.NotFound(w, "任务 "+name)
+	case errors.Is(err, job.ErrJobRunning), errors.Is(err, job.ErrJobLocked):
+		response.Error(w, errorx.New(errorx.ErrCodeOperationFailed, "任务执行中，请稍后重试"))
+	case err != nil:
+		response.Error(w, err)
+	default:
+		response.SuccessWithMsg(w, "任务已触发", nil)
+	}
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
+	limit := defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxLimit {
+			response.ErrorValidation(w, map[string]string{"limit": "limit 须为 1-" + strconv.Itoa(maxLimit) + " 的整数"})
+			return
+		}
+		limit = n
+	}
+
+	runs, err := s.svcCtx.History.Recent(r.Context(), r.PathValue("name"), limit)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+	response.Success(w, runs)
+}
+
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		response.ErrorValidation(w, map[string]string{"id": "id 须为整数"})
+		return
+	}
+
+	run, err := s.svcCtx.History.FindOne(r.Context(), id)
+	if errors.Is(err, repo.ErrNotFound) {
+		response.NotFound(w, "执行记录")
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+	response.Success(w, run)
+}
+
+// sourceInfo GET /sources 响应项（不含账号密码）
+type sourceInfo struct {
+	Name       string   `json:"name"`
+	Datasource string   `json:"datasource,omitempty"` // 引用已注册的数据源时连接信息见 API 数据源接口
+	Driver     string   `json:"driver,omitempty"`
+	Addr       string   `json:"addr,omitempty"`
+	Database   string   `json:"database,omitempty"`
+	Schemas    []string `json:"schemas"`
+	Exclude    []string `json:"exclude"`
+}
+
+func (s *Server) listSources(w http.ResponseWriter, r *http.Request) {
+	sources := make([]sourceInfo, 0, len(s.svcCtx.Config.Sources))
+	for _, src := range s.svcCtx.Config.Sources {
+		info := sourceInfo{Name: src.Name, Datasource: src.Datasource, Schemas: src.Schemas, Exclude: src.Exclude}
+		if src.Datasource == "" {
+			info.Driver = string(src.DB.Dialect())
+			info.Addr = src.DB.Addr()
+			info.Database = src.DB.Database
+		}
+		sources = append(sources, info)
+	}
+	response.Success(w, sources)
+}
+
+func (s *Server) harvest(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			response.ErrorValidation(w, map[string]string{"dry_run": "dry_run 须为 true 或 false"})
+			return
+		}
+		dryRun = b
+	}
+
+	name := r.PathValue("name")
+	report, err := handler.Harvest(r.Context(), s.svcCtx, name, dryRun)
+	switch {
+	case errors.Is(err, handler.ErrUnknownSource):
+		response.NotFound(w, "采集源 "+name)
+	case errors.Is(err, lock.ErrNotAcquired):
+		response.Error(w, errorx.New(errorx.ErrCodeOperationFailed, "采集执行中，请稍后重试"))
+	case err != nil:
+		response.InternalError(w, err)
+	default:
+		response.Success(w, report)
+	}
+}
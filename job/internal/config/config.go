@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+
+	"idrm/migrations"
+	"idrm/pkg/config"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/kms"
+	"idrm/pkg/lock"
+	"idrm/pkg/telemetry"
+)
+
+type Config struct {
+	Name string
+	Mode string `json:",default=pro"`
+
+	// Telemetry配置
+	Telemetry telemetry.Config
+
+	// 数据库配置（执行记录写入资源目录数据库 job_run 表）
+	DB struct {
+		// 资源目录数据库
+		ResourceCatalog db.Config
+
+		// 启动时自动执行数据库迁移（migrations 目录）
+		AutoMigrate bool `json:",default=false"`
+
+		// 开启 AutoMigrate 时等待数据库可用的最长时间（秒）
+		ConnectTimeout int `json:",default=300"`
+	}
+
+	// 定时任务配置
+	Jobs config.JobsConfig
+
+	// 元数据采集源（sync_data 任务采集表、视图、列及索引，写入 data_view 表）
+	Sources []harvest.Source `json:",optional"`
+
+	// 发件箱：采集发现的结构变化同时写入 data_view.schema_changed 事件（由 API 服务的 Relay 投递）
+	Outbox struct {
+		Enabled bool `json:",default=false"`
+	}
+
+	// 密钥管理（解密已注册数据源的密码，与 API 服务使用同一密钥文件）
+	KMS kms.Config
+
+	// 分布式锁：多实例部署时只有 leader 按 cron 调度，同一任务同时只在一个实例执行
+	// Type 为 local 时不选举（单实例部署）
+	Lock lock.Config
+
+	// 管理接口（查看任务、手动触发、执行记录），Addr 为空时不启动
+	Admin struct {
+		Addr string `json:",optional"` // 如 :8889
+	}
+}
+
+// Datasources 数据源配置（名称与 migrations 中的数据库目录一致）
+func (c Config) Datasources() map[string]db.Config {
+	return map[string]db.Config{
+		migrations.ResourceCatalog: c.DB.ResourceCatalog,
+	}
+}
+
+// Source 按名称获取采集源
+func (c Config) Source(name string) (harvest.Source, bool) {
+	for _, src := range c.Sources {
+		if src.Name == name {
+			return src, true
+		}
+	}
+	return harvest.Source{}, false
+}
+
+// SourceConfigs 直接配置连接的采集源（不含引用已注册数据源的采集源），名称重复或未配置连接时返回错误
+func (c Config) SourceConfigs() (map[string]db.Config, error) {
+	configs := make(map[string]db.Config, len(c.Sources))
+	seen := make(map[string]bool, len(c.Sources))
+	for _, src := range c.Sources {
+		if src.Name == "" {
+			return nil, fmt.Errorf("采集源名称不能为空")
+		}
+		if seen[src.Name] {
+			return nil, fmt.Errorf("采集源名称重复: %s", src.Name)
+		}
+		seen[src.Name] = true
+		switch {
+		case src.Datasource != "":
+		case src.DB.Database != "":
+			configs[src.Name] = src.DB
+		default:
+			return nil, fmt.Errorf("采集源 %s 未配置 Datasource 或 DB", src.Name)
+		}
+	}
+	return configs, nil
+}
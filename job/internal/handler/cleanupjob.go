@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"idrm/job/internal/svc"
+	"idrm/pkg/job"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// CleanupJob 清理保留期（RetentionDays）之前的数据，结果说明记录各项删除数量：
+//   - job_run     执行记录
+//   - outbox      已投递的发件箱记录（待投递的不删除）
+//   - spool_files SpoolDirs 中修改时间早于保留期的文件
+//
+// 数据库记录按 BatchSize 分批删除，每批单独提交；某项失败时返回已完成项的数量及错误
+func CleanupJob(svcCtx *svc.ServiceContext) job.Func {
+	return func(ctx context.Context) (job.Result, error) {
+		c := svcCtx.Config.Jobs.Cleanup
+		if c.RetentionDays < 1 {
+			return job.Result{}, fmt.Errorf("RetentionDays 须大于 0，当前为 %d", c.RetentionDays)
+		}
+		if c.BatchSize < 1 {
+			return job.Result{}, fmt.Errorf("BatchSize 须大于 0，当前为 %d", c.BatchSize)
+		}
+		before := time.Now().AddDate(0, 0, -c.RetentionDays)
+
+		steps := []struct {
+			name  string
+			purge func(ctx context.Context) (int64, error)
+		}{
+			{"job_run", func(ctx context.Context) (int64, error) {
+				return svcCtx.History.Purge(ctx, before, c.BatchSize)
+			}},
+			{"outbox", func(ctx context.Context) (int64, error) {
+				return svcCtx.Outbox.PurgePublished(ctx, before, c.BatchSize)
+			}},
+			{"spool_files", func(ctx context.Context) (int64, error) {
+				return purgeFiles(ctx, c.SpoolDirs, c.SpoolPattern, before)
+			}},
+		}
+
+		var (
+			result job.Result
+			counts []string
+		)
+		for _, step := range steps {
+			n, err := step.purge(ctx)
+			result.Rows += n
+			counts = append(counts, fmt.Sprintf("%s=%d", step.name, n))
+			result.Message = strings.Join(counts, ", ")
+			if err != nil {
+				return result, fmt.Errorf("清理 %s 失败: %w", step.name, err)
+			}
+		}
+		return result, nil
+	}
+}
+
+// purgeFiles 删除目录（含子目录）中名称匹配 pattern 且修改时间早于 before 的文件，目录不存在时跳过
+func purgeFiles(ctx context.Context, dirs []string, pattern string, before time.Time) (int64, error) {
+	var n int64
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == dir && errors.Is(err, fs.ErrNotExist) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if ok, err := filepath.Match(pattern, d.Name()); err != nil || !ok {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !info.ModTime().Before(before) {
+				return nil
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			logx.WithContext(ctx).Infof("已删除过期文件: %s", path)
+			n++
+			return nil
+		})
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
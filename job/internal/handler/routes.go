@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"idrm/job/internal/svc"
+	"idrm/pkg/config"
+	"idrm/pkg/job"
+)
+
+// jobEntry 待注册的任务
+type jobEntry struct {
+	name string
+	conf config.JobItemConfig
+	fn   job.Func
+}
+
+// RegisterJobs 注册所有任务：配置中 Enabled 的任务按 Cron 定时执行，其余可手动触发
+// 新增任务时在 jobs 中追加，任务函数放在本目录下独立文件中
+func RegisterJobs(svcCtx *svc.ServiceContext) error {
+	jobs := []jobEntry{
+		{name: "sync_data", conf: svcCtx.Config.Jobs.SyncData, fn: SyncDataJob(svcCtx)},
+		{name: "statistics", conf: svcCtx.Config.Jobs.Statistics, fn: StatisticsJob(svcCtx)},
+		{name: "cleanup", conf: svcCtx.Config.Jobs.Cleanup.JobItemConfig, fn: CleanupJob(svcCtx)},
+	}
+
+	for _, j := range jobs {
+		if err := svcCtx.Scheduler.Register(j.name, j.conf, j.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"idrm/job/internal/svc"
+	"idrm/model/resource_catalog/stats"
+	"idrm/pkg/job"
+)
+
+// StatisticsJob 统计类别及资源（数据视图）并写入当日快照（catalog_stat 表，同一天重复执行时覆盖），
+// 供 GET /api/v1/catalog/stats 查询；影响行数为快照项数
+func StatisticsJob(svcCtx *svc.ServiceContext) job.Func {
+	return func(ctx context.Context) (job.Result, error) {
+		categories, err := svcCtx.CategoryModel.FindAll(ctx)
+		if err != nil {
+			return job.Result{}, fmt.Errorf("查询类别失败: %w", err)
+		}
+
+		views, err := svcCtx.DataViewModel.FindAll(ctx)
+		if err != nil {
+			return job.Result{}, fmt.Errorf("查询数据视图失败: %w", err)
+		}
+
+		now := time.Now()
+		snapshot := stats.Compute(categories, views, now)
+		date := now.Format(stats.DateLayout)
+		if err := svcCtx.StatsModel.Save(ctx, date, snapshot); err != nil {
+			return job.Result{}, fmt.Errorf("保存统计快照失败: %w", err)
+		}
+		return job.Result{
+			Rows:    int64(len(snapshot)),
+			Message: fmt.Sprintf("date=%s, categories=%d, data_views=%d", date, len(categories), len(views)),
+		}, nil
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"idrm/job/internal/svc"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/job"
+	"idrm/pkg/lock"
+)
+
+// ErrUnknownSource 采集源未配置
+var ErrUnknownSource = errors.New("unknown source")
+
+// SyncDataJob 依次采集所有配置的源，增量写入数据视图（data_view 表）；
+// 单个源失败不影响其他源，全部执行后返回第一个错误；影响行数为新增、变化及删除的表数
+func SyncDataJob(svcCtx *svc.ServiceContext) job.Func {
+	return func(ctx context.Context) (job.Result, error) {
+		var (
+			rows     int64
+			messages []string
+			firstErr error
+		)
+		for _, src := range svcCtx.Config.Sources {
+			report, err := Harvest(ctx, svcCtx, src.Name, false)
+			if report != nil {
+				rows += int64(report.Affected())
+				messages = append(messages, report.Summary())
+			}
+			if err != nil {
+				messages = append(messages, fmt.Sprintf("%s: %v", src.Name, err))
+				if firstErr == nil {
+					firstErr = fmt.Errorf("采集 %s 失败: %w", src.Name, err)
+				}
+			}
+		}
+		if len(messages) == 0 {
+			messages = append(messages, "未配置采集源")
+		}
+		return job.Result{Rows: rows, Message: strings.Join(messages, "; ")}, firstErr
+	}
+}
+
+// Harvest 采集单个源并写入数据视图及血缘图，dryRun 时只返回与已有数据视图的差异不写入
+// 写入时按源加锁（harvest:<name>），同一源同时只执行一次采集
+func Harvest(ctx context.Context, svcCtx *svc.ServiceContext, name string, dryRun bool) (*dataview.Report, error) {
+	src, ok := svcCtx.Config.Source(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSource, name)
+	}
+	conn, owner, closeConn, err := sourceConn(ctx, svcCtx, src)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	run := func(ctx context.Context) (*dataview.Report, error) {
+		tables, err := harvest.Inspect(ctx, conn, src)
+		if err != nil {
+			return nil, fmt.Errorf("读取元数据失败: %w", err)
+		}
+		report, err := dataview.Sync(ctx, svcCtx.DataViewModel, dataview.Source{Name: name, Datasource: src.Datasource, Owner: owner}, tables, dryRun)
+		if err != nil || dryRun {
+			return report, err
+		}
+		// 按采集结果更新血缘图（表、列及视图定义引用的表和列），结构指纹未变化的表跳过
+		if _, err := lineage.SyncSource(ctx, svcCtx.LineageModel, svcCtx.DataViewModel, name); err != nil {
+			return report, fmt.Errorf("更新血缘失败: %w", err)
+		}
+		return report, nil
+	}
+	if dryRun {
+		return run(ctx)
+	}
+
+	var report *dataview.Report
+	ttl := time.Duration(svcCtx.Config.Lock.TTL) * time.Second
+	err = lock.Do(ctx, svcCtx.Locker, "harvest:"+name, ttl, 0, func(ctx context.Context) error {
+		var err error
+		report, err = run(ctx)
+		return err
+	})
+	return report, err
+}
+
+// sourceConn 采集源连接及负责人：引用已注册数据源时按当前登记信息创建连接（用后关闭），
+// 未配置 Owner 时使用数据源的负责人；否则使用配置的连接
+func sourceConn(ctx context.Context, svcCtx *svc.ServiceContext, src harvest.Source) (*db.Conn, string, func(), error) {
+	if src.Datasource == "" {
+		conn, err := svcCtx.Sources.Conn(src.Name)
+		return conn, src.Owner, func() {}, err
+	}
+
+	d, err := svcCtx.DatasourceModel.FindByName(ctx, src.Datasource)
+	if errors.Is(err, datasource.ErrNotFound) {
+		return nil, "", nil, fmt.Errorf("数据源 %s 未注册", src.Datasource)
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+	conn, err := datasource.Connect(ctx, svcCtx.KMS, d)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	owner := src.Owner
+	if owner == "" {
+		owner = d.Owner
+	}
+	return conn, owner, func() { conn.Close() }, nil
+}
@@ -0,0 +1,182 @@
+package svc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"idrm/job/internal/config"
+	"idrm/migrations"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/datasource"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/model/resource_catalog/lineage"
+	"idrm/model/resource_catalog/stats"
+	"idrm/pkg/db"
+	"idrm/pkg/db/migrate"
+	"idrm/pkg/job"
+	"idrm/pkg/kms"
+	"idrm/pkg/lock"
+	"idrm/pkg/outbox"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ServiceContext struct {
+	Config config.Config
+
+	// 数据源管理器
+	DB *db.Manager
+
+	// 元数据采集源连接（按 Sources 配置的名称获取，不含引用已注册数据源的采集源）
+	Sources *db.Manager
+
+	// 密钥管理（解密已注册数据源的密码）
+	KMS kms.KeyManager
+
+	// 分布式锁（同一采集源同时只执行一次采集），Type 为 local 时为进程内锁
+	Locker lock.Locker
+
+	// 执行记录存储
+	History *job.History
+
+	// 发件箱存储（清理已投递的记录）
+	Outbox *outbox.Store
+
+	// Model层（统计任务读取类别，写入统计快照）
+	CategoryModel   category.Model
+	StatsModel      stats.Model
+	DataViewModel   dataview.Model
+	DatasourceModel datasource.Model
+	LineageModel    lineage.Model // 采集后按数据视图更新血缘图
+
+	// 定时任务调度器
+	Scheduler *job.Scheduler
+}
+
+func NewServiceContext(c config.Config) *ServiceContext {
+	// 1. 创建数据源管理器（不建立网络连接，任务执行时连接）
+	manager, err := db.NewManager(c.Datasources())
+	if err != nil {
+		panic(fmt.Sprintf("数据源配置错误: %v", err))
+	}
+	conn, err := manager.Conn(migrations.ResourceCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("数据源配置错误: %v", err))
+	}
+	logx.Infof("数据源: %s %s", c.DB.ResourceCatalog.Dialect(), c.DB.ResourceCatalog.Addr())
+
+	// 启动时自动执行数据库迁移（可选，等待数据库可用后执行）
+	if c.DB.AutoMigrate {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.DB.ConnectTimeout)*time.Second)
+		err := manager.WaitReady(ctx, migrations.ResourceCatalog)
+		cancel()
+		if err != nil {
+			panic(fmt.Sprintf("数据库连接失败: %v", err))
+		}
+		if err := autoMigrate(conn); err != nil {
+			panic(fmt.Sprintf("数据库迁移失败: %v", err))
+		}
+	}
+
+	// 2. 调度器及执行记录，多实例部署时使用分布式锁选举 leader
+	var opts []job.Option
+	var locker lock.Locker = lock.NewLocal()
+	if c.Lock.Type != lock.TypeLocal {
+		locker, err = lock.New(c.Lock, conn)
+		if err != nil {
+			panic(fmt.Sprintf("分布式锁配置错误: %v", err))
+		}
+		opts = append(opts, job.WithLocker(locker, time.Duration(c.Lock.TTL)*time.Second))
+		logx.Infof("分布式锁: %s, TTL %ds", c.Lock.Type, c.Lock.TTL)
+	}
+
+	// 3. 任务使用的Model
+	categoryModel, err := category.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	statsModel, err := stats.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	var writer outbox.Writer
+	if c.Outbox.Enabled {
+		writer = outbox.NewStore(conn)
+	}
+	dataViewModel, err := dataview.NewModel(conn, writer)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+
+	datasourceModel, err := datasource.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	lineageModel, err := lineage.NewModel(conn)
+	if err != nil {
+		panic(fmt.Sprintf("创建Model失败: %v", err))
+	}
+	km, err := kms.New(c.KMS)
+	if err != nil {
+		panic(fmt.Sprintf("密钥管理配置错误: %v", err))
+	}
+
+	// 4. 元数据采集源（不建立网络连接，采集时连接）
+	sourceConfigs, err := c.SourceConfigs()
+	if err != nil {
+		panic(fmt.Sprintf("采集源配置错误: %v", err))
+	}
+	sources, err := db.NewManager(sourceConfigs)
+	if err != nil {
+		panic(fmt.Sprintf("采集源配置错误: %v", err))
+	}
+	for _, src := range c.Sources {
+		if src.Datasource != "" {
+			logx.Infof("采集源: %s 数据源 %s", src.Name, src.Datasource)
+		} else {
+			logx.Infof("采集源: %s %s %s", src.Name, src.DB.Dialect(), src.DB.Addr())
+		}
+	}
+
+	history := job.NewHistory(conn)
+	return &ServiceContext{
+		Config:          c,
+		DB:              manager,
+		Sources:         sources,
+		KMS:             km,
+		Locker:          locker,
+		History:         history,
+		Outbox:          outbox.NewStore(conn),
+		CategoryModel:   categoryModel,
+		StatsModel:      statsModel,
+		DataViewModel:   dataViewModel,
+		DatasourceModel: datasourceModel,
+		LineageModel:    lineageModel,
+		Scheduler:       job.NewScheduler(history, opts...),
+	}
+}
+
+// Close 关闭所有数据源连接池（须在调度器停止后调用）
+func (s *ServiceContext) Close() {
+	if err := s.DB.Close(); err != nil {
+		logx.Errorf("关闭数据源失败: %v", err)
+	}
+	if err := s.Sources.Close(); err != nil {
+		logx.Errorf("关闭采集源失败: %v", err)
+	}
+}
+
+// autoMigrate 对资源目录数据库执行未执行的迁移
+func autoMigrate(conn *db.Conn) error {
+	migrator, err := migrate.NewEmbedded(conn.DB, string(conn.Dialect()), migrations.ResourceCatalog)
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		return err
+	}
+	logx.Infof("数据库迁移完成: %s, 本次执行 %d 个迁移", migrations.ResourceCatalog, len(applied))
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"idrm/job/internal/admin"
+	"idrm/job/internal/config"
+	"idrm/job/internal/handler"
+	"idrm/job/internal/svc"
+	"idrm/pkg/telemetry"
+
+	"github.com/zeromicro/go-zero/core/conf"
+	"github.com/zeromicro/go-zero/core/service"
+)
+
+var (
+	configFile = flag.String("f", "etc/job.yaml", "the config file")
+	runJob     = flag.String("run", "", "run the named job once and exit (ignores Enabled)")
+)
+
+func main() {
+	flag.Parse()
+
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+
+	// Initialize Telemetry (Logging, Tracing, Audit)
+	if err := telemetry.Init(c.Telemetry); err != nil {
+		panic(fmt.Sprintf("failed to initialize telemetry: %v", err))
+	}
+	defer telemetry.Close(context.Background())
+
+	// Initialize service context and register jobs
+	ctx := svc.NewServiceContext(c)
+	defer ctx.Close()
+	if err := handler.RegisterJobs(ctx); err != nil {
+		panic(fmt.Sprintf("failed to register jobs: %v", err))
+	}
+
+	// 手动执行一次（替代 shell crontab 中的临时调用），失败时退出码非 0
+	if *runJob != "" {
+		run, err := ctx.Scheduler.Run(context.Background(), *runJob)
+		ctx.Scheduler.Stop()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "job %s failed: %v\n", *runJob, err)
+			ctx.Close()
+			os.Exit(1)
+		}
+		fmt.Printf("job %s %s: rows=%d, duration=%s %s\n", *runJob, run.Status, run.RowsAffected, run.Duration(), run.Message)
+		return
+	}
+
+	group := service.NewServiceGroup()
+	defer group.Stop()
+	group.Add(ctx.Scheduler)
+	if c.Admin.Addr != "" {
+		group.Add(admin.NewServer(c.Admin.Addr, ctx))
+	}
+
+	fmt.Printf("Starting job server %s with %d jobs...\n", c.Name, len(ctx.Scheduler.Jobs()))
+	group.Start()
+}
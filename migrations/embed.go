@@ -0,0 +1,27 @@
+// Package migrations 数据库版本化迁移脚本
+//
+// 目录结构: {dialect}/{database}/{version}_{name}.up.sql / .down.sql
+// 例如: mysql/resource_catalog/000001_create_category.up.sql
+// dialect 与 DB.*.Driver 一致（mysql/postgres/sqlite），各方言同一版本号的脚本语义保持一致
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+)
+
+//go:embed mysql postgres sqlite
+var files embed.FS
+
+// Source 获取指定方言、数据库的迁移脚本目录
+// 目录不存在时读取会返回 fs.ErrNotExist，由 migrate.Load 视为无迁移脚本
+func Source(dialect, database string) (fs.FS, error) {
+	return fs.Sub(files, path.Join(dialect, database))
+}
+
+// 数据库（迁移脚本目录名）
+const (
+	ResourceCatalog   = "resource_catalog"
+	DataUnderstanding = "data_understanding"
+)
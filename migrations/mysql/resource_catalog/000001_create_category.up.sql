@@ -0,0 +1,17 @@
+-- 资源类别表
+CREATE TABLE IF NOT EXISTS `category` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `name` varchar(100) NOT NULL COMMENT '类别名称',
+  `code` varchar(50) NOT NULL COMMENT '类别编码',
+  `parent_id` bigint NOT NULL DEFAULT '0' COMMENT '父级ID',
+  `level` int NOT NULL DEFAULT '1' COMMENT '层级',
+  `sort` int NOT NULL DEFAULT '0' COMMENT '排序',
+  `description` text COMMENT '描述',
+  `status` tinyint NOT NULL DEFAULT '1' COMMENT '状态(1:启用 0:禁用)',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_code` (`code`),
+  KEY `idx_parent_id` (`parent_id`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='资源类别表';
@@ -0,0 +1,17 @@
+-- 事件发件箱：业务数据变更时在同一事务中写入，由 Relay 异步投递
+CREATE TABLE IF NOT EXISTS `outbox` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID（同一聚合按 ID 顺序投递）',
+  `aggregate_type` varchar(50) NOT NULL COMMENT '聚合类型(category)',
+  `aggregate_id` varchar(64) NOT NULL COMMENT '聚合ID',
+  `event_type` varchar(100) NOT NULL COMMENT '事件类型(category.created)',
+  `payload` json NOT NULL COMMENT '事件内容',
+  `headers` json NOT NULL COMMENT '消息头（链路追踪上下文）',
+  `status` tinyint NOT NULL DEFAULT '0' COMMENT '状态(0:待投递 1:已投递)',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '投递失败次数',
+  `last_error` varchar(500) NOT NULL DEFAULT '' COMMENT '最近一次投递错误',
+  `next_attempt_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次投递时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间（已投递时为投递时间）',
+  PRIMARY KEY (`id`),
+  KEY `idx_outbox_status` (`status`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='事件发件箱';
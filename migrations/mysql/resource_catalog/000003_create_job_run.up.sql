@@ -0,0 +1,16 @@
+-- 定时任务执行记录：每次执行（定时或手动触发）一行
+CREATE TABLE IF NOT EXISTS `job_run` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `job_name` varchar(100) NOT NULL COMMENT '任务名称',
+  `trigger_type` varchar(20) NOT NULL COMMENT '触发方式(cron/manual)',
+  `status` varchar(20) NOT NULL COMMENT '状态(running/success/failed/timeout)',
+  `host` varchar(100) NOT NULL DEFAULT '' COMMENT '执行实例',
+  `rows_affected` bigint NOT NULL DEFAULT '0' COMMENT '影响行数',
+  `message` varchar(500) NOT NULL DEFAULT '' COMMENT '执行结果说明',
+  `error` varchar(500) NOT NULL DEFAULT '' COMMENT '错误信息',
+  `started_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '开始时间',
+  `finished_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '结束时间（执行中时等于开始时间）',
+  PRIMARY KEY (`id`),
+  KEY `idx_job_run_name` (`job_name`, `id`),
+  KEY `idx_job_run_started_at` (`started_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='定时任务执行记录';
@@ -0,0 +1,8 @@
+-- 分布式锁租约（pkg/lock 数据库实现）：行不删除，token 单调递增作为 fencing token
+CREATE TABLE IF NOT EXISTS `distributed_lock` (
+  `name` varchar(191) NOT NULL COMMENT '锁名称',
+  `owner` varchar(100) NOT NULL DEFAULT '' COMMENT '持有者（空表示已释放）',
+  `token` bigint NOT NULL DEFAULT '0' COMMENT 'fencing token，每次获取加 1',
+  `expires_at` bigint NOT NULL DEFAULT '0' COMMENT '租约到期时间（Unix 毫秒）',
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='分布式锁租约';
@@ -0,0 +1,13 @@
+-- 资源目录统计快照：统计任务每次执行写入当日各维度的计数（同一天重复执行时覆盖）
+CREATE TABLE IF NOT EXISTS `catalog_stat` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `stat_date` varchar(10) NOT NULL COMMENT '统计日期(YYYY-MM-DD)',
+  `dimension` varchar(50) NOT NULL COMMENT '维度(total/status/level/subtree/created)',
+  `dim_key` varchar(100) NOT NULL DEFAULT '' COMMENT '维度取值（如状态值、顶级类别ID）',
+  `dim_label` varchar(100) NOT NULL DEFAULT '' COMMENT '维度取值名称',
+  `value` bigint NOT NULL DEFAULT '0' COMMENT '计数',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_catalog_stat` (`stat_date`, `dimension`, `dim_key`),
+  KEY `idx_catalog_stat_dimension` (`dimension`, `dim_key`, `stat_date`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='资源目录统计快照';
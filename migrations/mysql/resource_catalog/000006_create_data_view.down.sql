@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `data_view_column`;
+DROP TABLE IF EXISTS `data_view`;
@@ -0,0 +1,33 @@
+-- 数据视图：元数据采集（job sync_data）从源数据库读取的表及视图，按源、schema、表名唯一
+CREATE TABLE IF NOT EXISTS `data_view` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `source` varchar(100) NOT NULL COMMENT '采集源名称',
+  `schema_name` varchar(100) NOT NULL COMMENT 'schema（MySQL 为库名）',
+  `table_name` varchar(191) NOT NULL COMMENT '表名',
+  `table_type` varchar(20) NOT NULL COMMENT '类型(table/view)',
+  `comment` varchar(1000) NOT NULL DEFAULT '' COMMENT '表注释',
+  `indexes` text NOT NULL COMMENT '索引(JSON)',
+  `fingerprint` varchar(64) NOT NULL DEFAULT '' COMMENT '结构指纹，增量采集时未变化的表跳过',
+  `status` tinyint NOT NULL DEFAULT '1' COMMENT '状态(1:存在 0:源中已删除)',
+  `harvested_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '最近一次结构变化的采集时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_data_view_table` (`source`, `schema_name`, `table_name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='数据视图';
+
+-- 数据视图的列（每次结构变化时全量替换）
+CREATE TABLE IF NOT EXISTS `data_view_column` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `view_id` bigint NOT NULL COMMENT '数据视图ID',
+  `name` varchar(191) NOT NULL COMMENT '列名',
+  `position` int NOT NULL COMMENT '位置（从 1 开始）',
+  `data_type` varchar(100) NOT NULL COMMENT '类型名',
+  `column_type` varchar(255) NOT NULL COMMENT '完整类型',
+  `nullable` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否可空',
+  `default_value` varchar(500) NOT NULL DEFAULT '' COMMENT '默认值表达式',
+  `comment` varchar(1000) NOT NULL DEFAULT '' COMMENT '列注释',
+  `primary_key` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否主键列',
+  PRIMARY KEY (`id`),
+  KEY `idx_data_view_column_view` (`view_id`, `position`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='数据视图列';
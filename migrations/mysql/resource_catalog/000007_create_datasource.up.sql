@@ -0,0 +1,18 @@
+-- 数据源：外部源数据库的连接信息（元数据采集、数据预览、质量检查），密码加密存储
+CREATE TABLE IF NOT EXISTS `datasource` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `name` varchar(100) NOT NULL COMMENT '名称',
+  `type` varchar(20) NOT NULL COMMENT '类型(mysql/postgres/sqlite)',
+  `host` varchar(255) NOT NULL DEFAULT '' COMMENT '主机',
+  `port` int NOT NULL DEFAULT '0' COMMENT '端口',
+  `database_name` varchar(255) NOT NULL COMMENT '数据库名（sqlite 为文件路径）',
+  `username` varchar(100) NOT NULL DEFAULT '' COMMENT '用户名',
+  `password` varchar(1000) NOT NULL DEFAULT '' COMMENT '密码密文（pkg/kms 加密，含密钥ID）',
+  `options` text NOT NULL COMMENT '连接参数(JSON)',
+  `owner` varchar(100) NOT NULL DEFAULT '' COMMENT '负责人',
+  `description` varchar(500) NOT NULL DEFAULT '' COMMENT '描述',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_datasource_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='数据源';
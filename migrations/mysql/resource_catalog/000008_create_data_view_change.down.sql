@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `data_view_change`;
+ALTER TABLE `data_view` DROP COLUMN `owner`;
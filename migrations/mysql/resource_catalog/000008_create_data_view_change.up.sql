@@ -0,0 +1,19 @@
+-- 数据视图负责人（采集源或数据源的负责人），用于结构变化通知
+ALTER TABLE `data_view` ADD COLUMN `owner` varchar(100) NOT NULL DEFAULT '' COMMENT '负责人' AFTER `status`;
+
+-- 数据视图结构变化：连续两次采集间的表及列变化
+CREATE TABLE IF NOT EXISTS `data_view_change` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `view_id` bigint NOT NULL COMMENT '数据视图ID',
+  `source` varchar(100) NOT NULL COMMENT '采集源名称',
+  `table_name` varchar(300) NOT NULL COMMENT 'schema.table',
+  `change_type` varchar(30) NOT NULL COMMENT '变化类型(column_added/column_dropped/column_renamed/type_changed/nullable_changed/comment_changed/table_comment_changed/table_removed)',
+  `column_name` varchar(191) NOT NULL DEFAULT '' COMMENT '列名（重命名时为新列名，表级变化为空）',
+  `old_value` varchar(1000) NOT NULL DEFAULT '' COMMENT '变化前',
+  `new_value` varchar(1000) NOT NULL DEFAULT '' COMMENT '变化后',
+  `breaking` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否为破坏性变化',
+  `detected_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '发现时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_data_view_change_view` (`view_id`),
+  KEY `idx_data_view_change_detected` (`detected_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='数据视图结构变化';
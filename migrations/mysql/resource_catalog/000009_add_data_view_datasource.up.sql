@@ -0,0 +1,2 @@
+-- 数据视图所在的已注册数据源（采集源引用 datasource 表时记录），用于数据预览
+ALTER TABLE `data_view` ADD COLUMN `datasource` varchar(100) NOT NULL DEFAULT '' COMMENT '已注册数据源名称（为空时采集源直接配置连接）' AFTER `source`;
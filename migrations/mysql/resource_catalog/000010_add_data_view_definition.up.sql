@@ -0,0 +1,4 @@
+-- 视图定义及解析出的血缘（pkg/sqlparse），采集到的视图及 SQL 定义的数据视图使用
+ALTER TABLE `data_view` ADD COLUMN `definition` text NOT NULL COMMENT '视图定义(SELECT 语句)，表为空' AFTER `indexes`;
+ALTER TABLE `data_view` ADD COLUMN `lineage` text NOT NULL COMMENT '定义解析结果：引用的表及输出列的源列(JSON)' AFTER `definition`;
+ALTER TABLE `data_view` ADD COLUMN `lineage_error` varchar(1000) NOT NULL DEFAULT '' COMMENT '定义解析失败原因' AFTER `lineage`;
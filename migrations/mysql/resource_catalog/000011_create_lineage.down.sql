@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `lineage_edge`;
+DROP TABLE IF EXISTS `lineage_node`;
@@ -0,0 +1,35 @@
+-- 血缘节点：源表及列、数据视图、目录资源、API
+CREATE TABLE IF NOT EXISTS `lineage_node` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `node_key` varchar(500) NOT NULL COMMENT '节点键（如 table:crm:public.users、table:crm:public.users#id，小写）',
+  `type` varchar(20) NOT NULL COMMENT '节点类型(table/column/data_view/resource/api)',
+  `name` varchar(500) NOT NULL COMMENT '显示名称',
+  `namespace` varchar(100) NOT NULL DEFAULT '' COMMENT '数据源或采集源名称',
+  `parent_id` bigint NOT NULL DEFAULT '0' COMMENT '列所属的表或数据视图节点ID',
+  `ref_id` bigint NOT NULL DEFAULT '0' COMMENT '关联对象ID（表、数据视图为 data_view.id）',
+  `fingerprint` varchar(64) NOT NULL DEFAULT '' COMMENT '同步时数据视图的结构指纹',
+  `status` tinyint NOT NULL DEFAULT '1' COMMENT '状态(0已删除 1有效)',
+  `description` varchar(500) NOT NULL DEFAULT '' COMMENT '描述',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_lineage_node_key` (`node_key`),
+  KEY `idx_lineage_node_type` (`type`),
+  KEY `idx_lineage_node_parent` (`parent_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='血缘节点';
+
+-- 血缘边：按数据流向（source 流向 target）
+CREATE TABLE IF NOT EXISTS `lineage_edge` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `source_id` bigint NOT NULL COMMENT '上游节点ID',
+  `target_id` bigint NOT NULL COMMENT '下游节点ID',
+  `type` varchar(20) NOT NULL COMMENT '边类型(contains/feeds/derives/publishes/serves)',
+  `origin` varchar(20) NOT NULL COMMENT '来源(harvest/sql/manual)',
+  `owner_id` bigint NOT NULL DEFAULT '0' COMMENT '产生该边的节点ID，重新同步时按此替换；手工登记为 0',
+  `expr` varchar(1000) NOT NULL DEFAULT '' COMMENT '列级边的表达式',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_lineage_edge` (`source_id`, `target_id`, `type`),
+  KEY `idx_lineage_edge_target` (`target_id`),
+  KEY `idx_lineage_edge_owner` (`owner_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='血缘边';
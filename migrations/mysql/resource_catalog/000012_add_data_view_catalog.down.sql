@@ -0,0 +1,4 @@
+ALTER TABLE `data_view` DROP INDEX `idx_data_view_category`;
+ALTER TABLE `data_view` DROP COLUMN `department`;
+ALTER TABLE `data_view` DROP COLUMN `sensitivity`;
+ALTER TABLE `data_view` DROP COLUMN `category_id`;
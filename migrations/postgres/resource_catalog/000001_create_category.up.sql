@@ -0,0 +1,21 @@
+-- 资源类别表
+CREATE TABLE IF NOT EXISTS category (
+  id bigserial NOT NULL,
+  name varchar(100) NOT NULL,
+  code varchar(50) NOT NULL,
+  parent_id bigint NOT NULL DEFAULT 0,
+  level integer NOT NULL DEFAULT 1,
+  sort integer NOT NULL DEFAULT 0,
+  description text,
+  status smallint NOT NULL DEFAULT 1,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  CONSTRAINT uk_code UNIQUE (code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_parent_id ON category (parent_id);
+CREATE INDEX IF NOT EXISTS idx_status ON category (status);
+
+COMMENT ON TABLE category IS '资源类别表';
+COMMENT ON COLUMN category.status IS '状态(1:启用 0:禁用)';
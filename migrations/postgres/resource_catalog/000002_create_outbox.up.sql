@@ -0,0 +1,22 @@
+-- 事件发件箱：业务数据变更时在同一事务中写入，由 Relay 异步投递
+CREATE TABLE IF NOT EXISTS outbox (
+  id bigserial NOT NULL,
+  aggregate_type varchar(50) NOT NULL,
+  aggregate_id varchar(64) NOT NULL,
+  event_type varchar(100) NOT NULL,
+  payload jsonb NOT NULL,
+  headers jsonb NOT NULL,
+  status smallint NOT NULL DEFAULT 0,
+  attempts integer NOT NULL DEFAULT 0,
+  last_error varchar(500) NOT NULL DEFAULT '',
+  next_attempt_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_status ON outbox (status, id);
+
+COMMENT ON TABLE outbox IS '事件发件箱';
+COMMENT ON COLUMN outbox.id IS 'ID（同一聚合按 ID 顺序投递）';
+COMMENT ON COLUMN outbox.status IS '状态(0:待投递 1:已投递)';
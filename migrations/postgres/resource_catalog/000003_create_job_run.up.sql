@@ -0,0 +1,22 @@
+-- 定时任务执行记录：每次执行（定时或手动触发）一行
+CREATE TABLE IF NOT EXISTS job_run (
+  id bigserial NOT NULL,
+  job_name varchar(100) NOT NULL,
+  trigger_type varchar(20) NOT NULL,
+  status varchar(20) NOT NULL,
+  host varchar(100) NOT NULL DEFAULT '',
+  rows_affected bigint NOT NULL DEFAULT 0,
+  message varchar(500) NOT NULL DEFAULT '',
+  error varchar(500) NOT NULL DEFAULT '',
+  started_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_run_name ON job_run (job_name, id);
+CREATE INDEX IF NOT EXISTS idx_job_run_started_at ON job_run (started_at);
+
+COMMENT ON TABLE job_run IS '定时任务执行记录';
+COMMENT ON COLUMN job_run.trigger_type IS '触发方式(cron/manual)';
+COMMENT ON COLUMN job_run.status IS '状态(running/success/failed/timeout)';
+COMMENT ON COLUMN job_run.finished_at IS '结束时间（执行中时等于开始时间）';
@@ -0,0 +1,13 @@
+-- 分布式锁租约（pkg/lock 数据库实现）：行不删除，token 单调递增作为 fencing token
+CREATE TABLE IF NOT EXISTS distributed_lock (
+  name varchar(191) NOT NULL,
+  owner varchar(100) NOT NULL DEFAULT '',
+  token bigint NOT NULL DEFAULT 0,
+  expires_at bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (name)
+);
+
+COMMENT ON TABLE distributed_lock IS '分布式锁租约';
+COMMENT ON COLUMN distributed_lock.owner IS '持有者（空表示已释放）';
+COMMENT ON COLUMN distributed_lock.token IS 'fencing token，每次获取加 1';
+COMMENT ON COLUMN distributed_lock.expires_at IS '租约到期时间（Unix 毫秒）';
@@ -0,0 +1,19 @@
+-- 资源目录统计快照：统计任务每次执行写入当日各维度的计数（同一天重复执行时覆盖）
+CREATE TABLE IF NOT EXISTS catalog_stat (
+  id bigserial NOT NULL,
+  stat_date varchar(10) NOT NULL,
+  dimension varchar(50) NOT NULL,
+  dim_key varchar(100) NOT NULL DEFAULT '',
+  dim_label varchar(100) NOT NULL DEFAULT '',
+  value bigint NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_catalog_stat ON catalog_stat (stat_date, dimension, dim_key);
+CREATE INDEX IF NOT EXISTS idx_catalog_stat_dimension ON catalog_stat (dimension, dim_key, stat_date);
+
+COMMENT ON TABLE catalog_stat IS '资源目录统计快照';
+COMMENT ON COLUMN catalog_stat.stat_date IS '统计日期(YYYY-MM-DD)';
+COMMENT ON COLUMN catalog_stat.dimension IS '维度(total/status/level/subtree/created)';
+COMMENT ON COLUMN catalog_stat.dim_key IS '维度取值（如状态值、顶级类别ID）';
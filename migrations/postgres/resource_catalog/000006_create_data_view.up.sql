@@ -0,0 +1,43 @@
+-- 数据视图：元数据采集（job sync_data）从源数据库读取的表及视图，按源、schema、表名唯一
+CREATE TABLE IF NOT EXISTS data_view (
+  id bigserial NOT NULL,
+  source varchar(100) NOT NULL,
+  schema_name varchar(100) NOT NULL,
+  table_name varchar(191) NOT NULL,
+  table_type varchar(20) NOT NULL,
+  comment varchar(1000) NOT NULL DEFAULT '',
+  indexes text NOT NULL,
+  fingerprint varchar(64) NOT NULL DEFAULT '',
+  status smallint NOT NULL DEFAULT 1,
+  harvested_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_data_view_table ON data_view (source, schema_name, table_name);
+
+COMMENT ON TABLE data_view IS '数据视图';
+COMMENT ON COLUMN data_view.table_type IS '类型(table/view)';
+COMMENT ON COLUMN data_view.indexes IS '索引(JSON)';
+COMMENT ON COLUMN data_view.fingerprint IS '结构指纹，增量采集时未变化的表跳过';
+COMMENT ON COLUMN data_view.status IS '状态(1:存在 0:源中已删除)';
+
+-- 数据视图的列（每次结构变化时全量替换）
+CREATE TABLE IF NOT EXISTS data_view_column (
+  id bigserial NOT NULL,
+  view_id bigint NOT NULL,
+  name varchar(191) NOT NULL,
+  position integer NOT NULL,
+  data_type varchar(100) NOT NULL,
+  column_type varchar(255) NOT NULL,
+  nullable boolean NOT NULL DEFAULT false,
+  default_value varchar(500) NOT NULL DEFAULT '',
+  comment varchar(1000) NOT NULL DEFAULT '',
+  primary_key boolean NOT NULL DEFAULT false,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_view_column_view ON data_view_column (view_id, position);
+
+COMMENT ON TABLE data_view_column IS '数据视图列';
@@ -0,0 +1,25 @@
+-- 数据源：外部源数据库的连接信息（元数据采集、数据预览、质量检查），密码加密存储
+CREATE TABLE IF NOT EXISTS datasource (
+  id bigserial NOT NULL,
+  name varchar(100) NOT NULL,
+  type varchar(20) NOT NULL,
+  host varchar(255) NOT NULL DEFAULT '',
+  port integer NOT NULL DEFAULT 0,
+  database_name varchar(255) NOT NULL,
+  username varchar(100) NOT NULL DEFAULT '',
+  password varchar(1000) NOT NULL DEFAULT '',
+  options text NOT NULL,
+  owner varchar(100) NOT NULL DEFAULT '',
+  description varchar(500) NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_datasource_name ON datasource (name);
+
+COMMENT ON TABLE datasource IS '数据源';
+COMMENT ON COLUMN datasource.type IS '类型(mysql/postgres/sqlite)';
+COMMENT ON COLUMN datasource.database_name IS '数据库名（sqlite 为文件路径）';
+COMMENT ON COLUMN datasource.password IS '密码密文（pkg/kms 加密，含密钥ID）';
+COMMENT ON COLUMN datasource.options IS '连接参数(JSON)';
@@ -0,0 +1,27 @@
+-- 数据视图负责人（采集源或数据源的负责人），用于结构变化通知
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS owner varchar(100) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN data_view.owner IS '负责人';
+
+-- 数据视图结构变化：连续两次采集间的表及列变化
+CREATE TABLE IF NOT EXISTS data_view_change (
+  id bigserial NOT NULL,
+  view_id bigint NOT NULL,
+  source varchar(100) NOT NULL,
+  table_name varchar(300) NOT NULL,
+  change_type varchar(30) NOT NULL,
+  column_name varchar(191) NOT NULL DEFAULT '',
+  old_value varchar(1000) NOT NULL DEFAULT '',
+  new_value varchar(1000) NOT NULL DEFAULT '',
+  breaking boolean NOT NULL DEFAULT false,
+  detected_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_view_change_view ON data_view_change (view_id);
+CREATE INDEX IF NOT EXISTS idx_data_view_change_detected ON data_view_change (detected_at);
+
+COMMENT ON TABLE data_view_change IS '数据视图结构变化';
+COMMENT ON COLUMN data_view_change.table_name IS 'schema.table';
+COMMENT ON COLUMN data_view_change.change_type IS '变化类型(column_added/column_dropped/column_renamed/type_changed/nullable_changed/comment_changed/table_comment_changed/table_removed)';
+COMMENT ON COLUMN data_view_change.breaking IS '是否为破坏性变化';
@@ -0,0 +1,3 @@
+ALTER TABLE data_view DROP COLUMN IF EXISTS lineage_error;
+ALTER TABLE data_view DROP COLUMN IF EXISTS lineage;
+ALTER TABLE data_view DROP COLUMN IF EXISTS definition;
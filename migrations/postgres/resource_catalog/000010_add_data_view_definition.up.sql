@@ -0,0 +1,8 @@
+-- 视图定义及解析出的血缘（pkg/sqlparse），采集到的视图及 SQL 定义的数据视图使用
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS definition text NOT NULL DEFAULT '';
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS lineage text NOT NULL DEFAULT '';
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS lineage_error varchar(1000) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN data_view.definition IS '视图定义(SELECT 语句)，表为空';
+COMMENT ON COLUMN data_view.lineage IS '定义解析结果：引用的表及输出列的源列(JSON)';
+COMMENT ON COLUMN data_view.lineage_error IS '定义解析失败原因';
@@ -0,0 +1,49 @@
+-- 血缘节点：源表及列、数据视图、目录资源、API
+CREATE TABLE IF NOT EXISTS lineage_node (
+  id bigserial NOT NULL,
+  node_key varchar(500) NOT NULL,
+  type varchar(20) NOT NULL,
+  name varchar(500) NOT NULL,
+  namespace varchar(100) NOT NULL DEFAULT '',
+  parent_id bigint NOT NULL DEFAULT 0,
+  ref_id bigint NOT NULL DEFAULT 0,
+  fingerprint varchar(64) NOT NULL DEFAULT '',
+  status smallint NOT NULL DEFAULT 1,
+  description varchar(500) NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_lineage_node_key ON lineage_node (node_key);
+CREATE INDEX IF NOT EXISTS idx_lineage_node_type ON lineage_node (type);
+CREATE INDEX IF NOT EXISTS idx_lineage_node_parent ON lineage_node (parent_id);
+
+COMMENT ON TABLE lineage_node IS '血缘节点';
+COMMENT ON COLUMN lineage_node.node_key IS '节点键（如 table:crm:public.users、table:crm:public.users#id，小写）';
+COMMENT ON COLUMN lineage_node.type IS '节点类型(table/column/data_view/resource/api)';
+COMMENT ON COLUMN lineage_node.parent_id IS '列所属的表或数据视图节点ID';
+COMMENT ON COLUMN lineage_node.ref_id IS '关联对象ID（表、数据视图为 data_view.id）';
+COMMENT ON COLUMN lineage_node.status IS '状态(0已删除 1有效)';
+
+-- 血缘边：按数据流向（source 流向 target）
+CREATE TABLE IF NOT EXISTS lineage_edge (
+  id bigserial NOT NULL,
+  source_id bigint NOT NULL,
+  target_id bigint NOT NULL,
+  type varchar(20) NOT NULL,
+  origin varchar(20) NOT NULL,
+  owner_id bigint NOT NULL DEFAULT 0,
+  expr varchar(1000) NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_lineage_edge ON lineage_edge (source_id, target_id, type);
+CREATE INDEX IF NOT EXISTS idx_lineage_edge_target ON lineage_edge (target_id);
+CREATE INDEX IF NOT EXISTS idx_lineage_edge_owner ON lineage_edge (owner_id);
+
+COMMENT ON TABLE lineage_edge IS '血缘边';
+COMMENT ON COLUMN lineage_edge.type IS '边类型(contains/feeds/derives/publishes/serves)';
+COMMENT ON COLUMN lineage_edge.origin IS '来源(harvest/sql/manual)';
+COMMENT ON COLUMN lineage_edge.owner_id IS '产生该边的节点ID，重新同步时按此替换；手工登记为 0';
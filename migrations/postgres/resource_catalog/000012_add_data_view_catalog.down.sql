@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_data_view_category;
+ALTER TABLE data_view DROP COLUMN IF EXISTS department;
+ALTER TABLE data_view DROP COLUMN IF EXISTS sensitivity;
+ALTER TABLE data_view DROP COLUMN IF EXISTS category_id;
@@ -0,0 +1,9 @@
+-- 数据视图的编目属性（所属类别、敏感级别、所属部门），由 API 维护，采集不覆盖；统计任务按其统计目录资源
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS category_id bigint NOT NULL DEFAULT 0;
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS sensitivity varchar(20) NOT NULL DEFAULT '';
+ALTER TABLE data_view ADD COLUMN IF NOT EXISTS department varchar(100) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_data_view_category ON data_view (category_id);
+
+COMMENT ON COLUMN data_view.category_id IS '所属类别ID，0 表示未编目';
+COMMENT ON COLUMN data_view.sensitivity IS '敏感级别：public/internal/confidential/secret，为空表示未定级';
+COMMENT ON COLUMN data_view.department IS '所属部门';
@@ -0,0 +1,17 @@
+-- 资源类别表
+CREATE TABLE IF NOT EXISTS category (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name varchar(100) NOT NULL,
+  code varchar(50) NOT NULL,
+  parent_id bigint NOT NULL DEFAULT 0,
+  level integer NOT NULL DEFAULT 1,
+  sort integer NOT NULL DEFAULT 0,
+  description text,
+  status tinyint NOT NULL DEFAULT 1, -- 状态(1:启用 0:禁用)
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_code ON category (code);
+CREATE INDEX IF NOT EXISTS idx_parent_id ON category (parent_id);
+CREATE INDEX IF NOT EXISTS idx_status ON category (status);
@@ -0,0 +1,17 @@
+-- 事件发件箱：业务数据变更时在同一事务中写入，由 Relay 异步投递
+CREATE TABLE IF NOT EXISTS outbox (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT, -- 同一聚合按 ID 顺序投递
+  aggregate_type varchar(50) NOT NULL,
+  aggregate_id varchar(64) NOT NULL,
+  event_type varchar(100) NOT NULL,
+  payload text NOT NULL,
+  headers text NOT NULL,
+  status tinyint NOT NULL DEFAULT 0, -- 状态(0:待投递 1:已投递)
+  attempts integer NOT NULL DEFAULT 0,
+  last_error varchar(500) NOT NULL DEFAULT '',
+  next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_status ON outbox (status, id);
@@ -0,0 +1,16 @@
+-- 定时任务执行记录：每次执行（定时或手动触发）一行
+CREATE TABLE IF NOT EXISTS job_run (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  job_name varchar(100) NOT NULL,
+  trigger_type varchar(20) NOT NULL, -- 触发方式(cron/manual)
+  status varchar(20) NOT NULL, -- 状态(running/success/failed/timeout)
+  host varchar(100) NOT NULL DEFAULT '',
+  rows_affected bigint NOT NULL DEFAULT 0,
+  message varchar(500) NOT NULL DEFAULT '',
+  error varchar(500) NOT NULL DEFAULT '',
+  started_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_run_name ON job_run (job_name, id);
+CREATE INDEX IF NOT EXISTS idx_job_run_started_at ON job_run (started_at);
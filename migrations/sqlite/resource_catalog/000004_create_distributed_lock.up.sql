@@ -0,0 +1,7 @@
+-- 分布式锁租约（pkg/lock 数据库实现）：行不删除，token 单调递增作为 fencing token
+CREATE TABLE IF NOT EXISTS distributed_lock (
+  name varchar(191) NOT NULL PRIMARY KEY,
+  owner varchar(100) NOT NULL DEFAULT '', -- 持有者（空表示已释放）
+  token bigint NOT NULL DEFAULT 0, -- fencing token，每次获取加 1
+  expires_at bigint NOT NULL DEFAULT 0 -- 租约到期时间（Unix 毫秒）
+);
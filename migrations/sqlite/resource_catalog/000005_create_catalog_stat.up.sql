@@ -0,0 +1,13 @@
+-- 资源目录统计快照：统计任务每次执行写入当日各维度的计数（同一天重复执行时覆盖）
+CREATE TABLE IF NOT EXISTS catalog_stat (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  stat_date varchar(10) NOT NULL, -- 统计日期(YYYY-MM-DD)
+  dimension varchar(50) NOT NULL, -- 维度(total/status/level/subtree/created)
+  dim_key varchar(100) NOT NULL DEFAULT '',
+  dim_label varchar(100) NOT NULL DEFAULT '',
+  value bigint NOT NULL DEFAULT 0,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_catalog_stat ON catalog_stat (stat_date, dimension, dim_key);
+CREATE INDEX IF NOT EXISTS idx_catalog_stat_dimension ON catalog_stat (dimension, dim_key, stat_date);
@@ -0,0 +1,33 @@
+-- 数据视图：元数据采集（job sync_data）从源数据库读取的表及视图，按源、schema、表名唯一
+CREATE TABLE IF NOT EXISTS data_view (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  source varchar(100) NOT NULL,
+  schema_name varchar(100) NOT NULL,
+  table_name varchar(191) NOT NULL,
+  table_type varchar(20) NOT NULL, -- 类型(table/view)
+  comment varchar(1000) NOT NULL DEFAULT '',
+  indexes text NOT NULL, -- 索引(JSON)
+  fingerprint varchar(64) NOT NULL DEFAULT '',
+  status tinyint NOT NULL DEFAULT 1, -- 状态(1:存在 0:源中已删除)
+  harvested_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_data_view_table ON data_view (source, schema_name, table_name);
+
+-- 数据视图的列（每次结构变化时全量替换）
+CREATE TABLE IF NOT EXISTS data_view_column (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  view_id bigint NOT NULL,
+  name varchar(191) NOT NULL,
+  position integer NOT NULL,
+  data_type varchar(100) NOT NULL,
+  column_type varchar(255) NOT NULL,
+  nullable boolean NOT NULL DEFAULT 0,
+  default_value varchar(500) NOT NULL DEFAULT '',
+  comment varchar(1000) NOT NULL DEFAULT '',
+  primary_key boolean NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_view_column_view ON data_view_column (view_id, position);
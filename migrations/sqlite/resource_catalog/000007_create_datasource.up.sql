@@ -0,0 +1,18 @@
+-- 数据源：外部源数据库的连接信息（元数据采集、数据预览、质量检查），密码加密存储
+CREATE TABLE IF NOT EXISTS datasource (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name varchar(100) NOT NULL,
+  type varchar(20) NOT NULL, -- 类型(mysql/postgres/sqlite)
+  host varchar(255) NOT NULL DEFAULT '',
+  port integer NOT NULL DEFAULT 0,
+  database_name varchar(255) NOT NULL, -- sqlite 为文件路径
+  username varchar(100) NOT NULL DEFAULT '',
+  password varchar(1000) NOT NULL DEFAULT '', -- 密码密文（pkg/kms 加密，含密钥ID）
+  options text NOT NULL, -- 连接参数(JSON)
+  owner varchar(100) NOT NULL DEFAULT '',
+  description varchar(500) NOT NULL DEFAULT '',
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_datasource_name ON datasource (name);
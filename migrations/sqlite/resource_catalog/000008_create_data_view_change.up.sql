@@ -0,0 +1,19 @@
+-- 数据视图负责人（采集源或数据源的负责人），用于结构变化通知
+ALTER TABLE data_view ADD COLUMN owner varchar(100) NOT NULL DEFAULT '';
+
+-- 数据视图结构变化：连续两次采集间的表及列变化
+CREATE TABLE IF NOT EXISTS data_view_change (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  view_id bigint NOT NULL,
+  source varchar(100) NOT NULL,
+  table_name varchar(300) NOT NULL, -- schema.table
+  change_type varchar(30) NOT NULL, -- column_added/column_dropped/column_renamed/type_changed/nullable_changed/comment_changed/table_comment_changed/table_removed
+  column_name varchar(191) NOT NULL DEFAULT '',
+  old_value varchar(1000) NOT NULL DEFAULT '',
+  new_value varchar(1000) NOT NULL DEFAULT '',
+  breaking boolean NOT NULL DEFAULT 0, -- 是否为破坏性变化
+  detected_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_view_change_view ON data_view_change (view_id);
+CREATE INDEX IF NOT EXISTS idx_data_view_change_detected ON data_view_change (detected_at);
@@ -0,0 +1 @@
+ALTER TABLE data_view DROP COLUMN datasource;
@@ -0,0 +1,3 @@
+ALTER TABLE data_view DROP COLUMN lineage_error;
+ALTER TABLE data_view DROP COLUMN lineage;
+ALTER TABLE data_view DROP COLUMN definition;
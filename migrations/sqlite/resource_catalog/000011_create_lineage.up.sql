@@ -0,0 +1,35 @@
+-- 血缘节点：源表及列、数据视图、目录资源、API
+CREATE TABLE IF NOT EXISTS lineage_node (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  node_key varchar(500) NOT NULL, -- 如 table:crm:public.users、table:crm:public.users#id，小写
+  type varchar(20) NOT NULL, -- table/column/data_view/resource/api
+  name varchar(500) NOT NULL,
+  namespace varchar(100) NOT NULL DEFAULT '',
+  parent_id bigint NOT NULL DEFAULT 0, -- 列所属的表或数据视图节点ID
+  ref_id bigint NOT NULL DEFAULT 0, -- 表、数据视图为 data_view.id
+  fingerprint varchar(64) NOT NULL DEFAULT '',
+  status integer NOT NULL DEFAULT 1, -- 0已删除 1有效
+  description varchar(500) NOT NULL DEFAULT '',
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_lineage_node_key ON lineage_node (node_key);
+CREATE INDEX IF NOT EXISTS idx_lineage_node_type ON lineage_node (type);
+CREATE INDEX IF NOT EXISTS idx_lineage_node_parent ON lineage_node (parent_id);
+
+-- 血缘边：按数据流向（source 流向 target）
+CREATE TABLE IF NOT EXISTS lineage_edge (
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  source_id bigint NOT NULL,
+  target_id bigint NOT NULL,
+  type varchar(20) NOT NULL, -- contains/feeds/derives/publishes/serves
+  origin varchar(20) NOT NULL, -- harvest/sql/manual
+  owner_id bigint NOT NULL DEFAULT 0, -- 产生该边的节点ID，手工登记为 0
+  expr varchar(1000) NOT NULL DEFAULT '',
+  created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_lineage_edge ON lineage_edge (source_id, target_id, type);
+CREATE INDEX IF NOT EXISTS idx_lineage_edge_target ON lineage_edge (target_id);
+CREATE INDEX IF NOT EXISTS idx_lineage_edge_owner ON lineage_edge (owner_id);
@@ -0,0 +1,5 @@
+-- 数据视图的编目属性（所属类别、敏感级别、所属部门），由 API 维护，采集不覆盖；统计任务按其统计目录资源
+ALTER TABLE data_view ADD COLUMN category_id bigint NOT NULL DEFAULT 0; -- 所属类别ID，0 表示未编目
+ALTER TABLE data_view ADD COLUMN sensitivity varchar(20) NOT NULL DEFAULT ''; -- 敏感级别：public/internal/confidential/secret，为空表示未定级
+ALTER TABLE data_view ADD COLUMN department varchar(100) NOT NULL DEFAULT ''; -- 所属部门
+CREATE INDEX IF NOT EXISTS idx_data_view_category ON data_view (category_id);
@@ -0,0 +1,245 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"idrm/pkg/cache"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 缓存 key（cache.Cache 会再加上 KeyPrefix 及名称前缀）
+const (
+	cacheKeyAll = "all"
+)
+
+var _ Model = (*cachedModel)(nil)
+
+// cachedModel 类别模型缓存装饰器
+//
+// FindOne、FindByCode、FindAll、FindByParentId 读穿缓存（List 为分页查询，不缓存）。
+// 写操作成功后删除受影响的 key：记录本身（id、新旧 code）、新旧父节点的子节点列表及全量列表。
+// Trans 内的读直接访问数据库（看到事务内未提交的修改），待删除的 key 在事务提交后统一删除，
+// 回滚时不删除。
+type cachedModel struct {
+	model Model
+	cache *cache.Cache
+
+	// pending 非 nil 表示处于 Trans 中：写操作只记录待删除的 key
+	pending *[]string
+}
+
+// NewCachedModel 为类别模型增加读穿缓存
+func NewCachedModel(model Model, c *cache.Cache) Model {
+	return &cachedModel{model: model, cache: c}
+}
+
+func cacheKeyId(id int64) string {
+	return "id:" + strconv.FormatInt(id, 10)
+}
+
+func cacheKeyCode(code string) string {
+	return "code:" + code
+}
+
+func cacheKeyParent(parentId int64) string {
+	return "parent:" + strconv.FormatInt(parentId, 10)
+}
+
+// Insert 插入类别，删除该 code 的负缓存及所在父节点的列表缓存
+func (m *cachedModel) Insert(ctx context.Context, data *Category) (*Category, error) {
+	result, err := m.model.Insert(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	m.invalidate(ctx, cacheKeyId(result.Id), cacheKeyCode(result.Code), cacheKeyParent(result.ParentId), cacheKeyAll)
+	return result, nil
+}
+
+// FindOne 根据ID查找类别
+func (m *cachedModel) FindOne(ctx context.Context, id int64) (*Category, error) {
+	if m.inTrans() {
+		return m.model.FindOne(ctx, id)
+	}
+
+	var data Category
+	err := m.cache.Take(ctx, cacheKeyId(id), &data, func(ctx context.Context) (interface{}, error) {
+		data, err := m.model.FindOne(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return data, err
+	})
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindByCode 根据code查找类别，不存在时返回 nil
+func (m *cachedModel) FindByCode(ctx context.Context, code string) (*Category, error) {
+	if m.inTrans() {
+		return m.model.FindByCode(ctx, code)
+	}
+
+	var data Category
+	err := m.cache.Take(ctx, cacheKeyCode(code), &data, func(ctx context.Context) (interface{}, error) {
+		return m.model.FindByCode(ctx, code)
+	})
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Update 更新类别
+func (m *cachedModel) Update(ctx context.Context, data *Category) error {
+	keys, err := m.rowKeys(ctx, data.Id)
+	if err != nil {
+		return err
+	}
+	if err := m.model.Update(ctx, data); err != nil {
+		return err
+	}
+	m.invalidate(ctx, append(keys, cacheKeyCode(data.Code), cacheKeyParent(data.ParentId))...)
+	return nil
+}
+
+// UpdateFields 按字段掩码更新（修改 parent_id 即移动节点，同时删除新旧父节点的列表缓存）
+func (m *cachedModel) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return m.model.UpdateFields(ctx, id, fields)
+	}
+
+	keys, err := m.rowKeys(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := m.model.UpdateFields(ctx, id, fields); err != nil {
+		return err
+	}
+
+	// 新值类型由调用方决定，删除前重新读取，确保新 code、新父节点的缓存同样失效
+	if updated, err := m.model.FindOne(ctx, id); err == nil {
+		keys = append(keys, cacheKeyCode(updated.Code), cacheKeyParent(updated.ParentId))
+	}
+	m.invalidate(ctx, keys...)
+	return nil
+}
+
+// Delete 删除类别
+func (m *cachedModel) Delete(ctx context.Context, id int64) error {
+	keys, err := m.rowKeys(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := m.model.Delete(ctx, id); err != nil {
+		return err
+	}
+	m.invalidate(ctx, keys...)
+	return nil
+}
+
+// FindAll 查找所有类别（类别树数据来源）
+func (m *cachedModel) FindAll(ctx context.Context) ([]*Category, error) {
+	if m.inTrans() {
+		return m.model.FindAll(ctx)
+	}
+	return m.takeList(ctx, cacheKeyAll, m.model.FindAll)
+}
+
+// FindByParentId 根据父ID查找子类别
+func (m *cachedModel) FindByParentId(ctx context.Context, parentId int64) ([]*Category, error) {
+	if m.inTrans() {
+		return m.model.FindByParentId(ctx, parentId)
+	}
+	return m.takeList(ctx, cacheKeyParent(parentId), func(ctx context.Context) ([]*Category, error) {
+		return m.model.FindByParentId(ctx, parentId)
+	})
+}
+
+// List 分页查询类别列表（不缓存）
+func (m *cachedModel) List(ctx context.Context, page, pageSize int) ([]*Category, int64, error) {
+	return m.model.List(ctx, page, pageSize)
+}
+
+// WithTx 返回使用外部事务的实例
+// 事务由调用方提交，无法得知提交时机：写操作后立即删除缓存，提交前的并发读可能回填旧值（至 TTL 过期），
+// 需要提交后失效时使用 Trans
+func (m *cachedModel) WithTx(tx interface{}) Model {
+	return &cachedModel{model: m.model.WithTx(tx), cache: m.cache}
+}
+
+// Trans 执行事务，提交成功后删除事务内写操作影响的缓存
+func (m *cachedModel) Trans(ctx context.Context, fn func(ctx context.Context, model Model) error) error {
+	// 嵌套事务并入外层，由最外层事务提交后统一删除
+	if m.inTrans() {
+		return m.model.Trans(ctx, func(ctx context.Context, model Model) error {
+			return fn(ctx, &cachedModel{model: model, cache: m.cache, pending: m.pending})
+		})
+	}
+
+	var pending []string
+	err := m.model.Trans(ctx, func(ctx context.Context, model Model) error {
+		return fn(ctx, &cachedModel{model: model, cache: m.cache, pending: &pending})
+	})
+	if err != nil {
+		return err
+	}
+	m.invalidate(ctx, pending...)
+	return nil
+}
+
+// inTrans 是否处于 Trans 中
+func (m *cachedModel) inTrans() bool {
+	return m.pending != nil
+}
+
+// takeList 读穿缓存查询列表，空列表同样缓存
+func (m *cachedModel) takeList(ctx context.Context, key string, query func(ctx context.Context) ([]*Category, error)) ([]*Category, error) {
+	var list []*Category
+	err := m.cache.Take(ctx, key, &list, func(ctx context.Context) (interface{}, error) {
+		return query(ctx)
+	})
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// rowKeys 写操作前读取原记录，返回受影响的 key；记录不存在时只返回 id 及全量列表
+func (m *cachedModel) rowKeys(ctx context.Context, id int64) ([]string, error) {
+	keys := []string{cacheKeyId(id), cacheKeyAll}
+
+	old, err := m.model.FindOne(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(keys, cacheKeyCode(old.Code), cacheKeyParent(old.ParentId)), nil
+}
+
+// invalidate 删除缓存；处于 Trans 中时记录，待提交后删除
+// 删除失败只记录日志：数据已写入，缓存在 TTL 后过期
+func (m *cachedModel) invalidate(ctx context.Context, keys ...string) {
+	if m.inTrans() {
+		*m.pending = append(*m.pending, keys...)
+		return
+	}
+	if err := m.cache.Del(ctx, keys...); err != nil {
+		logx.WithContext(ctx).Errorf("删除类别缓存失败: keys=%v, err=%v", keys, err)
+	}
+}
@@ -0,0 +1,188 @@
+package categorytest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"idrm/model/resource_catalog/category"
+	"idrm/pkg/db"
+	"idrm/pkg/outbox"
+)
+
+func TestModels(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory(
+		&category.Category{Id: 1, Name: "数据资源", Code: "DR", Level: 1, Sort: 2},
+		&category.Category{Id: 2, Name: "业务资源", Code: "BR", Level: 1, Sort: 1},
+		&category.Category{Id: 3, Name: "客户数据", Code: "DR_CUSTOMER", ParentId: 1, Level: 2},
+	)
+	gormModel, _ := NewSQLite(t, db.ORMGorm, "testdata/category.yaml")
+	sqlxModel, _ := NewSQLite(t, db.ORMSqlx, "testdata/category.yaml")
+
+	tests := []struct {
+		name  string
+		model category.Model
+	}{
+		{"内存", memory},
+		{"SQLite-gorm", gormModel},
+		{"SQLite-sqlx", sqlxModel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, total, err := tt.model.List(ctx, 1, 2)
+			if err != nil || total != 3 || len(list) != 2 || list[0].Code != "DR_CUSTOMER" || list[1].Code != "BR" {
+				t.Fatalf("List() = %v, %d, %v, want [DR_CUSTOMER BR] of 3", list, total, err)
+			}
+
+			children, err := tt.model.FindByParentId(ctx, 1)
+			if err != nil || len(children) != 1 || children[0].Id != 3 {
+				t.Errorf("FindByParentId(1) = %v, %v, want [3]", children, err)
+			}
+
+			if _, err := tt.model.FindOne(ctx, 99); !errors.Is(err, category.ErrNotFound) {
+				t.Errorf("FindOne(99) error = %v, want ErrNotFound", err)
+			}
+
+			// 事务回滚后数据不变
+			errRollback := errors.New("rollback")
+			err = tt.model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+				if _, err := tx.Insert(ctx, &category.Category{Name: "临时", Code: "TMP", Level: 1, Status: 1}); err != nil {
+					return err
+				}
+				return errRollback
+			})
+			if !errors.Is(err, errRollback) {
+				t.Fatalf("Trans() error = %v, want rollback", err)
+			}
+			if c, err := tt.model.FindByCode(ctx, "TMP"); err != nil || c != nil {
+				t.Errorf("FindByCode(TMP) after rollback = %v, %v, want nil", c, err)
+			}
+		})
+	}
+}
+
+// TestCachedModel 缓存命中、写后失效及事务提交后失效
+func TestCachedModel(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory(
+		&category.Category{Id: 1, Name: "数据资源", Code: "DR", Level: 1},
+		&category.Category{Id: 2, Name: "客户数据", Code: "DR_CUSTOMER", ParentId: 1, Level: 2},
+	)
+	model := category.NewCachedModel(memory, newCache(t))
+
+	tests := []struct {
+		name  string
+		write func() error // 写操作，nil 表示直接修改底层数据（绕过缓存）
+		want  string       // 写操作后 FindOne(2) 的名称
+	}{
+		{"绕过缓存的修改不可见", func() error {
+			return memory.UpdateFields(ctx, 2, map[string]interface{}{category.FieldName: "绕过"})
+		}, "客户数据"},
+		{"写操作后失效", func() error {
+			return model.UpdateFields(ctx, 2, map[string]interface{}{category.FieldName: "更新"})
+		}, "更新"},
+		{"事务回滚不失效", func() error {
+			err := model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+				if err := tx.UpdateFields(ctx, 2, map[string]interface{}{category.FieldName: "回滚"}); err != nil {
+					return err
+				}
+				// 事务内读取未提交的修改
+				if c, err := tx.FindOne(ctx, 2); err != nil || c.Name != "回滚" {
+					t.Errorf("FindOne(2) in trans = %v, %v, want 回滚", c, err)
+				}
+				return errors.New("rollback")
+			})
+			if err == nil {
+				return errors.New("Trans() error = nil, want rollback")
+			}
+			return nil
+		}, "更新"},
+		{"事务提交后失效", func() error {
+			return model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+				return tx.UpdateFields(ctx, 2, map[string]interface{}{category.FieldName: "提交"})
+			})
+		}, "提交"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := model.FindOne(ctx, 2); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.write(); err != nil {
+				t.Fatal(err)
+			}
+			if c, err := model.FindOne(ctx, 2); err != nil || c.Name != tt.want {
+				t.Errorf("FindOne(2) = %v, %v, want %s", c, err, tt.want)
+			}
+		})
+	}
+
+	// 移动节点后新旧父节点的子节点列表均失效
+	for _, parentId := range []int64{0, 1} {
+		if _, err := model.FindByParentId(ctx, parentId); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := model.UpdateFields(ctx, 2, map[string]interface{}{category.FieldParentId: int64(0)}); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := model.FindByParentId(ctx, 0); err != nil || len(list) != 2 {
+		t.Errorf("FindByParentId(0) after move = %v, %v, want 2 items", list, err)
+	}
+	if list, err := model.FindByParentId(ctx, 1); err != nil || len(list) != 0 {
+		t.Errorf("FindByParentId(1) after move = %v, %v, want empty", list, err)
+	}
+}
+
+// TestEventModel 写操作在同一事务内写入发件箱，回滚时不产生事件
+func TestEventModel(t *testing.T) {
+	ctx := context.Background()
+	inner, conn := NewSQLite(t, db.ORMGorm)
+	model := category.NewEventModel(inner, outbox.NewStore(conn))
+
+	data, err := model.Insert(ctx, &category.Category{Name: "数据资源", Code: "DR", Level: 1, Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := model.UpdateFields(ctx, data.Id, map[string]interface{}{category.FieldName: "数据"}); err != nil {
+		t.Fatal(err)
+	}
+	// 记录不存在时不产生事件
+	if err := model.UpdateFields(ctx, 99, map[string]interface{}{category.FieldName: "不存在"}); err != nil {
+		t.Fatal(err)
+	}
+	err = model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+		if _, err := tx.Insert(ctx, &category.Category{Name: "临时", Code: "TMP", Level: 1}); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	if err == nil {
+		t.Fatal("Trans() error = nil, want rollback")
+	}
+	if err := model.Delete(ctx, data.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []outbox.Message
+	if err := conn.Gorm.Order("id").Find(&msgs).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := []string{category.EventCreated, category.EventUpdated, category.EventDeleted}
+	if len(msgs) != len(want) {
+		t.Fatalf("outbox = %+v, want %v", msgs, want)
+	}
+	for i, msg := range msgs {
+		if msg.EventType != want[i] || msg.AggregateId != strconv.FormatInt(data.Id, 10) {
+			t.Errorf("outbox[%d] = %s %s, want %s %d", i, msg.EventType, msg.AggregateId, want[i], data.Id)
+		}
+	}
+	if !strings.Contains(msgs[1].Payload, `"name":"数据"`) {
+		t.Errorf("updated payload = %s, want new name", msgs[1].Payload)
+	}
+}
@@ -0,0 +1,397 @@
+package categorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"idrm/model/resource_catalog/category"
+)
+
+// Factory 创建待测实现，每次调用返回一个无数据的独立实例
+// begin 开启原生事务并返回可传给 Model.WithTx 的事务对象（nil 表示不支持，跳过相关用例）
+type Factory func(t *testing.T) (model category.Model, begin BeginFunc)
+
+// BeginFunc 开启原生事务，end(true) 提交、end(false) 回滚
+type BeginFunc func(ctx context.Context) (tx interface{}, end func(commit bool) error, err error)
+
+// RunContract 对 category.Model 实现执行一致性测试
+// 所有实现（gorm、sqlx、内存版）须通过同一套用例，保证切换 ORM 不改变业务行为
+func RunContract(t *testing.T, factory Factory) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, ctx context.Context, model category.Model, begin BeginFunc)
+	}{
+		{"插入并回填主键和时间", testInsert},
+		{"零值字段原样保存", testInsertZeroValues},
+		{"编码重复", testDuplicateCode},
+		{"记录不存在", testNotFound},
+		{"更新包含零值", testUpdate},
+		{"按字段更新", testUpdateFields},
+		{"删除", testDelete},
+		{"按排序字段和ID排序", testOrdering},
+		{"分页边界", testPagination},
+		{"事务提交与回滚", testTrans},
+		{"WithTx", testWithTx},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			model, begin := factory(t)
+			tc.fn(t, context.Background(), model, begin)
+		})
+	}
+}
+
+// mustInsert 插入测试数据
+func mustInsert(t *testing.T, ctx context.Context, model category.Model, c category.Category) *category.Category {
+	t.Helper()
+
+	data, err := model.Insert(ctx, &c)
+	if err != nil {
+		t.Fatalf("Insert(%s) error = %v", c.Code, err)
+	}
+	return data
+}
+
+// mustFind 根据ID查询
+func mustFind(t *testing.T, ctx context.Context, model category.Model, id int64) *category.Category {
+	t.Helper()
+
+	data, err := model.FindOne(ctx, id)
+	if err != nil {
+		t.Fatalf("FindOne(%d) error = %v", id, err)
+	}
+	return data
+}
+
+// assertSameFields 比较业务字段（不含时间）
+func assertSameFields(t *testing.T, got, want *category.Category) {
+	t.Helper()
+
+	g, w := *got, *want
+	g.CreatedAt, g.UpdatedAt = w.CreatedAt, w.UpdatedAt
+	if g != w {
+		t.Errorf("got %+v, want %+v", *got, *want)
+	}
+}
+
+// assertCodes 比较列表中的编码顺序
+func assertCodes(t *testing.T, name string, list []*category.Category, want ...string) {
+	t.Helper()
+
+	got := make([]string, len(list))
+	for i, c := range list {
+		got[i] = c.Code
+	}
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s = %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func testInsert(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	data := mustInsert(t, ctx, model, category.Category{
+		Name: "数据资源", Code: "DR", Level: 1, Sort: 3, Description: "描述", Status: category.StatusEnabled,
+	})
+	if data.Id <= 0 {
+		t.Fatalf("Insert() id = %d, want > 0", data.Id)
+	}
+	if data.CreatedAt.IsZero() || data.UpdatedAt.IsZero() {
+		t.Errorf("Insert() created_at = %v, updated_at = %v, want filled", data.CreatedAt, data.UpdatedAt)
+	}
+
+	second := mustInsert(t, ctx, model, category.Category{Name: "业务资源", Code: "BR", Level: 1, Status: category.StatusEnabled})
+	if second.Id <= data.Id {
+		t.Errorf("Insert() id = %d, want > %d", second.Id, data.Id)
+	}
+
+	got := mustFind(t, ctx, model, data.Id)
+	assertSameFields(t, got, data)
+	if got.CreatedAt.IsZero() {
+		t.Errorf("FindOne() created_at is zero")
+	}
+}
+
+func testInsertZeroValues(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	// 状态 0（禁用）、排序 0、空描述不应被替换为默认值
+	data := mustInsert(t, ctx, model, category.Category{Name: "禁用类别", Code: "DISABLED", Level: 1, Status: category.StatusDisabled})
+
+	got := mustFind(t, ctx, model, data.Id)
+	if got.Status != category.StatusDisabled || got.Sort != 0 || got.Description != "" {
+		t.Errorf("FindOne() = %+v, want zero status, sort and description", *got)
+	}
+}
+
+func testDuplicateCode(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	mustInsert(t, ctx, model, category.Category{Name: "数据资源", Code: "DR", Level: 1})
+	other := mustInsert(t, ctx, model, category.Category{Name: "业务资源", Code: "BR", Level: 1})
+
+	if _, err := model.Insert(ctx, &category.Category{Name: "重复", Code: "DR", Level: 1}); !errors.Is(err, category.ErrCodeAlreadyExists) {
+		t.Errorf("Insert(duplicate) error = %v, want ErrCodeAlreadyExists", err)
+	}
+
+	other.Code = "DR"
+	if err := model.Update(ctx, other); !errors.Is(err, category.ErrCodeAlreadyExists) {
+		t.Errorf("Update(duplicate) error = %v, want ErrCodeAlreadyExists", err)
+	}
+	if all, _ := model.FindAll(ctx); len(all) != 2 {
+		t.Errorf("FindAll() len = %d, want 2", len(all))
+	}
+}
+
+func testNotFound(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	if got, err := model.FindOne(ctx, 404); !errors.Is(err, category.ErrNotFound) || got != nil {
+		t.Errorf("FindOne(404) = %v, %v, want nil, ErrNotFound", got, err)
+	}
+	if got, err := model.FindByCode(ctx, "NONE"); err != nil || got != nil {
+		t.Errorf("FindByCode(NONE) = %v, %v, want nil, nil", got, err)
+	}
+	if err := model.Update(ctx, &category.Category{Id: 404, Name: "不存在", Code: "NONE", Level: 1}); err != nil {
+		t.Errorf("Update(404) error = %v, want nil", err)
+	}
+	if err := model.Delete(ctx, 404); err != nil {
+		t.Errorf("Delete(404) error = %v, want nil", err)
+	}
+	if all, err := model.FindAll(ctx); err != nil || len(all) != 0 {
+		t.Errorf("FindAll() = %v, %v, want empty", all, err)
+	}
+	if list, total, err := model.List(ctx, 1, 10); err != nil || len(list) != 0 || total != 0 {
+		t.Errorf("List() = %v, %d, %v, want empty", list, total, err)
+	}
+}
+
+func testUpdate(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	data := mustInsert(t, ctx, model, category.Category{
+		Name: "数据资源", Code: "DR", Level: 1, Sort: 5, Description: "描述", Status: category.StatusEnabled,
+	})
+	before := mustFind(t, ctx, model, data.Id)
+
+	// 全量更新：零值字段同样写入
+	update := &category.Category{Id: data.Id, Name: "数据资源2", Code: "DR2", ParentId: 0, Level: 2}
+	if err := model.Update(ctx, update); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	got := mustFind(t, ctx, model, data.Id)
+	want := *update
+	assertSameFields(t, got, &want)
+	if !got.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("Update() changed created_at from %v to %v", before.CreatedAt, got.CreatedAt)
+	}
+	if got.UpdatedAt.Before(before.UpdatedAt) {
+		t.Errorf("Update() updated_at = %v, want >= %v", got.UpdatedAt, before.UpdatedAt)
+	}
+	if c, err := model.FindByCode(ctx, "DR"); err != nil || c != nil {
+		t.Errorf("FindByCode(old code) = %v, %v, want nil", c, err)
+	}
+}
+
+func testUpdateFields(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	data := mustInsert(t, ctx, model, category.Category{
+		Name: "数据资源", Code: "DR", Level: 1, Sort: 5, Description: "描述", Status: category.StatusEnabled,
+	})
+	mustInsert(t, ctx, model, category.Category{Name: "业务资源", Code: "BR", Level: 1})
+
+	// 只修改列出的字段：禁用并清空描述，其余字段保持不变
+	err := model.UpdateFields(ctx, data.Id, map[string]interface{}{
+		category.FieldStatus:      category.StatusDisabled,
+		category.FieldDescription: "",
+	})
+	if err != nil {
+		t.Fatalf("UpdateFields() error = %v", err)
+	}
+	got := mustFind(t, ctx, model, data.Id)
+	want := *data
+	want.Status, want.Description = category.StatusDisabled, ""
+	assertSameFields(t, got, &want)
+	if got.UpdatedAt.Before(data.UpdatedAt) {
+		t.Errorf("UpdateFields() updated_at = %v, want >= %v", got.UpdatedAt, data.UpdatedAt)
+	}
+
+	tests := []struct {
+		name    string
+		id      int64
+		fields  map[string]interface{}
+		wantErr error
+	}{
+		{"空掩码不修改", data.Id, nil, nil},
+		{"记录不存在", 404, map[string]interface{}{category.FieldSort: 1}, nil},
+		{"不可更新的列", data.Id, map[string]interface{}{"created_at": 0}, category.ErrUnknownField},
+		{"未知列", data.Id, map[string]interface{}{"name; DROP TABLE category": "x"}, category.ErrUnknownField},
+		{"编码重复", data.Id, map[string]interface{}{category.FieldCode: "BR"}, category.ErrCodeAlreadyExists},
+	}
+	for _, tt := range tests {
+		if err := model.UpdateFields(ctx, tt.id, tt.fields); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: UpdateFields() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	assertSameFields(t, mustFind(t, ctx, model, data.Id), &want)
+}
+
+func testDelete(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	data := mustInsert(t, ctx, model, category.Category{Name: "数据资源", Code: "DR", Level: 1})
+	keep := mustInsert(t, ctx, model, category.Category{Name: "业务资源", Code: "BR", Level: 1})
+
+	if err := model.Delete(ctx, data.Id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := model.FindOne(ctx, data.Id); !errors.Is(err, category.ErrNotFound) {
+		t.Errorf("FindOne(deleted) error = %v, want ErrNotFound", err)
+	}
+	mustFind(t, ctx, model, keep.Id)
+
+	// 删除后编码可再次使用
+	mustInsert(t, ctx, model, category.Category{Name: "数据资源", Code: "DR", Level: 1})
+}
+
+func testOrdering(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	root := mustInsert(t, ctx, model, category.Category{Name: "根", Code: "ROOT", Level: 1, Sort: 9})
+	mustInsert(t, ctx, model, category.Category{Name: "子C", Code: "C", ParentId: root.Id, Level: 2, Sort: 2})
+	mustInsert(t, ctx, model, category.Category{Name: "子A", Code: "A", ParentId: root.Id, Level: 2, Sort: 1})
+	mustInsert(t, ctx, model, category.Category{Name: "子B", Code: "B", ParentId: root.Id, Level: 2, Sort: 1})
+	mustInsert(t, ctx, model, category.Category{Name: "其他", Code: "OTHER", Level: 1, Sort: 0})
+
+	all, err := model.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	assertCodes(t, "FindAll()", all, "OTHER", "A", "B", "C", "ROOT")
+
+	children, err := model.FindByParentId(ctx, root.Id)
+	if err != nil {
+		t.Fatalf("FindByParentId() error = %v", err)
+	}
+	assertCodes(t, "FindByParentId()", children, "A", "B", "C")
+
+	if none, err := model.FindByParentId(ctx, 404); err != nil || len(none) != 0 {
+		t.Errorf("FindByParentId(404) = %v, %v, want empty", none, err)
+	}
+}
+
+func testPagination(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	for i, code := range []string{"P1", "P2", "P3", "P4", "P5"} {
+		mustInsert(t, ctx, model, category.Category{Name: "分页" + code, Code: code, Level: 1, Sort: i})
+	}
+
+	tests := []struct {
+		name           string
+		page, pageSize int
+		want           []string
+	}{
+		{"第一页", 1, 2, []string{"P1", "P2"}},
+		{"最后一页不足", 3, 2, []string{"P5"}},
+		{"超出范围", 4, 2, nil},
+		{"页码小于1按第一页", 0, 2, []string{"P1", "P2"}},
+		{"每页数量大于总数", 1, 100, []string{"P1", "P2", "P3", "P4", "P5"}},
+	}
+
+	for _, tt := range tests {
+		list, total, err := model.List(ctx, tt.page, tt.pageSize)
+		if err != nil {
+			t.Errorf("%s: List(%d, %d) error = %v", tt.name, tt.page, tt.pageSize, err)
+			continue
+		}
+		if total != 5 {
+			t.Errorf("%s: List(%d, %d) total = %d, want 5", tt.name, tt.page, tt.pageSize, total)
+		}
+		assertCodes(t, tt.name, list, tt.want...)
+	}
+}
+
+func testTrans(t *testing.T, ctx context.Context, model category.Model, _ BeginFunc) {
+	// 提交：事务内可读到自身写入，提交后对外可见
+	err := model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+		data, err := tx.Insert(ctx, &category.Category{Name: "提交", Code: "COMMIT", Level: 1})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.FindOne(ctx, data.Id); err != nil {
+			return err
+		}
+		data.Name = "提交后更新"
+		return tx.Update(ctx, data)
+	})
+	if err != nil {
+		t.Fatalf("Trans(commit) error = %v", err)
+	}
+	if c, err := model.FindByCode(ctx, "COMMIT"); err != nil || c == nil || c.Name != "提交后更新" {
+		t.Errorf("FindByCode(COMMIT) = %v, %v, want committed row", c, err)
+	}
+
+	// 回滚：fn 返回错误时全部撤销，错误原样返回
+	errRollback := errors.New("rollback")
+	err = model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+		if _, err := tx.Insert(ctx, &category.Category{Name: "回滚", Code: "ROLLBACK", Level: 1}); err != nil {
+			return err
+		}
+		c, err := tx.FindByCode(ctx, "COMMIT")
+		if err != nil || c == nil {
+			return errors.New("committed row not visible in transaction")
+		}
+		if err := tx.Delete(ctx, c.Id); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Trans(rollback) error = %v, want %v", err, errRollback)
+	}
+	if c, err := model.FindByCode(ctx, "ROLLBACK"); err != nil || c != nil {
+		t.Errorf("FindByCode(ROLLBACK) = %v, %v, want nil", c, err)
+	}
+	if c, err := model.FindByCode(ctx, "COMMIT"); err != nil || c == nil {
+		t.Errorf("FindByCode(COMMIT) after rollback = %v, %v, want row kept", c, err)
+	}
+
+	// 事务内编码重复同样返回 ErrCodeAlreadyExists
+	err = model.Trans(ctx, func(ctx context.Context, tx category.Model) error {
+		_, err := tx.Insert(ctx, &category.Category{Name: "重复", Code: "COMMIT", Level: 1})
+		return err
+	})
+	if !errors.Is(err, category.ErrCodeAlreadyExists) {
+		t.Errorf("Trans(duplicate) error = %v, want ErrCodeAlreadyExists", err)
+	}
+}
+
+func testWithTx(t *testing.T, ctx context.Context, model category.Model, begin BeginFunc) {
+	// 不支持的事务对象：返回可用的 Model
+	fallback := model.WithTx(struct{}{})
+	if fallback == nil {
+		t.Fatal("WithTx(unsupported) = nil, want usable model")
+	}
+	mustInsert(t, ctx, fallback, category.Category{Name: "数据资源", Code: "DR", Level: 1})
+
+	if begin == nil {
+		return
+	}
+
+	for _, commit := range []bool{true, false} {
+		tx, end, err := begin(ctx)
+		if err != nil {
+			t.Fatalf("begin() error = %v", err)
+		}
+		code := "TX_ROLLBACK"
+		if commit {
+			code = "TX_COMMIT"
+		}
+		if _, err := model.WithTx(tx).Insert(ctx, &category.Category{Name: "事务", Code: code, Level: 1}); err != nil {
+			_ = end(false)
+			t.Fatalf("WithTx().Insert() error = %v", err)
+		}
+		if err := end(commit); err != nil {
+			t.Fatalf("end(%v) error = %v", commit, err)
+		}
+
+		c, err := model.FindByCode(ctx, code)
+		if err != nil || (c != nil) != commit {
+			t.Errorf("FindByCode(%s) = %v, %v, want exists = %v", code, c, err, commit)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package categorytest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"idrm/migrations"
+	"idrm/model/resource_catalog/category"
+	"idrm/pkg/cache"
+	"idrm/pkg/db"
+	"idrm/pkg/outbox"
+	"idrm/pkg/testkit"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func TestContract_Memory(t *testing.T) {
+	RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+		return NewMemory(), nil
+	})
+}
+
+func TestContract_Gorm(t *testing.T) {
+	RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+		model, conn := NewSQLite(t, db.ORMGorm)
+		return model, beginGorm(conn)
+	})
+}
+
+func TestContract_Sqlx(t *testing.T) {
+	RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+		model, conn := NewSQLite(t, db.ORMSqlx)
+		return model, beginSqlx(conn.DB)
+	})
+}
+
+// TestContract_ModelOverride 默认 ORM 与按模型覆盖（DB.*.Models）选出的实现行为一致
+func TestContract_ModelOverride(t *testing.T) {
+	RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+		conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm,
+			testkit.WithModels(map[string]string{category.ModelName: db.ORMSqlx}))
+		model, err := category.NewModel(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return model, beginSqlx(conn.DB)
+	})
+}
+
+// TestContract_Cached 缓存装饰器不改变业务行为
+func TestContract_Cached(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+			return category.NewCachedModel(NewMemory(), newCache(t)), nil
+		})
+	})
+	t.Run("Sqlx", func(t *testing.T) {
+		RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+			model, conn := NewSQLite(t, db.ORMSqlx)
+			return category.NewCachedModel(model, newCache(t)), beginSqlx(conn.DB)
+		})
+	})
+}
+
+// TestContract_Events 变更事件装饰器不改变业务行为
+func TestContract_Events(t *testing.T) {
+	for _, orm := range []string{db.ORMGorm, db.ORMSqlx} {
+		t.Run(orm, func(t *testing.T) {
+			RunContract(t, func(t *testing.T) (category.Model, BeginFunc) {
+				model, conn := NewSQLite(t, orm)
+				begin := beginSqlx(conn.DB)
+				if orm == db.ORMGorm {
+					begin = beginGorm(conn)
+				}
+				return category.NewEventModel(model, outbox.NewStore(conn)), begin
+			})
+		})
+	}
+}
+
+// newCache 创建只有进程内缓存的实例（TTL 足够长，保证用例内不过期）
+func newCache(t *testing.T) *cache.Cache {
+	t.Helper()
+
+	c, err := cache.New(category.ModelName, cache.Config{LocalTTL: 600, LocalLimit: 1000, RedisTTL: 600, NotFoundTTL: 600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// beginGorm 开启 gorm 事务
+func beginGorm(conn *db.Conn) BeginFunc {
+	return func(ctx context.Context) (interface{}, func(bool) error, error) {
+		tx := conn.Gorm.WithContext(ctx).Begin()
+		return tx, func(commit bool) error {
+			if commit {
+				return tx.Commit().Error
+			}
+			return tx.Rollback().Error
+		}, tx.Error
+	}
+}
+
+// beginSqlx 开启 database/sql 事务，包装为 sqlx.SqlConn 供 WithTx 使用
+func beginSqlx(sqlDB *sql.DB) BeginFunc {
+	return func(ctx context.Context) (interface{}, func(bool) error, error) {
+		tx, err := sqlDB.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlx.NewSqlConnFromSession(sqlx.NewSessionFromTx(tx)), func(commit bool) error {
+			if commit {
+				return tx.Commit()
+			}
+			return tx.Rollback()
+		}, nil
+	}
+}
@@ -0,0 +1,266 @@
+// Package categorytest category.Model 的测试实现：内存版及基于 SQLite 的真实实现
+package categorytest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"idrm/model/resource_catalog/category"
+)
+
+var _ category.Model = (*Memory)(nil)
+
+// Memory 内存版 category.Model，行为与数据库实现保持一致：
+// FindOne 未找到返回 category.ErrNotFound，FindByCode 未找到返回 nil, nil，
+// 列表按 sort、id 升序；code 重复时 Insert 返回 category.ErrCodeAlreadyExists（对应唯一索引）
+type Memory struct {
+	mu     *sync.Mutex
+	data   map[int64]category.Category
+	nextId *int64
+	inTx   bool
+}
+
+// NewMemory 创建内存版 Model，seed 为初始数据（Id 为 0 时自动分配）
+func NewMemory(seed ...*category.Category) *Memory {
+	m := &Memory{
+		mu:     &sync.Mutex{},
+		data:   make(map[int64]category.Category),
+		nextId: new(int64),
+	}
+	for _, c := range seed {
+		if _, err := m.Insert(context.Background(), c); err != nil {
+			panic(err)
+		}
+	}
+	return m
+}
+
+// lock 事务内已持有锁，不再重复加锁
+func (m *Memory) lock() func() {
+	if m.inTx {
+		return func() {}
+	}
+	m.mu.Lock()
+	return m.mu.Unlock
+}
+
+// Insert 插入类别
+func (m *Memory) Insert(ctx context.Context, data *category.Category) (*category.Category, error) {
+	defer m.lock()()
+
+	for _, c := range m.data {
+		if c.Code == data.Code {
+			return nil, category.ErrCodeAlreadyExists
+		}
+	}
+
+	if data.Id == 0 {
+		*m.nextId++
+		data.Id = *m.nextId
+	} else if data.Id > *m.nextId {
+		*m.nextId = data.Id
+	}
+	now := time.Now()
+	data.CreatedAt, data.UpdatedAt = now, now
+
+	m.data[data.Id] = *data
+	return data, nil
+}
+
+// FindOne 根据ID查找类别
+func (m *Memory) FindOne(ctx context.Context, id int64) (*category.Category, error) {
+	defer m.lock()()
+
+	c, ok := m.data[id]
+	if !ok {
+		return nil, category.ErrNotFound
+	}
+	return &c, nil
+}
+
+// FindByCode 根据code查找类别
+func (m *Memory) FindByCode(ctx context.Context, code string) (*category.Category, error) {
+	defer m.lock()()
+
+	for _, c := range m.data {
+		if c.Code == code {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// Update 更新类别（记录不存在时不报错，与数据库实现一致）
+func (m *Memory) Update(ctx context.Context, data *category.Category) error {
+	defer m.lock()()
+
+	old, ok := m.data[data.Id]
+	if !ok {
+		return nil
+	}
+	for _, c := range m.data {
+		if c.Code == data.Code && c.Id != data.Id {
+			return category.ErrCodeAlreadyExists
+		}
+	}
+
+	data.CreatedAt, data.UpdatedAt = old.CreatedAt, time.Now()
+	m.data[data.Id] = *data
+	return nil
+}
+
+// UpdateFields 按字段掩码更新
+func (m *Memory) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if err := category.CheckFields(fields); err != nil {
+		return err
+	}
+
+	defer m.lock()()
+
+	c, ok := m.data[id]
+	if !ok {
+		return nil
+	}
+	for field, value := range fields {
+		if err := setField(&c, field, value); err != nil {
+			return err
+		}
+	}
+	for _, other := range m.data {
+		if other.Code == c.Code && other.Id != id {
+			return category.ErrCodeAlreadyExists
+		}
+	}
+
+	c.UpdatedAt = time.Now()
+	m.data[id] = c
+	return nil
+}
+
+// setField 按列名设置字段值（数值类型按数据库驱动的方式转换）
+func setField(c *category.Category, field string, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	switch field {
+	case category.FieldName, category.FieldCode, category.FieldDescription:
+		if rv.Kind() != reflect.String {
+			return fmt.Errorf("%s: want string, got %T", field, value)
+		}
+		switch field {
+		case category.FieldName:
+			c.Name = rv.String()
+		case category.FieldCode:
+			c.Code = rv.String()
+		default:
+			c.Description = rv.String()
+		}
+	default:
+		if !rv.CanInt() {
+			return fmt.Errorf("%s: want integer, got %T", field, value)
+		}
+		switch n := rv.Int(); field {
+		case category.FieldParentId:
+			c.ParentId = n
+		case category.FieldLevel:
+			c.Level = int(n)
+		case category.FieldSort:
+			c.Sort = int(n)
+		default:
+			c.Status = int(n)
+		}
+	}
+	return nil
+}
+
+// Delete 删除类别
+func (m *Memory) Delete(ctx context.Context, id int64) error {
+	defer m.lock()()
+
+	delete(m.data, id)
+	return nil
+}
+
+// FindAll 查找所有类别
+func (m *Memory) FindAll(ctx context.Context) ([]*category.Category, error) {
+	return m.filter(func(*category.Category) bool { return true }), nil
+}
+
+// FindByParentId 根据父ID查找子类别
+func (m *Memory) FindByParentId(ctx context.Context, parentId int64) ([]*category.Category, error) {
+	return m.filter(func(c *category.Category) bool { return c.ParentId == parentId }), nil
+}
+
+// List 分页查询类别列表
+func (m *Memory) List(ctx context.Context, page, pageSize int) ([]*category.Category, int64, error) {
+	all, _ := m.FindAll(ctx)
+
+	start := category.Offset(page, pageSize)
+	if start > len(all) {
+		start = len(all)
+	}
+	end := start + pageSize
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[start:end], int64(len(all)), nil
+}
+
+// WithTx 内存实现无事务对象，返回自身
+func (m *Memory) WithTx(tx interface{}) category.Model {
+	return m
+}
+
+// Trans 执行事务：fn 返回错误时恢复到执行前的数据
+func (m *Memory) Trans(ctx context.Context, fn func(ctx context.Context, model category.Model) error) error {
+	if m.inTx {
+		return fn(ctx, m)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[int64]category.Category, len(m.data))
+	for id, c := range m.data {
+		snapshot[id] = c
+	}
+	nextId := *m.nextId
+
+	tx := &Memory{mu: m.mu, data: m.data, nextId: m.nextId, inTx: true}
+	if err := fn(ctx, tx); err != nil {
+		for id := range m.data {
+			delete(m.data, id)
+		}
+		for id, c := range snapshot {
+			m.data[id] = c
+		}
+		*m.nextId = nextId
+		return err
+	}
+	return nil
+}
+
+// filter 按条件筛选，结果按 sort、id 升序
+func (m *Memory) filter(match func(*category.Category) bool) []*category.Category {
+	defer m.lock()()
+
+	var list []*category.Category
+	for _, c := range m.data {
+		c := c
+		if match(&c) {
+			list = append(list, &c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Sort != list[j].Sort {
+			return list[i].Sort < list[j].Sort
+		}
+		return list[i].Id < list[j].Id
+	})
+	return list
+}
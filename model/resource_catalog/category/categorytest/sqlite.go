@@ -0,0 +1,25 @@
+package categorytest
+
+import (
+	"testing"
+
+	"idrm/migrations"
+	"idrm/model/resource_catalog/category"
+	"idrm/pkg/db"
+	"idrm/pkg/testkit"
+)
+
+// NewSQLite 创建基于临时 SQLite 数据库的 category.Model（orm 为 db.ORMGorm 或 db.ORMSqlx）
+// 表结构来自内置迁移脚本，fixtures 为可选的 YAML 测试数据文件（见 testkit.LoadFixtures）
+func NewSQLite(t testing.TB, orm string, fixtures ...string) (category.Model, *db.Conn) {
+	t.Helper()
+
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, orm)
+	testkit.LoadFixtures(t, conn, fixtures...)
+
+	model, err := category.NewModel(conn)
+	if err != nil {
+		t.Fatalf("new category model: %v", err)
+	}
+	return model, conn
+}
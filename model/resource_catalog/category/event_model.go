@@ -0,0 +1,162 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"idrm/pkg/db"
+	"idrm/pkg/outbox"
+)
+
+// 变更事件（outbox），事件内容为变更后的类别（删除时为删除前的类别）
+const (
+	AggregateType = "category"
+	EventCreated  = "category.created"
+	EventUpdated  = "category.updated"
+	EventDeleted  = "category.deleted"
+)
+
+var _ Model = (*eventModel)(nil)
+
+// eventModel 类别模型变更事件装饰器
+// 每个写操作在 Trans 中执行，并在同一事务内写入发件箱，业务数据与事件同时提交或回滚；
+// 记录不存在（Update/UpdateFields/Delete 未影响任何行）时不产生事件
+type eventModel struct {
+	model  Model
+	writer outbox.Writer
+
+	inTx bool        // 处于 Trans 或 WithTx 中：直接写入，不再开启事务
+	tx   interface{} // WithTx 传入的外部事务，写入发件箱时放入 context
+}
+
+// NewEventModel 为类别模型增加变更事件
+func NewEventModel(model Model, writer outbox.Writer) Model {
+	return &eventModel{model: model, writer: writer}
+}
+
+// Insert 插入类别
+func (m *eventModel) Insert(ctx context.Context, data *Category) (result *Category, err error) {
+	err = m.transact(ctx, func(ctx context.Context, model Model) error {
+		if result, err = model.Insert(ctx, data); err != nil {
+			return err
+		}
+		return m.write(ctx, EventCreated, result)
+	})
+	return result, err
+}
+
+// FindOne 根据ID查找类别
+func (m *eventModel) FindOne(ctx context.Context, id int64) (*Category, error) {
+	return m.model.FindOne(ctx, id)
+}
+
+// FindByCode 根据code查找类别
+func (m *eventModel) FindByCode(ctx context.Context, code string) (*Category, error) {
+	return m.model.FindByCode(ctx, code)
+}
+
+// Update 更新类别
+func (m *eventModel) Update(ctx context.Context, data *Category) error {
+	return m.transact(ctx, func(ctx context.Context, model Model) error {
+		if err := model.Update(ctx, data); err != nil {
+			return err
+		}
+		return m.writeUpdated(ctx, model, data.Id)
+	})
+}
+
+// UpdateFields 按字段掩码更新
+func (m *eventModel) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return m.model.UpdateFields(ctx, id, fields)
+	}
+	return m.transact(ctx, func(ctx context.Context, model Model) error {
+		if err := model.UpdateFields(ctx, id, fields); err != nil {
+			return err
+		}
+		return m.writeUpdated(ctx, model, id)
+	})
+}
+
+// Delete 删除类别
+func (m *eventModel) Delete(ctx context.Context, id int64) error {
+	return m.transact(ctx, func(ctx context.Context, model Model) error {
+		old, err := model.FindOne(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			return model.Delete(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := model.Delete(ctx, id); err != nil {
+			return err
+		}
+		return m.write(ctx, EventDeleted, old)
+	})
+}
+
+// FindAll 查找所有类别
+func (m *eventModel) FindAll(ctx context.Context) ([]*Category, error) {
+	return m.model.FindAll(ctx)
+}
+
+// FindByParentId 根据父ID查找子类别
+func (m *eventModel) FindByParentId(ctx context.Context, parentId int64) ([]*Category, error) {
+	return m.model.FindByParentId(ctx, parentId)
+}
+
+// List 分页查询类别列表
+func (m *eventModel) List(ctx context.Context, page, pageSize int) ([]*Category, int64, error) {
+	return m.model.List(ctx, page, pageSize)
+}
+
+// WithTx 返回使用外部事务的实例，事件写入同一事务
+func (m *eventModel) WithTx(tx interface{}) Model {
+	txModel := m.model.WithTx(tx)
+	if txModel == m.model {
+		// 不支持的事务对象，底层模型返回自身：同样按非事务处理
+		return m
+	}
+	return &eventModel{model: txModel, writer: m.writer, inTx: true, tx: tx}
+}
+
+// Trans 执行事务，事务内的写操作与事件一同提交
+func (m *eventModel) Trans(ctx context.Context, fn func(ctx context.Context, model Model) error) error {
+	return m.model.Trans(ctx, func(ctx context.Context, model Model) error {
+		return fn(ctx, &eventModel{model: model, writer: m.writer, inTx: true})
+	})
+}
+
+// transact 已在事务中时直接执行，否则开启事务
+func (m *eventModel) transact(ctx context.Context, fn func(ctx context.Context, model Model) error) error {
+	if m.inTx {
+		return fn(ctx, m.model)
+	}
+	return m.model.Trans(ctx, fn)
+}
+
+// writeUpdated 读取更新后的记录写入事件
+func (m *eventModel) writeUpdated(ctx context.Context, model Model, id int64) error {
+	data, err := model.FindOne(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return m.write(ctx, EventUpdated, data)
+}
+
+// write 写入发件箱
+func (m *eventModel) write(ctx context.Context, eventType string, data *Category) error {
+	if m.tx != nil {
+		ctx = db.ContextWithTx(ctx, m.tx)
+	}
+	return m.writer.Write(ctx, outbox.Event{
+		AggregateType: AggregateType,
+		AggregateId:   strconv.FormatInt(data.Id, 10),
+		Type:          eventType,
+		Payload:       data,
+	})
+}
@@ -1,12 +1,16 @@
 package category
 
 import (
-	"database/sql"
+	"fmt"
+
+	"idrm/pkg/db"
 
 	"github.com/zeromicro/go-zero/core/logx"
-	"gorm.io/gorm"
 )
 
+// ModelName 模型名称（用于配置 DB.*.Models 按模型指定ORM）
+const ModelName = "category"
+
 // Factory 类别模型工厂函数类型
 type Factory func(interface{}) Model
 
@@ -25,20 +29,23 @@ func RegisterSqlxFactory(factory Factory) {
 	sqlxFactory = factory
 }
 
-// NewModel 创建Category模型（自动选择ORM）
-// 优先使用gorm（更强大），如果gorm不可用则降级到sqlx
-func NewModel(sqlConn *sql.DB, gormDB *gorm.DB) Model {
-	// 优先使用gorm
-	if gormDB != nil && gormFactory != nil {
+// NewModel 创建Category模型
+// 按配置（DB.*.ORM 及 DB.*.Models.category）选择ORM，对应连接不可用时返回错误，不做降级
+func NewModel(conn *db.Conn) (Model, error) {
+	switch orm := conn.ORMFor(ModelName); orm {
+	case db.ORMGorm:
+		if conn.Gorm == nil || gormFactory == nil {
+			return nil, fmt.Errorf("%s: %w: gorm", ModelName, db.ErrORMNotOpened)
+		}
 		logx.Info("Using GORM for CategoryModel")
-		return gormFactory(gormDB)
+		return gormFactory(conn.Gorm), nil
+	case db.ORMSqlx:
+		if conn.DB == nil || sqlxFactory == nil {
+			return nil, fmt.Errorf("%s: %w: sqlx", ModelName, db.ErrORMNotOpened)
+		}
+		logx.Info("Using SQLx for CategoryModel")
+		return sqlxFactory(conn), nil
+	default:
+		return nil, fmt.Errorf("%s: %w: %q", ModelName, db.ErrUnknownORM, orm)
 	}
-
-	// 降级使用sqlx
-	if sqlConn != nil && sqlxFactory != nil {
-		logx.Info("Using SQLx for CategoryModel (fallback)")
-		return sqlxFactory(sqlConn)
-	}
-
-	panic("no database connection available for CategoryModel")
 }
@@ -0,0 +1,35 @@
+package category
+
+import "fmt"
+
+// 可按字段更新的列（UpdateFields 的键）
+const (
+	FieldName        = "name"
+	FieldCode        = "code"
+	FieldParentId    = "parent_id"
+	FieldLevel       = "level"
+	FieldSort        = "sort"
+	FieldDescription = "description"
+	FieldStatus      = "status"
+)
+
+// updatableFields 允许 UpdateFields 修改的列（主键及时间字段不可修改）
+var updatableFields = map[string]bool{
+	FieldName:        true,
+	FieldCode:        true,
+	FieldParentId:    true,
+	FieldLevel:       true,
+	FieldSort:        true,
+	FieldDescription: true,
+	FieldStatus:      true,
+}
+
+// CheckFields 校验字段掩码中的列均可更新（各 Model 实现共用）
+func CheckFields(fields map[string]interface{}) error {
+	for field := range fields {
+		if !updatableFields[field] {
+			return fmt.Errorf("%w: %s", ErrUnknownField, field)
+		}
+	}
+	return nil
+}
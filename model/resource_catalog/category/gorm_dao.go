@@ -3,8 +3,12 @@ package category
 import (
 	"context"
 	"errors"
+	"time"
+
+	"idrm/pkg/db"
 
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
 var _ Model = (*CategoryDao)(nil)
@@ -21,6 +25,9 @@ func NewCategoryDao(db *gorm.DB) Model {
 // Insert 插入类别
 func (d *CategoryDao) Insert(ctx context.Context, data *Category) (*Category, error) {
 	if err := d.db.WithContext(ctx).Create(data).Error; err != nil {
+		if db.IsDuplicateKey(err) {
+			return nil, ErrCodeAlreadyExists
+		}
 		return nil, err
 	}
 	return data, nil
@@ -32,17 +39,17 @@ func (d *CategoryDao) FindOne(ctx context.Context, id int64) (*Category, error)
 	err := d.db.WithContext(ctx).Where("id = ?", id).First(&category).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
 	return &category, nil
 }
 
-// FindByCode 根据code查找类别
+// FindByCode 根据code查找类别（用于唯一性校验，读主库）
 func (d *CategoryDao) FindByCode(ctx context.Context, code string) (*Category, error) {
 	var category Category
-	err := d.db.WithContext(ctx).Where("code = ?", code).First(&category).Error
+	err := d.db.WithContext(ctx).Clauses(dbresolver.Write).Where("code = ?", code).First(&category).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // code不存在返回nil
@@ -52,9 +59,35 @@ func (d *CategoryDao) FindByCode(ctx context.Context, code string) (*Category, e
 	return &category, nil
 }
 
-// Update 更新类别
+// Update 更新类别（全量更新，零值字段同样写入，不修改创建时间）
 func (d *CategoryDao) Update(ctx context.Context, data *Category) error {
-	return d.db.WithContext(ctx).Updates(data).Error
+	err := d.db.WithContext(ctx).Select("*").Omit("id", "created_at").Updates(data).Error
+	if db.IsDuplicateKey(err) {
+		return ErrCodeAlreadyExists
+	}
+	return err
+}
+
+// UpdateFields 按字段掩码更新（map 形式的 Updates 会写入零值）
+func (d *CategoryDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if err := CheckFields(fields); err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{}, len(fields)+1)
+	for field, value := range fields {
+		values[field] = value
+	}
+	values["updated_at"] = time.Now()
+
+	err := d.db.WithContext(ctx).Model(&Category{}).Where("id = ?", id).Updates(values).Error
+	if db.IsDuplicateKey(err) {
+		return ErrCodeAlreadyExists
+	}
+	return err
 }
 
 // Delete 删除类别
@@ -90,7 +123,7 @@ func (d *CategoryDao) List(ctx context.Context, page, pageSize int) ([]*Category
 	}
 
 	// 分页查询
-	offset := (page - 1) * pageSize
+	offset := Offset(page, pageSize)
 	err := d.db.WithContext(ctx).
 		Offset(offset).
 		Limit(pageSize).
@@ -113,7 +146,7 @@ func (d *CategoryDao) WithTx(tx interface{}) Model {
 func (d *CategoryDao) Trans(ctx context.Context, fn func(ctx context.Context, model Model) error) error {
 	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		txModel := &CategoryDao{db: tx}
-		return fn(ctx, txModel)
+		return fn(db.ContextWithTx(ctx, tx), txModel)
 	})
 }
 
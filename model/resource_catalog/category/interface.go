@@ -10,6 +10,9 @@ type Model interface {
 	FindOne(ctx context.Context, id int64) (*Category, error)
 	FindByCode(ctx context.Context, code string) (*Category, error)
 	Update(ctx context.Context, data *Category) error
+	// UpdateFields 按字段掩码更新：只修改 fields 中列出的列（键为列名，见 Field* 常量），零值同样写入
+	// fields 为空时不做修改，包含不可更新的列时返回 ErrUnknownField
+	UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error
 	Delete(ctx context.Context, id int64) error
 
 	// 列表查询
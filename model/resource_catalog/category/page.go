@@ -0,0 +1,9 @@
+package category
+
+// Offset 分页偏移量，页码小于1时按第一页处理（各实现共用，保证分页行为一致）
+func Offset(page, pageSize int) int {
+	if page < 1 {
+		page = 1
+	}
+	return (page - 1) * pageSize
+}
@@ -4,32 +4,68 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
 
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 var _ Model = (*CategoryModel)(nil)
 
+// sqlxTable sqlx实现使用的表名（须与 Category.TableName 一致，由 schemacheck 校验）
+const sqlxTable = "category"
+
+// categoryRows 查询列
+const categoryRows = "id, name, code, parent_id, level, sort, description, status, created_at, updated_at"
+
 type CategoryModel struct {
-	conn sqlx.SqlConn
+	conn     sqlx.SqlConn // 写操作及事务（主库）
+	readConn sqlx.SqlConn // 只读查询（配置副本时路由到副本）
+	table    string
+	dialect  dialect.Dialect // 占位符改写及主键回填方式
 }
 
-// NewModel 创建Model实例
+// NewCategoryModel 创建Model实例（MySQL，读写均使用同一连接）
 func NewCategoryModel(conn *sql.DB) Model {
+	sqlConn := sqlx.NewSqlConnFromDB(conn)
+	return newCategoryModel(sqlConn, sqlConn, dialect.MySQL)
+}
+
+// newCategoryModel 创建读写分离的Model实例
+func newCategoryModel(conn, readConn sqlx.SqlConn, d dialect.Dialect) *CategoryModel {
 	return &CategoryModel{
-		conn: sqlx.NewSqlConnFromDB(conn),
+		conn:     conn,
+		readConn: readConn,
+		table:    sqlxTable,
+		dialect:  d,
 	}
 }
 
-// Insert 插入类别
+// Insert 插入类别（创建、更新时间由应用写入，与 gorm 实现一致）
 func (m *CategoryModel) Insert(ctx context.Context, data *Category) (*Category, error) {
-	query := `INSERT INTO category (name, code, parent_id, level, sort, description, status) 
-              VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := fmt.Sprintf(`INSERT INTO %s (name, code, parent_id, level, sort, description, status, created_at, updated_at) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, m.table)
+	now := time.Now()
+	args := []interface{}{data.Name, data.Code, data.ParentId, data.Level, data.Sort, data.Description, data.Status, now, now}
+
+	// 支持 RETURNING 的方言（PostgreSQL/SQLite）直接返回主键
+	if m.dialect.SupportsReturning() {
+		query += m.dialect.Returning("id")
+		if err := m.conn.QueryRowCtx(ctx, &data.Id, m.dialect.Rebind(query), args...); err != nil {
+			return nil, translateError(err)
+		}
+		data.CreatedAt, data.UpdatedAt = now, now
+		return data, nil
+	}
 
-	result, err := m.conn.ExecCtx(ctx, query,
-		data.Name, data.Code, data.ParentId, data.Level, data.Sort, data.Description, data.Status)
+	result, err := m.conn.ExecCtx(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	id, err := result.LastInsertId()
@@ -38,19 +74,19 @@ func (m *CategoryModel) Insert(ctx context.Context, data *Category) (*Category,
 	}
 
 	data.Id = id
+	data.CreatedAt, data.UpdatedAt = now, now
 	return data, nil
 }
 
 // FindOne 根据ID查找类别
 func (m *CategoryModel) FindOne(ctx context.Context, id int64) (*Category, error) {
 	var category Category
-	query := `SELECT id, name, code, parent_id, level, sort, description, status, created_at, updated_at 
-              FROM category WHERE id = ? LIMIT 1`
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ? LIMIT 1", categoryRows, m.table)
 
-	err := m.conn.QueryRowCtx(ctx, &category, query, id)
+	err := m.readConn.QueryRowCtx(ctx, &category, m.dialect.Rebind(query), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("category not found")
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
@@ -58,13 +94,12 @@ func (m *CategoryModel) FindOne(ctx context.Context, id int64) (*Category, error
 	return &category, nil
 }
 
-// FindByCode 根据code查找类别
+// FindByCode 根据code查找类别（用于唯一性校验，读主库）
 func (m *CategoryModel) FindByCode(ctx context.Context, code string) (*Category, error) {
 	var category Category
-	query := `SELECT id, name, code, parent_id, level, sort, description, status, created_at, updated_at 
-              FROM category WHERE code = ? LIMIT 1`
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE code = ? LIMIT 1", categoryRows, m.table)
 
-	err := m.conn.QueryRowCtx(ctx, &category, query, code)
+	err := m.conn.QueryRowCtx(ctx, &category, m.dialect.Rebind(query), code)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil // code不存在返回nil
@@ -75,40 +110,68 @@ func (m *CategoryModel) FindByCode(ctx context.Context, code string) (*Category,
 	return &category, nil
 }
 
-// Update 更新类别
+// Update 更新类别（显式刷新 updated_at，PostgreSQL/SQLite 无 ON UPDATE）
 func (m *CategoryModel) Update(ctx context.Context, data *Category) error {
-	query := `UPDATE category SET name = ?, code = ?, parent_id = ?, level = ?, sort = ?, 
-              description = ?, status = ? WHERE id = ?`
+	query := fmt.Sprintf(`UPDATE %s SET name = ?, code = ?, parent_id = ?, level = ?, sort = ?, 
+              description = ?, status = ?, updated_at = ? WHERE id = ?`, m.table)
 
-	_, err := m.conn.ExecCtx(ctx, query,
-		data.Name, data.Code, data.ParentId, data.Level, data.Sort, data.Description, data.Status, data.Id)
-	return err
+	data.UpdatedAt = time.Now()
+	_, err := m.conn.ExecCtx(ctx, m.dialect.Rebind(query),
+		data.Name, data.Code, data.ParentId, data.Level, data.Sort, data.Description, data.Status, data.UpdatedAt, data.Id)
+	return translateError(err)
+}
+
+// UpdateFields 按字段掩码更新（列按名称排序，保证语句稳定）
+func (m *CategoryModel) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	if err := CheckFields(fields); err != nil {
+		return err
+	}
+
+	columns := make([]string, 0, len(fields))
+	for field := range fields {
+		columns = append(columns, field)
+	}
+	sort.Strings(columns)
+
+	sets := make([]string, 0, len(columns)+1)
+	args := make([]interface{}, 0, len(columns)+2)
+	for _, column := range columns {
+		sets = append(sets, column+" = ?")
+		args = append(args, fields[column])
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, time.Now(), id)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ?", m.table, strings.Join(sets, ", "))
+	_, err := m.conn.ExecCtx(ctx, m.dialect.Rebind(query), args...)
+	return translateError(err)
 }
 
 // Delete 删除类别
 func (m *CategoryModel) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM category WHERE id = ?`
-	_, err := m.conn.ExecCtx(ctx, query, id)
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", m.table)
+	_, err := m.conn.ExecCtx(ctx, m.dialect.Rebind(query), id)
 	return err
 }
 
 // FindAll 查找所有类别
 func (m *CategoryModel) FindAll(ctx context.Context) ([]*Category, error) {
 	var categories []*Category
-	query := `SELECT id, name, code, parent_id, level, sort, description, status, created_at, updated_at 
-              FROM category ORDER BY sort ASC, id ASC`
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY sort ASC, id ASC", categoryRows, m.table)
 
-	err := m.conn.QueryRowsCtx(ctx, &categories, query)
+	err := m.readConn.QueryRowsCtx(ctx, &categories, m.dialect.Rebind(query))
 	return categories, err
 }
 
 // FindByParentId 根据父ID查找子类别
 func (m *CategoryModel) FindByParentId(ctx context.Context, parentId int64) ([]*Category, error) {
 	var categories []*Category
-	query := `SELECT id, name, code, parent_id, level, sort, description, status, created_at, updated_at 
-              FROM category WHERE parent_id = ? ORDER BY sort ASC, id ASC`
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE parent_id = ? ORDER BY sort ASC, id ASC", categoryRows, m.table)
 
-	err := m.conn.QueryRowsCtx(ctx, &categories, query, parentId)
+	err := m.readConn.QueryRowsCtx(ctx, &categories, m.dialect.Rebind(query), parentId)
 	return categories, err
 }
 
@@ -118,25 +181,24 @@ func (m *CategoryModel) List(ctx context.Context, page, pageSize int) ([]*Catego
 	var total int64
 
 	// 计算总数
-	countQuery := `SELECT COUNT(*) FROM category`
-	err := m.conn.QueryRowCtx(ctx, &total, countQuery)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", m.table)
+	err := m.readConn.QueryRowCtx(ctx, &total, m.dialect.Rebind(countQuery))
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// 分页查询
-	offset := (page - 1) * pageSize
-	query := `SELECT id, name, code, parent_id, level, sort, description, status, created_at, updated_at 
-              FROM category ORDER BY sort ASC, id ASC LIMIT ? OFFSET ?`
+	offset := Offset(page, pageSize)
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY sort ASC, id ASC LIMIT ? OFFSET ?", categoryRows, m.table)
 
-	err = m.conn.QueryRowsCtx(ctx, &categories, query, pageSize, offset)
+	err = m.readConn.QueryRowsCtx(ctx, &categories, m.dialect.Rebind(query), pageSize, offset)
 	return categories, total, err
 }
 
 // WithTx 返回带事务的Model实例
 func (m *CategoryModel) WithTx(tx interface{}) Model {
 	if sqlxConn, ok := tx.(sqlx.SqlConn); ok {
-		return &CategoryModel{conn: sqlxConn}
+		return newCategoryModel(sqlxConn, sqlxConn, m.dialect)
 	}
 	// 如果不是sqlx连接，返回自身
 	return m
@@ -146,16 +208,27 @@ func (m *CategoryModel) WithTx(tx interface{}) Model {
 func (m *CategoryModel) Trans(ctx context.Context, fn func(ctx context.Context, model Model) error) error {
 	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
 		txConn := sqlx.NewSqlConnFromSession(session)
-		txModel := &CategoryModel{conn: txConn}
-		return fn(ctx, txModel)
+		txModel := newCategoryModel(txConn, txConn, m.dialect) // 事务内读写均使用主库
+		return fn(db.ContextWithTx(ctx, txConn), txModel)
 	})
 }
 
+// translateError 唯一键冲突转换为 ErrCodeAlreadyExists（code 为唯一索引）
+func translateError(err error) error {
+	if db.IsDuplicateKey(err) {
+		return ErrCodeAlreadyExists
+	}
+	return err
+}
+
 // init 注册sqlx工厂
 func init() {
-	RegisterSqlxFactory(func(db interface{}) Model {
-		if sqlDB, ok := db.(*sql.DB); ok {
-			return NewCategoryModel(sqlDB)
+	RegisterSqlxFactory(func(conn interface{}) Model {
+		switch c := conn.(type) {
+		case *db.Conn:
+			return newCategoryModel(c.SqlConn(), c.ReadSqlConn(), c.Dialect())
+		case *sql.DB:
+			return NewCategoryModel(c)
 		}
 		panic("invalid database type for sqlx factory")
 	})
@@ -2,21 +2,23 @@ package category
 
 import "time"
 
-// Category 类别实体（sqlx和gorm共用同一个结构）
+// Category 类别实体（sqlx和gorm共用同一个结构，json 用于缓存及变更事件）
+// 非零默认值（level、status）只在迁移脚本中定义，不使用 gorm default 标签：
+// gorm 插入时会把零值替换为 default，导致无法插入禁用状态（0），且与 sqlx 实现不一致
 type Category struct {
-	Id          int64     `db:"id" gorm:"column:id;primaryKey"`
-	Name        string    `db:"name" gorm:"column:name;type:varchar(100);not null"`
-	Code        string    `db:"code" gorm:"column:code;type:varchar(50);uniqueIndex;not null"`
-	ParentId    int64     `db:"parent_id" gorm:"column:parent_id;index;default:0"`
-	Level       int       `db:"level" gorm:"column:level;default:1"`
-	Sort        int       `db:"sort" gorm:"column:sort;default:0"`
-	Description string    `db:"description" gorm:"column:description;type:text"`
-	Status      int       `db:"status" gorm:"column:status;index;default:1"`
-	CreatedAt   time.Time `db:"created_at" gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time `db:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+	Id          int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	Name        string    `json:"name" db:"name" gorm:"column:name;type:varchar(100);not null"`
+	Code        string    `json:"code" db:"code" gorm:"column:code;type:varchar(50);uniqueIndex;not null"`
+	ParentId    int64     `json:"parent_id" db:"parent_id" gorm:"column:parent_id;index;default:0"`
+	Level       int       `json:"level" db:"level" gorm:"column:level"`
+	Sort        int       `json:"sort" db:"sort" gorm:"column:sort;default:0"`
+	Description string    `json:"description" db:"description" gorm:"column:description;type:text"`
+	Status      int       `json:"status" db:"status" gorm:"column:status;index"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
 }
 
-// TableName gorm表名
+// TableName gorm表名（与 migrations 中的DDL及sqlx查询保持一致）
 func (Category) TableName() string {
-	return "categories"
+	return "category"
 }
@@ -7,6 +7,7 @@ var (
 	ErrNotFound          = errors.New("category not found")
 	ErrCodeAlreadyExists = errors.New("category code already exists")
 	ErrInvalidStatus     = errors.New("invalid status")
+	ErrUnknownField      = errors.New("unknown or non-updatable category field")
 )
 
 // 状态常量
@@ -0,0 +1,129 @@
+package datasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"idrm/pkg/db"
+	"idrm/pkg/kms"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// defaultMaxOpenConns 源数据库最大连接数（只读访问，避免占用源库连接）
+const defaultMaxOpenConns = 2
+
+// ParseOptions 解析并校验连接参数，空字符串视为无参数
+func ParseOptions(options string) (map[string]string, error) {
+	opts := map[string]string{}
+	if options == "" {
+		return opts, nil
+	}
+	if err := json.Unmarshal([]byte(options), &opts); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOption, err)
+	}
+	for key, value := range opts {
+		switch key {
+		case OptionCharset:
+			if !slices.Contains(Charsets, value) {
+				return nil, fmt.Errorf("%w: %s must be one of %s", ErrInvalidOption, key, strings.Join(Charsets, ", "))
+			}
+		case OptionSSLMode:
+			if !slices.Contains(SSLModes, value) {
+				return nil, fmt.Errorf("%w: %s must be one of %s", ErrInvalidOption, key, strings.Join(SSLModes, ", "))
+			}
+		case OptionMaxOpenConns:
+			if n, err := strconv.Atoi(value); err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: %s must be a positive integer", ErrInvalidOption, key)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s (supported: %s, %s, %s)", ErrInvalidOption, key,
+				OptionCharset, OptionSSLMode, OptionMaxOpenConns)
+		}
+	}
+	return opts, nil
+}
+
+// FormatOptions 连接参数序列化为 JSON（键有序）
+func FormatOptions(opts map[string]string) string {
+	if opts == nil {
+		opts = map[string]string{}
+	}
+	data, _ := json.Marshal(opts) // map[string]string 序列化不会失败，键按字典序输出
+	return string(data)
+}
+
+// DBConfig 连接配置，password 为解密后的密码（使用 sqlx，不初始化 GORM）
+func (d *Datasource) DBConfig(password string) (db.Config, error) {
+	opts, err := ParseOptions(d.Options)
+	if err != nil {
+		return db.Config{}, err
+	}
+	var c db.Config
+	if err := conf.FillDefault(&c); err != nil {
+		return db.Config{}, err
+	}
+	c.Driver = d.Type
+	c.Host = d.Host
+	c.Port = d.Port
+	c.Database = d.DatabaseName
+	c.Username = d.Username
+	c.Password = password
+	c.ORM = db.ORMSqlx
+	c.MaxOpenConns = defaultMaxOpenConns
+	c.MaxIdleConns = defaultMaxOpenConns
+	if v, ok := opts[OptionCharset]; ok {
+		c.Charset = v
+	}
+	if v, ok := opts[OptionSSLMode]; ok {
+		c.SSLMode = v
+	}
+	if v, ok := opts[OptionMaxOpenConns]; ok {
+		c.MaxOpenConns, _ = strconv.Atoi(v)
+		c.MaxIdleConns = c.MaxOpenConns
+	}
+	return c, nil
+}
+
+// Connect 解密密码并创建连接（不建立网络连接），使用后须 Close
+func Connect(ctx context.Context, km kms.KeyManager, d *Datasource) (*db.Conn, error) {
+	password, err := Password(ctx, km, d)
+	if err != nil {
+		return nil, err
+	}
+	c, err := d.DBConfig(password)
+	if err != nil {
+		return nil, err
+	}
+	return db.NewConn(c)
+}
+
+// Password 解密密码，未设置密码时返回空字符串
+func Password(ctx context.Context, km kms.KeyManager, d *Datasource) (string, error) {
+	if d.Password == "" {
+		return "", nil
+	}
+	plaintext, err := km.Decrypt(ctx, d.Password)
+	if err != nil {
+		return "", fmt.Errorf("decrypt password of datasource %s: %w", d.Name, err)
+	}
+	return string(plaintext), nil
+}
+
+// SetPassword 加密并设置密码，password 为空时清空
+func SetPassword(ctx context.Context, km kms.KeyManager, d *Datasource, password string) error {
+	if password == "" {
+		d.Password = ""
+		return nil
+	}
+	ciphertext, err := km.Encrypt(ctx, []byte(password))
+	if err != nil {
+		return err
+	}
+	d.Password = ciphertext
+	return nil
+}
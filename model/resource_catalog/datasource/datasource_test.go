@@ -0,0 +1,94 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/kms"
+	"idrm/pkg/testkit"
+)
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options string
+		wantErr bool
+	}{
+		{"空", "", false},
+		{"支持的参数", `{"charset":"utf8","max_open_conns":"5"}`, false},
+		{"不支持的参数", `{"timeout":"5s"}`, true},
+		{"连接数非法", `{"max_open_conns":"0"}`, true},
+		{"字符集不在白名单", `{"charset":"utf8mb4&allowAllFiles=true"}`, true},
+		{"sslmode不在白名单", `{"sslmode":"disable host=evil"}`, true},
+		{"支持的sslmode", `{"sslmode":"verify-full"}`, false},
+		{"非JSON对象", `[1]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOptions(tt.options)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidOption)) {
+				t.Errorf("ParseOptions(%s) error = %v, wantErr %v", tt.options, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestModel(t *testing.T) {
+	ctx := context.Background()
+	km, err := kms.NewLocal(filepath.Join(t.TempDir(), "secret.key"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, orm := range []string{db.ORMGorm, db.ORMSqlx} {
+		t.Run(orm, func(t *testing.T) {
+			m, err := NewModel(testkit.SQLite(t, migrations.ResourceCatalog, orm))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 源为 SQLite 文件库，连接测试无需外部数据库
+			d := &Datasource{Name: "erp", Type: TypeSQLite, DatabaseName: filepath.Join(t.TempDir(), "erp.db"),
+				Options: FormatOptions(nil)}
+			if err := SetPassword(ctx, km, d, "s3cret"); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Insert(ctx, d); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Insert(ctx, &Datasource{Name: "erp", Type: TypeSQLite, DatabaseName: "x", Options: "{}"}); !errors.Is(err, ErrDuplicateName) {
+				t.Errorf("Insert() duplicate error = %v, want ErrDuplicateName", err)
+			}
+
+			got, err := m.FindByName(ctx, "erp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Password == "s3cret" {
+				t.Error("password stored in plaintext")
+			}
+			if password, err := Password(ctx, km, got); err != nil || password != "s3cret" {
+				t.Errorf("Password() = %q, %v, want s3cret", password, err)
+			}
+
+			conn, err := Connect(ctx, km, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if err := conn.Ping(ctx); err != nil {
+				t.Errorf("Ping() error = %v", err)
+			}
+
+			if err := m.Delete(ctx, got.Id); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := m.FindOne(ctx, got.Id); !errors.Is(err, ErrNotFound) {
+				t.Errorf("FindOne() after delete error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
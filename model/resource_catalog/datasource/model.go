@@ -0,0 +1,69 @@
+package datasource
+
+import (
+	"context"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/repo"
+)
+
+// Model 数据源仓储
+type Model interface {
+	// Insert 新增，名称重复时返回 ErrDuplicateName
+	Insert(ctx context.Context, d *Datasource) error
+	// FindOne 根据ID获取，不存在时返回 ErrNotFound
+	FindOne(ctx context.Context, id int64) (*Datasource, error)
+	// FindByName 根据名称获取，不存在时返回 ErrNotFound
+	FindByName(ctx context.Context, name string) (*Datasource, error)
+	// List 分页查询，按ID升序
+	List(ctx context.Context, page, pageSize int) ([]*Datasource, int64, error)
+	// Update 更新全部字段，名称重复时返回 ErrDuplicateName
+	Update(ctx context.Context, d *Datasource) error
+	// Delete 删除
+	Delete(ctx context.Context, id int64) error
+}
+
+type model struct {
+	repo repo.Repository[Datasource]
+}
+
+// NewModel 创建数据源仓储（按配置选择ORM）
+func NewModel(conn *db.Conn) (Model, error) {
+	r, err := repo.New[Datasource](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	return &model{repo: r}, nil
+}
+
+func (m *model) Insert(ctx context.Context, d *Datasource) error {
+	return duplicate(m.repo.Insert(ctx, d))
+}
+
+func (m *model) FindOne(ctx context.Context, id int64) (*Datasource, error) {
+	return m.repo.FindOne(ctx, id)
+}
+
+func (m *model) FindByName(ctx context.Context, name string) (*Datasource, error) {
+	return m.repo.FindOneBy(ctx, repo.Eq("name", name))
+}
+
+func (m *model) List(ctx context.Context, page, pageSize int) ([]*Datasource, int64, error) {
+	return m.repo.List(ctx, repo.Query{Page: page, PageSize: pageSize})
+}
+
+func (m *model) Update(ctx context.Context, d *Datasource) error {
+	return duplicate(m.repo.Update(ctx, d))
+}
+
+func (m *model) Delete(ctx context.Context, id int64) error {
+	return m.repo.Delete(ctx, id)
+}
+
+// duplicate 唯一键冲突转换为 ErrDuplicateName（唯一键只有名称）
+func duplicate(err error) error {
+	if db.IsDuplicateKey(err) {
+		return ErrDuplicateName
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+package datasource
+
+import (
+	"idrm/migrations"
+	"idrm/pkg/db/schemacheck"
+)
+
+// init 注册表结构一致性检查
+func init() {
+	schemacheck.Register(schemacheck.Entity{
+		Database:  migrations.ResourceCatalog,
+		Model:     &Datasource{},
+		SqlxTable: "datasource",
+	})
+}
@@ -0,0 +1,59 @@
+// Package datasource 数据源注册：外部源数据库的连接信息，供元数据采集、数据预览、质量检查使用
+//
+// 密码使用 pkg/kms 加密后存储（Password 为密文），连接时通过 Connect 解密；
+// 对外返回时不包含密码。
+package datasource
+
+import (
+	"time"
+
+	"idrm/pkg/db/dialect"
+)
+
+// ModelName 模型名称（用于配置 DB.*.Models 按模型指定ORM）
+const ModelName = "datasource"
+
+// 数据源类型（与 db.Config.Driver 一致）
+//
+// 接口只能登记 mysql、postgres：sqlite 的库名为服务器上的文件路径，
+// 仅供运维直接写入或测试使用，接口不能创建或修改其路径
+const (
+	TypeMySQL    = string(dialect.MySQL)
+	TypePostgres = string(dialect.Postgres)
+	TypeSQLite   = string(dialect.SQLite)
+)
+
+// 连接参数（Options 中的键）
+const (
+	OptionCharset      = "charset"        // 仅 mysql，默认 utf8mb4
+	OptionSSLMode      = "sslmode"        // 仅 postgres，默认 disable
+	OptionMaxOpenConns = "max_open_conns" // 最大连接数，默认 2
+)
+
+// 连接参数允许的取值（拼入 DSN，只接受白名单）
+var (
+	Charsets = []string{"utf8mb4", "utf8mb3", "utf8", "latin1", "gbk", "gb18030", "ascii", "binary"}
+	SSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+)
+
+// Datasource 数据源
+type Datasource struct {
+	Id           int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	Name         string    `json:"name" db:"name" gorm:"column:name;type:varchar(100);not null;uniqueIndex:uk_datasource_name"`
+	Type         string    `json:"type" db:"type" gorm:"column:type;type:varchar(20);not null"`
+	Host         string    `json:"host" db:"host" gorm:"column:host;type:varchar(255);not null"`
+	Port         int       `json:"port" db:"port" gorm:"column:port;not null"`
+	DatabaseName string    `json:"database_name" db:"database_name" gorm:"column:database_name;type:varchar(255);not null"`
+	Username     string    `json:"username" db:"username" gorm:"column:username;type:varchar(100);not null"`
+	Password     string    `json:"-" db:"password" gorm:"column:password;type:varchar(1000);not null"` // 密文
+	Options      string    `json:"options" db:"options" gorm:"column:options;type:text;not null"`      // map[string]string 的 JSON
+	Owner        string    `json:"owner" db:"owner" gorm:"column:owner;type:varchar(100);not null"`
+	Description  string    `json:"description" db:"description" gorm:"column:description;type:varchar(500);not null"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Datasource) TableName() string {
+	return "datasource"
+}
@@ -0,0 +1,16 @@
+package datasource
+
+import (
+	"errors"
+
+	"idrm/pkg/db/repo"
+)
+
+var (
+	// ErrNotFound 数据源不存在
+	ErrNotFound = repo.ErrNotFound
+	// ErrDuplicateName 数据源名称已存在
+	ErrDuplicateName = errors.New("datasource name already exists")
+	// ErrInvalidOption 不支持的连接参数
+	ErrInvalidOption = errors.New("invalid datasource option")
+)
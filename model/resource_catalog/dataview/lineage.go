@@ -0,0 +1,47 @@
+package dataview
+
+import (
+	"encoding/json"
+
+	"idrm/pkg/sqlparse"
+)
+
+// SetDefinition 设置视图定义并解析：成功时 Lineage 为解析结果的 JSON，失败时 LineageError 为原因；
+// 定义为空（表）时清空两者
+func (v *DataView) SetDefinition(definition string) (*sqlparse.Result, error) {
+	v.Definition, v.Lineage, v.LineageError = definition, "", ""
+	if definition == "" {
+		return nil, nil
+	}
+	result, err := sqlparse.Parse(definition)
+	if err != nil {
+		v.LineageError = truncate(err.Error(), 1000)
+		return nil, err
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	v.Lineage = string(b)
+	return result, nil
+}
+
+// ParsedLineage 已保存的解析结果，未解析（表或解析失败）时返回 nil
+func (v *DataView) ParsedLineage() (*sqlparse.Result, error) {
+	if v.Lineage == "" {
+		return nil, nil
+	}
+	var result sqlparse.Result
+	if err := json.Unmarshal([]byte(v.Lineage), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// truncate 按字符截断
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
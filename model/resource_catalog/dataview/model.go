@@ -0,0 +1,189 @@
+package dataview
+
+import (
+	"context"
+	"strconv"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/repo"
+	"idrm/pkg/outbox"
+)
+
+// Model 数据视图仓储
+type Model interface {
+	// FindOne 根据ID获取，不存在时返回 ErrNotFound
+	FindOne(ctx context.Context, id int64) (*DataView, error)
+	// FindBySource 获取采集源的全部数据视图（含已删除）
+	FindBySource(ctx context.Context, source string) ([]*DataView, error)
+	// FindByDatasource 获取已注册数据源的全部数据视图（含已删除）
+	FindByDatasource(ctx context.Context, datasource string) ([]*DataView, error)
+	// FindAll 获取全部数据视图（含已删除）
+	FindAll(ctx context.Context) ([]*DataView, error)
+	// Columns 获取数据视图的列，按位置排序
+	Columns(ctx context.Context, viewId int64) ([]*Column, error)
+	// Save 插入（Id 为 0）或更新数据视图，在同一事务中：
+	// columns 非 nil 时全量替换列；changes 非空时写入结构变化记录（ViewId 自动填充），
+	// 配置了发件箱时同时写入 data_view.schema_changed 事件；源、schema、名称重复时返回 ErrDuplicateName
+	Save(ctx context.Context, view *DataView, columns []*Column, changes []*SchemaChange) error
+	// Changes 查询结构变化记录，按ID倒序
+	Changes(ctx context.Context, q ChangeQuery) ([]*SchemaChange, int64, error)
+}
+
+// ChangeQuery 结构变化查询条件，零值字段不过滤
+type ChangeQuery struct {
+	ViewId       int64
+	Source       string
+	BreakingOnly bool
+	Page         int
+	PageSize     int
+}
+
+type model struct {
+	views   repo.Repository[DataView]
+	columns repo.Repository[Column]
+	changes repo.Repository[SchemaChange]
+	writer  outbox.Writer // 为 nil 时不写入结构变化事件
+}
+
+// NewModel 创建数据视图仓储（按配置选择ORM，数据视图、列及变化记录使用同一ORM）
+// writer 非 nil 时结构变化在同一事务中写入发件箱
+func NewModel(conn *db.Conn, writer outbox.Writer) (Model, error) {
+	views, err := repo.New[DataView](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := repo.New[Column](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := repo.New[SchemaChange](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	return &model{views: views, columns: columns, changes: changes, writer: writer}, nil
+}
+
+func (m *model) FindOne(ctx context.Context, id int64) (*DataView, error) {
+	return m.views.FindOne(ctx, id)
+}
+
+func (m *model) FindBySource(ctx context.Context, source string) ([]*DataView, error) {
+	return m.views.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("source", source)}})
+}
+
+func (m *model) FindByDatasource(ctx context.Context, datasource string) ([]*DataView, error) {
+	return m.views.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("datasource", datasource)}})
+}
+
+func (m *model) FindAll(ctx context.Context) ([]*DataView, error) {
+	return m.views.Find(ctx, repo.Query{})
+}
+
+func (m *model) Columns(ctx context.Context, viewId int64) ([]*Column, error) {
+	return m.columns.Find(ctx, repo.Query{
+		Conds:  []repo.Cond{repo.Eq("view_id", viewId)},
+		Orders: []repo.Order{repo.Asc("position")},
+	})
+}
+
+func (m *model) Save(ctx context.Context, view *DataView, columns []*Column, changes []*SchemaChange) error {
+	return m.views.Trans(ctx, func(ctx context.Context, views repo.Repository[DataView]) error {
+		tx, _ := db.TxFromContext(ctx)
+
+		if view.Id == 0 {
+			if err := views.Insert(ctx, view); err != nil {
+				return duplicate(err)
+			}
+		} else if err := views.Update(ctx, view); err != nil {
+			return duplicate(err)
+		}
+
+		if columns != nil {
+			if err := m.replaceColumns(ctx, m.columns.WithTx(tx), view, columns); err != nil {
+				return err
+			}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		breaking := false
+		for _, c := range changes {
+			c.Id = 0
+			c.ViewId = view.Id
+			breaking = breaking || c.Breaking
+		}
+		if err := m.changes.WithTx(tx).BatchInsert(ctx, changes); err != nil {
+			return err
+		}
+		if m.writer == nil {
+			return nil
+		}
+		return m.writer.Write(ctx, outbox.Event{
+			AggregateType: AggregateType,
+			AggregateId:   strconv.FormatInt(view.Id, 10),
+			Type:          EventSchemaChanged,
+			Payload: &SchemaChangeEvent{
+				DataViewId: view.Id,
+				Source:     view.Source,
+				Table:      view.FullName(),
+				Owner:      view.Owner,
+				Breaking:   breaking,
+				Changes:    changes,
+			},
+		})
+	})
+}
+
+// replaceColumns 删除数据视图已有的列后插入新列
+func (m *model) replaceColumns(ctx context.Context, cols repo.Repository[Column], view *DataView, columns []*Column) error {
+	old, err := cols.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("view_id", view.Id)}})
+	if err != nil {
+		return err
+	}
+	if len(old) > 0 {
+		ids := make([]int64, len(old))
+		for i, c := range old {
+			ids[i] = c.Id
+		}
+		if err := cols.BatchDelete(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+	for _, c := range columns {
+		c.Id = 0
+		c.ViewId = view.Id
+	}
+	return cols.BatchInsert(ctx, columns)
+}
+
+func (m *model) Changes(ctx context.Context, q ChangeQuery) ([]*SchemaChange, int64, error) {
+	var conds []repo.Cond
+	if q.ViewId != 0 {
+		conds = append(conds, repo.Eq("view_id", q.ViewId))
+	}
+	if q.Source != "" {
+		conds = append(conds, repo.Eq("source", q.Source))
+	}
+	if q.BreakingOnly {
+		conds = append(conds, repo.Eq("breaking", true))
+	}
+	return m.changes.List(ctx, repo.Query{
+		Conds:    conds,
+		Orders:   []repo.Order{repo.Desc("id")},
+		Page:     q.Page,
+		PageSize: q.PageSize,
+	})
+}
+
+// duplicate 唯一键冲突转换为 ErrDuplicateName
+func duplicate(err error) error {
+	if db.IsDuplicateKey(err) {
+		return ErrDuplicateName
+	}
+	return err
+}
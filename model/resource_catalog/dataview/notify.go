@@ -0,0 +1,48 @@
+package dataview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"idrm/pkg/notify"
+)
+
+// NotifySchemaChanged 解码 data_view.schema_changed 事件内容并通知数据视图负责人
+// 含破坏性变化时级别为 warning
+func NotifySchemaChanged(ctx context.Context, n notify.Notifier, payload []byte) error {
+	var e SchemaChangeEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return fmt.Errorf("decode %s: %w", EventSchemaChanged, err)
+	}
+	return n.Notify(ctx, e.Notification())
+}
+
+// Notification 结构变化通知内容，每项变化一行
+func (e *SchemaChangeEvent) Notification() *notify.Message {
+	level, subject := notify.LevelInfo, fmt.Sprintf("数据视图 %s/%s 结构变化", e.Source, e.Table)
+	if e.Breaking {
+		level, subject = notify.LevelWarning, subject+"（含破坏性变化）"
+	}
+	lines := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		lines[i] = c.Describe()
+	}
+	return &notify.Message{To: e.Owner, Subject: subject, Content: strings.Join(lines, "\n"), Level: level, Data: e}
+}
+
+// Describe 单项变化的说明
+func (c *SchemaChange) Describe() string {
+	s := c.ChangeType
+	if c.ColumnName != "" {
+		s += " " + c.ColumnName
+	}
+	if c.OldValue != "" || c.NewValue != "" {
+		s += fmt.Sprintf(": %q -> %q", c.OldValue, c.NewValue)
+	}
+	if c.Breaking {
+		s += " [breaking]"
+	}
+	return s
+}
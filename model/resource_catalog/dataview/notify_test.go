@@ -0,0 +1,50 @@
+package dataview
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"idrm/pkg/harvest"
+	"idrm/pkg/notify"
+)
+
+type recorder struct{ msgs []*notify.Message }
+
+func (r *recorder) Notify(_ context.Context, msg *notify.Message) error {
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+
+func TestNotifySchemaChanged(t *testing.T) {
+	payload, err := json.Marshal(&SchemaChangeEvent{
+		DataViewId: 1, Source: "src", Table: "main.users", Owner: "张三", Breaking: true,
+		Changes: []*SchemaChange{
+			{ChangeType: harvest.ChangeColumnAdded, ColumnName: "email", NewValue: "TEXT"},
+			{ChangeType: harvest.ChangeColumnDropped, ColumnName: "name", OldValue: "TEXT", Breaking: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r recorder
+	if err := NotifySchemaChanged(context.Background(), &r, payload); err != nil {
+		t.Fatalf("NotifySchemaChanged() error = %v", err)
+	}
+	if len(r.msgs) != 1 {
+		t.Fatalf("notified %d times, want 1", len(r.msgs))
+	}
+	msg := r.msgs[0]
+	if msg.To != "张三" || msg.Level != notify.LevelWarning || !strings.Contains(msg.Subject, "src/main.users") {
+		t.Errorf("message = %+v, want warning to 张三 about src/main.users", msg)
+	}
+	if lines := strings.Split(msg.Content, "\n"); len(lines) != 2 || !strings.HasSuffix(lines[1], "[breaking]") {
+		t.Errorf("content = %q, want one line per change", msg.Content)
+	}
+
+	if err := NotifySchemaChanged(context.Background(), &r, []byte("{")); err == nil {
+		t.Error("NotifySchemaChanged(invalid payload) error = nil, want error")
+	}
+}
@@ -0,0 +1,25 @@
+package dataview
+
+import (
+	"idrm/migrations"
+	"idrm/pkg/db/schemacheck"
+)
+
+// init 注册表结构一致性检查
+func init() {
+	schemacheck.Register(schemacheck.Entity{
+		Database:  migrations.ResourceCatalog,
+		Model:     &DataView{},
+		SqlxTable: "data_view",
+	})
+	schemacheck.Register(schemacheck.Entity{
+		Database:  migrations.ResourceCatalog,
+		Model:     &Column{},
+		SqlxTable: "data_view_column",
+	})
+	schemacheck.Register(schemacheck.Entity{
+		Database:  migrations.ResourceCatalog,
+		Model:     &SchemaChange{},
+		SqlxTable: "data_view_change",
+	})
+}
@@ -0,0 +1,204 @@
+package dataview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"idrm/pkg/harvest"
+)
+
+// 变化类型
+const (
+	ActionAdded   = "added"   // 新增（含源中删除后重新出现的表）
+	ActionChanged = "changed" // 结构变化
+	ActionRemoved = "removed" // 源中已删除
+)
+
+// Source 采集源
+type Source struct {
+	Name       string
+	Datasource string // 引用的已注册数据源名称，写入数据视图，数据预览时按此连接
+	Owner      string // 负责人，写入数据视图，结构变化时随事件通知
+}
+
+// Report 一次采集的结果
+type Report struct {
+	Source    string   `json:"source"`
+	DryRun    bool     `json:"dry_run"` // 只比较不写入
+	Tables    int      `json:"tables"`  // 本次采集到的表及视图数
+	Added     int      `json:"added"`
+	Changed   int      `json:"changed"`
+	Removed   int      `json:"removed"`
+	Unchanged int      `json:"unchanged"`
+	Breaking  int      `json:"breaking"` // 含破坏性变化的表数（删除列、重命名列、类型变化、删除表）
+	Changes   []Change `json:"changes"`  // 新增、变化及删除的表
+}
+
+// Change 单个表的变化
+type Change struct {
+	Action     string           `json:"action"`
+	Table      string           `json:"table"`             // schema.table
+	DataViewId int64            `json:"data_view_id"`      // dry-run 时新增的表为 0
+	Columns    []harvest.Change `json:"columns,omitempty"` // 已有表的结构变化（仅索引变化时为空）
+}
+
+// Summary 执行记录中的结果说明
+func (r *Report) Summary() string {
+	return fmt.Sprintf("%s: tables=%d, added=%d, changed=%d, removed=%d, unchanged=%d, breaking=%d",
+		r.Source, r.Tables, r.Added, r.Changed, r.Removed, r.Unchanged, r.Breaking)
+}
+
+// Affected 新增、变化及删除的表数
+func (r *Report) Affected() int {
+	return r.Added + r.Changed + r.Removed
+}
+
+// Sync 将采集结果写入数据视图：按结构指纹增量写入，源中已删除的表标记为 StatusRemoved，
+// 已有表的结构变化与数据视图在同一事务中写入变化记录
+// dryRun 时只生成报告不写入；写入失败时返回已完成部分的报告及错误（已写入的表不回滚，下次采集继续）
+func Sync(ctx context.Context, m Model, src Source, tables []*harvest.Table, dryRun bool) (*Report, error) {
+	existing, err := m.FindBySource(ctx, src.Name)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*DataView, len(existing))
+	for _, v := range existing {
+		byName[v.FullName()] = v
+	}
+
+	report := &Report{Source: src.Name, DryRun: dryRun, Tables: len(tables), Changes: []Change{}}
+	now := time.Now()
+	seen := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		seen[t.FullName()] = true
+		fingerprint := t.Fingerprint()
+		view, ok := byName[t.FullName()]
+		action := ActionAdded
+		var diff []harvest.Change
+		switch {
+		case ok && view.Status == StatusActive && view.Fingerprint == fingerprint:
+			report.Unchanged++
+			// 负责人或数据源变化时只更新数据视图
+			if !dryRun && (view.Owner != src.Owner || view.Datasource != src.Datasource) {
+				view.Owner, view.Datasource = src.Owner, src.Datasource
+				if err := m.Save(ctx, view, nil, nil); err != nil {
+					return report, fmt.Errorf("save %s: %w", t.FullName(), err)
+				}
+			}
+			continue
+		case ok && view.Status == StatusActive:
+			action = ActionChanged
+			if diff, err = diffView(ctx, m, view, t); err != nil {
+				return report, err
+			}
+		case !ok:
+			view = &DataView{Source: src.Name, SchemaName: t.Schema, Table: t.Name}
+		}
+
+		if !dryRun {
+			indexes, err := json.Marshal(indexesOf(t))
+			if err != nil {
+				return report, err
+			}
+			view.TableType = t.Type
+			view.Comment = t.Comment
+			view.Indexes = string(indexes)
+			// 视图定义解析失败时只记录原因（如非 MySQL 语法），不影响采集
+			_, _ = view.SetDefinition(t.Definition)
+			view.Fingerprint = fingerprint
+			view.Status = StatusActive
+			view.Owner, view.Datasource = src.Owner, src.Datasource
+			view.HarvestedAt = now
+			if err := m.Save(ctx, view, columnsOf(t), newSchemaChanges(view, diff, now)); err != nil {
+				return report, fmt.Errorf("save %s: %w", t.FullName(), err)
+			}
+		}
+		report.add(action, t.FullName(), view.Id, diff)
+	}
+
+	for _, v := range existing {
+		if v.Status != StatusActive || seen[v.FullName()] {
+			continue
+		}
+		diff := []harvest.Change{{Type: harvest.ChangeTableRemoved, OldValue: v.FullName(), Breaking: true}}
+		if !dryRun {
+			v.Status = StatusRemoved
+			v.Owner = src.Owner
+			v.HarvestedAt = now
+			if err := m.Save(ctx, v, nil, newSchemaChanges(v, diff, now)); err != nil {
+				return report, fmt.Errorf("remove %s: %w", v.FullName(), err)
+			}
+		}
+		report.add(ActionRemoved, v.FullName(), v.Id, diff)
+	}
+	return report, nil
+}
+
+func (r *Report) add(action, table string, id int64, diff []harvest.Change) {
+	switch action {
+	case ActionAdded:
+		r.Added++
+	case ActionChanged:
+		r.Changed++
+	case ActionRemoved:
+		r.Removed++
+	}
+	for _, c := range diff {
+		if c.Breaking {
+			r.Breaking++
+			break
+		}
+	}
+	r.Changes = append(r.Changes, Change{Action: action, Table: table, DataViewId: id, Columns: diff})
+}
+
+// diffView 比较已保存的结构与本次采集的结构
+func diffView(ctx context.Context, m Model, view *DataView, t *harvest.Table) ([]harvest.Change, error) {
+	columns, err := m.Columns(ctx, view.Id)
+	if err != nil {
+		return nil, fmt.Errorf("columns of %s: %w", view.FullName(), err)
+	}
+	old := &harvest.Table{Schema: view.SchemaName, Name: view.Table, Type: view.TableType, Comment: view.Comment,
+		Columns: make([]harvest.Column, len(columns))}
+	for i, c := range columns {
+		old.Columns[i] = harvest.Column{
+			Name:       c.Name,
+			Position:   c.Position,
+			DataType:   c.DataType,
+			ColumnType: c.ColumnType,
+			Nullable:   c.Nullable,
+			Default:    c.DefaultValue,
+			Comment:    c.Comment,
+			PrimaryKey: c.PrimaryKey,
+		}
+	}
+	return harvest.Diff(old, t), nil
+}
+
+// columnsOf 采集到的列转换为实体
+func columnsOf(t *harvest.Table) []*Column {
+	columns := make([]*Column, len(t.Columns))
+	for i, c := range t.Columns {
+		columns[i] = &Column{
+			Name:         c.Name,
+			Position:     c.Position,
+			DataType:     c.DataType,
+			ColumnType:   c.ColumnType,
+			Nullable:     c.Nullable,
+			DefaultValue: c.Default,
+			Comment:      c.Comment,
+			PrimaryKey:   c.PrimaryKey,
+		}
+	}
+	return columns
+}
+
+// indexesOf 索引列表（视图无索引时为空数组）
+func indexesOf(t *harvest.Table) []harvest.Index {
+	if t.Indexes == nil {
+		return []harvest.Index{}
+	}
+	return t.Indexes
+}
@@ -0,0 +1,143 @@
+package dataview
+
+import (
+	"context"
+	"testing"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/outbox"
+	"idrm/pkg/testkit"
+)
+
+func TestSync(t *testing.T) {
+	users := func(cols ...string) *harvest.Table {
+		tbl := &harvest.Table{Schema: "main", Name: "users", Type: harvest.TypeTable}
+		for i, c := range cols {
+			tbl.Columns = append(tbl.Columns, harvest.Column{Name: c, Position: i + 1, DataType: "text", ColumnType: "text"})
+		}
+		return tbl
+	}
+	orders := &harvest.Table{Schema: "main", Name: "orders", Type: harvest.TypeTable,
+		Columns: []harvest.Column{{Name: "id", Position: 1, DataType: "integer", ColumnType: "integer", PrimaryKey: true}}}
+
+	for _, orm := range []string{db.ORMGorm, db.ORMSqlx} {
+		t.Run(orm, func(t *testing.T) {
+			ctx := context.Background()
+			conn := testkit.SQLite(t, migrations.ResourceCatalog, orm)
+			m, err := NewModel(conn, outbox.NewStore(conn))
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := Source{Name: "src", Datasource: "crm", Owner: "张三"}
+
+			steps := []struct {
+				name   string
+				tables []*harvest.Table
+				dryRun bool
+				want   [5]int // added, changed, removed, unchanged, breaking
+			}{
+				{"首次采集全部新增", []*harvest.Table{users("id", "name"), orders}, false, [5]int{2, 0, 0, 0, 0}},
+				{"结构未变不写入", []*harvest.Table{users("id", "name"), orders}, false, [5]int{0, 0, 0, 2, 0}},
+				{"dry-run只报告", []*harvest.Table{users("id")}, true, [5]int{0, 1, 1, 0, 2}},
+				{"新增列且表删除", []*harvest.Table{users("id", "name", "email")}, false, [5]int{0, 1, 1, 0, 1}},
+				{"删除后重新出现视为新增", []*harvest.Table{users("id", "name", "email"), orders}, false, [5]int{1, 0, 0, 1, 0}},
+			}
+			for _, s := range steps {
+				r, err := Sync(ctx, m, src, s.tables, s.dryRun)
+				if err != nil {
+					t.Fatalf("%s: Sync() error = %v", s.name, err)
+				}
+				if got := [5]int{r.Added, r.Changed, r.Removed, r.Unchanged, r.Breaking}; got != s.want {
+					t.Errorf("%s: report = %v, want %v (%+v)", s.name, got, s.want, r.Changes)
+				}
+			}
+
+			views, err := m.FindBySource(ctx, "src")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(views) != 2 {
+				t.Fatalf("FindBySource() = %d views, want 2", len(views))
+			}
+			for _, v := range views {
+				if v.Status != StatusActive || v.Owner != "张三" || v.Datasource != "crm" {
+					t.Errorf("%s status = %d, owner = %q, datasource = %q, want active, 张三 and crm", v.FullName(), v.Status, v.Owner, v.Datasource)
+				}
+				if v.Table != "users" {
+					continue
+				}
+				cols, err := m.Columns(ctx, v.Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(cols) != 3 || cols[2].Name != "email" {
+					t.Errorf("users columns = %+v, want id,name,email", cols)
+				}
+			}
+
+			// dry-run 不写入变化记录：新增列（非破坏性）及删除表（破坏性）各一条，每条变化记录对应一个事件
+			changes, total, err := m.Changes(ctx, ChangeQuery{Source: "src"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != 2 || changes[0].ChangeType != harvest.ChangeTableRemoved || changes[1].ChangeType != harvest.ChangeColumnAdded {
+				t.Errorf("Changes() = %+v, total %d, want table_removed and column_added", changes, total)
+			}
+			if _, total, _ := m.Changes(ctx, ChangeQuery{BreakingOnly: true}); total != 1 {
+				t.Errorf("Changes(BreakingOnly) total = %d, want 1", total)
+			}
+			var events int
+			if err := conn.SqlConn().QueryRowCtx(ctx, &events, "SELECT count(*) FROM outbox WHERE event_type = ?", EventSchemaChanged); err != nil {
+				t.Fatal(err)
+			}
+			if events != 2 {
+				t.Errorf("outbox events = %d, want 2", events)
+			}
+		})
+	}
+}
+
+func TestSyncViewDefinition(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	m, err := NewModel(conn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := []*harvest.Table{
+		{Schema: "main", Name: "active_users", Type: harvest.TypeView, Definition: "SELECT id, name AS user_name FROM users WHERE status = 1",
+			Columns: []harvest.Column{{Name: "id", Position: 1}, {Name: "user_name", Position: 2}}},
+		{Schema: "main", Name: "broken", Type: harvest.TypeView, Definition: "SELECT id::text FROM users",
+			Columns: []harvest.Column{{Name: "id", Position: 1}}},
+	}
+	if _, err := Sync(ctx, m, Source{Name: "src", Datasource: "crm"}, tables, false); err != nil {
+		t.Fatal(err)
+	}
+
+	views, err := m.FindByDatasource(ctx, "crm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 2 {
+		t.Fatalf("FindByDatasource() = %d views, want 2", len(views))
+	}
+	for _, v := range views {
+		lineage, err := v.ParsedLineage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch v.Table {
+		case "active_users":
+			if lineage == nil || len(lineage.Tables) != 1 || lineage.Tables[0].Name != "users" ||
+				lineage.Columns[1].Sources[0].Column != "name" || v.LineageError != "" {
+				t.Errorf("active_users lineage = %+v, error = %q", lineage, v.LineageError)
+			}
+		case "broken":
+			if lineage != nil || v.LineageError == "" || v.Definition == "" {
+				t.Errorf("broken lineage = %+v, error = %q, want parse error", lineage, v.LineageError)
+			}
+		}
+	}
+}
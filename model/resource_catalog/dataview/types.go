@@ -0,0 +1,156 @@
+// Package dataview 数据视图：元数据采集（pkg/harvest）得到的源表及视图，按源、schema、表名唯一；
+// 也可通过 API 以 SQL 定义（TableTypeSQL），保存前校验语法及引用的表
+//
+// Sync 将一次采集结果与已有数据视图比较：新增的表插入，结构指纹变化的表更新（列全量替换），
+// 源中已删除的表标记为 StatusRemoved（保留历史，不物理删除），未变化的表不写入。
+// 已有表的结构变化（列增删、重命名、类型及注释变化、表删除）记录为 SchemaChange，
+// 可选在同一事务中写入发件箱事件 data_view.schema_changed 通知负责人。
+// 视图定义由 pkg/sqlparse 解析，引用的表及列级血缘保存在 Lineage 中。
+// 数据视图即资源目录中的资源：编目属性（所属类别、敏感级别、所属部门）由 API 设置，采集时保留，
+// 统计任务（model/resource_catalog/stats）按其统计资源数量。
+package dataview
+
+import (
+	"time"
+
+	"idrm/pkg/harvest"
+)
+
+// 模型名称（用于配置 DB.*.Models 按模型指定ORM）
+const (
+	ModelName       = "data_view"
+	ColumnModelName = "data_view_column"
+	ChangeModelName = "data_view_change"
+)
+
+// 变更事件（outbox），事件内容为 SchemaChangeEvent
+const (
+	AggregateType      = "data_view"
+	EventSchemaChanged = "data_view.schema_changed"
+)
+
+// TableTypeSQL 通过 API 以 SQL 定义的数据视图（table_type），采集源为空，不参与采集比较
+const TableTypeSQL = "sql"
+
+// 状态
+const (
+	StatusRemoved = 0 // 源中已删除
+	StatusActive  = 1
+)
+
+// 敏感级别（从低到高）
+const (
+	SensitivityPublic       = "public"
+	SensitivityInternal     = "internal"
+	SensitivityConfidential = "confidential"
+	SensitivitySecret       = "secret"
+)
+
+// SensitivityLabels 敏感级别名称
+var SensitivityLabels = map[string]string{
+	SensitivityPublic:       "公开",
+	SensitivityInternal:     "内部",
+	SensitivityConfidential: "秘密",
+	SensitivitySecret:       "机密",
+}
+
+// DataView 数据视图（源表或视图）
+type DataView struct {
+	Id           int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	Source       string    `json:"source" db:"source" gorm:"column:source;type:varchar(100);not null;uniqueIndex:uk_data_view_table,priority:1"`
+	Datasource   string    `json:"datasource" db:"datasource" gorm:"column:datasource;type:varchar(100);not null"` // 已注册数据源名称，为空时采集源直接配置连接
+	SchemaName   string    `json:"schema_name" db:"schema_name" gorm:"column:schema_name;type:varchar(100);not null;uniqueIndex:uk_data_view_table,priority:2"`
+	Table        string    `json:"table_name" db:"table_name" gorm:"column:table_name;type:varchar(191);not null;uniqueIndex:uk_data_view_table,priority:3"`
+	TableType    string    `json:"table_type" db:"table_type" gorm:"column:table_type;type:varchar(20);not null"`
+	Comment      string    `json:"comment" db:"comment" gorm:"column:comment;type:varchar(1000);not null"`
+	Indexes      string    `json:"indexes" db:"indexes" gorm:"column:indexes;type:text;not null"`                            // []harvest.Index 的 JSON
+	Definition   string    `json:"definition" db:"definition" gorm:"column:definition;type:text;not null"`                   // 视图定义（SELECT 语句），表为空
+	Lineage      string    `json:"lineage" db:"lineage" gorm:"column:lineage;type:text;not null"`                            // 定义的解析结果（sqlparse.Result 的 JSON）
+	LineageError string    `json:"lineage_error" db:"lineage_error" gorm:"column:lineage_error;type:varchar(1000);not null"` // 解析失败原因
+	Fingerprint  string    `json:"fingerprint" db:"fingerprint" gorm:"column:fingerprint;type:varchar(64);not null"`
+	Status       int       `json:"status" db:"status" gorm:"column:status;not null"`
+	Owner        string    `json:"owner" db:"owner" gorm:"column:owner;type:varchar(100);not null"`                              // 采集源或数据源的负责人
+	CategoryId   int64     `json:"category_id" db:"category_id" gorm:"column:category_id;not null;index:idx_data_view_category"` // 编目属性（API 维护，采集不覆盖）：所属类别，0 表示未编目
+	Sensitivity  string    `json:"sensitivity" db:"sensitivity" gorm:"column:sensitivity;type:varchar(20);not null"`             // 敏感级别，为空表示未定级
+	Department   string    `json:"department" db:"department" gorm:"column:department;type:varchar(100);not null"`               // 所属部门
+	HarvestedAt  time.Time `json:"harvested_at" db:"harvested_at" gorm:"column:harvested_at"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (DataView) TableName() string {
+	return "data_view"
+}
+
+// FullName schema.table
+func (v *DataView) FullName() string {
+	return v.SchemaName + "." + v.Table
+}
+
+// Column 数据视图的列
+type Column struct {
+	Id           int64  `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	ViewId       int64  `json:"view_id" db:"view_id" gorm:"column:view_id;not null;index:idx_data_view_column_view,priority:1"`
+	Name         string `json:"name" db:"name" gorm:"column:name;type:varchar(191);not null"`
+	Position     int    `json:"position" db:"position" gorm:"column:position;not null;index:idx_data_view_column_view,priority:2"`
+	DataType     string `json:"data_type" db:"data_type" gorm:"column:data_type;type:varchar(100);not null"`
+	ColumnType   string `json:"column_type" db:"column_type" gorm:"column:column_type;type:varchar(255);not null"`
+	Nullable     bool   `json:"nullable" db:"nullable" gorm:"column:nullable;not null"`
+	DefaultValue string `json:"default_value" db:"default_value" gorm:"column:default_value;type:varchar(500);not null"`
+	Comment      string `json:"comment" db:"comment" gorm:"column:comment;type:varchar(1000);not null"`
+	PrimaryKey   bool   `json:"primary_key" db:"primary_key" gorm:"column:primary_key;not null"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Column) TableName() string {
+	return "data_view_column"
+}
+
+// SchemaChange 数据视图结构变化记录
+type SchemaChange struct {
+	Id         int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	ViewId     int64     `json:"view_id" db:"view_id" gorm:"column:view_id;not null;index:idx_data_view_change_view"`
+	Source     string    `json:"source" db:"source" gorm:"column:source;type:varchar(100);not null"`
+	Table      string    `json:"table_name" db:"table_name" gorm:"column:table_name;type:varchar(300);not null"` // schema.table
+	ChangeType string    `json:"change_type" db:"change_type" gorm:"column:change_type;type:varchar(30);not null"`
+	ColumnName string    `json:"column_name" db:"column_name" gorm:"column:column_name;type:varchar(191);not null"`
+	OldValue   string    `json:"old_value" db:"old_value" gorm:"column:old_value;type:varchar(1000);not null"`
+	NewValue   string    `json:"new_value" db:"new_value" gorm:"column:new_value;type:varchar(1000);not null"`
+	Breaking   bool      `json:"breaking" db:"breaking" gorm:"column:breaking;not null"`
+	DetectedAt time.Time `json:"detected_at" db:"detected_at" gorm:"column:detected_at;not null;index:idx_data_view_change_detected"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (SchemaChange) TableName() string {
+	return "data_view_change"
+}
+
+// SchemaChangeEvent 结构变化事件内容
+type SchemaChangeEvent struct {
+	DataViewId int64           `json:"data_view_id"`
+	Source     string          `json:"source"`
+	Table      string          `json:"table"` // schema.table
+	Owner      string          `json:"owner"`
+	Breaking   bool            `json:"breaking"` // 包含破坏性变化
+	Changes    []*SchemaChange `json:"changes"`
+}
+
+// newSchemaChanges 采集比较结果转换为变化记录
+func newSchemaChanges(view *DataView, changes []harvest.Change, now time.Time) []*SchemaChange {
+	records := make([]*SchemaChange, len(changes))
+	for i, c := range changes {
+		records[i] = &SchemaChange{
+			ViewId:     view.Id,
+			Source:     view.Source,
+			Table:      view.FullName(),
+			ChangeType: c.Type,
+			ColumnName: c.Column,
+			OldValue:   c.OldValue,
+			NewValue:   c.NewValue,
+			Breaking:   c.Breaking,
+			DetectedAt: now,
+		}
+	}
+	return records
+}
@@ -0,0 +1,14 @@
+package dataview
+
+import (
+	"errors"
+
+	"idrm/pkg/db/repo"
+)
+
+var (
+	// ErrNotFound 数据视图不存在
+	ErrNotFound = repo.ErrNotFound
+	// ErrDuplicateName 同一源、schema 下的数据视图名称已存在
+	ErrDuplicateName = errors.New("data view name already exists")
+)
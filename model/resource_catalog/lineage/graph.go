@@ -0,0 +1,164 @@
+package lineage
+
+import (
+	"context"
+	"sort"
+)
+
+// 遍历限制
+const (
+	DefaultDepth = 3
+	MaxDepth     = 10
+	MaxNodes     = 500 // 单次遍历返回的节点数上限，达到时 Graph.Truncated 为 true
+)
+
+// Graph 遍历结果
+type Graph struct {
+	Root      *Node
+	Direction Direction
+	Depth     int
+	Nodes     []*Node       // 按距离、ID排序，首个为起点
+	Distance  map[int64]int // 节点ID → 与起点的距离（边数）
+	Edges     []*Edge       // 两端均在 Nodes 中的边
+	Truncated bool          // 节点数达到上限或深度用尽时仍有未展开的边
+}
+
+// Traverse 从节点出发按方向广度优先遍历 depth 层，节点不存在时返回 ErrNotFound
+//
+// 上游方向不经过 contains 边（列的上游是计算它的列，而不是所属表的全部上游）；
+// 下游方向经过 contains 边（表的变化影响其全部列及引用它的视图）
+func Traverse(ctx context.Context, m Model, id int64, dir Direction, depth int) (*Graph, error) {
+	root, err := m.FindNode(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	g := &Graph{Root: root, Direction: dir, Depth: depth, Distance: map[int64]int{root.Id: 0}}
+	seen := make(map[int64]bool)
+	frontier := []int64{root.Id}
+	for level := 1; len(frontier) > 0; level++ {
+		edges, err := m.Edges(ctx, frontier, dir)
+		if err != nil {
+			return nil, err
+		}
+		if level > depth {
+			g.Truncated = g.Truncated || len(follow(edges, dir)) > 0
+			break
+		}
+		var next []int64
+		for _, e := range follow(edges, dir) {
+			other := e.TargetId
+			if dir == Upstream {
+				other = e.SourceId
+			}
+			if _, ok := g.Distance[other]; !ok {
+				if len(g.Distance) >= MaxNodes {
+					g.Truncated = true
+					continue
+				}
+				g.Distance[other] = level
+				next = append(next, other)
+			}
+			if !seen[e.Id] {
+				seen[e.Id] = true
+				g.Edges = append(g.Edges, e)
+			}
+		}
+		frontier = next
+	}
+
+	ids := make([]int64, 0, len(g.Distance))
+	for nid := range g.Distance {
+		ids = append(ids, nid)
+	}
+	if g.Nodes, err = m.FindNodes(ctx, ids); err != nil {
+		return nil, err
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		a, b := g.Nodes[i], g.Nodes[j]
+		if g.Distance[a.Id] != g.Distance[b.Id] {
+			return g.Distance[a.Id] < g.Distance[b.Id]
+		}
+		return a.Id < b.Id
+	})
+	sort.Slice(g.Edges, func(i, j int) bool { return g.Edges[i].Id < g.Edges[j].Id })
+	return g, nil
+}
+
+// follow 按方向可经过的边
+func follow(edges []*Edge, dir Direction) []*Edge {
+	if dir == Downstream {
+		return edges
+	}
+	result := edges[:0]
+	for _, e := range edges {
+		if e.Type != EdgeContains {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Affected 受影响的对象
+type Affected struct {
+	Node     *Node
+	Distance int   // 与起点的距离；由列推出的所属表或数据视图为该列的距离
+	Via      int64 // 由列推出时为该列的节点ID，否则为 0
+}
+
+// Impact 影响分析结果
+type Impact struct {
+	*Graph
+	Affected []Affected     // 下游的表、数据视图、目录资源及 API（不含起点及列），按距离、ID排序
+	Columns  int            // 下游受影响的列数
+	ByType   map[string]int // Affected 按节点类型计数
+}
+
+// Analyze 影响分析：下游遍历 depth 层，受影响的列归并到其所属表或数据视图
+func Analyze(ctx context.Context, m Model, id int64, depth int) (*Impact, error) {
+	g, err := Traverse(ctx, m, id, Downstream, depth)
+	if err != nil {
+		return nil, err
+	}
+	impact := &Impact{Graph: g, ByType: make(map[string]int)}
+	found := make(map[int64]bool, len(g.Nodes))
+	parents := make(map[int64]Affected)
+	for _, n := range g.Nodes[1:] {
+		if n.Type != NodeColumn {
+			found[n.Id] = true
+			impact.Affected = append(impact.Affected, Affected{Node: n, Distance: g.Distance[n.Id]})
+			continue
+		}
+		impact.Columns++
+		if p, ok := parents[n.ParentId]; n.ParentId != 0 && n.ParentId != g.Root.Id && (!ok || g.Distance[n.Id] < p.Distance) {
+			parents[n.ParentId] = Affected{Distance: g.Distance[n.Id], Via: n.Id}
+		}
+	}
+
+	var ids []int64
+	for pid := range parents {
+		if !found[pid] {
+			ids = append(ids, pid)
+		}
+	}
+	nodes, err := m.FindNodes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		a := parents[n.Id]
+		a.Node = n
+		impact.Affected = append(impact.Affected, a)
+	}
+
+	sort.Slice(impact.Affected, func(i, j int) bool {
+		a, b := impact.Affected[i], impact.Affected[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Node.Id < b.Node.Id
+	})
+	for _, a := range impact.Affected {
+		impact.ByType[a.Node.Type]++
+	}
+	return impact, nil
+}
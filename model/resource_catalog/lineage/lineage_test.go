@@ -0,0 +1,257 @@
+package lineage
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"idrm/migrations"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/db"
+	"idrm/pkg/harvest"
+	"idrm/pkg/testkit"
+)
+
+func TestLineage(t *testing.T) {
+	table := func(name, definition string, cols ...string) *harvest.Table {
+		tbl := &harvest.Table{Schema: "main", Name: name, Type: harvest.TypeTable, Definition: definition}
+		if definition != "" {
+			tbl.Type = harvest.TypeView
+		}
+		for i, c := range cols {
+			tbl.Columns = append(tbl.Columns, harvest.Column{Name: c, Position: i + 1, DataType: "text", ColumnType: "text"})
+		}
+		return tbl
+	}
+	src := dataview.Source{Name: "src", Datasource: "crm"}
+
+	for _, orm := range []string{db.ORMGorm, db.ORMSqlx} {
+		t.Run(orm, func(t *testing.T) {
+			ctx := context.Background()
+			conn := testkit.SQLite(t, migrations.ResourceCatalog, orm)
+			views, err := dataview.NewModel(conn, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := NewModel(conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 视图先于其引用的 orders 同步时，orders 及其列先创建为占位节点
+			harvested := []*harvest.Table{
+				table("users", "", "id", "name", "status"),
+				table("active_users", "SELECT id, name AS user_name FROM users WHERE status = 1", "id", "user_name"),
+				table("big_orders", "SELECT * FROM orders WHERE amount > 100", "id", "user_id", "amount"),
+				table("orders", "", "id", "user_id", "amount"),
+			}
+			if _, err := dataview.Sync(ctx, views, src, harvested, false); err != nil {
+				t.Fatal(err)
+			}
+			if n, err := SyncSource(ctx, m, views, "src"); err != nil || n != 4 {
+				t.Fatalf("SyncSource() = %d, %v, want 4", n, err)
+			}
+			if n, err := SyncSource(ctx, m, views, "src"); err != nil || n != 0 {
+				t.Fatalf("SyncSource() again = %d, %v, want 0 (unchanged)", n, err)
+			}
+
+			summary := &dataview.DataView{Datasource: "crm", SchemaName: "main", Table: "user_summary", TableType: dataview.TableTypeSQL,
+				Indexes: "[]", Status: dataview.StatusActive, HarvestedAt: time.Now()}
+			if _, err := summary.SetDefinition("SELECT u.id, SUM(o.amount) AS total FROM users u JOIN orders o ON o.user_id = u.id GROUP BY u.id"); err != nil {
+				t.Fatal(err)
+			}
+			if err := views.Save(ctx, summary, []*dataview.Column{{Name: "id", Position: 1}, {Name: "total", Position: 2}}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := SyncView(ctx, m, views, summary); err != nil {
+				t.Fatal(err)
+			}
+
+			node := func(key string) *Node {
+				t.Helper()
+				nodes, err := m.FindNodesByKeys(ctx, []string{key})
+				if err != nil || len(nodes) != 1 {
+					t.Fatalf("FindNodesByKeys(%s) = %v, %v", key, nodes, err)
+				}
+				return nodes[0]
+			}
+			users := TableKey("crm", "main", "users")
+			orders := TableKey("crm", "main", "orders")
+			summaryKey := DataViewKey("crm", "main", "user_summary")
+			if n := node(orders); n.RefId == 0 || n.Fingerprint == "" {
+				t.Errorf("orders node = %+v, want placeholder replaced by harvested table", n)
+			}
+
+			// 目录资源及 API 手工登记
+			resource := &Node{NodeKey: ExternalKey(NodeResource, "R001"), Type: NodeResource, Name: "用户汇总", Status: StatusActive}
+			api := &Node{NodeKey: ExternalKey(NodeAPI, "GET /users/summary"), Type: NodeAPI, Name: "用户汇总接口", Status: StatusActive}
+			for _, n := range []*Node{resource, api} {
+				if err := m.InsertNode(ctx, n); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := m.InsertNode(ctx, &Node{NodeKey: resource.NodeKey, Type: NodeResource, Name: "重复"}); err != ErrDuplicateNode {
+				t.Errorf("InsertNode(duplicate) error = %v, want ErrDuplicateNode", err)
+			}
+			for _, e := range []*Edge{
+				{SourceId: node(summaryKey).Id, TargetId: resource.Id, Type: EdgePublishes, Origin: OriginManual},
+				{SourceId: resource.Id, TargetId: api.Id, Type: EdgeServes, Origin: OriginManual},
+			} {
+				if err := m.InsertEdge(ctx, e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := m.InsertEdge(ctx, &Edge{SourceId: resource.Id, TargetId: api.Id, Type: EdgeServes, Origin: OriginManual}); err != ErrDuplicateEdge {
+				t.Errorf("InsertEdge(duplicate) error = %v, want ErrDuplicateEdge", err)
+			}
+
+			names := func(nodes []*Node) string {
+				list := make([]string, len(nodes))
+				for i, n := range nodes {
+					list[i] = n.Name
+				}
+				sort.Strings(list)
+				return strings.Join(list, ",")
+			}
+
+			// 列的上游只沿 derives 边
+			g, err := Traverse(ctx, m, node(ColumnKey(summaryKey, "total")).Id, Upstream, DefaultDepth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(g.Nodes); got != "amount,total" || g.Truncated {
+				t.Errorf("upstream(total) = %s, truncated %t, want amount,total", got, g.Truncated)
+			}
+			g, err = Traverse(ctx, m, api.Id, Upstream, MaxDepth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(g.Nodes); got != "main.orders,main.user_summary,main.users,用户汇总,用户汇总接口" {
+				t.Errorf("upstream(api) = %s", got)
+			}
+			// * 展开的列按同名列推导
+			g, err = Traverse(ctx, m, node(ColumnKey(TableKey("crm", "main", "big_orders"), "amount")).Id, Upstream, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(g.Nodes) != 2 || g.Nodes[1].NodeKey != ColumnKey(orders, "amount") || g.Distance[g.Nodes[1].Id] != 1 {
+				t.Errorf("upstream(big_orders.amount) = %+v", g.Nodes)
+			}
+
+			g, err = Traverse(ctx, m, node(users).Id, Downstream, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(g.Nodes); got != "id,main.active_users,main.user_summary,main.users,name,status" || !g.Truncated {
+				t.Errorf("downstream(users, 1) = %s, truncated %t", got, g.Truncated)
+			}
+
+			// 列的影响归并到所属表或数据视图
+			impact, err := Analyze(ctx, m, node(ColumnKey(users, "name")).Id, MaxDepth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(impact.Affected) != 1 || impact.Affected[0].Node.Name != "main.active_users" || impact.Columns != 1 {
+				t.Errorf("impact(users.name) = %+v, columns %d", impact.Affected, impact.Columns)
+			}
+			impact, err = Analyze(ctx, m, node(orders).Id, MaxDepth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]int{NodeTable: 1, NodeDataView: 1, NodeResource: 1, NodeAPI: 1}
+			if len(impact.ByType) != len(want) {
+				t.Errorf("impact(orders) by type = %v, want %v", impact.ByType, want)
+			}
+			for k, v := range want {
+				if impact.ByType[k] != v {
+					t.Errorf("impact(orders) by type = %v, want %v", impact.ByType, want)
+				}
+			}
+
+			// 视图定义变化：替换该视图产生的边，删除的列标记为删除
+			harvested[1] = table("active_users", "SELECT id FROM users WHERE status = 1", "id")
+			if _, err := dataview.Sync(ctx, views, src, harvested, false); err != nil {
+				t.Fatal(err)
+			}
+			if n, err := SyncSource(ctx, m, views, "src"); err != nil || n != 1 {
+				t.Fatalf("SyncSource() after change = %d, %v, want 1", n, err)
+			}
+			if n := node(ColumnKey(TableKey("crm", "main", "active_users"), "user_name")); n.Status != StatusRemoved {
+				t.Errorf("user_name status = %d, want removed", n.Status)
+			}
+			edges, err := m.Edges(ctx, []int64{node(ColumnKey(users, "name")).Id}, Downstream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(edges) != 0 {
+				t.Errorf("users.name downstream edges = %+v, want none", edges)
+			}
+		})
+	}
+}
+
+func TestTableKeyCase(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	views, err := dataview.NewModel(conn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModel(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 定义中的表名、schema 大小写与采集的不同（及带引号）时指向同一表节点
+	orders := &harvest.Table{Schema: "main", Name: "orders", Type: harvest.TypeTable,
+		Columns: []harvest.Column{{Name: "id", Position: 1, DataType: "text", ColumnType: "text"}, {Name: "amount", Position: 2, DataType: "text", ColumnType: "text"}}}
+	if _, err := dataview.Sync(ctx, views, dataview.Source{Name: "src", Datasource: "crm"}, []*harvest.Table{orders}, false); err != nil {
+		t.Fatal(err)
+	}
+	for i, sql := range []string{"SELECT ID, Amount FROM Orders", "SELECT o.id FROM `MAIN`.`ORDERS` o"} {
+		v := &dataview.DataView{Datasource: "crm", SchemaName: "Main", Table: "v" + strconv.Itoa(i), TableType: dataview.TableTypeSQL,
+			Indexes: "[]", Status: dataview.StatusActive, HarvestedAt: time.Now()}
+		if _, err := v.SetDefinition(sql); err != nil {
+			t.Fatal(err)
+		}
+		if err := views.Save(ctx, v, []*dataview.Column{{Name: "id", Position: 1}}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := SyncView(ctx, m, views, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := SyncSource(ctx, m, views, "src"); err != nil {
+		t.Fatal(err)
+	}
+
+	tables, total, err := m.ListNodes(ctx, NodeQuery{Type: NodeTable, Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || tables[0].RefId == 0 {
+		t.Fatalf("table nodes = %+v, want the harvested orders only", tables)
+	}
+	impact, err := Analyze(ctx, m, tables[0].Id, MaxDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impact.ByType[NodeDataView] != 2 {
+		t.Errorf("impact(orders) by type = %v, want 2 data views", impact.ByType)
+	}
+	// 列名大小写不同时同样指向采集的列
+	col, err := m.FindNodesByKeys(ctx, []string{ColumnKey(TableKey("crm", "main", "orders"), "id")})
+	if err != nil || len(col) != 1 {
+		t.Fatalf("orders.id = %v, %v", col, err)
+	}
+	g, err := Traverse(ctx, m, col[0].Id, Downstream, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes) != 3 {
+		t.Errorf("downstream(orders.id) = %+v, want v0.id and v1.id", g.Nodes)
+	}
+}
@@ -0,0 +1,312 @@
+package lineage
+
+import (
+	"context"
+	"fmt"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/repo"
+)
+
+// Direction 遍历方向
+type Direction string
+
+const (
+	Upstream   Direction = "upstream"   // 沿边逆向：数据从哪里来
+	Downstream Direction = "downstream" // 沿边正向：数据流向哪里
+)
+
+// Model 血缘图仓储
+type Model interface {
+	// FindNode 根据ID获取，不存在时返回 ErrNotFound
+	FindNode(ctx context.Context, id int64) (*Node, error)
+	// FindNodes 根据ID批量获取，不存在的ID忽略
+	FindNodes(ctx context.Context, ids []int64) ([]*Node, error)
+	// FindNodesByKeys 根据节点键批量获取，不存在的键忽略
+	FindNodesByKeys(ctx context.Context, keys []string) ([]*Node, error)
+	// ListNodes 分页查询节点，按ID升序
+	ListNodes(ctx context.Context, q NodeQuery) ([]*Node, int64, error)
+	// InsertNode 新增节点，节点键重复时返回 ErrDuplicateNode
+	InsertNode(ctx context.Context, node *Node) error
+	// InsertEdge 新增边，同一对节点间同类型的边已存在时返回 ErrDuplicateEdge
+	InsertEdge(ctx context.Context, edge *Edge) error
+	// Edges 与节点相连的边：Downstream 为以节点为起点的边，Upstream 为以节点为终点的边
+	Edges(ctx context.Context, ids []int64, dir Direction) ([]*Edge, error)
+	// Apply 在同一事务中写入表或数据视图的子图，见 Subgraph
+	Apply(ctx context.Context, g *Subgraph) error
+}
+
+// NodeQuery 节点查询条件，零值字段不过滤
+type NodeQuery struct {
+	Type      string
+	Namespace string
+	Keyword   string // 名称包含
+	Page      int
+	PageSize  int
+}
+
+// Subgraph 一个表或数据视图产生的子图
+//
+// Apply 时：Owner 及 Columns 按节点键插入或更新，Owner 已有但不在 Columns 中的列标记为删除；
+// Refs 中不存在的表及列创建为占位节点，已存在的不修改；
+// 删除 Owner 此前产生的边后写入 Edges（其他节点已产生的同一条边跳过）。
+type Subgraph struct {
+	Owner   *Node
+	Columns []*Node
+	Refs    []*RefTable
+	Edges   []EdgeRef
+	Origin  string
+}
+
+// RefTable 定义中引用的表及其被引用的列
+type RefTable struct {
+	Table   *Node
+	Columns []*Node
+}
+
+// EdgeRef 以节点键表示的边
+type EdgeRef struct {
+	Source string
+	Target string
+	Type   string
+	Expr   string
+	Owner  string // 产生该边的节点键，为空时为 Subgraph.Owner（引用表的 contains 边归属于引用表）
+}
+
+type model struct {
+	nodes repo.Repository[Node]
+	edges repo.Repository[Edge]
+}
+
+// NewModel 创建血缘图仓储（按配置选择ORM，节点与边使用同一ORM）
+func NewModel(conn *db.Conn) (Model, error) {
+	nodes, err := repo.New[Node](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := repo.New[Edge](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	return &model{nodes: nodes, edges: edges}, nil
+}
+
+func (m *model) FindNode(ctx context.Context, id int64) (*Node, error) {
+	return m.nodes.FindOne(ctx, id)
+}
+
+func (m *model) FindNodes(ctx context.Context, ids []int64) ([]*Node, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return m.nodes.Find(ctx, repo.Query{Conds: []repo.Cond{repo.In("id", ids)}})
+}
+
+func (m *model) FindNodesByKeys(ctx context.Context, keys []string) ([]*Node, error) {
+	return findByKeys(ctx, m.nodes, keys)
+}
+
+func (m *model) ListNodes(ctx context.Context, q NodeQuery) ([]*Node, int64, error) {
+	var conds []repo.Cond
+	if q.Type != "" {
+		conds = append(conds, repo.Eq("type", q.Type))
+	}
+	if q.Namespace != "" {
+		conds = append(conds, repo.Eq("namespace", q.Namespace))
+	}
+	if q.Keyword != "" {
+		conds = append(conds, repo.Like("name", "%"+q.Keyword+"%"))
+	}
+	return m.nodes.List(ctx, repo.Query{Conds: conds, Page: q.Page, PageSize: q.PageSize})
+}
+
+func (m *model) InsertNode(ctx context.Context, node *Node) error {
+	err := m.nodes.Insert(ctx, node)
+	if db.IsDuplicateKey(err) {
+		return ErrDuplicateNode
+	}
+	return err
+}
+
+func (m *model) InsertEdge(ctx context.Context, edge *Edge) error {
+	err := m.edges.Insert(ctx, edge)
+	if db.IsDuplicateKey(err) {
+		return ErrDuplicateEdge
+	}
+	return err
+}
+
+func (m *model) Edges(ctx context.Context, ids []int64, dir Direction) ([]*Edge, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	column := "source_id"
+	if dir == Upstream {
+		column = "target_id"
+	}
+	return m.edges.Find(ctx, repo.Query{Conds: []repo.Cond{repo.In(column, ids)}})
+}
+
+func (m *model) Apply(ctx context.Context, g *Subgraph) error {
+	return m.nodes.Trans(ctx, func(ctx context.Context, nodes repo.Repository[Node]) error {
+		tx, _ := db.TxFromContext(ctx)
+
+		keys := []string{g.Owner.NodeKey}
+		for _, c := range g.Columns {
+			keys = append(keys, c.NodeKey)
+		}
+		for _, r := range g.Refs {
+			keys = append(keys, r.Table.NodeKey)
+			for _, c := range r.Columns {
+				keys = append(keys, c.NodeKey)
+			}
+		}
+		found, err := findByKeys(ctx, nodes, keys)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]*Node, len(found))
+		for _, n := range found {
+			existing[n.NodeKey] = n
+		}
+		ids := make(map[string]int64, len(keys))
+
+		// 1. 表或数据视图及其列
+		if err := upsert(ctx, nodes, g.Owner, existing[g.Owner.NodeKey]); err != nil {
+			return err
+		}
+		ids[g.Owner.NodeKey] = g.Owner.Id
+		current := make(map[string]bool, len(g.Columns))
+		for _, c := range g.Columns {
+			c.ParentId = g.Owner.Id
+			if err := upsert(ctx, nodes, c, existing[c.NodeKey]); err != nil {
+				return err
+			}
+			ids[c.NodeKey] = c.Id
+			current[c.NodeKey] = true
+		}
+		old, err := nodes.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("parent_id", g.Owner.Id)}})
+		if err != nil {
+			return err
+		}
+		for _, c := range old {
+			if current[c.NodeKey] || c.Status == StatusRemoved {
+				continue
+			}
+			c.Status = StatusRemoved
+			if err := nodes.Update(ctx, c); err != nil {
+				return err
+			}
+		}
+
+		// 2. 引用的表及列（不存在时创建占位节点）
+		for _, r := range g.Refs {
+			if err := ensure(ctx, nodes, r.Table, existing, ids); err != nil {
+				return err
+			}
+			for _, c := range r.Columns {
+				c.ParentId = ids[r.Table.NodeKey]
+				if err := ensure(ctx, nodes, c, existing, ids); err != nil {
+					return err
+				}
+			}
+		}
+
+		// 3. 替换边
+		return replaceEdges(ctx, m.edges.WithTx(tx), g, ids)
+	})
+}
+
+// upsert 节点不存在时插入，存在时更新（保留创建时间），Id 回填
+func upsert(ctx context.Context, nodes repo.Repository[Node], n, existing *Node) error {
+	if existing == nil {
+		n.Id = 0
+		return nodes.Insert(ctx, n)
+	}
+	n.Id, n.CreatedAt = existing.Id, existing.CreatedAt
+	return nodes.Update(ctx, n)
+}
+
+// ensure 节点不存在时插入，存在时不修改
+func ensure(ctx context.Context, nodes repo.Repository[Node], n *Node, existing map[string]*Node, ids map[string]int64) error {
+	if _, ok := ids[n.NodeKey]; ok {
+		return nil
+	}
+	if e := existing[n.NodeKey]; e != nil {
+		ids[n.NodeKey] = e.Id
+		return nil
+	}
+	n.Id = 0
+	if err := nodes.Insert(ctx, n); err != nil {
+		return err
+	}
+	ids[n.NodeKey] = n.Id
+	return nil
+}
+
+// replaceEdges 删除 Owner 产生的边后写入新边，跳过重复及已由其他节点产生的边
+func replaceEdges(ctx context.Context, edges repo.Repository[Edge], g *Subgraph, ids map[string]int64) error {
+	old, err := edges.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("owner_id", g.Owner.Id)}})
+	if err != nil {
+		return err
+	}
+	if len(old) > 0 {
+		del := make([]int64, len(old))
+		for i, e := range old {
+			del[i] = e.Id
+		}
+		if err := edges.BatchDelete(ctx, del); err != nil {
+			return err
+		}
+	}
+	if len(g.Edges) == 0 {
+		return nil
+	}
+
+	type edgeKey struct {
+		source, target int64
+		typ            string
+	}
+	var sources []int64
+	for _, e := range g.Edges {
+		sources = append(sources, ids[e.Source])
+	}
+	others, err := edges.Find(ctx, repo.Query{Conds: []repo.Cond{repo.In("source_id", sources)}})
+	if err != nil {
+		return err
+	}
+	seen := make(map[edgeKey]bool, len(others)+len(g.Edges))
+	for _, e := range others {
+		seen[edgeKey{e.SourceId, e.TargetId, e.Type}] = true
+	}
+
+	var list []*Edge
+	for _, e := range g.Edges {
+		source, target := ids[e.Source], ids[e.Target]
+		if source == 0 || target == 0 {
+			return fmt.Errorf("lineage: edge references unknown node %s -> %s", e.Source, e.Target)
+		}
+		k := edgeKey{source, target, e.Type}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		owner := g.Owner.Id
+		if e.Owner != "" {
+			owner = ids[e.Owner]
+		}
+		list = append(list, &Edge{SourceId: source, TargetId: target, Type: e.Type, Origin: g.Origin, OwnerId: owner, Expr: e.Expr})
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return edges.BatchInsert(ctx, list)
+}
+
+// findByKeys 根据节点键批量查询
+func findByKeys(ctx context.Context, nodes repo.Repository[Node], keys []string) ([]*Node, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return nodes.Find(ctx, repo.Query{Conds: []repo.Cond{repo.In("node_key", keys)}})
+}
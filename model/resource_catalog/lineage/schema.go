@@ -0,0 +1,20 @@
+package lineage
+
+import (
+	"idrm/migrations"
+	"idrm/pkg/db/schemacheck"
+)
+
+// init 注册表结构一致性检查
+func init() {
+	schemacheck.Register(schemacheck.Entity{
+		Database:  migrations.ResourceCatalog,
+		Model:     &Node{},
+		SqlxTable: "lineage_node",
+	})
+	schemacheck.Register(schemacheck.Entity{
+		Database:  migrations.ResourceCatalog,
+		Model:     &Edge{},
+		SqlxTable: "lineage_edge",
+	})
+}
@@ -0,0 +1,183 @@
+package lineage
+
+import (
+	"context"
+
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/sqlparse"
+)
+
+// SyncSource 同步采集源的全部数据视图，节点的结构指纹及状态未变化的跳过，返回同步的数据视图数
+// 失败时返回已同步的数量及错误（已同步的不回滚，下次继续）
+func SyncSource(ctx context.Context, m Model, views dataview.Model, source string) (int, error) {
+	list, err := views.FindBySource(ctx, source)
+	if err != nil {
+		return 0, err
+	}
+	keys := make([]string, len(list))
+	for i, v := range list {
+		keys[i] = OwnerKey(v)
+	}
+	nodes, err := m.FindNodesByKeys(ctx, keys)
+	if err != nil {
+		return 0, err
+	}
+	byKey := make(map[string]*Node, len(nodes))
+	for _, n := range nodes {
+		byKey[n.NodeKey] = n
+	}
+
+	synced := 0
+	for _, v := range list {
+		if n := byKey[OwnerKey(v)]; n != nil && n.Fingerprint == v.Fingerprint && n.Status == v.Status && n.RefId == v.Id {
+			continue
+		}
+		if err := SyncView(ctx, m, views, v); err != nil {
+			return synced, err
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+// SyncView 按数据视图的列及定义解析结果（dataview.DataView.Lineage）写入其子图
+func SyncView(ctx context.Context, m Model, views dataview.Model, view *dataview.DataView) error {
+	var columns []*dataview.Column
+	if view.Status == dataview.StatusActive {
+		var err error
+		if columns, err = views.Columns(ctx, view.Id); err != nil {
+			return err
+		}
+	}
+	result, err := view.ParsedLineage()
+	if err != nil {
+		return err
+	}
+	return m.Apply(ctx, Build(view, columns, result))
+}
+
+// OwnerKey 数据视图对应的节点键：以 SQL 定义的为数据视图节点，其余为表节点
+func OwnerKey(view *dataview.DataView) string {
+	if view.TableType == dataview.TableTypeSQL {
+		return DataViewKey(namespace(view), view.SchemaName, view.Table)
+	}
+	return TableKey(namespace(view), view.SchemaName, view.Table)
+}
+
+// Build 生成数据视图的子图：表或数据视图节点、列节点（contains 边），
+// 及定义中引用的表（feeds 边）和列（derives 边）；已删除的数据视图只更新节点状态并清除其产生的边。
+// 未限定 schema 的表按数据视图所在 schema 解析，无法确定所属表的列只保留表级边；
+// 只有一个表的 * 时，未显式输出的列视为该表的同名列
+func Build(view *dataview.DataView, columns []*dataview.Column, result *sqlparse.Result) *Subgraph {
+	ns := namespace(view)
+	nodeType, origin := NodeTable, OriginHarvest
+	if view.TableType == dataview.TableTypeSQL {
+		nodeType, origin = NodeDataView, OriginSQL
+	}
+	owner := &Node{
+		NodeKey:     OwnerKey(view),
+		Type:        nodeType,
+		Name:        view.FullName(),
+		Namespace:   ns,
+		RefId:       view.Id,
+		Fingerprint: view.Fingerprint,
+		Status:      view.Status,
+		Description: truncate(view.Comment, 500),
+	}
+	g := &Subgraph{Owner: owner, Origin: origin}
+
+	for _, c := range columns {
+		col := &Node{NodeKey: ColumnKey(owner.NodeKey, c.Name), Type: NodeColumn, Name: c.Name, Namespace: ns,
+			Status: StatusActive, Description: truncate(c.Comment, 500)}
+		g.Columns = append(g.Columns, col)
+		g.Edges = append(g.Edges, EdgeRef{Source: owner.NodeKey, Target: col.NodeKey, Type: EdgeContains})
+	}
+	if result == nil || view.Status != dataview.StatusActive {
+		return g
+	}
+
+	refs := make(map[string]*RefTable)
+	refTable := func(schema, name string) *RefTable {
+		if schema == "" {
+			schema = view.SchemaName
+		}
+		k := TableKey(ns, schema, name)
+		if r, ok := refs[k]; ok {
+			return r
+		}
+		r := &RefTable{Table: &Node{NodeKey: k, Type: NodeTable, Name: schema + "." + name, Namespace: ns, Status: StatusActive}}
+		refs[k] = r
+		g.Refs = append(g.Refs, r)
+		g.Edges = append(g.Edges, EdgeRef{Source: k, Target: owner.NodeKey, Type: EdgeFeeds})
+		return r
+	}
+	for _, t := range result.Tables {
+		refTable(t.Schema, t.Name)
+	}
+
+	refColumns := make(map[string]bool)
+	derive := func(src sqlparse.ColumnRef, target, expr string) {
+		r := refTable(src.Schema, src.Table)
+		source := ColumnKey(r.Table.NodeKey, src.Column)
+		if !refColumns[source] {
+			refColumns[source] = true
+			r.Columns = append(r.Columns, &Node{NodeKey: source, Type: NodeColumn, Name: src.Column, Namespace: ns, Status: StatusActive})
+			g.Edges = append(g.Edges, EdgeRef{Source: r.Table.NodeKey, Target: source, Type: EdgeContains, Owner: r.Table.NodeKey})
+		}
+		g.Edges = append(g.Edges, EdgeRef{Source: source, Target: target, Type: EdgeDerives, Expr: expr})
+	}
+
+	// 显式输出的列按来源生成 derives 边，其余列视为单表 * 展开的同名列
+	outputs := make(map[string]bool, len(g.Columns))
+	for _, c := range g.Columns {
+		outputs[c.NodeKey] = true
+	}
+	explicit := make(map[string]bool, len(result.Columns))
+	var stars []sqlparse.ColumnRef
+	for _, out := range result.Columns {
+		if out.Name == "*" {
+			if out.Direct && len(out.Sources) == 1 && out.Sources[0].Table != "" {
+				stars = append(stars, out.Sources[0])
+			}
+			continue
+		}
+		target := ColumnKey(owner.NodeKey, out.Name)
+		explicit[target] = true
+		if !outputs[target] {
+			continue
+		}
+		expr := ""
+		if !out.Direct {
+			expr = truncate(out.Expr, 1000)
+		}
+		for _, src := range out.Sources {
+			if src.Table != "" && src.Column != "*" {
+				derive(src, target, expr)
+			}
+		}
+	}
+	if len(stars) == 1 {
+		for _, c := range g.Columns {
+			if !explicit[c.NodeKey] {
+				derive(sqlparse.ColumnRef{Schema: stars[0].Schema, Table: stars[0].Table, Column: c.Name}, c.NodeKey, "")
+			}
+		}
+	}
+	return g
+}
+
+// namespace 节点所在的命名空间：已注册数据源名称，未引用数据源时为采集源名称
+func namespace(view *dataview.DataView) string {
+	if view.Datasource != "" {
+		return view.Datasource
+	}
+	return view.Source
+}
+
+// truncate 按字符截断
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
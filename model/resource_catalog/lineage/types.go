@@ -0,0 +1,115 @@
+// Package lineage 数据血缘图：节点为源表及列、数据视图、目录资源、API，边按数据流向（上游指向下游）
+//
+// 表、列及数据视图节点由 SyncSource / SyncView 根据数据视图（model/resource_catalog/dataview）生成：
+// 视图定义的解析结果（pkg/sqlparse）转换为表级 feeds 边和列级 derives 边，
+// 每次同步在同一事务中替换该视图产生的边（Edge.OwnerId）；定义中引用但尚未采集的表及列创建为占位节点。
+// 目录资源、API 节点及 publishes、serves 边通过 API 手工登记。
+//
+// Traverse 按方向广度优先遍历，Analyze 汇总下游受影响的对象（影响分析）。
+package lineage
+
+import (
+	"strings"
+	"time"
+)
+
+// 模型名称（用于配置 DB.*.Models 按模型指定ORM）
+const (
+	ModelName     = "lineage_node"
+	EdgeModelName = "lineage_edge"
+)
+
+// 节点类型
+const (
+	NodeTable    = "table"     // 源表或视图（采集得到）
+	NodeColumn   = "column"    // 表或数据视图的列
+	NodeDataView = "data_view" // 以 SQL 定义的数据视图
+	NodeResource = "resource"  // 目录资源
+	NodeAPI      = "api"       // 对外服务接口
+)
+
+// 边类型（source 流向 target）
+const (
+	EdgeContains  = "contains"  // 表或数据视图 → 列
+	EdgeFeeds     = "feeds"     // 表 → 引用它的视图或数据视图
+	EdgeDerives   = "derives"   // 列 → 由它计算得到的列
+	EdgePublishes = "publishes" // 表或数据视图 → 目录资源
+	EdgeServes    = "serves"    // 目录资源 → API
+)
+
+// 边的来源
+const (
+	OriginHarvest = "harvest" // 采集的视图定义
+	OriginSQL     = "sql"     // 以 SQL 定义的数据视图
+	OriginManual  = "manual"  // 通过 API 登记
+)
+
+// 状态
+const (
+	StatusRemoved = 0 // 对应的表、列已删除
+	StatusActive  = 1
+)
+
+// Node 血缘节点
+type Node struct {
+	Id          int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	NodeKey     string    `json:"node_key" db:"node_key" gorm:"column:node_key;type:varchar(500);not null;uniqueIndex:uk_lineage_node_key"` // 见 TableKey、ColumnKey 等，小写
+	Type        string    `json:"type" db:"type" gorm:"column:type;type:varchar(20);not null;index:idx_lineage_node_type"`
+	Name        string    `json:"name" db:"name" gorm:"column:name;type:varchar(500);not null"`                // 表、数据视图为 schema.name，列为列名
+	Namespace   string    `json:"namespace" db:"namespace" gorm:"column:namespace;type:varchar(100);not null"` // 数据源或采集源名称
+	ParentId    int64     `json:"parent_id" db:"parent_id" gorm:"column:parent_id;not null;index:idx_lineage_node_parent"`
+	RefId       int64     `json:"ref_id" db:"ref_id" gorm:"column:ref_id;not null"`                                 // 表、数据视图为 data_view.id，占位节点为 0
+	Fingerprint string    `json:"fingerprint" db:"fingerprint" gorm:"column:fingerprint;type:varchar(64);not null"` // 同步时数据视图的结构指纹，未变化时跳过
+	Status      int       `json:"status" db:"status" gorm:"column:status;not null"`
+	Description string    `json:"description" db:"description" gorm:"column:description;type:varchar(500);not null"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Node) TableName() string {
+	return "lineage_node"
+}
+
+// Edge 血缘边
+type Edge struct {
+	Id        int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	SourceId  int64     `json:"source_id" db:"source_id" gorm:"column:source_id;not null;uniqueIndex:uk_lineage_edge,priority:1"`
+	TargetId  int64     `json:"target_id" db:"target_id" gorm:"column:target_id;not null;uniqueIndex:uk_lineage_edge,priority:2;index:idx_lineage_edge_target"`
+	Type      string    `json:"type" db:"type" gorm:"column:type;type:varchar(20);not null;uniqueIndex:uk_lineage_edge,priority:3"`
+	Origin    string    `json:"origin" db:"origin" gorm:"column:origin;type:varchar(20);not null"`
+	OwnerId   int64     `json:"owner_id" db:"owner_id" gorm:"column:owner_id;not null;index:idx_lineage_edge_owner"` // 产生该边的节点，手工登记为 0
+	Expr      string    `json:"expr" db:"expr" gorm:"column:expr;type:varchar(1000);not null"`                       // 列级边的表达式
+	CreatedAt time.Time `json:"created_at" db:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Edge) TableName() string {
+	return "lineage_edge"
+}
+
+// TableKey 表节点键：table:{namespace}:{schema}.{table}
+// 标识符统一转为小写：定义中的 FROM Orders 与采集的 orders（及 PostgreSQL 折叠为小写的未加引号标识符）为同一节点
+func TableKey(namespace, schema, table string) string {
+	return NodeTable + ":" + ident(namespace) + ":" + ident(schema) + "." + ident(table)
+}
+
+// DataViewKey 数据视图节点键：data_view:{namespace}:{schema}.{name}，标识符规则同 TableKey
+func DataViewKey(namespace, schema, name string) string {
+	return NodeDataView + ":" + ident(namespace) + ":" + ident(schema) + "." + ident(name)
+}
+
+// ColumnKey 列节点键：所属表或数据视图的键#{column}，标识符规则同 TableKey
+func ColumnKey(parentKey, column string) string {
+	return parentKey + "#" + ident(column)
+}
+
+// ExternalKey 目录资源、API 节点键：{type}:{key}，key 不区分大小写
+func ExternalKey(nodeType, k string) string {
+	return nodeType + ":" + strings.ToLower(k)
+}
+
+// ident 规范化标识符（不区分大小写）
+func ident(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,16 @@
+package lineage
+
+import (
+	"errors"
+
+	"idrm/pkg/db/repo"
+)
+
+var (
+	// ErrNotFound 节点不存在
+	ErrNotFound = repo.ErrNotFound
+	// ErrDuplicateNode 节点键已存在
+	ErrDuplicateNode = errors.New("lineage node already exists")
+	// ErrDuplicateEdge 同类型的边已存在
+	ErrDuplicateEdge = errors.New("lineage edge already exists")
+)
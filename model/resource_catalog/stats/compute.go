@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/dataview"
+)
+
+// 状态名称
+var statusLabels = map[int]string{
+	0: "禁用",
+	1: "启用",
+}
+
+// 资源（数据视图）状态名称
+var resourceStatusLabels = map[int]string{
+	dataview.StatusRemoved: "已删除",
+	dataview.StatusActive:  "正常",
+}
+
+// Compute 由全部类别及数据视图计算 now 所在日期的统计快照（各维度按 key 排序）
+//
+// 类别维度：每个类别计入其顶级类别的子树，父级不存在的类别视为顶级类别。
+// 资源维度：数据视图即目录资源，按状态统计全部数据视图，其他维度只统计未在源中删除的数据视图；
+// 按所属类别的顶级类别计入子树，未编目或类别不存在的计入 key 0。
+func Compute(categories []*category.Category, views []*dataview.DataView, now time.Time) []*Stat {
+	date := now.Format(DateLayout)
+	byId := make(map[int64]*category.Category, len(categories))
+	for _, c := range categories {
+		byId[c.Id] = c
+	}
+
+	var (
+		status  = make(map[int]int64)
+		level   = make(map[int]int64)
+		subtree = make(map[int64]int64)
+		created = make(map[string]int64)
+	)
+	for _, c := range categories {
+		status[c.Status]++
+		level[c.Level]++
+		subtree[rootOf(c, byId).Id]++
+		countCreated(created, c.CreatedAt, now)
+	}
+
+	stats := []*Stat{{StatDate: date, Dimension: DimTotal, Value: int64(len(categories))}}
+	for _, k := range sortedKeys(status) {
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimStatus, DimKey: strconv.Itoa(k), DimLabel: labelOf(statusLabels, k), Value: status[k]})
+	}
+	for _, k := range sortedKeys(level) {
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimLevel, DimKey: strconv.Itoa(k), DimLabel: fmt.Sprintf("%d级", k), Value: level[k]})
+	}
+	for _, id := range sortedKeys(subtree) {
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimSubtree, DimKey: strconv.FormatInt(id, 10), DimLabel: byId[id].Name, Value: subtree[id]})
+	}
+	stats = append(stats, createdStats(date, DimCreated, created)...)
+	return append(stats, computeResources(date, views, byId, now)...)
+}
+
+// computeResources 资源（数据视图）各维度的计数
+func computeResources(date string, views []*dataview.DataView, byId map[int64]*category.Category, now time.Time) []*Stat {
+	var (
+		total       int64
+		status      = make(map[int]int64)
+		subtree     = make(map[int64]int64)
+		sensitivity = make(map[string]int64)
+		department  = make(map[string]int64)
+		created     = make(map[string]int64)
+	)
+	for _, v := range views {
+		status[v.Status]++
+		if v.Status != dataview.StatusActive {
+			continue
+		}
+		total++
+		var root int64
+		if c, ok := byId[v.CategoryId]; ok {
+			root = rootOf(c, byId).Id
+		}
+		subtree[root]++
+		sensitivity[v.Sensitivity]++
+		department[v.Department]++
+		countCreated(created, v.CreatedAt, now)
+	}
+
+	stats := []*Stat{{StatDate: date, Dimension: DimResourceTotal, Value: total}}
+	for _, k := range sortedKeys(status) {
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimResourceStatus, DimKey: strconv.Itoa(k), DimLabel: labelOf(resourceStatusLabels, k), Value: status[k]})
+	}
+	for _, id := range sortedKeys(subtree) {
+		label := "未编目"
+		if id != 0 {
+			label = byId[id].Name
+		}
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimResourceSubtree, DimKey: strconv.FormatInt(id, 10), DimLabel: label, Value: subtree[id]})
+	}
+	for _, k := range sortedKeys(sensitivity) {
+		label, ok := dataview.SensitivityLabels[k]
+		switch {
+		case k == "":
+			label = "未定级"
+		case !ok:
+			label = k
+		}
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimResourceSensitivity, DimKey: k, DimLabel: label, Value: sensitivity[k]})
+	}
+	for _, k := range sortedKeys(department) {
+		label := k
+		if k == "" {
+			label = "未指定"
+		}
+		stats = append(stats, &Stat{StatDate: date, Dimension: DimResourceDepartment, DimKey: k, DimLabel: label, Value: department[k]})
+	}
+	return append(stats, createdStats(date, DimResourceCreated, created)...)
+}
+
+// countCreated 按统计窗口累计新增数量
+func countCreated(created map[string]int64, at, now time.Time) {
+	if !at.Before(now.AddDate(0, 0, -7)) {
+		created[KeyCreated7d]++
+	}
+	if !at.Before(now.AddDate(0, 0, -30)) {
+		created[KeyCreated30d]++
+	}
+}
+
+// createdStats 新增数量的快照项（两个统计窗口均写入，无新增时为 0）
+func createdStats(date, dimension string, created map[string]int64) []*Stat {
+	return []*Stat{
+		{StatDate: date, Dimension: dimension, DimKey: KeyCreated7d, DimLabel: "近7天", Value: created[KeyCreated7d]},
+		{StatDate: date, Dimension: dimension, DimKey: KeyCreated30d, DimLabel: "近30天", Value: created[KeyCreated30d]},
+	}
+}
+
+// labelOf 状态名称，未定义时为状态值
+func labelOf(labels map[int]string, k int) string {
+	if label, ok := labels[k]; ok {
+		return label
+	}
+	return strconv.Itoa(k)
+}
+
+// rootOf 沿父级查找顶级类别（父级不存在或出现环时返回当前节点）
+func rootOf(c *category.Category, byId map[int64]*category.Category) *category.Category {
+	seen := map[int64]bool{c.Id: true}
+	for {
+		parent, ok := byId[c.ParentId]
+		if c.ParentId == 0 || !ok || seen[parent.Id] {
+			return c
+		}
+		seen[parent.Id] = true
+		c = parent
+	}
+}
+
+// sortedKeys 升序排列的 map key
+func sortedKeys[K int | int64 | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"context"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/repo"
+)
+
+// Model 统计快照仓储
+type Model interface {
+	// Save 保存 date 的快照，同一事务中先删除该日已有的快照（同一天重复统计时覆盖）
+	Save(ctx context.Context, date string, stats []*Stat) error
+	// Latest 最近一天的快照，无快照时返回 ErrNotFound
+	Latest(ctx context.Context) ([]*Stat, error)
+	// Series 某维度取值自 from 日期（含）起的快照，按日期升序
+	Series(ctx context.Context, dimension, key, from string) ([]*Stat, error)
+}
+
+type model struct {
+	repo repo.Repository[Stat]
+}
+
+// NewModel 创建统计快照仓储（按配置选择ORM）
+func NewModel(conn *db.Conn) (Model, error) {
+	r, err := repo.New[Stat](conn, ModelName)
+	if err != nil {
+		return nil, err
+	}
+	return &model{repo: r}, nil
+}
+
+func (m *model) Save(ctx context.Context, date string, stats []*Stat) error {
+	return m.repo.Trans(ctx, func(ctx context.Context, r repo.Repository[Stat]) error {
+		existing, err := r.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("stat_date", date)}})
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			ids := make([]int64, len(existing))
+			for i, s := range existing {
+				ids[i] = s.Id
+			}
+			if err := r.BatchDelete(ctx, ids); err != nil {
+				return err
+			}
+		}
+		return r.BatchInsert(ctx, stats)
+	})
+}
+
+func (m *model) Latest(ctx context.Context) ([]*Stat, error) {
+	latest, err := m.repo.Find(ctx, repo.Query{
+		Conds:    []repo.Cond{repo.Eq("dimension", DimTotal)},
+		Orders:   []repo.Order{repo.Desc("stat_date")},
+		Page:     1,
+		PageSize: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) == 0 {
+		return nil, ErrNotFound
+	}
+	return m.repo.Find(ctx, repo.Query{Conds: []repo.Cond{repo.Eq("stat_date", latest[0].StatDate)}})
+}
+
+func (m *model) Series(ctx context.Context, dimension, key, from string) ([]*Stat, error) {
+	return m.repo.Find(ctx, repo.Query{
+		Conds:  []repo.Cond{repo.Eq("dimension", dimension), repo.Eq("dim_key", key), repo.Gte("stat_date", from)},
+		Orders: []repo.Order{repo.Asc("stat_date")},
+	})
+}
@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"idrm/migrations"
+	"idrm/model/resource_catalog/category"
+	"idrm/model/resource_catalog/dataview"
+	"idrm/pkg/db"
+	"idrm/pkg/testkit"
+)
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC)
+	categories := []*category.Category{
+		{Id: 1, Name: "数据资源", Level: 1, Status: 1, CreatedAt: now.AddDate(0, 0, -60)},
+		{Id: 2, Name: "业务资源", Level: 1, Status: 1, CreatedAt: now.AddDate(0, 0, -20)},
+		{Id: 3, Name: "库表", ParentId: 1, Level: 2, Status: 1, CreatedAt: now.AddDate(0, 0, -3)},
+		{Id: 4, Name: "接口", ParentId: 3, Level: 3, Status: 0, CreatedAt: now},
+		{Id: 5, Name: "孤立", ParentId: 99, Level: 2, Status: 1, CreatedAt: now.AddDate(0, 0, -40)},
+	}
+	views := []*dataview.DataView{
+		{Id: 1, Status: dataview.StatusActive, CategoryId: 3, Sensitivity: dataview.SensitivitySecret, Department: "财务部", CreatedAt: now.AddDate(0, 0, -2)},
+		{Id: 2, Status: dataview.StatusActive, CategoryId: 4, Sensitivity: dataview.SensitivityPublic, Department: "财务部", CreatedAt: now.AddDate(0, 0, -10)},
+		{Id: 3, Status: dataview.StatusActive, CategoryId: 2, Department: "销售部", CreatedAt: now.AddDate(0, 0, -60)},
+		{Id: 4, Status: dataview.StatusActive, CreatedAt: now.AddDate(0, 0, -60)},
+		{Id: 5, Status: dataview.StatusActive, CategoryId: 99, CreatedAt: now.AddDate(0, 0, -60)},
+		{Id: 6, Status: dataview.StatusRemoved, CategoryId: 2, Sensitivity: dataview.SensitivitySecret, Department: "销售部", CreatedAt: now},
+	}
+	got := make(map[string]int64)
+	for _, s := range Compute(categories, views, now) {
+		if s.StatDate != "2026-10-19" {
+			t.Errorf("StatDate = %s, want 2026-10-19", s.StatDate)
+		}
+		got[s.Dimension+"/"+s.DimKey] = s.Value
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want int64
+	}{
+		{"总数", "total/", 5},
+		{"启用", "status/1", 4},
+		{"禁用", "status/0", 1},
+		{"一级", "level/1", 2},
+		{"二级", "level/2", 2},
+		{"子树含子孙", "subtree/1", 3},
+		{"子树仅自身", "subtree/2", 1},
+		{"父级不存在视为顶级", "subtree/5", 1},
+		{"近7天新增", "created/7d", 2},
+		{"近30天新增", "created/30d", 3},
+		{"资源总数不含已删除", "resource_total/", 5},
+		{"资源正常", "resource_status/1", 5},
+		{"资源已删除", "resource_status/0", 1},
+		{"资源子树含子孙类别", "resource_subtree/1", 2},
+		{"资源子树", "resource_subtree/2", 1},
+		{"未编目及类别不存在", "resource_subtree/0", 2},
+		{"机密", "resource_sensitivity/secret", 1},
+		{"公开", "resource_sensitivity/public", 1},
+		{"未定级", "resource_sensitivity/", 3},
+		{"部门", "resource_department/财务部", 2},
+		{"部门不含已删除", "resource_department/销售部", 1},
+		{"未指定部门", "resource_department/", 2},
+		{"资源近7天新增", "resource_created/7d", 1},
+		{"资源近30天新增", "resource_created/30d", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got[tt.key] != tt.want {
+				t.Errorf("%s = %d, want %d", tt.key, got[tt.key], tt.want)
+			}
+		})
+	}
+}
+
+func TestModel(t *testing.T) {
+	for _, orm := range []string{db.ORMGorm, db.ORMSqlx} {
+		t.Run(orm, func(t *testing.T) {
+			ctx := context.Background()
+			model, err := NewModel(testkit.SQLite(t, migrations.ResourceCatalog, orm))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := model.Latest(ctx); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Latest() without snapshot error = %v, want ErrNotFound", err)
+			}
+
+			day1 := time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC)
+			day2 := day1.AddDate(0, 0, 1)
+			one := []*category.Category{{Id: 1, Name: "数据资源", Level: 1, Status: 1}}
+			two := append(one, &category.Category{Id: 2, Name: "业务资源", Level: 1, Status: 1})
+			// 同一天重复统计时覆盖
+			for _, snap := range []struct {
+				now        time.Time
+				categories []*category.Category
+			}{{day1, two}, {day1, one}, {day2, two}} {
+				if err := model.Save(ctx, snap.now.Format(DateLayout), Compute(snap.categories, nil, snap.now)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			latest, err := model.Latest(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := len(Compute(two, nil, day2)); len(latest) != want {
+				t.Fatalf("Latest() = %d stats, want %d", len(latest), want)
+			}
+			if latest[0].StatDate != "2026-10-19" {
+				t.Errorf("Latest() date = %s, want 2026-10-19", latest[0].StatDate)
+			}
+
+			series, err := model.Series(ctx, DimTotal, "", "2026-10-01")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(series) != 2 || series[0].Value != 1 || series[1].Value != 2 {
+				t.Errorf("Series() = %+v, want totals [1 2]", series)
+			}
+		})
+	}
+}
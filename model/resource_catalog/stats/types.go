@@ -0,0 +1,54 @@
+// Package stats 资源目录统计快照：统计任务（job statistics）按日写入各维度计数，供看板接口查询
+//
+// 统计类别数量及资源数量，资源即数据视图（model/resource_catalog/dataview），
+// 按其编目属性（所属类别、敏感级别、所属部门）及状态统计
+package stats
+
+import "time"
+
+// ModelName 模型名称（用于配置 DB.*.Models 按模型指定ORM）
+const ModelName = "catalog_stat"
+
+// DateLayout 统计日期格式
+const DateLayout = "2006-01-02"
+
+// 类别统计维度
+const (
+	DimTotal   = "total"   // 类别总数，key 为空
+	DimStatus  = "status"  // 按状态，key 为状态值
+	DimLevel   = "level"   // 按层级，key 为层级
+	DimSubtree = "subtree" // 按顶级类别的子树（含自身），key 为顶级类别ID
+	DimCreated = "created" // 新增数量，key 为统计窗口（7d/30d）
+)
+
+// 资源统计维度（除按状态外只统计未在源中删除的数据视图）
+const (
+	DimResourceTotal       = "resource_total"       // 资源总数，key 为空
+	DimResourceStatus      = "resource_status"      // 按状态（含已删除），key 为状态值
+	DimResourceSubtree     = "resource_subtree"     // 按所属类别的顶级类别子树，key 为顶级类别ID，0 为未编目
+	DimResourceSensitivity = "resource_sensitivity" // 按敏感级别，key 为级别，空为未定级
+	DimResourceDepartment  = "resource_department"  // 按所属部门，key 为部门，空为未指定
+	DimResourceCreated     = "resource_created"     // 新增数量，key 为统计窗口（7d/30d）
+)
+
+// 新增数量统计窗口
+const (
+	KeyCreated7d  = "7d"
+	KeyCreated30d = "30d"
+)
+
+// Stat 统计快照中的一项
+type Stat struct {
+	Id        int64     `json:"id" db:"id" gorm:"column:id;primaryKey"`
+	StatDate  string    `json:"stat_date" db:"stat_date" gorm:"column:stat_date;type:varchar(10);not null;uniqueIndex:uk_catalog_stat,priority:1;index:idx_catalog_stat_dimension,priority:3"`
+	Dimension string    `json:"dimension" db:"dimension" gorm:"column:dimension;type:varchar(50);not null;uniqueIndex:uk_catalog_stat,priority:2;index:idx_catalog_stat_dimension,priority:1"`
+	DimKey    string    `json:"dim_key" db:"dim_key" gorm:"column:dim_key;type:varchar(100);not null;uniqueIndex:uk_catalog_stat,priority:3;index:idx_catalog_stat_dimension,priority:2"`
+	DimLabel  string    `json:"dim_label" db:"dim_label" gorm:"column:dim_label;type:varchar(100);not null"`
+	Value     int64     `json:"value" db:"value" gorm:"column:value;not null"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Stat) TableName() string {
+	return "catalog_stat"
+}
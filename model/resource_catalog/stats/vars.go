@@ -0,0 +1,6 @@
+package stats
+
+import "idrm/pkg/db/repo"
+
+// ErrNotFound 暂无统计快照
+var ErrNotFound = repo.ErrNotFound
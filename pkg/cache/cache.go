@@ -0,0 +1,215 @@
+// Package cache 两级读穿缓存：进程内 LRU + 可选 Redis
+//
+// 未命中时通过 singleflight 合并同一 key 的并发回源，回源结果为空时缓存占位符（负缓存），
+// 防止缓存击穿及穿透。值以 JSON 存储，读取时解码为调用方提供的类型。
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/collection"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/mathx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/syncx"
+)
+
+// notFoundPlaceholder 负缓存占位符
+const notFoundPlaceholder = "*"
+
+// expiryDeviation 过期时间随机偏差，避免大量 key 同时过期
+const expiryDeviation = 0.05
+
+// ErrNotFound 回源结果为空（含命中负缓存）
+var ErrNotFound = errors.New("cache: not found")
+
+// Config 缓存配置
+type Config struct {
+	Enabled     bool            `json:",default=true"`
+	LocalTTL    int             `json:",default=5"`     // 进程内缓存过期时间(秒)，多实例间不同步，宜短
+	LocalLimit  int             `json:",default=10000"` // 进程内缓存最大条目数（LRU 淘汰）
+	RedisTTL    int             `json:",default=600"`   // Redis 缓存过期时间(秒)
+	NotFoundTTL int             `json:",default=60"`    // 负缓存过期时间(秒)
+	KeyPrefix   string          `json:",default=idrm:"` // Redis key 前缀
+	Redis       redis.RedisConf `json:",optional"`      // 未配置 Host 时只使用进程内缓存
+}
+
+// Stats 命中统计
+type Stats struct {
+	LocalHits   uint64
+	LocalMisses uint64
+	RedisHits   uint64
+	RedisMisses uint64
+	Loads       uint64 // 回源次数
+	NotFound    uint64 // 回源结果为空的次数
+}
+
+// Cache 两级缓存
+type Cache struct {
+	name        string
+	local       *collection.Cache
+	redis       *redis.Redis // nil 表示未启用 Redis
+	keyPrefix   string
+	redisTTL    time.Duration
+	notFoundTTL time.Duration
+	flight      syncx.SingleFlight
+	unstable    mathx.Unstable
+
+	localHits, localMisses, redisHits, redisMisses, loads, notFound atomic.Uint64
+}
+
+// New 创建缓存，name 用于区分指标及日志（如 category）
+func New(name string, c Config) (*Cache, error) {
+	local, err := collection.NewCache(time.Duration(c.LocalTTL)*time.Second,
+		collection.WithLimit(c.LocalLimit), collection.WithName(name))
+	if err != nil {
+		return nil, fmt.Errorf("create local cache: %w", err)
+	}
+
+	cache := &Cache{
+		name:        name,
+		local:       local,
+		keyPrefix:   c.KeyPrefix + name + ":",
+		redisTTL:    time.Duration(c.RedisTTL) * time.Second,
+		notFoundTTL: time.Duration(c.NotFoundTTL) * time.Second,
+		flight:      syncx.NewSingleFlight(),
+		unstable:    mathx.NewUnstable(expiryDeviation),
+	}
+	if c.Redis.Host != "" {
+		if cache.redis, err = redis.NewRedis(c.Redis); err != nil {
+			return nil, fmt.Errorf("create redis: %w", err)
+		}
+	}
+	return cache, nil
+}
+
+// Take 读取缓存，未命中时调用 query 回源并写入缓存
+// query 返回 nil 值表示不存在，此时写入负缓存并返回 ErrNotFound；query 返回错误时不缓存
+func (c *Cache) Take(ctx context.Context, key string, v interface{}, query func(ctx context.Context) (interface{}, error)) error {
+	if data, ok := c.local.Get(key); ok {
+		c.record(&c.localHits, tierLocal, resultHit)
+		return decode(data.(string), v)
+	}
+	c.record(&c.localMisses, tierLocal, resultMiss)
+
+	data, err := c.flight.Do(key, func() (interface{}, error) {
+		return c.load(ctx, key, query)
+	})
+	if err != nil {
+		return err
+	}
+	return decode(data.(string), v)
+}
+
+// load 依次读取 Redis、回源，并回填上一级缓存
+func (c *Cache) load(ctx context.Context, key string, query func(ctx context.Context) (interface{}, error)) (string, error) {
+	if c.redis != nil {
+		data, err := c.redis.GetCtx(ctx, c.keyPrefix+key)
+		switch {
+		case err != nil:
+			// Redis 不可用时降级为直接回源
+			logx.WithContext(ctx).Errorf("缓存 %s 读取 Redis 失败: key=%s, err=%v", c.name, key, err)
+		case data != "":
+			c.record(&c.redisHits, tierRedis, resultHit)
+			c.local.Set(key, data)
+			return data, nil
+		default:
+			c.record(&c.redisMisses, tierRedis, resultMiss)
+		}
+	}
+
+	c.loads.Add(1)
+	val, err := query(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	data, ttl := notFoundPlaceholder, c.notFoundTTL
+	if !isNil(val) {
+		content, err := json.Marshal(val)
+		if err != nil {
+			return "", fmt.Errorf("cache: encode %s: %w", key, err)
+		}
+		data, ttl = string(content), c.redisTTL
+	} else {
+		c.notFound.Add(1)
+		metricNotFound.Inc(c.name)
+	}
+
+	c.local.Set(key, data)
+	if c.redis != nil {
+		seconds := int(c.unstable.AroundDuration(ttl).Seconds())
+		if err := c.redis.SetexCtx(ctx, c.keyPrefix+key, data, max(seconds, 1)); err != nil {
+			logx.WithContext(ctx).Errorf("缓存 %s 写入 Redis 失败: key=%s, err=%v", c.name, key, err)
+		}
+	}
+	return data, nil
+}
+
+// Del 删除缓存（两级均删除）
+// 进程内缓存只能删除本实例的数据，其他实例依赖 LocalTTL 过期
+func (c *Cache) Del(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	for _, key := range keys {
+		c.local.Del(key)
+	}
+	if c.redis == nil {
+		return nil
+	}
+
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = c.keyPrefix + key
+	}
+	if _, err := c.redis.DelCtx(ctx, redisKeys...); err != nil {
+		return fmt.Errorf("cache: delete %v: %w", keys, err)
+	}
+	return nil
+}
+
+// Stats 命中统计
+func (c *Cache) Stats() Stats {
+	return Stats{
+		LocalHits:   c.localHits.Load(),
+		LocalMisses: c.localMisses.Load(),
+		RedisHits:   c.redisHits.Load(),
+		RedisMisses: c.redisMisses.Load(),
+		Loads:       c.loads.Load(),
+		NotFound:    c.notFound.Load(),
+	}
+}
+
+// record 更新统计及指标
+func (c *Cache) record(counter *atomic.Uint64, tier, result string) {
+	counter.Add(1)
+	metricRequests.Inc(c.name, tier, result)
+}
+
+// decode 解码缓存值，占位符返回 ErrNotFound
+func decode(data string, v interface{}) error {
+	if data == notFoundPlaceholder {
+		return ErrNotFound
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+// isNil 判断回源结果是否为空（含值为 nil 的指针、切片）
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
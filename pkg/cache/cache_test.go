@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type item struct {
+	Name string
+}
+
+func newTestCache(t *testing.T) *Cache {
+	t.Helper()
+
+	c, err := New("test", Config{LocalTTL: 60, LocalLimit: 100, RedisTTL: 60, NotFoundTTL: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCache_Take(t *testing.T) {
+	errQuery := errors.New("query failed")
+
+	tests := []struct {
+		name      string
+		result    interface{}
+		err       error
+		wantErr   error
+		wantName  string
+		wantLoads uint64 // 连续两次 Take 的回源次数
+	}{
+		{"命中后不再回源", &item{Name: "a"}, nil, nil, "a", 1},
+		{"不存在时负缓存", (*item)(nil), nil, ErrNotFound, "", 1},
+		{"回源失败不缓存", nil, errQuery, errQuery, "", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache(t)
+			query := func(ctx context.Context) (interface{}, error) {
+				return tt.result, tt.err
+			}
+
+			for i := 0; i < 2; i++ {
+				var got item
+				err := c.Take(context.Background(), "k", &got, query)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Take() error = %v, want %v", err, tt.wantErr)
+				}
+				if got.Name != tt.wantName {
+					t.Errorf("Take() = %+v, want name %q", got, tt.wantName)
+				}
+			}
+			if got := c.Stats().Loads; got != tt.wantLoads {
+				t.Errorf("Stats().Loads = %d, want %d", got, tt.wantLoads)
+			}
+		})
+	}
+}
+
+func TestCache_Del(t *testing.T) {
+	c := newTestCache(t)
+	ctx := context.Background()
+
+	name := "a"
+	query := func(ctx context.Context) (interface{}, error) {
+		return &item{Name: name}, nil
+	}
+
+	var got item
+	if err := c.Take(ctx, "k", &got, query); err != nil {
+		t.Fatal(err)
+	}
+	name = "b"
+	if err := c.Del(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Take(ctx, "k", &got, query); err != nil || got.Name != "b" {
+		t.Errorf("Take() after Del = %+v, %v, want b", got, err)
+	}
+}
+
+func TestCache_SingleFlight(t *testing.T) {
+	c := newTestCache(t)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	query := func(ctx context.Context) (interface{}, error) {
+		loads.Add(1)
+		<-release
+		return &item{Name: "a"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var got item
+			if err := c.Take(context.Background(), "k", &got, query); err != nil || got.Name != "a" {
+				t.Errorf("Take() = %+v, %v", got, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Errorf("concurrent Take() loads = %d, want 1", got)
+	}
+}
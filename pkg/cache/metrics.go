@@ -0,0 +1,29 @@
+package cache
+
+import "github.com/zeromicro/go-zero/core/metric"
+
+// 指标层级及结果
+const (
+	tierLocal  = "local"
+	tierRedis  = "redis"
+	resultHit  = "hit"
+	resultMiss = "miss"
+)
+
+var (
+	metricRequests = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "idrm",
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "cache requests by tier and result",
+		Labels:    []string{"name", "tier", "result"},
+	})
+
+	metricNotFound = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "idrm",
+		Subsystem: "cache",
+		Name:      "not_found_total",
+		Help:      "cache loads that returned not found",
+		Labels:    []string{"name"},
+	})
+)
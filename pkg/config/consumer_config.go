@@ -3,19 +3,19 @@ package config
 // ConsumerConfig 消费者服务配置
 type ConsumerConfig struct {
 	Name string
-	Mode string
+	Mode string `json:",default=pro"`
 
 	// 多数据库配置
-	DataSources DataSourcesConfig
+	DataSources DataSourcesConfig `json:",optional"`
 
 	// Redis配置
-	Redis RedisConfig
+	Redis RedisConfig `json:",optional"`
 
 	// Kafka消费者配置
 	Kafka KafkaConsumerConfig
 
 	// 日志配置
-	Log LogConfig
+	Log LogConfig `json:",optional"`
 
 	// 重试配置
 	Retry RetryConfig
@@ -29,17 +29,18 @@ type KafkaConsumerConfig struct {
 
 // ConsumerItemConfig 单个消费者配置
 type ConsumerItemConfig struct {
-	Group          string
-	Topics         []string
-	Workers        int
-	AutoCommit     bool
-	CommitInterval int
+	Group           string
+	Topics          []string
+	Workers         int    `json:",default=1"`     // 并发处理数，同一 key 的消息由同一 worker 顺序处理
+	AutoCommit      bool   `json:",default=false"` // true：按 CommitInterval 定期提交；false：每条处理完成后立即提交
+	CommitInterval  int    `json:",default=1"`     // 定期提交间隔(秒)
+	DeadLetterTopic string `json:",optional"`      // 死信主题，为空时为 {topic}.dlq
 }
 
 // RetryConfig 重试配置
 type RetryConfig struct {
-	MaxRetries      int
-	InitialInterval int
-	MaxInterval     int
-	Multiplier      int
+	MaxRetries      int `json:",default=3"`     // 处理失败后的最大重试次数，耗尽后写入死信
+	InitialInterval int `json:",default=1000"`  // 首次重试间隔(毫秒)
+	MaxInterval     int `json:",default=30000"` // 最大重试间隔(毫秒)
+	Multiplier      int `json:",default=2"`     // 重试间隔倍数
 }
@@ -23,7 +23,9 @@ type JobConfig struct {
 
 // KafkaProducerConfig Kafka生产者配置
 type KafkaProducerConfig struct {
-	Brokers []string
+	Brokers      []string
+	Topic        string `json:",default=idrm.catalog.events"` // 目录变更事件主题
+	WriteTimeout int    `json:",default=10"`                  // 写入超时(秒)
 }
 
 // JobsConfig 定时任务配置
@@ -35,15 +37,18 @@ type JobsConfig struct {
 
 // JobItemConfig 单个任务配置
 type JobItemConfig struct {
-	Cron    string
-	Enabled bool
+	Cron    string `json:",optional"`      // cron 表达式（5 位，支持 @every 1h 等描述符）
+	Enabled bool   `json:",default=false"` // 是否按 Cron 定时执行，未启用时仍可手动触发
+	Timeout int    `json:",default=3600"`  // 单次执行超时(秒)
 }
 
-// CleanupJobConfig 清理任务配置
+// CleanupJobConfig 清理任务配置：删除保留期之前的执行记录、已投递的发件箱记录及本地缓存文件
 type CleanupJobConfig struct {
-	Cron          string
-	Enabled       bool
-	RetentionDays int
+	JobItemConfig
+	RetentionDays int      `json:",default=90"`   // 保留天数
+	BatchSize     int      `json:",default=1000"` // 每批删除条数（每批单独提交，避免长时间锁表）
+	SpoolDirs     []string `json:",optional"`     // 本地缓存目录（如审计/日志落盘目录），删除修改时间早于保留期的文件
+	SpoolPattern  string   `json:",default=*"`    // 缓存文件名匹配规则（filepath.Match）
 }
 
 // LogConfig 日志配置
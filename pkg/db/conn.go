@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"idrm/pkg/db/dialect"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"gorm.io/gorm"
+)
+
+// ORM 类型
+const (
+	ORMGorm = "gorm"
+	ORMSqlx = "sqlx"
+)
+
+// pingTimeout 启动时连接检查超时
+const pingTimeout = 5 * time.Second
+
+// 错误定义
+var (
+	ErrUnknownORM    = errors.New("unknown orm")
+	ErrUnknownDriver = errors.New("unknown driver")
+	ErrORMNotOpened  = errors.New("orm connection not opened")
+)
+
+// Conn 数据库连接
+// gorm 与 sqlx 共享同一个 *sql.DB 连接池，仅当配置中有模型使用 gorm 时才初始化 gorm
+// 配置了副本时，gorm 通过 dbresolver 将查询路由到副本，sqlx 通过 ReadSqlConn 获取读连接
+type Conn struct {
+	DB   *sql.DB  // 主库
+	Gorm *gorm.DB // 主库（已注册读写分离）
+
+	config   Config
+	replicas *replicaSet
+}
+
+// Open 按配置打开数据库连接并检查连通性，连接失败或ORM配置非法时直接返回错误（不做降级）
+func Open(c Config) (*Conn, error) {
+	conn, err := NewConn(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := conn.Ping(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// NewConn 按配置创建数据库连接但不建立网络连接（首次使用时才连接）
+// ORM配置非法时返回错误
+func NewConn(c Config) (*Conn, error) {
+	if !c.Dialect().Valid() {
+		return nil, fmt.Errorf("%w: %q (expected mysql, postgres or sqlite)", ErrUnknownDriver, c.Driver)
+	}
+	if err := c.validateORM(); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := c.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", c.Database, err)
+	}
+	c.configurePool(sqlDB)
+
+	conn := &Conn{DB: sqlDB, config: c}
+	if len(c.Replicas) > 0 {
+		if conn.replicas, err = newReplicaSet(c); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	if c.uses(ORMGorm) {
+		// 不在初始化时访问数据库（跳过 ping 及版本查询）
+		gormConfig := c.gormConfig()
+		gormConfig.DisableAutomaticPing = true
+		conn.Gorm, err = gorm.Open(c.gormDialector(sqlDB), gormConfig)
+		if err == nil && conn.replicas != nil {
+			err = conn.replicas.registerResolver(c, conn.Gorm, sqlDB)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("init gorm for %s: %w", c.Database, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// Ping 检查连通性
+func (c *Conn) Ping(ctx context.Context) error {
+	if err := c.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("connect %s: %w", c.config.Addr(), err)
+	}
+	return nil
+}
+
+// Dialect SQL 方言（sqlx 模型据此改写占位符、选择 RETURNING 或 LastInsertId）
+func (c *Conn) Dialect() dialect.Dialect {
+	return c.config.Dialect()
+}
+
+// ORMFor 获取模型使用的ORM（Models 中的覆盖优先，否则使用默认 ORM）
+func (c *Conn) ORMFor(model string) string {
+	return c.config.ORMFor(model)
+}
+
+// SqlConn 主库 sqlx 连接（写操作及事务）
+func (c *Conn) SqlConn() sqlx.SqlConn {
+	return sqlx.NewSqlConnFromDB(c.DB)
+}
+
+// ReadSqlConn sqlx 读连接，查询路由到副本；未配置副本时等同于 SqlConn
+func (c *Conn) ReadSqlConn() sqlx.SqlConn {
+	primary := c.SqlConn()
+	if c.replicas == nil {
+		return primary
+	}
+
+	replicas := make([]sqlx.SqlConn, len(c.replicas.replicas))
+	for i, r := range c.replicas.replicas {
+		replicas[i] = sqlx.NewSqlConnFromDB(r.db)
+	}
+	return &readConn{SqlConn: primary, set: c.replicas, replicas: replicas}
+}
+
+// CheckReplicas 检查副本连通性及复制延迟，不可用的副本暂不路由读请求
+func (c *Conn) CheckReplicas(ctx context.Context) {
+	if c.replicas != nil {
+		c.replicas.check(ctx)
+	}
+}
+
+// ReplicaStats 副本状态
+func (c *Conn) ReplicaStats() []ReplicaStats {
+	if c.replicas == nil {
+		return nil
+	}
+	return c.replicas.stats()
+}
+
+// Close 关闭连接池（含副本）
+func (c *Conn) Close() error {
+	err := c.DB.Close()
+	if c.replicas != nil {
+		err = errors.Join(err, c.replicas.close())
+	}
+	return err
+}
+
+// ORMFor 获取模型使用的ORM
+func (c Config) ORMFor(model string) string {
+	if orm, ok := c.Models[model]; ok {
+		return orm
+	}
+	if c.ORM == "" {
+		return ORMGorm
+	}
+	return c.ORM
+}
+
+// uses 是否有模型使用指定ORM
+func (c Config) uses(orm string) bool {
+	if c.ORMFor("") == orm {
+		return true
+	}
+	for _, o := range c.Models {
+		if o == orm {
+			return true
+		}
+	}
+	return false
+}
+
+// validateORM 校验默认ORM及按模型覆盖的配置
+func (c Config) validateORM() error {
+	if orm := c.ORMFor(""); orm != ORMGorm && orm != ORMSqlx {
+		return fmt.Errorf("%w: %q (expected gorm or sqlx)", ErrUnknownORM, orm)
+	}
+
+	models := make([]string, 0, len(c.Models))
+	for model := range c.Models {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	for _, model := range models {
+		if orm := c.Models[model]; orm != ORMGorm && orm != ORMSqlx {
+			return fmt.Errorf("%w: %q for model %s (expected gorm or sqlx)", ErrUnknownORM, orm, model)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestConfigORM(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		model   string
+		want    string
+		useGorm bool
+		wantErr error
+	}{
+		{"默认gorm", Config{}, "category", ORMGorm, true, nil},
+		{"默认sqlx", Config{ORM: ORMSqlx}, "category", ORMSqlx, false, nil},
+		{"按模型覆盖", Config{ORM: ORMSqlx, Models: map[string]string{"category": ORMGorm}}, "category", ORMGorm, true, nil},
+		{"其他模型使用默认", Config{ORM: ORMSqlx, Models: map[string]string{"category": ORMGorm}}, "directory", ORMSqlx, true, nil},
+		{"非法默认ORM", Config{ORM: "xorm"}, "category", "xorm", false, ErrUnknownORM},
+		{"非法模型ORM", Config{Models: map[string]string{"category": "ent"}}, "category", "ent", true, ErrUnknownORM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.ORMFor(tt.model); got != tt.want {
+				t.Errorf("ORMFor(%s) = %s, want %s", tt.model, got, tt.want)
+			}
+			if got := tt.config.uses(ORMGorm); got != tt.useGorm {
+				t.Errorf("uses(gorm) = %v, want %v", got, tt.useGorm)
+			}
+			if err := tt.config.validateORM(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateORM() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigDSN(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{"默认mysql", Config{Host: "db", Username: "u", Password: "p", Database: "d", Charset: "utf8mb4"},
+			"u:p@tcp(db:3306)/d?loc=Local&parseTime=true&charset=utf8mb4"},
+		{"postgres默认端口", Config{Driver: "postgres", Host: "db", Username: "u", Password: "p", Database: "d", SSLMode: "disable"},
+			"postgres://u:p@db:5432/d?sslmode=disable"},
+		{"sqlite文件路径", Config{Driver: "sqlite", Database: "/tmp/d.db"},
+			"/tmp/d.db?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.DSN(); got != tt.want {
+				t.Errorf("DSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigDSNEscape(t *testing.T) {
+	// 密码、库名中的特殊字符不能产生额外的连接参数
+	password := "x host=evil sslmode=disable @/:?&"
+	database := "db?allowAllFiles=true&x=/y"
+
+	my, err := mysql.ParseDSN(Config{Host: "db", Username: "u@x", Password: password, Database: database, Charset: "utf8mb4"}.DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if my.User != "u@x" || my.Passwd != password || my.DBName != database || my.Addr != "db:3306" || my.AllowAllFiles {
+		t.Errorf("mysql config = %+v", my)
+	}
+
+	pg, err := pgx.ParseConfig(Config{Driver: "postgres", Host: "db", Username: "u", Password: password, Database: database, SSLMode: "disable"}.DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pg.Host != "db" || pg.Port != 5432 || pg.User != "u" || pg.Password != password || pg.Database != database || pg.TLSConfig != nil {
+		t.Errorf("postgres config = host %s port %d user %s password %q database %q", pg.Host, pg.Port, pg.User, pg.Password, pg.Database)
+	}
+}
+
+func TestConnPingError(t *testing.T) {
+	// 未配置端口时错误中为方言默认端口
+	conn, err := NewConn(Config{Driver: "postgres", Host: "127.0.0.1", Username: "u", Password: "p", Database: "d", SSLMode: "disable", ORM: ORMSqlx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err = conn.Ping(ctx)
+	if err == nil || !strings.Contains(err.Error(), "connect 127.0.0.1:5432/d:") {
+		t.Errorf("Ping() error = %v, want address 127.0.0.1:5432/d", err)
+	}
+}
@@ -0,0 +1,98 @@
+// Package dialect SQL 方言：占位符改写、RETURNING 支持及 database/sql 驱动名
+package dialect
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect SQL 方言
+type Dialect string
+
+// 支持的方言
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// Valid 是否为支持的方言
+func (d Dialect) Valid() bool {
+	switch d {
+	case MySQL, Postgres, SQLite:
+		return true
+	}
+	return false
+}
+
+// DriverName database/sql 驱动名
+func (d Dialect) DriverName() string {
+	switch d {
+	case Postgres:
+		return "pgx"
+	case SQLite:
+		return "sqlite"
+	default:
+		return "mysql"
+	}
+}
+
+// SupportsReturning 是否支持 INSERT ... RETURNING（不支持时使用 LastInsertId）
+func (d Dialect) SupportsReturning() bool {
+	return d == Postgres || d == SQLite
+}
+
+// Rebind 将 ? 占位符改写为方言占位符（PostgreSQL 为 $1, $2...）
+// 忽略引号内及注释中的 ?
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		b     strings.Builder
+		n     int
+		quote byte // 当前所在引号，0 表示不在引号内
+	)
+	b.Grow(len(query) + 8)
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		if quote != 0 {
+			b.WriteByte(c)
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"':
+			quote = c
+			b.WriteByte(c)
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Returning 生成插入返回主键的子句，不支持时返回空字符串
+func (d Dialect) Returning(column string) string {
+	if !d.SupportsReturning() {
+		return ""
+	}
+	return " RETURNING " + column
+}
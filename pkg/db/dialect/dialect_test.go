@@ -0,0 +1,26 @@
+package dialect
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{"MySQL不改写", MySQL, "SELECT * FROM t WHERE a = ? AND b = ?", "SELECT * FROM t WHERE a = ? AND b = ?"},
+		{"SQLite不改写", SQLite, "SELECT * FROM t WHERE a = ?", "SELECT * FROM t WHERE a = ?"},
+		{"PostgreSQL改写", Postgres, "UPDATE t SET a = ?, b = ? WHERE id = ?", "UPDATE t SET a = $1, b = $2 WHERE id = $3"},
+		{"忽略引号内", Postgres, "SELECT '?' AS q, \"a?\" FROM t WHERE a = ?", "SELECT '?' AS q, \"a?\" FROM t WHERE a = $1"},
+		{"忽略注释", Postgres, "SELECT 1 -- why?\nFROM t WHERE a = ?", "SELECT 1 -- why?\nFROM t WHERE a = $1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.Rebind(tt.query); got != tt.want {
+				t.Errorf("Rebind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
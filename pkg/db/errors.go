@@ -0,0 +1,33 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// errorTranslators 各方言的驱动错误翻译（复用 gorm dialector 的错误码映射）
+var errorTranslators = []gorm.ErrorTranslator{
+	mysql.Dialector{},
+	postgres.Dialector{},
+	sqlite.Dialector{},
+}
+
+// IsDuplicateKey 是否为唯一键冲突（MySQL 1062、PostgreSQL 23505、SQLite UNIQUE/PRIMARY KEY 约束）
+// 同时适用于 gorm 与 sqlx 返回的错误
+func IsDuplicateKey(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return true
+		}
+		for _, translator := range errorTranslators {
+			if translator.Translate(err) == gorm.ErrDuplicatedKey {
+				return true
+			}
+		}
+	}
+	return false
+}
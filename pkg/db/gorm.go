@@ -1,10 +1,20 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 
+	"idrm/pkg/db/dialect"
+
+	"github.com/glebarez/sqlite"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
@@ -13,12 +23,14 @@ import (
 // Config 数据库配置
 type Config struct {
 	// 基础连接配置
+	Driver   string `json:",default=mysql,options=mysql|postgres|sqlite"` // SQL 方言
 	Host     string `json:",default=127.0.0.1"`
-	Port     int    `json:",default=3306"`
-	Database string
-	Username string
-	Password string
-	Charset  string `json:",default=utf8mb4"`
+	Port     int    `json:",optional"` // 默认 mysql 3306、postgres 5432
+	Database string // sqlite 为数据库文件路径（:memory: 为内存库）
+	Username string `json:",optional"`
+	Password string `json:",optional"`
+	Charset  string `json:",default=utf8mb4"` // 仅 mysql
+	SSLMode  string `json:",default=disable"` // 仅 postgres
 
 	// 连接池配置
 	MaxIdleConns    int `json:",default=10"`   // 最大空闲连接数
@@ -26,6 +38,14 @@ type Config struct {
 	ConnMaxLifetime int `json:",default=3600"` // 连接最大生存时间(秒)
 	ConnMaxIdleTime int `json:",default=600"`  // 连接最大空闲时间(秒)
 
+	// 读写分离配置：只读查询按权重路由到副本，写操作及事务使用主库
+	Replicas      []ReplicaConfig `json:",optional"`
+	MaxReplicaLag int             `json:",default=5"` // 副本最大复制延迟(秒)，超过时不再路由读请求，0 表示不检查
+
+	// ORM 配置
+	ORM    string            `json:",default=gorm,options=gorm|sqlx"` // 默认ORM: gorm/sqlx
+	Models map[string]string `json:",optional"`                       // 按模型覆盖ORM，如 category: sqlx
+
 	// 日志配置
 	LogLevel          string `json:",default=warn"` // silent/error/warn/info
 	SlowThreshold     int    `json:",default=200"`  // 慢查询阈值(毫秒)
@@ -35,55 +55,141 @@ type Config struct {
 	DisableForeignKey bool   `json:",default=true"` // 禁用外键约束
 }
 
-// InitGorm 初始化 GORM 连接
-func InitGorm(c Config) (*gorm.DB, error) {
-	// 1. 构建 DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		c.Username,
-		c.Password,
-		c.Host,
-		c.Port,
-		c.Database,
-		c.Charset,
-	)
-
-	// 2. 配置 GORM
-	gormConfig := &gorm.Config{
-		// 日志配置
-		Logger: logger.Default.LogMode(getLogLevel(c.LogLevel)),
+// DSN 按方言构建 DSN，账号、密码、库名及参数均经转义，不会注入额外的连接参数
+func (c Config) DSN() string {
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.port()))
+	switch c.Dialect() {
+	case dialect.Postgres:
+		u := url.URL{Scheme: "postgres", User: url.UserPassword(c.Username, c.Password), Host: addr, Path: "/" + c.Database}
+		if c.SSLMode != "" {
+			u.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+		}
+		return u.String()
+	case dialect.SQLite:
+		return c.Database + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+	default:
+		cfg := mysqldriver.NewConfig()
+		cfg.User = c.Username
+		cfg.Passwd = c.Password
+		cfg.Net = "tcp"
+		cfg.Addr = addr
+		cfg.DBName = c.Database
+		cfg.ParseTime = true
+		cfg.Loc = time.Local
+		if c.Charset != "" {
+			cfg.Params = map[string]string{"charset": c.Charset}
+		}
+		return cfg.FormatDSN()
+	}
+}
 
-		// 命名策略
-		NamingStrategy: schema.NamingStrategy{
-			SingularTable: c.SingularTable, // 使用单数表名
-		},
+// Dialect SQL 方言（未配置时为 mysql）
+func (c Config) Dialect() dialect.Dialect {
+	if c.Driver == "" {
+		return dialect.MySQL
+	}
+	return dialect.Dialect(c.Driver)
+}
 
-		// 性能优化
-		SkipDefaultTransaction: c.SkipDefaultTxn, // 跳过默认事务
-		PrepareStmt:            c.PrepareStmt,    // 预编译语句
+// Addr 连接地址（用于日志），sqlite 为数据库文件路径
+func (c Config) Addr() string {
+	if c.Dialect() == dialect.SQLite {
+		return c.Database
+	}
+	return fmt.Sprintf("%s:%d/%s", c.Host, c.port(), c.Database)
+}
 
-		// 外键约束
-		DisableForeignKeyConstraintWhenMigrating: c.DisableForeignKey,
+// port 端口（未配置时使用方言默认端口）
+func (c Config) port() int {
+	if c.Port != 0 {
+		return c.Port
+	}
+	if c.Dialect() == dialect.Postgres {
+		return 5432
 	}
+	return 3306
+}
+
+// Open 按方言打开 *sql.DB（不建立网络连接）
+func (c Config) Open() (*sql.DB, error) {
+	return sql.Open(c.Dialect().DriverName(), c.DSN())
+}
 
-	// 3. 打开连接
-	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
+// gormDialector 构建 GORM 方言，conn 非空时复用已有连接池（不在初始化时访问数据库）
+func (c Config) gormDialector(conn *sql.DB) gorm.Dialector {
+	switch c.Dialect() {
+	case dialect.Postgres:
+		if conn != nil {
+			return postgres.New(postgres.Config{Conn: conn})
+		}
+		return postgres.Open(c.DSN())
+	case dialect.SQLite:
+		if conn != nil {
+			return &sqlite.Dialector{Conn: conn}
+		}
+		return sqlite.Open(c.DSN())
+	default:
+		if conn != nil {
+			return mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true})
+		}
+		return mysql.Open(c.DSN())
+	}
+}
+
+// Namer GORM 命名策略（schemacheck 解析实体时需使用相同策略）
+func (c Config) Namer() schema.Namer {
+	return schema.NamingStrategy{
+		SingularTable: c.SingularTable, // 使用单数表名
+	}
+}
+
+// InitGorm 初始化 GORM 连接（独立连接池）
+func InitGorm(c Config) (*gorm.DB, error) {
+	// 1. 打开连接
+	db, err := gorm.Open(c.gormDialector(nil), c.gormConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
-	// 4. 获取底层 sql.DB 并配置连接池
+	// 2. 获取底层 sql.DB 并配置连接池
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
 	}
+	c.configurePool(sqlDB)
 
-	// 连接池配置
+	return db, nil
+}
+
+// gormConfig 构建 GORM 配置
+func (c Config) gormConfig() *gorm.Config {
+	return &gorm.Config{
+		// 日志配置
+		Logger: logger.Default.LogMode(getLogLevel(c.LogLevel)),
+
+		// 命名策略
+		NamingStrategy: c.Namer(),
+
+		// 性能优化
+		SkipDefaultTransaction: c.SkipDefaultTxn, // 跳过默认事务
+		PrepareStmt:            c.PrepareStmt,    // 预编译语句
+
+		// 外键约束
+		DisableForeignKeyConstraintWhenMigrating: c.DisableForeignKey,
+	}
+}
+
+// configurePool 配置连接池
+func (c Config) configurePool(sqlDB *sql.DB) {
 	sqlDB.SetMaxIdleConns(c.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(c.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(time.Duration(c.ConnMaxLifetime) * time.Second)
 	sqlDB.SetConnMaxIdleTime(time.Duration(c.ConnMaxIdleTime) * time.Second)
 
-	return db, nil
+	// SQLite 单写者，且内存库每个连接相互独立，只保留一个连接
+	if c.Dialect() == dialect.SQLite {
+		sqlDB.SetMaxOpenConns(1)
+	}
 }
 
 // getLogLevel 获取日志级别
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 重连退避参数
+const (
+	backoffInitial = 500 * time.Millisecond
+	backoffMax     = 30 * time.Second
+)
+
+// 错误定义
+var (
+	ErrUnknownDatasource = errors.New("unknown datasource")
+	ErrManagerClosed     = errors.New("datasource manager closed")
+)
+
+// Manager 多数据源管理器
+//
+// 创建时不建立网络连接（数据库不可达时服务仍可启动），首次使用时由 database/sql 自动连接；
+// 健康检查定期 ping 各数据源并记录状态，WaitReady 以指数退避等待数据源可用。
+type Manager struct {
+	sources map[string]*source
+	names   []string
+
+	once sync.Once
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// source 单个数据源
+type source struct {
+	name string
+	conn *Conn
+
+	mu        sync.RWMutex
+	healthy   bool
+	checked   bool
+	lastErr   error
+	lastCheck time.Time
+	failures  int
+}
+
+// SourceStats 数据源状态
+type SourceStats struct {
+	Name      string
+	Database  string
+	Healthy   bool
+	LastError string
+	LastCheck time.Time
+	Failures  int // 连续失败次数
+	Pool      sql.DBStats
+	Replicas  []ReplicaStats
+}
+
+// NewManager 创建数据源管理器，Database 为空的配置视为未配置并跳过
+// 仅在配置非法时返回错误，不会因数据库不可达而失败
+func NewManager(configs map[string]Config) (*Manager, error) {
+	m := &Manager{
+		sources: make(map[string]*source),
+		done:    make(chan struct{}),
+	}
+
+	for name, c := range configs {
+		if c.Database == "" {
+			continue
+		}
+		conn, err := NewConn(c)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("datasource %s: %w", name, err)
+		}
+		m.sources[name] = &source{name: name, conn: conn}
+		m.names = append(m.names, name)
+	}
+	sort.Strings(m.names)
+
+	return m, nil
+}
+
+// Conn 获取数据源连接
+func (m *Manager) Conn(name string) (*Conn, error) {
+	s, ok := m.sources[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDatasource, name)
+	}
+	return s.conn, nil
+}
+
+// Names 已配置的数据源名称
+func (m *Manager) Names() []string {
+	return m.names
+}
+
+// WaitReady 以指数退避重试 ping，直到数据源可用、ctx 结束或管理器关闭
+func (m *Manager) WaitReady(ctx context.Context, name string) error {
+	s, ok := m.sources[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownDatasource, name)
+	}
+
+	delay := backoffInitial
+	for {
+		err := m.check(ctx, s)
+		if err == nil {
+			return nil
+		}
+		logx.Infof("数据源 %s 不可用，%v 后重试: %v", name, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("wait for datasource %s: %w (last error: %v)", name, ctx.Err(), err)
+		case <-m.done:
+			timer.Stop()
+			return ErrManagerClosed
+		case <-timer.C:
+		}
+
+		if delay *= 2; delay > backoffMax {
+			delay = backoffMax
+		}
+	}
+}
+
+// StartHealthCheck 启动定期健康检查，interval <= 0 时不启动
+func (m *Manager) StartHealthCheck(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		m.checkAll()
+		for {
+			select {
+			case <-m.done:
+				return
+			case <-ticker.C:
+				m.checkAll()
+			}
+		}
+	}()
+}
+
+// checkAll 检查所有数据源
+func (m *Manager) checkAll() {
+	for _, name := range m.names {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		_ = m.check(ctx, m.sources[name])
+		cancel()
+	}
+}
+
+// check ping 数据源并更新状态，状态变化时输出日志
+func (m *Manager) check(ctx context.Context, s *source) error {
+	err := s.conn.Ping(ctx)
+	s.conn.CheckReplicas(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wasHealthy, wasChecked := s.healthy, s.checked
+	s.checked = true
+	s.healthy = err == nil
+	s.lastErr = err
+	s.lastCheck = time.Now()
+	if err != nil {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+
+	switch {
+	case err == nil && (!wasHealthy || !wasChecked):
+		logx.Infof("数据源 %s 连接正常", s.name)
+	case err != nil && (wasHealthy || !wasChecked):
+		logx.Errorf("数据源 %s 连接异常: %v", s.name, err)
+	}
+	return err
+}
+
+// Stats 获取所有数据源状态及连接池统计
+func (m *Manager) Stats() []SourceStats {
+	stats := make([]SourceStats, 0, len(m.names))
+	for _, name := range m.names {
+		s := m.sources[name]
+		s.mu.RLock()
+		st := SourceStats{
+			Name:      name,
+			Database:  s.conn.config.Database,
+			Healthy:   s.healthy,
+			LastCheck: s.lastCheck,
+			Failures:  s.failures,
+			Pool:      s.conn.DB.Stats(),
+			Replicas:  s.conn.ReplicaStats(),
+		}
+		if s.lastErr != nil {
+			st.LastError = s.lastErr.Error()
+		}
+		s.mu.RUnlock()
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// Close 停止健康检查并关闭所有连接池
+func (m *Manager) Close() error {
+	var errs []error
+	m.once.Do(func() {
+		close(m.done)
+		m.wg.Wait()
+		for _, name := range m.names {
+			if err := m.sources[name].conn.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close %s: %w", name, err))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
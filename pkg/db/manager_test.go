@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManager_Unreachable(t *testing.T) {
+	// 端口 1 不可达：创建管理器不应失败，WaitReady 在超时后返回错误
+	m, err := NewManager(map[string]Config{
+		"a":     {Host: "127.0.0.1", Port: 1, Database: "a", ORM: ORMSqlx},
+		"b":     {Host: "127.0.0.1", Port: 1, Database: "b", ORM: ORMGorm, Replicas: []ReplicaConfig{{Host: "127.0.0.1", Port: 1}}},
+		"empty": {},
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	defer m.Close()
+
+	if got := m.Names(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("Names() = %v, want [a b]", got)
+	}
+	if conn, err := m.Conn("b"); err != nil || conn.Gorm == nil {
+		t.Errorf("Conn(b) = %v, %v, want gorm initialized without connecting", conn, err)
+	}
+	if _, err := m.Conn("empty"); !errors.Is(err, ErrUnknownDatasource) {
+		t.Errorf("Conn(empty) error = %v, want ErrUnknownDatasource", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 800*time.Millisecond)
+	defer cancel()
+	if err := m.WaitReady(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitReady() error = %v, want deadline exceeded", err)
+	}
+
+	stats := m.Stats()
+	if len(stats) != 2 || stats[0].Healthy || stats[0].Failures < 2 || stats[0].LastError == "" {
+		t.Errorf("Stats()[0] = %+v, want unhealthy with retries", stats[0])
+	}
+
+	if err := m.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := m.WaitReady(context.Background(), "a"); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("WaitReady() after Close error = %v, want ErrManagerClosed", err)
+	}
+}
+
+func TestManager_InvalidConfig(t *testing.T) {
+	_, err := NewManager(map[string]Config{"a": {Database: "a", ORM: "xorm"}})
+	if !errors.Is(err, ErrUnknownORM) {
+		t.Errorf("NewManager() error = %v, want ErrUnknownORM", err)
+	}
+}
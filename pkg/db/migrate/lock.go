@@ -0,0 +1,53 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"idrm/pkg/db/dialect"
+)
+
+// lockName 迁移锁名称（MySQL 为服务器级命名锁，PostgreSQL 为库级 advisory lock）
+const lockName = "idrm:schema_migrations"
+
+// lock 获取迁移锁，多个实例同时迁移时依次执行，返回释放函数
+// 使用数据库原生的会话级锁而不是 pkg/lock：租约表 distributed_lock 本身由迁移创建；
+// SQLite 为单文件库，不加锁
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	var acquire, release string
+	switch m.dialect {
+	case dialect.MySQL:
+		// 超时 -1 为一直等待，ctx 取消时连接关闭，锁随会话释放
+		acquire, release = "SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)"
+	case dialect.Postgres:
+		acquire, release = "SELECT pg_advisory_lock(hashtext($1))", "SELECT pg_advisory_unlock(hashtext($1))"
+	default:
+		return func() {}, nil
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	if m.dialect == dialect.MySQL {
+		// GET_LOCK 成功返回 1，出错返回 NULL
+		var ok sql.NullInt64
+		err = conn.QueryRowContext(ctx, acquire, lockName).Scan(&ok)
+		if err == nil && ok.Int64 != 1 {
+			err = fmt.Errorf("GET_LOCK returned %v", ok)
+		}
+	} else {
+		// pg_advisory_lock 返回 void，阻塞至获取
+		_, err = conn.ExecContext(ctx, acquire, lockName)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	return func() {
+		// 释放失败时关闭连接，锁随会话释放
+		_, _ = conn.ExecContext(context.Background(), release, lockName)
+		conn.Close()
+	}, nil
+}
@@ -0,0 +1,444 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"idrm/migrations"
+	"idrm/pkg/db/dialect"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// DefaultTable 迁移记录表
+const DefaultTable = "schema_migrations"
+
+// 错误定义
+var (
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+	ErrMissingDown      = errors.New("migration has no down script")
+)
+
+// fileRegexp 迁移文件名: {version}_{name}.up.sql / {version}_{name}.down.sql
+var fileRegexp = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 单个版本迁移
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // Up 脚本的 sha256
+}
+
+// Status 迁移状态
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // 已执行的脚本在此后被修改（校验和不一致）
+}
+
+// record 已执行的迁移记录
+type record struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator 数据库迁移器
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect.Dialect // 迁移记录表语句的占位符方言
+	table      string
+	migrations []Migration
+}
+
+// New 创建迁移器（MySQL），source 为某个数据库的迁移脚本目录（见 migrations.Source）
+func New(db *sql.DB, source fs.FS) (*Migrator, error) {
+	return NewDialect(db, dialect.MySQL, source)
+}
+
+// NewDialect 创建指定方言的迁移器
+func NewDialect(db *sql.DB, d dialect.Dialect, source fs.FS) (*Migrator, error) {
+	list, err := Load(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		dialect:    d,
+		table:      DefaultTable,
+		migrations: list,
+	}, nil
+}
+
+// Load 加载目录下的迁移脚本（按版本升序），目录不存在时返回空列表
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := fileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s, %s", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(content)
+			m.Checksum = checksum(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+
+	return list, nil
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移
+// 执行期间持有迁移锁，多个实例同时启动时依次执行，后获取锁的实例只执行剩余的迁移
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := m.verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range m.migrations {
+		if _, ok := records[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down 回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := m.verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := sortedVersions(records)
+	var rolledBack []Migration
+	for i := len(versions) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		migration, err := m.find(versions[i])
+		if err != nil {
+			return rolledBack, err
+		}
+		if err := m.rollback(ctx, migration); err != nil {
+			return rolledBack, err
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+	return rolledBack, nil
+}
+
+// Goto 迁移到指定版本：执行不高于 version 的未执行迁移，回滚高于 version 的已执行迁移
+// version 为 0 时回滚全部
+func (m *Migrator) Goto(ctx context.Context, version int64) ([]Migration, error) {
+	if version != 0 {
+		if _, err := m.find(version); err != nil {
+			return nil, err
+		}
+	}
+
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	records, err := m.verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先回滚（从高到低）
+	var changed []Migration
+	versions := sortedVersions(records)
+	for i := len(versions) - 1; i >= 0 && versions[i] > version; i-- {
+		migration, err := m.find(versions[i])
+		if err != nil {
+			return changed, err
+		}
+		if err := m.rollback(ctx, migration); err != nil {
+			return changed, err
+		}
+		changed = append(changed, migration)
+	}
+
+	// 再执行（从低到高）
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		if _, ok := records[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return changed, err
+		}
+		changed = append(changed, migration)
+	}
+	return changed, nil
+}
+
+// Status 获取所有迁移的执行状态（包括已执行但脚本已不存在的版本）
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if r, ok := records[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = r.AppliedAt
+			status.Modified = r.Checksum != migration.Checksum
+			delete(records, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// 已执行但脚本缺失的版本
+	for _, r := range records {
+		statuses = append(statuses, Status{
+			Migration: Migration{Version: r.Version, Name: r.Name, Checksum: r.Checksum},
+			Applied:   true,
+			AppliedAt: r.AppliedAt,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Version 获取当前已执行的最高版本，未执行任何迁移时返回 0
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return 0, err
+	}
+	versions := sortedVersions(records)
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[len(versions)-1], nil
+}
+
+// verify 校验已执行迁移的脚本未被修改
+func (m *Migrator) verify(ctx context.Context) (map[int64]record, error) {
+	records, err := m.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range m.migrations {
+		if r, ok := records[migration.Version]; ok && r.Checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: version %d_%s (applied %s, current %s)",
+				ErrChecksumMismatch, migration.Version, migration.Name, shortSum(r.Checksum), shortSum(migration.Checksum))
+		}
+	}
+	return records, nil
+}
+
+// records 读取已执行的迁移记录（迁移记录表不存在时自动创建）
+func (m *Migrator) records(ctx context.Context) (map[int64]record, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT version, name, checksum, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", m.table, err)
+	}
+	defer rows.Close()
+
+	records := make(map[int64]record)
+	for rows.Next() {
+		var r record
+		if err := rows.Scan(&r.Version, &r.Name, &r.Checksum, &r.AppliedAt); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", m.table, err)
+		}
+		records[r.Version] = r
+	}
+	return records, rows.Err()
+}
+
+// ensureTable 创建迁移记录表
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    version BIGINT NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`, m.table)
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("create %s: %w", m.table, err)
+	}
+	return nil
+}
+
+// apply 执行单个迁移并写入记录
+// 注意：MySQL 的 DDL 会隐式提交事务，迁移脚本应尽量保持幂等（如 IF NOT EXISTS）；
+// PostgreSQL、SQLite 的 DDL 可在事务中回滚
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	logx.Infof("执行迁移: %d_%s", migration.Version, migration.Name)
+
+	return m.inTx(ctx, func(tx *sql.Tx) error {
+		if err := execScript(ctx, tx, m.dialect, migration.Up); err != nil {
+			return fmt.Errorf("apply %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, m.dialect.Rebind(
+			fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)", m.table)),
+			migration.Version, migration.Name, migration.Checksum, time.Now())
+		return err
+	})
+}
+
+// rollback 回滚单个迁移并删除记录
+func (m *Migrator) rollback(ctx context.Context, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("%w: %d_%s", ErrMissingDown, migration.Version, migration.Name)
+	}
+	logx.Infof("回滚迁移: %d_%s", migration.Version, migration.Name)
+
+	return m.inTx(ctx, func(tx *sql.Tx) error {
+		if err := execScript(ctx, tx, m.dialect, migration.Down); err != nil {
+			return fmt.Errorf("rollback %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, m.dialect.Rebind(
+			fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.table)), migration.Version)
+		return err
+	})
+}
+
+// inTx 在事务中执行
+func (m *Migrator) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// find 根据版本号查找迁移脚本
+func (m *Migrator) find(version int64) (Migration, error) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, nil
+		}
+	}
+	return Migration{}, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+}
+
+// execScript 逐条执行脚本中的 SQL 语句
+func execScript(ctx context.Context, tx *sql.Tx, d dialect.Dialect, script string) error {
+	for _, stmt := range SplitStatements(d, script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedVersions 已执行版本升序列表
+func sortedVersions(records map[int64]record) []int64 {
+	versions := make([]int64, 0, len(records))
+	for v := range records {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
+
+// checksum 计算脚本校验和
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewEmbedded 使用内置迁移脚本（idrm/migrations）创建迁移器
+// driver 如 mysql/postgres/sqlite，database 如 migrations.ResourceCatalog
+func NewEmbedded(db *sql.DB, driver, database string) (*Migrator, error) {
+	source, err := migrations.Source(driver, database)
+	if err != nil {
+		return nil, err
+	}
+	return NewDialect(db, dialect.Dialect(driver), source)
+}
+
+// shortSum 截短校验和用于展示
+func shortSum(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
@@ -0,0 +1,141 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"idrm/pkg/db/dialect"
+)
+
+func TestLoad(t *testing.T) {
+	source := fstest.MapFS{
+		"000002_add_index.up.sql":        {Data: []byte("CREATE INDEX idx ON t (a);")},
+		"000001_create_table.up.sql":     {Data: []byte("CREATE TABLE t (a INT);")},
+		"000001_create_table.down.sql":   {Data: []byte("DROP TABLE t;")},
+		"README.md":                      {Data: []byte("ignored")},
+		"000003_invalid-name.up.sql":     {Data: []byte("ignored")},
+		"nested/000004_nested.up.sql":    {Data: []byte("ignored")},
+		"000005_without_up.down.sql.bak": {Data: []byte("ignored")},
+	}
+
+	list, err := Load(source)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Load() got %d migrations, want 2", len(list))
+	}
+	if list[0].Version != 1 || list[0].Name != "create_table" || list[0].Down == "" {
+		t.Errorf("list[0] = %+v", list[0])
+	}
+	if list[1].Version != 2 || list[1].Down != "" {
+		t.Errorf("list[1] = %+v", list[1])
+	}
+	if list[0].Checksum == "" || list[0].Checksum == list[1].Checksum {
+		t.Errorf("checksum not computed: %q, %q", list[0].Checksum, list[1].Checksum)
+	}
+}
+
+func TestLoad_MissingUp(t *testing.T) {
+	source := fstest.MapFS{
+		"000001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+	if _, err := Load(source); err == nil {
+		t.Error("Load() expected error for migration without up script")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect dialect.Dialect
+		script  string
+		want    []string
+	}{
+		{
+			name:    "多条语句",
+			dialect: dialect.MySQL,
+			script:  "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:    []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:    "引号中的分号",
+			dialect: dialect.MySQL,
+			script:  "INSERT INTO a VALUES ('x;y', \"z;\");",
+			want:    []string{"INSERT INTO a VALUES ('x;y', \"z;\")"},
+		},
+		{
+			name:    "转义引号",
+			dialect: dialect.MySQL,
+			script:  "INSERT INTO a VALUES ('it\\'s;ok');",
+			want:    []string{"INSERT INTO a VALUES ('it\\'s;ok')"},
+		},
+		{
+			name:    "注释",
+			dialect: dialect.MySQL,
+			script:  "-- comment; here\nCREATE TABLE a (id INT); # trailing;\n/* block; */",
+			want:    []string{"CREATE TABLE a (id INT)"},
+		},
+		{
+			name:    "PostgreSQL 的 # 运算符",
+			dialect: dialect.Postgres,
+			script:  "UPDATE a SET v = doc #> '{x,y}';\nUPDATE a SET n = 5 # 3; -- comment;",
+			want:    []string{"UPDATE a SET v = doc #> '{x,y}'", "UPDATE a SET n = 5 # 3"},
+		},
+		{
+			name:    "PostgreSQL 标准字符串中的反斜杠",
+			dialect: dialect.Postgres,
+			script:  "INSERT INTO a VALUES ('C:\\');\nINSERT INTO a VALUES ('x;y');",
+			want:    []string{"INSERT INTO a VALUES ('C:\\')", "INSERT INTO a VALUES ('x;y')"},
+		},
+		{
+			name:    "PostgreSQL E 字符串中的转义",
+			dialect: dialect.Postgres,
+			script:  "INSERT INTO a VALUES (E'it\\'s;ok', e'\\\\');\nSELECT 1;",
+			want:    []string{"INSERT INTO a VALUES (E'it\\'s;ok', e'\\\\')", "SELECT 1"},
+		},
+		{
+			name:    "PostgreSQL 以 E 结尾的标识符后的字符串",
+			dialect: dialect.Postgres,
+			script:  "SELECT name'C:\\';\nSELECT 1;",
+			want:    []string{"SELECT name'C:\\'", "SELECT 1"},
+		},
+		{
+			name:    "SQLite 字符串中的反斜杠",
+			dialect: dialect.SQLite,
+			script:  "INSERT INTO a VALUES ('C:\\');\nSELECT 1;",
+			want:    []string{"INSERT INTO a VALUES ('C:\\')", "SELECT 1"},
+		},
+		{
+			name:    "COMMENT 属性",
+			dialect: dialect.MySQL,
+			script:  "CREATE TABLE a (id INT COMMENT 'ID;主键') COMMENT='表;注释';",
+			want:    []string{"CREATE TABLE a (id INT COMMENT 'ID;主键') COMMENT='表;注释'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.dialect, tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEmbedded(t *testing.T) {
+	m, err := NewEmbedded(nil, "mysql", "resource_catalog")
+	if err != nil {
+		t.Fatalf("NewEmbedded() error = %v", err)
+	}
+	if len(m.migrations) == 0 {
+		t.Error("NewEmbedded() loaded no migrations for resource_catalog")
+	}
+
+	// 暂无脚本的数据库返回空列表
+	m, err = NewEmbedded(nil, "mysql", "data_understanding")
+	if err != nil || len(m.migrations) != 0 {
+		t.Errorf("NewEmbedded() = %v, %v; want empty migrator", m, err)
+	}
+}
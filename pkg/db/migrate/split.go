@@ -0,0 +1,93 @@
+package migrate
+
+import (
+	"strings"
+
+	"idrm/pkg/db/dialect"
+)
+
+// SplitStatements 将脚本按分号拆分为单条 SQL 语句
+// 忽略引号（'、"、`）内及注释（--、/* */，MySQL 还有 #）中的分号，去除空语句；
+// PostgreSQL 中 # 为运算符（如 jsonb 的 #>），不作为注释。
+// 引号内的反斜杠只在 MySQL 字符串及 PostgreSQL 的 E'...' 中为转义符，
+// PostgreSQL、SQLite 的标准字符串（如 'C:\'）中为普通字符
+func SplitStatements(d dialect.Dialect, script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      byte // 当前所在引号，0 表示不在引号内
+		escapes    bool // 当前引号内反斜杠是否为转义符
+	)
+
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		if quote != 0 {
+			current.WriteByte(c)
+			if c == '\\' && escapes && i+1 < len(script) {
+				i++
+				current.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			escapes = backslashEscapes(d, script, i)
+			current.WriteByte(c)
+		case c == '-' && strings.HasPrefix(script[i:], "--"), c == '#' && d == dialect.MySQL:
+			// 单行注释：跳到行尾
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			i += end - 1
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			// 多行注释：跳到注释结束（以空格代替，避免前后内容粘连）
+			current.WriteByte(' ')
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+			}
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// backslashEscapes 位于 i 的引号开始的字符串中反斜杠是否为转义符：
+// MySQL 的字符串及双引号（sql_mode 默认未开启 NO_BACKSLASH_ESCAPES），PostgreSQL 的 E'...'（E 前不是标识符字符）
+func backslashEscapes(d dialect.Dialect, script string, i int) bool {
+	switch {
+	case script[i] == '`':
+		return false
+	case d == dialect.MySQL:
+		return true
+	case d == dialect.Postgres && script[i] == '\'' && i > 0 && (script[i-1] == 'E' || script[i-1] == 'e'):
+		return i == 1 || !isIdentChar(script[i-2])
+	default:
+		return false
+	}
+}
+
+// isIdentChar 是否为标识符字符（字母、数字、下划线及多字节字符）
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
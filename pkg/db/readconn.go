@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// readConn sqlx 读连接：查询按加权轮询路由到副本（无可用副本时使用主库），
+// 写操作、预编译语句及事务始终使用主库
+type readConn struct {
+	sqlx.SqlConn // 主库
+
+	set      *replicaSet
+	replicas []sqlx.SqlConn
+}
+
+// pick 选择本次查询使用的连接
+func (c *readConn) pick() sqlx.SqlConn {
+	if i := c.set.next(); i >= 0 {
+		return c.replicas[i]
+	}
+	return c.SqlConn
+}
+
+func (c *readConn) QueryRow(v any, query string, args ...any) error {
+	return c.pick().QueryRow(v, query, args...)
+}
+
+func (c *readConn) QueryRowCtx(ctx context.Context, v any, query string, args ...any) error {
+	return c.pick().QueryRowCtx(ctx, v, query, args...)
+}
+
+func (c *readConn) QueryRowPartial(v any, query string, args ...any) error {
+	return c.pick().QueryRowPartial(v, query, args...)
+}
+
+func (c *readConn) QueryRowPartialCtx(ctx context.Context, v any, query string, args ...any) error {
+	return c.pick().QueryRowPartialCtx(ctx, v, query, args...)
+}
+
+func (c *readConn) QueryRows(v any, query string, args ...any) error {
+	return c.pick().QueryRows(v, query, args...)
+}
+
+func (c *readConn) QueryRowsCtx(ctx context.Context, v any, query string, args ...any) error {
+	return c.pick().QueryRowsCtx(ctx, v, query, args...)
+}
+
+func (c *readConn) QueryRowsPartial(v any, query string, args ...any) error {
+	return c.pick().QueryRowsPartial(v, query, args...)
+}
+
+func (c *readConn) QueryRowsPartialCtx(ctx context.Context, v any, query string, args ...any) error {
+	return c.pick().QueryRowsPartialCtx(ctx, v, query, args...)
+}
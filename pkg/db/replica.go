@@ -0,0 +1,258 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"idrm/pkg/db/dialect"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ReplicaConfig 只读副本配置，未配置的账号信息沿用主库
+type ReplicaConfig struct {
+	Host     string
+	Port     int    `json:",optional"` // 默认与主库相同
+	Username string `json:",optional"`
+	Password string `json:",optional"`
+	Weight   int    `json:",default=1"` // 权重，按加权轮询分配读请求
+}
+
+// replica 只读副本
+type replica struct {
+	addr   string
+	db     *sql.DB
+	weight int
+
+	current   int // 平滑加权轮询的当前权重（由 replicaSet.mu 保护）
+	healthy   bool
+	lag       time.Duration
+	lastErr   error
+	lastCheck time.Time
+}
+
+// ReplicaStats 副本状态
+type ReplicaStats struct {
+	Addr      string
+	Weight    int
+	Healthy   bool
+	Lag       time.Duration
+	LastError string
+	LastCheck time.Time
+	Pool      sql.DBStats
+}
+
+// replicaSet 副本集合：平滑加权轮询，跳过不可达或延迟超过阈值的副本
+type replicaSet struct {
+	mu       sync.Mutex
+	replicas []*replica
+	maxLag   time.Duration // 0 表示不检查复制延迟
+	dialect  dialect.Dialect
+}
+
+// newReplicaSet 按配置创建副本连接（不建立网络连接）
+func newReplicaSet(c Config) (*replicaSet, error) {
+	set := &replicaSet{maxLag: time.Duration(c.MaxReplicaLag) * time.Second, dialect: c.Dialect()}
+	for _, rc := range c.Replicas {
+		rcfg := c
+		rcfg.Host = rc.Host
+		if rc.Port != 0 {
+			rcfg.Port = rc.Port
+		}
+		if rc.Username != "" {
+			rcfg.Username, rcfg.Password = rc.Username, rc.Password
+		}
+
+		sqlDB, err := rcfg.Open()
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("open replica %s:%d: %w", rcfg.Host, rcfg.port(), err)
+		}
+		c.configurePool(sqlDB)
+
+		weight := rc.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		// 初始视为可用，由健康检查更新
+		set.replicas = append(set.replicas, &replica{
+			addr:    fmt.Sprintf("%s:%d", rcfg.Host, rcfg.port()),
+			db:      sqlDB,
+			weight:  weight,
+			healthy: true,
+		})
+	}
+	return set, nil
+}
+
+// next 选择下一个副本，无可用副本时返回 -1
+func (s *replicaSet) next() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	best, total := -1, 0
+	for i, r := range s.replicas {
+		if !r.healthy {
+			continue
+		}
+		r.current += r.weight
+		total += r.weight
+		if best < 0 || r.current > s.replicas[best].current {
+			best = i
+		}
+	}
+	if best >= 0 {
+		s.replicas[best].current -= total
+	}
+	return best
+}
+
+// check 检查各副本连通性及复制延迟
+func (s *replicaSet) check(ctx context.Context) {
+	for _, r := range s.replicas {
+		lag, err := replicaLag(ctx, r.db, s.dialect)
+		if err == nil && s.maxLag > 0 && lag > s.maxLag {
+			err = fmt.Errorf("replication lag %v exceeds %v", lag, s.maxLag)
+		}
+
+		s.mu.Lock()
+		if r.healthy != (err == nil) {
+			if err != nil {
+				logx.Errorf("副本 %s 暂停读路由: %v", r.addr, err)
+			} else {
+				logx.Infof("副本 %s 恢复读路由", r.addr)
+			}
+		}
+		r.healthy = err == nil
+		r.lag = lag
+		r.lastErr = err
+		r.lastCheck = time.Now()
+		if !r.healthy {
+			r.current = 0
+		}
+		s.mu.Unlock()
+	}
+}
+
+// stats 副本状态
+func (s *replicaSet) stats() []ReplicaStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]ReplicaStats, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		st := ReplicaStats{
+			Addr:      r.addr,
+			Weight:    r.weight,
+			Healthy:   r.healthy,
+			Lag:       r.lag,
+			LastCheck: r.lastCheck,
+			Pool:      r.db.Stats(),
+		}
+		if r.lastErr != nil {
+			st.LastError = r.lastErr.Error()
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// close 关闭所有副本连接池
+func (s *replicaSet) close() error {
+	var errs []error
+	for _, r := range s.replicas {
+		if err := r.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close replica %s: %w", r.addr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registerResolver 为 gorm 注册读写分离插件
+// 副本列表末尾追加主库，无可用副本时策略返回主库
+func (s *replicaSet) registerResolver(c Config, db *gorm.DB, primary *sql.DB) error {
+	dialectors := make([]gorm.Dialector, 0, len(s.replicas)+1)
+	for _, r := range s.replicas {
+		dialectors = append(dialectors, c.gormDialector(r.db))
+	}
+	dialectors = append(dialectors, c.gormDialector(primary))
+
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy: dbresolver.PolicyFunc(func(pools []gorm.ConnPool) gorm.ConnPool {
+			if i := s.next(); i >= 0 && i < len(pools)-1 {
+				return pools[i]
+			}
+			return pools[len(pools)-1]
+		}),
+	}))
+}
+
+// replicaLag 查询复制延迟；非副本（无复制状态）视为无延迟，复制中断时返回错误
+func replicaLag(ctx context.Context, db *sql.DB, d dialect.Dialect) (time.Duration, error) {
+	if err := db.PingContext(ctx); err != nil {
+		return 0, err
+	}
+
+	switch d {
+	case dialect.Postgres:
+		// 备库返回最近一次回放距今的秒数，主库返回 0
+		var seconds sql.NullFloat64
+		err := db.QueryRowContext(ctx, `SELECT CASE WHEN pg_is_in_recovery()
+            THEN EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) ELSE 0 END`).Scan(&seconds)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds.Float64 * float64(time.Second)), nil
+	case dialect.SQLite:
+		return 0, nil
+	}
+
+	// MySQL 8.0.22+ 使用 SHOW REPLICA STATUS，旧版本使用 SHOW SLAVE STATUS
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		if rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+
+	for i, column := range columns {
+		if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+			continue
+		}
+		if !values[i].Valid {
+			return 0, errors.New("replication is not running")
+		}
+		seconds, err := strconv.ParseInt(values[i].String, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse %s: %w", column, err)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return 0, nil
+}
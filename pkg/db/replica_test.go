@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestReplicaSet_Next(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []int
+		healthy []bool
+		want    map[int]int // 6 次选择中各副本被选中的次数
+	}{
+		{"平均分配", []int{1, 1}, []bool{true, true}, map[int]int{0: 3, 1: 3}},
+		{"按权重分配", []int{2, 1}, []bool{true, true}, map[int]int{0: 4, 1: 2}},
+		{"跳过不可用副本", []int{2, 1}, []bool{false, true}, map[int]int{1: 6}},
+		{"无可用副本回退主库", []int{1}, []bool{false}, map[int]int{-1: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := &replicaSet{}
+			for i, w := range tt.weights {
+				set.replicas = append(set.replicas, &replica{weight: w, healthy: tt.healthy[i]})
+			}
+
+			got := make(map[int]int)
+			for i := 0; i < 6; i++ {
+				got[set.next()]++
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("next() distribution = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("next() distribution = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 支持的比较运算符
+const (
+	OpEq   = "="
+	OpNe   = "<>"
+	OpGt   = ">"
+	OpGte  = ">="
+	OpLt   = "<"
+	OpLte  = "<="
+	OpLike = "LIKE"
+	OpIn   = "IN"
+)
+
+var operators = map[string]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true, OpLike: true, OpIn: true,
+}
+
+// Cond 查询条件，多个条件之间为 AND
+// Column 必须是实体中声明的列，否则返回 ErrUnknownColumn（防止注入）
+type Cond struct {
+	Column string
+	Op     string
+	Value  interface{}
+}
+
+// Eq 等于
+func Eq(column string, value interface{}) Cond { return Cond{column, OpEq, value} }
+
+// Ne 不等于
+func Ne(column string, value interface{}) Cond { return Cond{column, OpNe, value} }
+
+// Gt 大于
+func Gt(column string, value interface{}) Cond { return Cond{column, OpGt, value} }
+
+// Gte 大于等于
+func Gte(column string, value interface{}) Cond { return Cond{column, OpGte, value} }
+
+// Lt 小于
+func Lt(column string, value interface{}) Cond { return Cond{column, OpLt, value} }
+
+// Lte 小于等于
+func Lte(column string, value interface{}) Cond { return Cond{column, OpLte, value} }
+
+// Like 模糊匹配，value 需自行包含 %
+func Like(column string, value string) Cond { return Cond{column, OpLike, value} }
+
+// In 包含，values 为切片
+func In(column string, values interface{}) Cond { return Cond{column, OpIn, values} }
+
+// Order 排序
+type Order struct {
+	Column string
+	Desc   bool
+}
+
+// Asc 升序
+func Asc(column string) Order { return Order{Column: column} }
+
+// Desc 降序
+func Desc(column string) Order { return Order{Column: column, Desc: true} }
+
+// buildWhere 生成 WHERE 子句（不含 WHERE 关键字）及参数，IN 条件展开为多个占位符
+func (m *meta) buildWhere(conds []Cond, unscoped bool) (string, []interface{}, error) {
+	var (
+		clauses []string
+		args    []interface{}
+	)
+
+	for _, c := range conds {
+		if err := m.checkColumn(c.Column); err != nil {
+			return "", nil, err
+		}
+		op := strings.ToUpper(c.Op)
+		if !operators[op] {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidOperator, c.Op)
+		}
+
+		if op != OpIn {
+			clauses = append(clauses, fmt.Sprintf("%s %s ?", c.Column, op))
+			args = append(args, c.Value)
+			continue
+		}
+
+		values := reflect.ValueOf(c.Value)
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			return "", nil, fmt.Errorf("%w: IN requires a slice, got %T", ErrInvalidOperator, c.Value)
+		}
+		if values.Len() == 0 {
+			// 空集合恒不成立
+			clauses = append(clauses, "1 = 0")
+			continue
+		}
+		placeholders := make([]string, values.Len())
+		for i := range placeholders {
+			placeholders[i] = "?"
+			args = append(args, values.Index(i).Interface())
+		}
+		clauses = append(clauses, fmt.Sprintf("%s IN (%s)", c.Column, strings.Join(placeholders, ", ")))
+	}
+
+	if m.softDelete != nil && !unscoped {
+		clauses = append(clauses, m.softDelete.DBName+" IS NULL")
+	}
+
+	return strings.Join(clauses, " AND "), args, nil
+}
+
+// buildOrder 生成 ORDER BY 子句（不含 ORDER BY 关键字），为空时按主键升序
+func (m *meta) buildOrder(orders []Order) (string, error) {
+	if len(orders) == 0 {
+		return m.primary.DBName + " ASC", nil
+	}
+
+	parts := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if err := m.checkColumn(o.Column); err != nil {
+			return "", err
+		}
+		dir := "ASC"
+		if o.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, o.Column+" "+dir)
+	}
+	return strings.Join(parts, ", "), nil
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"idrm/pkg/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// batchSize gorm 批量插入每批条数
+const batchSize = 100
+
+// gormRepo 基于 gorm 的实现
+type gormRepo[T any] struct {
+	db   *gorm.DB
+	meta *meta
+}
+
+// NewGorm 创建 gorm 仓储
+func NewGorm[T any](db *gorm.DB) Repository[T] {
+	return &gormRepo[T]{db: db, meta: mustParse[T](db.NamingStrategy)}
+}
+
+// Insert 插入
+func (r *gormRepo[T]) Insert(ctx context.Context, data *T) error {
+	return r.db.WithContext(ctx).Create(data).Error
+}
+
+// BatchInsert 批量插入
+func (r *gormRepo[T]) BatchInsert(ctx context.Context, list []*T) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(list, batchSize).Error
+}
+
+// FindOne 根据主键查找
+func (r *gormRepo[T]) FindOne(ctx context.Context, id int64) (*T, error) {
+	return r.FindOneBy(ctx, Eq(r.meta.primary.DBName, id))
+}
+
+// FindOneBy 根据条件查找一条
+func (r *gormRepo[T]) FindOneBy(ctx context.Context, conds ...Cond) (*T, error) {
+	tx, err := r.scope(ctx, conds, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data T
+	if err := tx.Take(&data).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Find 根据查询条件查找
+func (r *gormRepo[T]) Find(ctx context.Context, q Query) ([]*T, error) {
+	tx, err := r.scope(ctx, q.Conds, q.Unscoped)
+	if err != nil {
+		return nil, err
+	}
+	order, err := r.meta.buildOrder(q.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	tx = tx.Order(order)
+	if q.PageSize > 0 {
+		tx = tx.Limit(q.PageSize).Offset(q.offset())
+	}
+
+	var list []*T
+	err = tx.Find(&list).Error
+	return list, err
+}
+
+// List 分页查询
+func (r *gormRepo[T]) List(ctx context.Context, q Query) ([]*T, int64, error) {
+	total, err := r.count(ctx, q.Conds, q.Unscoped)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := r.Find(ctx, q)
+	return list, total, err
+}
+
+// Count 统计数量
+func (r *gormRepo[T]) Count(ctx context.Context, conds ...Cond) (int64, error) {
+	return r.count(ctx, conds, false)
+}
+
+func (r *gormRepo[T]) count(ctx context.Context, conds []Cond, unscoped bool) (int64, error) {
+	tx, err := r.scope(ctx, conds, unscoped)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	err = tx.Count(&total).Error
+	return total, err
+}
+
+// Update 根据主键更新全部字段（含零值）
+func (r *gormRepo[T]) Update(ctx context.Context, data *T) error {
+	omits := []string{r.meta.primary.DBName}
+	for _, field := range r.meta.fields {
+		if field.AutoCreateTime > 0 || field == r.meta.softDelete {
+			omits = append(omits, field.DBName)
+		}
+	}
+	return r.db.WithContext(ctx).Model(data).Select("*").Omit(omits...).Updates(data).Error
+}
+
+// Delete 根据主键删除（gorm.DeletedAt 字段存在时自动软删除）
+func (r *gormRepo[T]) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(new(T), id).Error
+}
+
+// BatchDelete 根据主键批量删除
+func (r *gormRepo[T]) BatchDelete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Delete(new(T), ids).Error
+}
+
+// WithTx 返回绑定事务的仓储
+func (r *gormRepo[T]) WithTx(tx interface{}) Repository[T] {
+	if gormTx, ok := tx.(*gorm.DB); ok {
+		return &gormRepo[T]{db: gormTx, meta: r.meta}
+	}
+	// 如果不是gorm事务，返回自身
+	return r
+}
+
+// Trans 在事务中执行
+func (r *gormRepo[T]) Trans(ctx context.Context, fn func(ctx context.Context, repo Repository[T]) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(db.ContextWithTx(ctx, tx), &gormRepo[T]{db: tx, meta: r.meta})
+	})
+}
+
+// scope 应用查询条件（列名及运算符与 sqlx 实现使用同一套校验）
+func (r *gormRepo[T]) scope(ctx context.Context, conds []Cond, unscoped bool) (*gorm.DB, error) {
+	tx := r.db.WithContext(ctx).Model(new(T))
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+
+	// 软删除条件由 gorm 自动追加，这里只生成业务条件
+	where, args, err := r.meta.buildWhere(conds, true)
+	if err != nil {
+		return nil, fmt.Errorf("build where: %w", err)
+	}
+	if where != "" {
+		tx = tx.Where(clause.Expr{SQL: where, Vars: args})
+	}
+	return tx, nil
+}
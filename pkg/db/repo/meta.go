@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	schemaCache   = &sync.Map{}
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+	// defaultNamer sqlx 实现使用的命名策略（与迁移脚本一致，使用单数表名）
+	defaultNamer schema.Namer = schema.NamingStrategy{SingularTable: true}
+)
+
+// meta 实体元数据（由 gorm schema 解析，sqlx 实现共用）
+type meta struct {
+	table      string
+	fields     []*schema.Field // 映射到列的字段，按声明顺序
+	columns    map[string]*schema.Field
+	primary    *schema.Field
+	softDelete *schema.Field // gorm.DeletedAt 字段，nil 表示不启用软删除
+}
+
+// parse 解析实体元数据，namer 为 nil 时使用 defaultNamer
+func parse[T any](namer schema.Namer) (*meta, error) {
+	if namer == nil {
+		namer = defaultNamer
+	}
+	sch, err := schema.Parse(new(T), schemaCache, namer)
+	if err != nil {
+		return nil, err
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoPrimaryKey, sch.Name)
+	}
+
+	m := &meta{
+		table:   sch.Table,
+		columns: make(map[string]*schema.Field),
+		primary: sch.PrioritizedPrimaryField,
+	}
+	for _, field := range sch.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		m.fields = append(m.fields, field)
+		m.columns[field.DBName] = field
+		if field.FieldType == deletedAtType {
+			m.softDelete = field
+		}
+	}
+	return m, nil
+}
+
+// mustParse 解析实体元数据，失败时panic（实体定义错误属于编程错误）
+func mustParse[T any](namer schema.Namer) *meta {
+	m, err := parse[T](namer)
+	if err != nil {
+		panic(fmt.Sprintf("repo: invalid entity %T: %v", *new(T), err))
+	}
+	return m
+}
+
+// checkColumn 校验列名是否属于实体
+func (m *meta) checkColumn(column string) error {
+	if _, ok := m.columns[column]; !ok {
+		return fmt.Errorf("%w: %s.%s", ErrUnknownColumn, m.table, column)
+	}
+	return nil
+}
+
+// selectColumns 查询列（全部映射列，与 sqlx 严格扫描要求一致）
+func (m *meta) selectColumns() []string {
+	columns := make([]string, len(m.fields))
+	for i, field := range m.fields {
+		columns[i] = field.DBName
+	}
+	return columns
+}
+
+// insertValues 生成插入列及值，并回填自动时间和默认值到 data
+// 自增主键为零值时不插入；软删除字段不插入
+func (m *meta) insertValues(ctx context.Context, data reflect.Value, now time.Time) ([]string, []interface{}, error) {
+	var (
+		columns []string
+		values  []interface{}
+	)
+	for _, field := range m.fields {
+		if field == m.softDelete {
+			continue
+		}
+		value, zero := field.ValueOf(ctx, data)
+		switch {
+		case field == m.primary && zero && field.AutoIncrement:
+			continue
+		case zero && (field.AutoCreateTime > 0 || field.AutoUpdateTime > 0):
+			kind := field.AutoCreateTime
+			if kind == 0 {
+				kind = field.AutoUpdateTime
+			}
+			value = timeValue(field, kind, now)
+			if err := field.Set(ctx, data, value); err != nil {
+				return nil, nil, err
+			}
+		case zero && field.DefaultValueInterface != nil:
+			value = field.DefaultValueInterface
+			if err := field.Set(ctx, data, value); err != nil {
+				return nil, nil, err
+			}
+		}
+		columns = append(columns, field.DBName)
+		values = append(values, value)
+	}
+	return columns, values, nil
+}
+
+// updateValues 生成更新列及值（不含主键、创建时间、软删除字段），并回填更新时间到 data
+func (m *meta) updateValues(ctx context.Context, data reflect.Value, now time.Time) ([]string, []interface{}, error) {
+	var (
+		columns []string
+		values  []interface{}
+	)
+	for _, field := range m.fields {
+		if field == m.primary || field == m.softDelete || field.AutoCreateTime > 0 {
+			continue
+		}
+		value, _ := field.ValueOf(ctx, data)
+		if field.AutoUpdateTime > 0 {
+			value = timeValue(field, field.AutoUpdateTime, now)
+			if err := field.Set(ctx, data, value); err != nil {
+				return nil, nil, err
+			}
+		}
+		columns = append(columns, field.DBName)
+		values = append(values, value)
+	}
+	return columns, values, nil
+}
+
+// timeValue 按字段类型生成自动时间值（与 gorm 的 autoCreateTime/autoUpdateTime 规则一致）
+func timeValue(field *schema.Field, kind schema.TimeType, now time.Time) interface{} {
+	if field.DataType == schema.Time {
+		return now
+	}
+	switch kind {
+	case schema.UnixNanosecond:
+		return now.UnixNano()
+	case schema.UnixMillisecond:
+		return now.UnixMilli()
+	default:
+		return now.Unix()
+	}
+}
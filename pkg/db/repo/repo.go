@@ -0,0 +1,81 @@
+// Package repo 基于泛型的通用仓储，由结构体 tag 驱动同时提供 gorm 与 sqlx 两种实现
+//
+// 实体约定：
+//   - 字段同时声明 db 与 gorm tag，且列名一致（可用 schemacheck 校验）
+//   - 实现 TableName() 指定表名
+//   - 主键字段声明 gorm:"primaryKey"（或命名为 Id/ID）
+//   - 包含 gorm.DeletedAt 类型字段时启用软删除
+//   - CreatedAt/UpdatedAt（或 autoCreateTime/autoUpdateTime tag）自动维护
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"idrm/pkg/db"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Repository 通用仓储接口
+type Repository[T any] interface {
+	// Insert 插入，自增主键回填到 data
+	Insert(ctx context.Context, data *T) error
+	// BatchInsert 批量插入（同一事务）
+	BatchInsert(ctx context.Context, list []*T) error
+	// FindOne 根据主键查找，不存在返回 ErrNotFound
+	FindOne(ctx context.Context, id int64) (*T, error)
+	// FindOneBy 根据条件查找一条，不存在返回 ErrNotFound
+	FindOneBy(ctx context.Context, conds ...Cond) (*T, error)
+	// Find 根据查询条件查找（PageSize > 0 时分页）
+	Find(ctx context.Context, q Query) ([]*T, error)
+	// List 分页查询，返回列表及总数
+	List(ctx context.Context, q Query) ([]*T, int64, error)
+	// Count 统计数量
+	Count(ctx context.Context, conds ...Cond) (int64, error)
+	// Update 根据主键更新全部字段（含零值，不含创建时间）
+	Update(ctx context.Context, data *T) error
+	// Delete 根据主键删除（启用软删除时为软删除）
+	Delete(ctx context.Context, id int64) error
+	// BatchDelete 根据主键批量删除
+	BatchDelete(ctx context.Context, ids []int64) error
+	// WithTx 返回绑定事务的仓储（gorm 传 *gorm.DB，sqlx 传 sqlx.SqlConn 或 sqlx.Session）
+	WithTx(tx interface{}) Repository[T]
+	// Trans 在事务中执行
+	Trans(ctx context.Context, fn func(ctx context.Context, repo Repository[T]) error) error
+}
+
+// Query 查询条件
+type Query struct {
+	Conds    []Cond
+	Orders   []Order // 为空时按主键升序
+	Page     int     // 从 1 开始，PageSize > 0 时生效
+	PageSize int     // 0 表示不分页
+	Unscoped bool    // 包含已软删除的记录
+}
+
+// offset 分页偏移量
+func (q Query) offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
+
+// New 创建仓储
+// 按配置（DB.*.ORM 及 DB.*.Models[model]）选择ORM，对应连接不可用时返回错误，不做降级
+func New[T any](conn *db.Conn, model string) (Repository[T], error) {
+	switch orm := conn.ORMFor(model); orm {
+	case db.ORMGorm:
+		if conn.Gorm == nil {
+			return nil, fmt.Errorf("%s: %w: gorm", model, db.ErrORMNotOpened)
+		}
+		logx.Infof("Using GORM for %s repository", model)
+		return NewGorm[T](conn.Gorm), nil
+	case db.ORMSqlx:
+		logx.Infof("Using SQLx for %s repository", model)
+		return NewSqlxDialect[T](conn.SqlConn(), conn.ReadSqlConn(), conn.Dialect()), nil
+	default:
+		return nil, fmt.Errorf("%s: %w: %q", model, db.ErrUnknownORM, orm)
+	}
+}
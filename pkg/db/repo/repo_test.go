@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type testEntity struct {
+	Id        int64          `db:"id" gorm:"column:id;primaryKey"`
+	Name      string         `db:"name" gorm:"column:name"`
+	Status    int            `db:"status" gorm:"column:status;default:1"`
+	CreatedAt time.Time      `db:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `db:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `db:"deleted_at" gorm:"column:deleted_at"`
+}
+
+func (testEntity) TableName() string {
+	return "test_entity"
+}
+
+type noPrimaryKey struct {
+	Name string `db:"name" gorm:"column:name"`
+}
+
+func TestParse(t *testing.T) {
+	m, err := parse[testEntity](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.table != "test_entity" || m.primary.DBName != "id" || m.softDelete == nil || m.softDelete.DBName != "deleted_at" {
+		t.Errorf("unexpected meta: table=%s primary=%s softDelete=%v", m.table, m.primary.DBName, m.softDelete)
+	}
+	want := []string{"id", "name", "status", "created_at", "updated_at", "deleted_at"}
+	if got := m.selectColumns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("selectColumns() = %v, want %v", got, want)
+	}
+
+	if _, err := parse[noPrimaryKey](nil); !errors.Is(err, ErrNoPrimaryKey) {
+		t.Errorf("parse(noPrimaryKey) err = %v, want ErrNoPrimaryKey", err)
+	}
+}
+
+func TestBuildWhere(t *testing.T) {
+	m := mustParse[testEntity](nil)
+
+	tests := []struct {
+		name     string
+		conds    []Cond
+		unscoped bool
+		want     string
+		args     []interface{}
+		err      error
+	}{
+		{"无条件追加软删除", nil, false, "deleted_at IS NULL", nil, nil},
+		{"包含已删除", nil, true, "", nil, nil},
+		{"多条件", []Cond{Eq("name", "a"), Gte("status", 1)}, true, "name = ? AND status >= ?", []interface{}{"a", 1}, nil},
+		{"IN展开", []Cond{In("id", []int64{1, 2})}, true, "id IN (?, ?)", []interface{}{int64(1), int64(2)}, nil},
+		{"IN空集合", []Cond{In("id", []int64{})}, true, "1 = 0", nil, nil},
+		{"未知列", []Cond{Eq("name; DROP TABLE x", 1)}, true, "", nil, ErrUnknownColumn},
+		{"非法运算符", []Cond{{Column: "name", Op: "OR 1=1 --"}}, true, "", nil, ErrInvalidOperator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := m.buildWhere(tt.conds, tt.unscoped)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("buildWhere() = %q %v, want %q %v", got, args, tt.want, tt.args)
+			}
+		})
+	}
+}
+
+func TestBuildOrder(t *testing.T) {
+	m := mustParse[testEntity](nil)
+
+	if got, _ := m.buildOrder(nil); got != "id ASC" {
+		t.Errorf("default order = %q", got)
+	}
+	if got, _ := m.buildOrder([]Order{Desc("status"), Asc("id")}); got != "status DESC, id ASC" {
+		t.Errorf("order = %q", got)
+	}
+	if _, err := m.buildOrder([]Order{Asc("unknown")}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("unknown column err = %v", err)
+	}
+}
+
+func TestInsertAndUpdateValues(t *testing.T) {
+	m := mustParse[testEntity](nil)
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	data := &testEntity{Name: "a"}
+	columns, values, err := m.insertValues(ctx, reflect.ValueOf(data).Elem(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"name", "status", "created_at", "updated_at"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("insert columns = %v, want %v", columns, want)
+	}
+	if data.Status != 1 || !data.CreatedAt.Equal(now) || !data.UpdatedAt.Equal(now) {
+		t.Errorf("defaults not filled: %+v", data)
+	}
+	if len(values) != len(columns) {
+		t.Errorf("values = %v", values)
+	}
+
+	data.Id = 10
+	later := now.Add(time.Hour)
+	columns, _, err = m.updateValues(ctx, reflect.ValueOf(data).Elem(), later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"name", "status", "updated_at"}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("update columns = %v, want %v", columns, want)
+	}
+	if !data.UpdatedAt.Equal(later) || !data.CreatedAt.Equal(now) {
+		t.Errorf("timestamps = %v/%v", data.CreatedAt, data.UpdatedAt)
+	}
+}
@@ -0,0 +1,265 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// sqlxRepo 基于 go-zero sqlx 的实现
+type sqlxRepo[T any] struct {
+	conn sqlx.SqlConn // 写操作及事务
+	read sqlx.SqlConn // 只读查询
+	meta *meta
+	inTx bool // go-zero 不支持嵌套事务，已在事务中时直接复用
+
+	dialect dialect.Dialect // 占位符改写及主键回填方式
+}
+
+// NewSqlx 创建 sqlx 仓储
+func NewSqlx[T any](db *sql.DB) Repository[T] {
+	return NewSqlxConn[T](sqlx.NewSqlConnFromDB(db))
+}
+
+// NewSqlxConn 基于已有 sqlx.SqlConn 创建仓储
+func NewSqlxConn[T any](conn sqlx.SqlConn) Repository[T] {
+	return NewSqlxReadWrite[T](conn, conn)
+}
+
+// NewSqlxReadWrite 创建读写分离的 sqlx 仓储，查询使用 read，写操作及事务使用 conn
+func NewSqlxReadWrite[T any](conn, read sqlx.SqlConn) Repository[T] {
+	return NewSqlxDialect[T](conn, read, dialect.MySQL)
+}
+
+// NewSqlxDialect 创建指定方言的读写分离 sqlx 仓储
+func NewSqlxDialect[T any](conn, read sqlx.SqlConn, d dialect.Dialect) Repository[T] {
+	return &sqlxRepo[T]{conn: conn, read: read, meta: mustParse[T](nil), dialect: d}
+}
+
+// withConn 返回绑定到指定连接（事务）的仓储，读写均使用该连接
+func (r *sqlxRepo[T]) withConn(conn sqlx.SqlConn) *sqlxRepo[T] {
+	return &sqlxRepo[T]{conn: conn, read: conn, meta: r.meta, inTx: true, dialect: r.dialect}
+}
+
+// Insert 插入
+func (r *sqlxRepo[T]) Insert(ctx context.Context, data *T) error {
+	rv := reflect.ValueOf(data).Elem()
+	columns, values, err := r.meta.insertValues(ctx, rv, time.Now())
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		r.meta.table, strings.Join(columns, ", "), placeholders(len(columns)))
+
+	_, zero := r.meta.primary.ValueOf(ctx, rv)
+	if !zero || !r.meta.primary.AutoIncrement {
+		_, err = r.conn.ExecCtx(ctx, r.dialect.Rebind(query), values...)
+		return err
+	}
+
+	// 回填自增主键：支持 RETURNING 的方言直接返回，否则使用 LastInsertId
+	var id int64
+	if r.dialect.SupportsReturning() {
+		query += r.dialect.Returning(r.meta.primary.DBName)
+		if err := r.conn.QueryRowCtx(ctx, &id, r.dialect.Rebind(query), values...); err != nil {
+			return err
+		}
+	} else {
+		result, err := r.conn.ExecCtx(ctx, query, values...)
+		if err != nil {
+			return err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+	return r.meta.primary.Set(ctx, rv, id)
+}
+
+// BatchInsert 批量插入（同一事务内逐条插入，保证自增主键正确回填）
+func (r *sqlxRepo[T]) BatchInsert(ctx context.Context, list []*T) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.Trans(ctx, func(ctx context.Context, repo Repository[T]) error {
+		for _, data := range list {
+			if err := repo.Insert(ctx, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindOne 根据主键查找
+func (r *sqlxRepo[T]) FindOne(ctx context.Context, id int64) (*T, error) {
+	return r.FindOneBy(ctx, Eq(r.meta.primary.DBName, id))
+}
+
+// FindOneBy 根据条件查找一条
+func (r *sqlxRepo[T]) FindOneBy(ctx context.Context, conds ...Cond) (*T, error) {
+	where, args, err := r.meta.buildWhere(conds, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data T
+	query := fmt.Sprintf("SELECT %s FROM %s%s LIMIT 1",
+		strings.Join(r.meta.selectColumns(), ", "), r.meta.table, whereClause(where))
+	if err := r.read.QueryRowCtx(ctx, &data, r.dialect.Rebind(query), args...); err != nil {
+		if errors.Is(err, sqlx.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Find 根据查询条件查找
+func (r *sqlxRepo[T]) Find(ctx context.Context, q Query) ([]*T, error) {
+	where, args, err := r.meta.buildWhere(q.Conds, q.Unscoped)
+	if err != nil {
+		return nil, err
+	}
+	order, err := r.meta.buildOrder(q.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s",
+		strings.Join(r.meta.selectColumns(), ", "), r.meta.table, whereClause(where), order)
+	if q.PageSize > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.PageSize, q.offset())
+	}
+
+	var list []*T
+	err = r.read.QueryRowsCtx(ctx, &list, r.dialect.Rebind(query), args...)
+	return list, err
+}
+
+// List 分页查询
+func (r *sqlxRepo[T]) List(ctx context.Context, q Query) ([]*T, int64, error) {
+	total, err := r.count(ctx, q.Conds, q.Unscoped)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := r.Find(ctx, q)
+	return list, total, err
+}
+
+// Count 统计数量
+func (r *sqlxRepo[T]) Count(ctx context.Context, conds ...Cond) (int64, error) {
+	return r.count(ctx, conds, false)
+}
+
+func (r *sqlxRepo[T]) count(ctx context.Context, conds []Cond, unscoped bool) (int64, error) {
+	where, args, err := r.meta.buildWhere(conds, unscoped)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", r.meta.table, whereClause(where))
+	err = r.read.QueryRowCtx(ctx, &total, r.dialect.Rebind(query), args...)
+	return total, err
+}
+
+// Update 根据主键更新全部字段
+func (r *sqlxRepo[T]) Update(ctx context.Context, data *T) error {
+	rv := reflect.ValueOf(data).Elem()
+	columns, values, err := r.meta.updateValues(ctx, rv, time.Now())
+	if err != nil {
+		return err
+	}
+	id, _ := r.meta.primary.ValueOf(ctx, rv)
+	where, args, err := r.meta.buildWhere([]Cond{Eq(r.meta.primary.DBName, id)}, false)
+	if err != nil {
+		return err
+	}
+
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		sets[i] = column + " = ?"
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", r.meta.table, strings.Join(sets, ", "), where)
+	_, err = r.conn.ExecCtx(ctx, r.dialect.Rebind(query), append(values, args...)...)
+	return err
+}
+
+// Delete 根据主键删除
+func (r *sqlxRepo[T]) Delete(ctx context.Context, id int64) error {
+	return r.delete(ctx, Eq(r.meta.primary.DBName, id))
+}
+
+// BatchDelete 根据主键批量删除
+func (r *sqlxRepo[T]) BatchDelete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.delete(ctx, In(r.meta.primary.DBName, ids))
+}
+
+// delete 启用软删除时更新删除时间，否则物理删除
+func (r *sqlxRepo[T]) delete(ctx context.Context, cond Cond) error {
+	where, args, err := r.meta.buildWhere([]Cond{cond}, false)
+	if err != nil {
+		return err
+	}
+
+	if r.meta.softDelete != nil {
+		query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s", r.meta.table, r.meta.softDelete.DBName, where)
+		_, err = r.conn.ExecCtx(ctx, r.dialect.Rebind(query), append([]interface{}{time.Now()}, args...)...)
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", r.meta.table, where)
+	_, err = r.conn.ExecCtx(ctx, r.dialect.Rebind(query), args...)
+	return err
+}
+
+// WithTx 返回绑定事务的仓储
+func (r *sqlxRepo[T]) WithTx(tx interface{}) Repository[T] {
+	switch conn := tx.(type) {
+	case sqlx.SqlConn:
+		return r.withConn(conn)
+	case sqlx.Session:
+		return r.withConn(sqlx.NewSqlConnFromSession(conn))
+	}
+	// 如果不是sqlx连接，返回自身
+	return r
+}
+
+// Trans 在事务中执行
+func (r *sqlxRepo[T]) Trans(ctx context.Context, fn func(ctx context.Context, repo Repository[T]) error) error {
+	if r.inTx {
+		return fn(ctx, r)
+	}
+	return r.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		txConn := sqlx.NewSqlConnFromSession(session)
+		return fn(db.ContextWithTx(ctx, txConn), r.withConn(txConn))
+	})
+}
+
+// placeholders 生成 n 个占位符
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// whereClause 非空时加上 WHERE 关键字
+func whereClause(where string) string {
+	if where == "" {
+		return ""
+	}
+	return " WHERE " + where
+}
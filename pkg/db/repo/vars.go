@@ -0,0 +1,11 @@
+package repo
+
+import "errors"
+
+// 错误定义
+var (
+	ErrNotFound        = errors.New("record not found")
+	ErrUnknownColumn   = errors.New("unknown column")
+	ErrInvalidOperator = errors.New("invalid operator")
+	ErrNoPrimaryKey    = errors.New("entity has no primary key")
+)
@@ -0,0 +1,154 @@
+package schemacheck
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// typeFamilies gorm 字段类型对应的兼容 MySQL 类型
+var typeFamilies = map[schema.DataType][]string{
+	schema.Bool:   {"tinyint", "bit", "boolean"},
+	schema.Int:    {"tinyint", "smallint", "mediumint", "int", "integer", "bigint"},
+	schema.Uint:   {"tinyint", "smallint", "mediumint", "int", "integer", "bigint"},
+	schema.Float:  {"float", "double", "decimal", "real"},
+	schema.String: {"char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set", "json"},
+	schema.Time:   {"datetime", "timestamp", "date"},
+	schema.Bytes:  {"binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob"},
+}
+
+// intDisplayWidth MySQL 5.7 整型显示宽度，如 bigint(20)
+var intDisplayWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|integer|bigint)\(\d+\)`)
+
+// checkColumns 对比实体字段与数据库列
+func checkColumns(sch *schema.Schema, columns map[string]column) []Issue {
+	var issues []Issue
+	mapped := make(map[string]bool)
+
+	for _, field := range sch.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		name := strings.ToLower(field.DBName)
+		mapped[name] = true
+
+		col, ok := columns[name]
+		if !ok {
+			issues = append(issues, Issue{Column: field.DBName, Kind: IssueColumnMissing, Severity: SeverityError,
+				Message: fmt.Sprintf("字段 %s 对应的列不存在", field.Name)})
+			continue
+		}
+		if msg := typeMismatch(field, col); msg != "" {
+			issues = append(issues, Issue{Column: field.DBName, Kind: IssueTypeMismatch, Severity: SeverityError, Message: msg})
+		}
+	}
+
+	// 未映射且 NOT NULL 无默认值的列会导致插入失败
+	for name, col := range columns {
+		if mapped[name] || col.Nullable || col.HasDefault || col.AutoIncr {
+			continue
+		}
+		issues = append(issues, Issue{Column: col.Name, Kind: IssueUnmappedColumn, Severity: SeverityWarning,
+			Message: "列为 NOT NULL 且无默认值，但实体未映射，插入时将失败"})
+	}
+
+	return issues
+}
+
+// typeMismatch 检查字段类型，兼容时返回空字符串
+// 若 gorm tag 显式声明了 type，则要求与列类型完全一致；否则按类型族判断
+func typeMismatch(field *schema.Field, col column) string {
+	if declared := normalizeType(field.TagSettings["TYPE"]); declared != "" {
+		actual := normalizeType(col.ColumnType)
+		if !strings.Contains(declared, "(") {
+			actual = col.DataType
+		}
+		if declared != actual {
+			return fmt.Sprintf("声明类型为 %s，实际为 %s", declared, col.ColumnType)
+		}
+		return ""
+	}
+
+	family, ok := typeFamilies[field.DataType]
+	if !ok {
+		// 自定义类型（实现 GormDataTypeInterface 等）不做判断
+		return ""
+	}
+	for _, t := range family {
+		if col.DataType == t {
+			return ""
+		}
+	}
+	return fmt.Sprintf("Go 类型 %s 与列类型 %s 不兼容", field.FieldType, col.ColumnType)
+}
+
+// normalizeType 规范化类型：小写、去空格、去整型显示宽度
+func normalizeType(t string) string {
+	t = strings.ToLower(strings.Join(strings.Fields(t), ""))
+	return intDisplayWidth.ReplaceAllString(t, "$1")
+}
+
+// checkIndexes 检查主键及 gorm tag 声明的索引
+// 索引按列匹配而非名称，因为迁移脚本中的索引名不必与 gorm 默认命名一致
+func checkIndexes(sch *schema.Schema, indexes []index) []Issue {
+	var issues []Issue
+
+	if len(sch.PrimaryFieldDBNames) > 0 {
+		if !hasIndex(indexes, sch.PrimaryFieldDBNames, true, "PRIMARY") {
+			issues = append(issues, Issue{Column: strings.Join(sch.PrimaryFieldDBNames, ","), Kind: IssuePrimaryKeyMissing,
+				Severity: SeverityError, Message: "主键不存在"})
+		}
+	}
+
+	for _, idx := range sch.ParseIndexes() {
+		if idx.Class != "" && idx.Class != "UNIQUE" {
+			// FULLTEXT/SPATIAL 不检查
+			continue
+		}
+		cols := make([]string, 0, len(idx.Fields))
+		for _, opt := range idx.Fields {
+			if opt.Field != nil {
+				cols = append(cols, opt.DBName)
+			}
+		}
+		unique := idx.Class == "UNIQUE"
+		if !hasIndex(indexes, cols, unique, "") {
+			kind := "索引"
+			if unique {
+				kind = "唯一索引"
+			}
+			issues = append(issues, Issue{Column: strings.Join(cols, ","), Kind: IssueIndexMissing, Severity: SeverityError,
+				Message: fmt.Sprintf("%s %s 不存在", kind, idx.Name)})
+		}
+	}
+
+	return issues
+}
+
+// hasIndex 是否存在覆盖指定列（顺序一致）的索引，unique 为 true 时要求唯一索引
+func hasIndex(indexes []index, cols []string, unique bool, name string) bool {
+	for _, idx := range indexes {
+		if name != "" && idx.Name != name {
+			continue
+		}
+		if unique && !idx.Unique {
+			continue
+		}
+		if len(idx.Columns) != len(cols) {
+			continue
+		}
+		match := true
+		for i, c := range cols {
+			if idx.Columns[i] != strings.ToLower(c) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
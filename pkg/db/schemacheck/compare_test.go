@@ -0,0 +1,108 @@
+package schemacheck
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+type testEntity struct {
+	Id        int64     `db:"id" gorm:"column:id;primaryKey"`
+	Name      string    `db:"name" gorm:"column:name;type:varchar(100);not null"`
+	Code      string    `db:"code" gorm:"column:code;type:varchar(50);uniqueIndex;not null"`
+	ParentId  int64     `db:"parent_id" gorm:"column:parent_id;index"`
+	CreatedAt time.Time `db:"created_at" gorm:"column:created_at"`
+}
+
+func (testEntity) TableName() string {
+	return "test_entity"
+}
+
+func parseTestEntity(t *testing.T) *schema.Schema {
+	sch, err := schema.Parse(&testEntity{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sch
+}
+
+func goodColumns() map[string]column {
+	return map[string]column{
+		"id":         {Name: "id", DataType: "bigint", ColumnType: "bigint(20)", AutoIncr: true},
+		"name":       {Name: "name", DataType: "varchar", ColumnType: "varchar(100)"},
+		"code":       {Name: "code", DataType: "varchar", ColumnType: "varchar(50)"},
+		"parent_id":  {Name: "parent_id", DataType: "bigint", ColumnType: "bigint", HasDefault: true},
+		"created_at": {Name: "created_at", DataType: "datetime", ColumnType: "datetime"},
+	}
+}
+
+func TestCheckColumns(t *testing.T) {
+	sch := parseTestEntity(t)
+
+	tests := []struct {
+		name   string
+		modify func(map[string]column)
+		want   []IssueKind
+	}{
+		{"完全一致", func(map[string]column) {}, nil},
+		{"缺少列", func(c map[string]column) { delete(c, "parent_id") }, []IssueKind{IssueColumnMissing}},
+		{"类型族不兼容", func(c map[string]column) {
+			c["created_at"] = column{Name: "created_at", DataType: "varchar", ColumnType: "varchar(20)"}
+		}, []IssueKind{IssueTypeMismatch}},
+		{"声明类型长度不一致", func(c map[string]column) {
+			c["name"] = column{Name: "name", DataType: "varchar", ColumnType: "varchar(50)"}
+		}, []IssueKind{IssueTypeMismatch}},
+		{"未映射的必填列", func(c map[string]column) {
+			c["tenant_id"] = column{Name: "tenant_id", DataType: "bigint", ColumnType: "bigint"}
+		}, []IssueKind{IssueUnmappedColumn}},
+		{"未映射的可空列", func(c map[string]column) {
+			c["remark"] = column{Name: "remark", DataType: "varchar", ColumnType: "varchar(20)", Nullable: true}
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := goodColumns()
+			tt.modify(columns)
+			assertKinds(t, checkColumns(sch, columns), tt.want)
+		})
+	}
+}
+
+func TestCheckIndexes(t *testing.T) {
+	sch := parseTestEntity(t)
+	primary := index{Name: "PRIMARY", Unique: true, Columns: []string{"id"}}
+	unique := index{Name: "uk_code", Unique: true, Columns: []string{"code"}}
+	parent := index{Name: "idx_parent_id", Columns: []string{"parent_id"}}
+
+	tests := []struct {
+		name    string
+		indexes []index
+		want    []IssueKind
+	}{
+		{"索引名不同但列一致", []index{primary, unique, parent}, nil},
+		{"缺少主键", []index{unique, parent}, []IssueKind{IssuePrimaryKeyMissing}},
+		{"唯一索引不唯一", []index{primary, {Name: "uk_code", Columns: []string{"code"}}, parent}, []IssueKind{IssueIndexMissing}},
+		{"缺少普通索引", []index{primary, unique}, []IssueKind{IssueIndexMissing}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertKinds(t, checkIndexes(sch, tt.indexes), tt.want)
+		})
+	}
+}
+
+func assertKinds(t *testing.T, issues []Issue, want []IssueKind) {
+	t.Helper()
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %v, want kinds %v", issues, want)
+	}
+	for i, issue := range issues {
+		if issue.Kind != want[i] {
+			t.Errorf("issue[%d] kind = %s, want %s", i, issue.Kind, want[i])
+		}
+	}
+}
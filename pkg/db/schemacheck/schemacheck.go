@@ -0,0 +1,275 @@
+package schemacheck
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// Severity 问题级别
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // 运行时会出错
+	SeverityWarning Severity = "warning" // 不影响运行，但与定义不一致
+)
+
+// IssueKind 问题类型
+type IssueKind string
+
+const (
+	IssueTableMissing         IssueKind = "table_missing"          // 表不存在
+	IssueTableNameDivergence  IssueKind = "table_name_divergence"  // gorm 与 sqlx 表名不一致
+	IssueColumnNameDivergence IssueKind = "column_name_divergence" // gorm column 与 db tag 不一致
+	IssueColumnMissing        IssueKind = "column_missing"         // 实体字段在表中不存在
+	IssueTypeMismatch         IssueKind = "type_mismatch"          // 字段类型不兼容
+	IssueIndexMissing         IssueKind = "index_missing"          // gorm tag 声明的索引不存在
+	IssueUnmappedColumn       IssueKind = "unmapped_column"        // 表中存在实体未映射的 NOT NULL 无默认值列
+	IssuePrimaryKeyMissing    IssueKind = "primary_key_missing"    // 主键不存在
+)
+
+// Entity 需要检查的实体
+type Entity struct {
+	Database  string      // 数据库（如 resource_catalog）
+	Model     interface{} // 实体指针，如 &category.Category{}
+	SqlxTable string      // sqlx 实现使用的表名
+}
+
+// Issue 检查发现的问题
+type Issue struct {
+	Database string
+	Table    string
+	Column   string
+	Kind     IssueKind
+	Severity Severity
+	Message  string
+}
+
+// String 格式化输出
+func (i Issue) String() string {
+	target := i.Table
+	if i.Column != "" {
+		target += "." + i.Column
+	}
+	return fmt.Sprintf("[%s] %s %s: %s (%s)", i.Severity, i.Database, target, i.Message, i.Kind)
+}
+
+// Report 检查报告
+type Report struct {
+	Issues []Issue
+}
+
+// HasErrors 是否存在 error 级别问题
+func (r *Report) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Entity
+)
+
+// Register 注册需要检查的实体（由各 model 包在 init 中调用）
+func Register(e Entity) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, e)
+}
+
+// Entities 获取指定数据库已注册的实体
+func Entities(database string) []Entity {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var entities []Entity
+	for _, e := range registry {
+		if e.Database == database {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+// Checker 实体与数据库表结构一致性检查器（MySQL information_schema）
+type Checker struct {
+	db    *sql.DB
+	namer schema.Namer
+}
+
+// NewChecker 创建检查器，namer 需与 gorm 连接使用的命名策略一致
+func NewChecker(db *sql.DB, namer schema.Namer) *Checker {
+	return &Checker{db: db, namer: namer}
+}
+
+// Check 检查指定数据库下所有已注册实体
+func (c *Checker) Check(ctx context.Context, database string) (*Report, error) {
+	report := &Report{}
+	for _, e := range Entities(database) {
+		issues, err := c.CheckEntity(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+	return report, nil
+}
+
+// CheckEntity 检查单个实体
+func (c *Checker) CheckEntity(ctx context.Context, e Entity) ([]Issue, error) {
+	sch, err := schema.Parse(e.Model, &sync.Map{}, c.namer)
+	if err != nil {
+		return nil, fmt.Errorf("parse entity %T: %w", e.Model, err)
+	}
+
+	var issues []Issue
+	report := func(table, column string, kind IssueKind, severity Severity, format string, args ...interface{}) {
+		issues = append(issues, Issue{
+			Database: e.Database,
+			Table:    table,
+			Column:   column,
+			Kind:     kind,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// 1. gorm 与 sqlx 表名
+	tables := []string{sch.Table}
+	if e.SqlxTable != "" && e.SqlxTable != sch.Table {
+		report(sch.Table, "", IssueTableNameDivergence, SeverityError,
+			"gorm 表名为 %s，sqlx 表名为 %s", sch.Table, e.SqlxTable)
+		tables = append(tables, e.SqlxTable)
+	}
+
+	// 2. 字段映射：gorm column 与 db tag
+	for _, field := range sch.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		if dbTag := strings.Split(field.Tag.Get("db"), ",")[0]; dbTag != "" && dbTag != "-" && dbTag != field.DBName {
+			report(sch.Table, field.DBName, IssueColumnNameDivergence, SeverityError,
+				"字段 %s 的 gorm 列名为 %s，db tag 为 %s", field.Name, field.DBName, dbTag)
+		}
+	}
+
+	// 3. 逐表检查列和索引
+	for _, table := range tables {
+		columns, err := c.columns(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			report(table, "", IssueTableMissing, SeverityError, "表不存在")
+			continue
+		}
+
+		indexes, err := c.indexes(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range checkColumns(sch, columns) {
+			report(table, issue.Column, issue.Kind, issue.Severity, "%s", issue.Message)
+		}
+		for _, issue := range checkIndexes(sch, indexes) {
+			report(table, issue.Column, issue.Kind, issue.Severity, "%s", issue.Message)
+		}
+	}
+
+	return issues, nil
+}
+
+// column 数据库列信息
+type column struct {
+	Name       string
+	DataType   string // 如 varchar
+	ColumnType string // 如 varchar(100)
+	Nullable   bool
+	HasDefault bool
+	AutoIncr   bool
+}
+
+// index 数据库索引信息
+type index struct {
+	Name    string
+	Unique  bool
+	Columns []string // 按 SEQ_IN_INDEX 排序
+}
+
+// columns 查询表的列信息，表不存在时返回空
+func (c *Checker) columns(ctx context.Context, table string) (map[string]column, error) {
+	rows, err := c.db.QueryContext(ctx, `SELECT COLUMN_NAME, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT IS NOT NULL, EXTRA
+        FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?`, table)
+	if err != nil {
+		return nil, fmt.Errorf("query columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]column)
+	for rows.Next() {
+		var (
+			col      column
+			nullable string
+			extra    string
+		)
+		if err := rows.Scan(&col.Name, &col.DataType, &col.ColumnType, &nullable, &col.HasDefault, &extra); err != nil {
+			return nil, err
+		}
+		col.DataType = strings.ToLower(col.DataType)
+		col.ColumnType = strings.ToLower(col.ColumnType)
+		col.Nullable = nullable == "YES"
+		col.AutoIncr = strings.Contains(strings.ToLower(extra), "auto_increment")
+		columns[strings.ToLower(col.Name)] = col
+	}
+	return columns, rows.Err()
+}
+
+// indexes 查询表的索引信息
+func (c *Checker) indexes(ctx context.Context, table string) ([]index, error) {
+	rows, err := c.db.QueryContext(ctx, `SELECT INDEX_NAME, NON_UNIQUE, COLUMN_NAME
+        FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+        ORDER BY INDEX_NAME, SEQ_IN_INDEX`, table)
+	if err != nil {
+		return nil, fmt.Errorf("query indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	byName := make(map[string]*index)
+	var names []string
+	for rows.Next() {
+		var (
+			name, col string
+			nonUnique int
+		)
+		if err := rows.Scan(&name, &nonUnique, &col); err != nil {
+			return nil, err
+		}
+		idx, ok := byName[name]
+		if !ok {
+			idx = &index{Name: name, Unique: nonUnique == 0}
+			byName[name] = idx
+			names = append(names, name)
+		}
+		idx.Columns = append(idx.Columns, strings.ToLower(col))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	indexes := make([]index, 0, len(names))
+	for _, name := range names {
+		indexes = append(indexes, *byName[name])
+	}
+	return indexes, nil
+}
@@ -0,0 +1,18 @@
+package db
+
+import "context"
+
+type txContextKey struct{}
+
+// ContextWithTx 将事务对象放入 context（*gorm.DB 或 sqlx.SqlConn）
+// Model.Trans 在调用回调前放入，同一事务中的其他仓储（如 outbox）通过 TxFromContext 取出后 WithTx，
+// 保证业务数据与附属记录原子写入
+func ContextWithTx(ctx context.Context, tx interface{}) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext 获取 context 中的事务对象，不在事务中时返回 false
+func TxFromContext(ctx context.Context) (interface{}, bool) {
+	tx := ctx.Value(txContextKey{})
+	return tx, tx != nil
+}
@@ -0,0 +1,105 @@
+package harvest
+
+import "sort"
+
+// 结构变化类型
+const (
+	ChangeColumnAdded     = "column_added"
+	ChangeColumnDropped   = "column_dropped"
+	ChangeColumnRenamed   = "column_renamed"
+	ChangeTypeChanged     = "type_changed"
+	ChangeNullableChanged = "nullable_changed"
+	ChangeCommentChanged  = "comment_changed"
+	ChangeTableComment    = "table_comment_changed"
+	ChangeTableRemoved    = "table_removed"
+)
+
+// Change 两次采集间的结构变化
+type Change struct {
+	Type     string `json:"type"`
+	Column   string `json:"column"` // 列名，重命名时为新列名，表级变化为空
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+	Breaking bool   `json:"breaking"` // 可能导致下游查询失败：删除列、重命名列、类型变化、删除表
+}
+
+// Diff 比较同一张表两次采集的结构，返回表注释及列的变化（索引变化不在此列出）
+//
+// 列按名称匹配；删除的列与新增的列位置相同且类型相同时视为重命名。
+func Diff(old, cur *Table) []Change {
+	var changes []Change
+	if old.Comment != cur.Comment {
+		changes = append(changes, Change{Type: ChangeTableComment, OldValue: old.Comment, NewValue: cur.Comment})
+	}
+
+	oldByName := make(map[string]Column, len(old.Columns))
+	for _, c := range old.Columns {
+		oldByName[c.Name] = c
+	}
+	curByName := make(map[string]bool, len(cur.Columns))
+	var added []Column
+	for _, c := range cur.Columns {
+		curByName[c.Name] = true
+		o, ok := oldByName[c.Name]
+		if !ok {
+			added = append(added, c)
+			continue
+		}
+		changes = append(changes, diffColumn(o, c)...)
+	}
+
+	// 删除的列按位置索引，与同位置、同类型的新增列配对为重命名
+	dropped := make(map[int]Column)
+	var droppedOrder []Column
+	for _, c := range old.Columns {
+		if !curByName[c.Name] {
+			dropped[c.Position] = c
+			droppedOrder = append(droppedOrder, c)
+		}
+	}
+	renamed := make(map[string]bool)
+	for _, c := range added {
+		if o, ok := dropped[c.Position]; ok && o.ColumnType == c.ColumnType && !renamed[o.Name] {
+			renamed[o.Name] = true
+			changes = append(changes, Change{Type: ChangeColumnRenamed, Column: c.Name, OldValue: o.Name, NewValue: c.Name, Breaking: true})
+			continue
+		}
+		changes = append(changes, Change{Type: ChangeColumnAdded, Column: c.Name, NewValue: c.ColumnType})
+	}
+	for _, o := range droppedOrder {
+		if !renamed[o.Name] {
+			changes = append(changes, Change{Type: ChangeColumnDropped, Column: o.Name, OldValue: o.ColumnType, Breaking: true})
+		}
+	}
+
+	// 表级变化在前，其余按列名排序，结果稳定
+	sort.SliceStable(changes, func(i, j int) bool {
+		if (changes[i].Column == "") != (changes[j].Column == "") {
+			return changes[i].Column == ""
+		}
+		return changes[i].Column < changes[j].Column
+	})
+	return changes
+}
+
+// diffColumn 同名列的变化
+func diffColumn(o, c Column) []Change {
+	var changes []Change
+	if o.ColumnType != c.ColumnType {
+		changes = append(changes, Change{Type: ChangeTypeChanged, Column: c.Name, OldValue: o.ColumnType, NewValue: c.ColumnType, Breaking: true})
+	}
+	if o.Nullable != c.Nullable {
+		changes = append(changes, Change{Type: ChangeNullableChanged, Column: c.Name, OldValue: nullable(o.Nullable), NewValue: nullable(c.Nullable)})
+	}
+	if o.Comment != c.Comment {
+		changes = append(changes, Change{Type: ChangeCommentChanged, Column: c.Name, OldValue: o.Comment, NewValue: c.Comment})
+	}
+	return changes
+}
+
+func nullable(b bool) string {
+	if b {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
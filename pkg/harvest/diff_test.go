@@ -0,0 +1,49 @@
+package harvest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	col := func(pos int, name, typ string) Column {
+		return Column{Name: name, Position: pos, DataType: typ, ColumnType: typ}
+	}
+	base := &Table{Name: "users", Comment: "用户", Columns: []Column{
+		col(1, "id", "bigint"), col(2, "name", "varchar(50)"), col(3, "age", "int"),
+	}}
+
+	tests := []struct {
+		name    string
+		columns []Column
+		comment string
+		want    []Change
+	}{
+		{"无变化", base.Columns, "用户", nil},
+		{"新增列", append(append([]Column{}, base.Columns...), col(4, "email", "varchar(100)")), "用户",
+			[]Change{{Type: ChangeColumnAdded, Column: "email", NewValue: "varchar(100)"}}},
+		{"删除列", base.Columns[:2], "用户",
+			[]Change{{Type: ChangeColumnDropped, Column: "age", OldValue: "int", Breaking: true}}},
+		{"同位置同类型视为重命名", []Column{col(1, "id", "bigint"), col(2, "full_name", "varchar(50)"), col(3, "age", "int")}, "用户",
+			[]Change{{Type: ChangeColumnRenamed, Column: "full_name", OldValue: "name", NewValue: "full_name", Breaking: true}}},
+		{"类型不同不视为重命名", []Column{col(1, "id", "bigint"), col(2, "full_name", "text"), col(3, "age", "int")}, "用户",
+			[]Change{
+				{Type: ChangeColumnAdded, Column: "full_name", NewValue: "text"},
+				{Type: ChangeColumnDropped, Column: "name", OldValue: "varchar(50)", Breaking: true},
+			}},
+		{"类型及注释变化", []Column{col(1, "id", "bigint"), col(2, "name", "varchar(100)"), {Name: "age", Position: 3, ColumnType: "int", Comment: "年龄"}}, "用户表",
+			[]Change{
+				{Type: ChangeTableComment, OldValue: "用户", NewValue: "用户表"},
+				{Type: ChangeCommentChanged, Column: "age", NewValue: "年龄"},
+				{Type: ChangeTypeChanged, Column: "name", OldValue: "varchar(50)", NewValue: "varchar(100)", Breaking: true},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(base, &Table{Name: "users", Comment: tt.comment, Columns: tt.columns})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package harvest 元数据采集：读取源数据库的表、视图、列（含注释）及索引
+//
+// 支持 MySQL（information_schema）、PostgreSQL（information_schema + pg_catalog）和 SQLite（sqlite_master + PRAGMA）。
+// 视图同时读取定义（SELECT 语句），用于解析血缘。
+// 每张表生成结构指纹（Fingerprint），增量采集时指纹未变化的表跳过写入。
+package harvest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+)
+
+// 表类型
+const (
+	TypeTable = "table"
+	TypeView  = "view"
+)
+
+var ErrUnsupportedDialect = errors.New("harvest: unsupported dialect")
+
+// Source 采集源配置
+type Source struct {
+	Name       string    // 源名称（唯一），采集结果按源区分
+	Datasource string    `json:",optional"` // 已注册的数据源名称（datasource 表，密码加密存储），设置时忽略 DB
+	DB         db.Config `json:",optional"` // 连接配置，只读账号即可
+	Schemas    []string  `json:",optional"` // 采集的 schema，为空时 MySQL 为连接的数据库，PostgreSQL 为 public，SQLite 固定为 main
+	Exclude    []string  `json:",optional"` // 排除的表名（filepath.Match 规则，如 tmp_*）
+	Owner      string    `json:",optional"` // 负责人，结构变化时通知；为空时使用已注册数据源的负责人
+}
+
+// Table 表或视图
+type Table struct {
+	Schema  string   `json:"schema"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"` // table/view
+	Comment string   `json:"comment"`
+	Columns []Column `json:"columns"` // 按位置排序
+	Indexes []Index  `json:"indexes"` // 按名称排序
+	// 视图定义（SELECT 语句），表为空；无权限读取时也为空
+	Definition string `json:"definition,omitempty"`
+}
+
+// Column 列
+type Column struct {
+	Name       string `json:"name"`
+	Position   int    `json:"position"`    // 从 1 开始
+	DataType   string `json:"data_type"`   // 类型名，如 varchar
+	ColumnType string `json:"column_type"` // 完整类型，如 varchar(100)
+	Nullable   bool   `json:"nullable"`
+	Default    string `json:"default"` // 默认值表达式，无默认值时为空
+	Comment    string `json:"comment"`
+	PrimaryKey bool   `json:"primary_key"`
+}
+
+// Index 索引
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"` // 按索引中的顺序
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+// FullName schema.name
+func (t *Table) FullName() string {
+	return t.Schema + "." + t.Name
+}
+
+// Fingerprint 结构指纹（表结构、注释及索引的 SHA-256），用于判断两次采集间是否变化
+func (t *Table) Fingerprint() string {
+	b, _ := json.Marshal(t)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// inspector 按方言读取元数据
+type inspector interface {
+	inspect(ctx context.Context, schemas []string) ([]*Table, error)
+}
+
+// Inspect 读取源数据库中的表及视图（按 schema、表名排序），排除 Exclude 匹配的表
+func Inspect(ctx context.Context, conn *db.Conn, src Source) ([]*Table, error) {
+	var (
+		in      inspector
+		schemas = src.Schemas
+	)
+	switch conn.Dialect() {
+	case dialect.MySQL:
+		in = &mysqlInspector{conn: conn}
+		if len(schemas) == 0 {
+			database := src.DB.Database
+			if database == "" {
+				// 引用已注册数据源时未配置 DB，使用连接的当前库
+				if err := conn.SqlConn().QueryRowCtx(ctx, &database, "SELECT DATABASE()"); err != nil {
+					return nil, fmt.Errorf("harvest %s: %w", src.Name, err)
+				}
+			}
+			schemas = []string{database}
+		}
+	case dialect.Postgres:
+		in = &postgresInspector{conn: conn}
+		if len(schemas) == 0 {
+			schemas = []string{"public"}
+		}
+	case dialect.SQLite:
+		in = &sqliteInspector{conn: conn}
+		schemas = []string{"main"}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDialect, conn.Dialect())
+	}
+
+	tables, err := in.inspect(ctx, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("harvest %s: %w", src.Name, err)
+	}
+
+	result := tables[:0]
+	for _, t := range tables {
+		if !excluded(t.Name, src.Exclude) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// excluded 表名是否匹配排除规则
+func excluded(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tableSet 按 schema.name 汇总查询结果，保持首次出现的顺序
+type tableSet struct {
+	tables []*Table
+	byName map[string]*Table
+}
+
+func newTableSet() *tableSet {
+	return &tableSet{byName: make(map[string]*Table)}
+}
+
+func (s *tableSet) add(t *Table) {
+	s.byName[t.FullName()] = t
+	s.tables = append(s.tables, t)
+}
+
+// get 获取已读取的表，不存在时返回 nil（如列查询中出现表查询之后新建的表）
+func (s *tableSet) get(schema, name string) *Table {
+	return s.byName[schema+"."+name]
+}
+
+// addIndexColumn 追加索引列（查询结果按索引名、列顺序排列）
+func (t *Table) addIndexColumn(name, column string, unique, primary bool) {
+	if n := len(t.Indexes); n > 0 && t.Indexes[n-1].Name == name {
+		t.Indexes[n-1].Columns = append(t.Indexes[n-1].Columns, column)
+		return
+	}
+	t.Indexes = append(t.Indexes, Index{Name: name, Columns: []string{column}, Unique: unique, Primary: primary})
+}
+
+// markPrimaryKey 按主键索引标记主键列
+func (t *Table) markPrimaryKey() {
+	for _, idx := range t.Indexes {
+		if !idx.Primary {
+			continue
+		}
+		for i := range t.Columns {
+			for _, c := range idx.Columns {
+				if t.Columns[i].Name == c {
+					t.Columns[i].PrimaryKey = true
+				}
+			}
+		}
+	}
+}
+
+// inClause 生成 IN 子句占位符及参数
+func inClause(values []string) (string, []interface{}) {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", args
+}
@@ -0,0 +1,72 @@
+package harvest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/testkit"
+)
+
+func TestInspect_SQLite(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	testkit.Exec(t, conn, "CREATE VIEW enabled_category AS SELECT id, name FROM category WHERE status = 1")
+	testkit.Exec(t, conn, "CREATE TABLE tmp_import (id integer)")
+
+	tables, err := Inspect(ctx, conn, Source{Name: "test", Exclude: []string{"tmp_*", "schema_migrations"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*Table)
+	for _, tbl := range tables {
+		byName[tbl.Name] = tbl
+	}
+	if byName["tmp_import"] != nil || byName["schema_migrations"] != nil {
+		t.Errorf("Inspect() returned excluded tables: %v", tables)
+	}
+
+	category := byName["category"]
+	if category == nil || category.Type != TypeTable || category.Schema != "main" {
+		t.Fatalf("category = %+v, want main.category table", category)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"主键列", category.Columns[0], Column{Name: "id", Position: 1, DataType: "integer", ColumnType: "integer", PrimaryKey: true}},
+		{"带长度的列", category.Columns[1], Column{Name: "name", Position: 2, DataType: "varchar", ColumnType: "varchar(100)"}},
+		{"可空列", category.Columns[6].Nullable, true},
+		{"默认值", category.Columns[3].Default, "0"},
+		{"rowid 主键补充为 PRIMARY 索引", category.Indexes[len(category.Indexes)-1], Index{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true}},
+		{"唯一索引", category.Indexes[2], Index{Name: "uk_code", Columns: []string{"code"}, Unique: true}},
+		{"视图", byName["enabled_category"].Type, TypeView},
+		{"视图列", len(byName["enabled_category"].Columns), 2},
+		{"视图定义", byName["enabled_category"].Definition, "SELECT id, name FROM category WHERE status = 1"},
+		{"表无定义", category.Definition, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+
+	// 结构变化时指纹变化
+	before := category.Fingerprint()
+	testkit.Exec(t, conn, "ALTER TABLE category ADD COLUMN owner varchar(50)")
+	tables, err = Inspect(ctx, conn, Source{Name: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tbl := range tables {
+		if tbl.Name == "category" && tbl.Fingerprint() == before {
+			t.Error("Fingerprint() unchanged after adding a column")
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package harvest
+
+import (
+	"context"
+	"database/sql"
+
+	"idrm/pkg/db"
+)
+
+// mysqlInspector 读取 information_schema
+type mysqlInspector struct {
+	conn *db.Conn
+}
+
+func (m *mysqlInspector) inspect(ctx context.Context, schemas []string) ([]*Table, error) {
+	in, args := inClause(schemas)
+	set := newTableSet()
+
+	rows, err := m.conn.DB.QueryContext(ctx,
+		"SELECT TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE, COALESCE(TABLE_COMMENT, '') FROM information_schema.TABLES "+
+			"WHERE TABLE_SCHEMA IN "+in+" ORDER BY TABLE_SCHEMA, TABLE_NAME", args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var t Table
+		var tableType string
+		if err := rows.Scan(&t.Schema, &t.Name, &tableType, &t.Comment); err != nil {
+			return err
+		}
+		t.Type = TypeTable
+		if tableType == "VIEW" {
+			t.Type = TypeView
+			t.Comment = "" // 视图的 TABLE_COMMENT 固定为 VIEW
+		}
+		set.add(&t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	rows, err = m.conn.DB.QueryContext(ctx,
+		"SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE, "+
+			"COLUMN_DEFAULT, COALESCE(COLUMN_COMMENT, '') FROM information_schema.COLUMNS "+
+			"WHERE TABLE_SCHEMA IN "+in+" ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION", args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var (
+			schema, table, nullable string
+			def                     sql.NullString
+			c                       Column
+		)
+		if err := rows.Scan(&schema, &table, &c.Name, &c.Position, &c.DataType, &c.ColumnType, &nullable, &def, &c.Comment); err != nil {
+			return err
+		}
+		c.Nullable = nullable == "YES"
+		c.Default = def.String
+		if t := set.get(schema, table); t != nil {
+			t.Columns = append(t.Columns, c)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	rows, err = m.conn.DB.QueryContext(ctx,
+		"SELECT TABLE_SCHEMA, TABLE_NAME, INDEX_NAME, NON_UNIQUE, COLUMN_NAME FROM information_schema.STATISTICS "+
+			"WHERE TABLE_SCHEMA IN "+in+" ORDER BY TABLE_SCHEMA, TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX", args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var (
+			schema, table, name, column string
+			nonUnique                   int
+		)
+		if err := rows.Scan(&schema, &table, &name, &nonUnique, &column); err != nil {
+			return err
+		}
+		if t := set.get(schema, table); t != nil {
+			t.addIndexColumn(name, column, nonUnique == 0, name == "PRIMARY")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// 视图定义，需要 SHOW VIEW 权限，否则为空
+	rows, err = m.conn.DB.QueryContext(ctx,
+		"SELECT TABLE_SCHEMA, TABLE_NAME, VIEW_DEFINITION FROM information_schema.VIEWS WHERE TABLE_SCHEMA IN "+in, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var (
+			schema, table string
+			definition    sql.NullString
+		)
+		if err := rows.Scan(&schema, &table, &definition); err != nil {
+			return err
+		}
+		if t := set.get(schema, table); t != nil {
+			t.Definition = definition.String
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, t := range set.tables {
+		t.markPrimaryKey()
+	}
+	return set.tables, nil
+}
+
+// scanRows 遍历结果集并关闭
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
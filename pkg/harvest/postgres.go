@@ -0,0 +1,139 @@
+package harvest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"idrm/pkg/db"
+)
+
+// postgresInspector 读取 information_schema（表、列）及 pg_catalog（注释、索引）
+type postgresInspector struct {
+	conn *db.Conn
+}
+
+func (p *postgresInspector) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.conn.DB.QueryContext(ctx, p.conn.Dialect().Rebind(query), args...)
+}
+
+func (p *postgresInspector) inspect(ctx context.Context, schemas []string) ([]*Table, error) {
+	in, args := inClause(schemas)
+	set := newTableSet()
+
+	rows, err := p.query(ctx,
+		"SELECT t.table_schema, t.table_name, t.table_type, COALESCE(obj_description(c.oid, 'pg_class'), '') "+
+			"FROM information_schema.tables t "+
+			"JOIN pg_catalog.pg_namespace n ON n.nspname = t.table_schema "+
+			"JOIN pg_catalog.pg_class c ON c.relname = t.table_name AND c.relnamespace = n.oid "+
+			"WHERE t.table_schema IN "+in+" ORDER BY t.table_schema, t.table_name", args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var t Table
+		var tableType string
+		if err := rows.Scan(&t.Schema, &t.Name, &tableType, &t.Comment); err != nil {
+			return err
+		}
+		t.Type = TypeTable
+		if tableType == "VIEW" {
+			t.Type = TypeView
+		}
+		set.add(&t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	rows, err = p.query(ctx,
+		"SELECT c.table_schema, c.table_name, c.column_name, c.ordinal_position, c.data_type, "+
+			"c.character_maximum_length, c.numeric_precision, c.numeric_scale, c.is_nullable, c.column_default, "+
+			"COALESCE(col_description(pc.oid, c.ordinal_position::int), '') "+
+			"FROM information_schema.columns c "+
+			"JOIN pg_catalog.pg_namespace n ON n.nspname = c.table_schema "+
+			"JOIN pg_catalog.pg_class pc ON pc.relname = c.table_name AND pc.relnamespace = n.oid "+
+			"WHERE c.table_schema IN "+in+" ORDER BY c.table_schema, c.table_name, c.ordinal_position", args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var (
+			schema, table, nullable string
+			length, precision, sc   sql.NullInt64
+			def                     sql.NullString
+			c                       Column
+		)
+		if err := rows.Scan(&schema, &table, &c.Name, &c.Position, &c.DataType,
+			&length, &precision, &sc, &nullable, &def, &c.Comment); err != nil {
+			return err
+		}
+		c.Nullable = nullable == "YES"
+		c.Default = def.String
+		c.ColumnType = c.DataType
+		switch {
+		case length.Valid:
+			c.ColumnType = fmt.Sprintf("%s(%d)", c.DataType, length.Int64)
+		case c.DataType == "numeric" && precision.Valid:
+			c.ColumnType = fmt.Sprintf("numeric(%d,%d)", precision.Int64, sc.Int64)
+		}
+		if t := set.get(schema, table); t != nil {
+			t.Columns = append(t.Columns, c)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	rows, err = p.query(ctx,
+		"SELECT n.nspname, t.relname, i.relname, ix.indisunique, ix.indisprimary, a.attname "+
+			"FROM pg_catalog.pg_index ix "+
+			"JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid "+
+			"JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace "+
+			"JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true "+
+			"JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum "+
+			"WHERE n.nspname IN "+in+" ORDER BY n.nspname, t.relname, i.relname, k.ord", args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var (
+			schema, table, name, column string
+			unique, primary             bool
+		)
+		if err := rows.Scan(&schema, &table, &name, &unique, &primary, &column); err != nil {
+			return err
+		}
+		if t := set.get(schema, table); t != nil {
+			t.addIndexColumn(name, column, unique, primary)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// 视图定义（pg_get_viewdef 重建的 SELECT 语句）
+	rows, err = p.query(ctx, "SELECT schemaname, viewname, COALESCE(definition, '') FROM pg_catalog.pg_views WHERE schemaname IN "+in, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		var schema, table, definition string
+		if err := rows.Scan(&schema, &table, &definition); err != nil {
+			return err
+		}
+		if t := set.get(schema, table); t != nil {
+			t.Definition = strings.TrimSpace(definition)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, t := range set.tables {
+		t.markPrimaryKey()
+	}
+	return set.tables, nil
+}
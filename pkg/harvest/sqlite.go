@@ -0,0 +1,147 @@
+package harvest
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"idrm/pkg/db"
+)
+
+// sqliteSchema SQLite 只采集主库
+const sqliteSchema = "main"
+
+// sqliteInspector 读取 sqlite_master 及 PRAGMA（SQLite 不支持注释）
+type sqliteInspector struct {
+	conn *db.Conn
+}
+
+func (s *sqliteInspector) inspect(ctx context.Context, _ []string) ([]*Table, error) {
+	set := newTableSet()
+	rows, err := s.conn.DB.QueryContext(ctx,
+		"SELECT name, type, COALESCE(sql, '') FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	if err := scanRows(rows, func() error {
+		t := Table{Schema: sqliteSchema}
+		var ddl string
+		if err := rows.Scan(&t.Name, &t.Type, &ddl); err != nil {
+			return err
+		}
+		if t.Type == TypeView {
+			t.Definition = viewDefinition(ddl)
+		}
+		set.add(&t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, t := range set.tables {
+		if err := s.columns(ctx, t); err != nil {
+			return nil, err
+		}
+		if err := s.indexes(ctx, t); err != nil {
+			return nil, err
+		}
+	}
+	return set.tables, nil
+}
+
+// sqliteViewDDL CREATE VIEW 语句，AS 之后为视图定义
+var sqliteViewDDL = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?VIEW\s+.*?\s+AS\s+(.*)$`)
+
+// viewDefinition 从 CREATE VIEW 语句中截取 SELECT 部分
+func viewDefinition(ddl string) string {
+	m := sqliteViewDDL.FindStringSubmatch(ddl)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+func (s *sqliteInspector) columns(ctx context.Context, t *Table) error {
+	rows, err := s.conn.DB.QueryContext(ctx, "SELECT cid, name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?)", t.Name)
+	if err != nil {
+		return err
+	}
+	return scanRows(rows, func() error {
+		var (
+			c       Column
+			cid, pk int
+			notNull bool
+			def     sql.NullString
+		)
+		if err := rows.Scan(&cid, &c.Name, &c.ColumnType, &notNull, &def, &pk); err != nil {
+			return err
+		}
+		c.Position = cid + 1
+		c.ColumnType = strings.ToLower(c.ColumnType)
+		c.DataType = c.ColumnType
+		if i := strings.IndexByte(c.DataType, '('); i >= 0 {
+			c.DataType = strings.TrimSpace(c.DataType[:i])
+		}
+		c.Nullable = !notNull && pk == 0
+		c.Default = def.String
+		c.PrimaryKey = pk > 0
+		t.Columns = append(t.Columns, c)
+		return nil
+	})
+}
+
+func (s *sqliteInspector) indexes(ctx context.Context, t *Table) error {
+	type index struct {
+		name            string
+		unique, primary bool
+	}
+	var list []index
+	rows, err := s.conn.DB.QueryContext(ctx, "SELECT name, \"unique\", origin FROM pragma_index_list(?) ORDER BY name", t.Name)
+	if err != nil {
+		return err
+	}
+	if err := scanRows(rows, func() error {
+		var (
+			idx    index
+			origin string
+		)
+		if err := rows.Scan(&idx.name, &idx.unique, &origin); err != nil {
+			return err
+		}
+		idx.primary = origin == "pk"
+		list = append(list, idx)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	hasPrimary := false
+	for _, idx := range list {
+		rows, err := s.conn.DB.QueryContext(ctx, "SELECT name FROM pragma_index_info(?) ORDER BY seqno", idx.name)
+		if err != nil {
+			return err
+		}
+		if err := scanRows(rows, func() error {
+			var column string
+			if err := rows.Scan(&column); err != nil {
+				return err
+			}
+			t.addIndexColumn(idx.name, column, idx.unique, idx.primary)
+			return nil
+		}); err != nil {
+			return err
+		}
+		hasPrimary = hasPrimary || idx.primary
+	}
+
+	// INTEGER PRIMARY KEY 为 rowid 别名，没有对应的索引
+	if !hasPrimary && t.Type == TypeTable {
+		for _, c := range t.Columns {
+			if c.PrimaryKey {
+				t.addIndexColumn("PRIMARY", c.Name, true, true)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/repo"
+)
+
+// maxTextLength message、error 列长度
+const maxTextLength = 500
+
+// History 执行记录存储
+type History struct {
+	repo repo.Repository[Run]
+}
+
+// NewHistory 创建执行记录存储（读写均使用主库）
+func NewHistory(conn *db.Conn) *History {
+	return &History{repo: repo.NewSqlxDialect[Run](conn.SqlConn(), conn.SqlConn(), conn.Dialect())}
+}
+
+// Recent 按时间倒序获取任务最近的执行记录，name 为空时不限任务
+func (h *History) Recent(ctx context.Context, name string, limit int) ([]*Run, error) {
+	var conds []repo.Cond
+	if name != "" {
+		conds = append(conds, repo.Eq("job_name", name))
+	}
+	return h.repo.Find(ctx, repo.Query{
+		Conds:    conds,
+		Orders:   []repo.Order{repo.Desc("id")},
+		Page:     1,
+		PageSize: limit,
+	})
+}
+
+// FindOne 根据ID获取执行记录
+func (h *History) FindOne(ctx context.Context, id int64) (*Run, error) {
+	return h.repo.FindOne(ctx, id)
+}
+
+// Purge 分批删除 before 之前开始的执行记录（每批 batchSize 条，单独提交，避免长时间锁表），返回删除条数
+// 开始时间早于保留期的 running 记录为进程异常退出遗留，一并删除
+func (h *History) Purge(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	var total int64
+	for {
+		runs, err := h.repo.Find(ctx, repo.Query{
+			Conds:    []repo.Cond{repo.Lt("started_at", before)},
+			Page:     1,
+			PageSize: batchSize,
+		})
+		if err != nil || len(runs) == 0 {
+			return total, err
+		}
+
+		ids := make([]int64, len(runs))
+		for i, run := range runs {
+			ids[i] = run.Id
+		}
+		if err := h.repo.BatchDelete(ctx, ids); err != nil {
+			return total, err
+		}
+		total += int64(len(ids))
+		if len(runs) < batchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// start 写入执行中的记录
+func (h *History) start(ctx context.Context, run *Run) error {
+	return h.repo.Insert(ctx, run)
+}
+
+// finish 更新执行结果
+func (h *History) finish(ctx context.Context, run *Run) error {
+	return h.repo.Update(ctx, run)
+}
+
+// truncate 截断到列长度（按字符）
+func truncate(s string) string {
+	if r := []rune(s); len(r) > maxTextLength {
+		return string(r[:maxTextLength])
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/testkit"
+)
+
+func TestHistory_Purge(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	history := NewHistory(conn)
+	now := time.Now()
+	// 5 条过期记录（含异常退出遗留的 running 记录），1 条保留期内的记录
+	for i := 0; i < 5; i++ {
+		run := &Run{JobName: "old", TriggerType: TriggerCron, Status: StatusSuccess, StartedAt: now.AddDate(0, 0, -100+i)}
+		if i == 0 {
+			run.Status = StatusRunning
+		}
+		if err := history.start(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := history.start(ctx, &Run{JobName: "new", TriggerType: TriggerCron, Status: StatusSuccess, StartedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 每批 2 条，共 3 批
+	n, err := history.Purge(ctx, now.AddDate(0, 0, -90), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 {
+		t.Errorf("Purge() = %d, want 5", n)
+	}
+	runs, err := history.Recent(ctx, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].JobName != "new" {
+		t.Errorf("Recent() after Purge = %+v, want only new", runs)
+	}
+}
@@ -0,0 +1,72 @@
+// Package job 定时任务运行时：按 cron 表达式调度已注册的任务，记录每次执行（job_run 表）
+//
+//   - 同一任务同时只执行一次，上次未结束时跳过本次（定时与手动触发共用）
+//   - 配置分布式锁（WithLocker）时：多实例中只有 leader 按 cron 调度，每次执行（含手动触发）持有任务锁，
+//     其他实例正在执行时跳过
+//   - 每次执行有超时（JobItemConfig.Timeout），超时后 context 取消，任务需响应取消
+//   - 每次执行创建 internal span，执行记录包含开始/结束时间、状态、错误及影响行数
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ModelName 执行记录模型名称（对应 DB.*.Models 配置）
+const ModelName = "job_run"
+
+// 触发方式
+const (
+	TriggerCron   = "cron"
+	TriggerManual = "manual"
+)
+
+// 执行状态
+const (
+	StatusRunning = "running"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusTimeout = "timeout"
+)
+
+var (
+	ErrUnknownJob   = errors.New("job: unknown job")
+	ErrJobRunning   = errors.New("job: job is already running")
+	ErrJobLocked    = errors.New("job: job is running on another instance")
+	ErrDuplicateJob = errors.New("job: job already registered")
+	ErrStopped      = errors.New("job: scheduler stopped")
+)
+
+// Result 任务执行结果
+type Result struct {
+	Rows    int64  // 影响行数
+	Message string // 结果说明（如各表清理数量），写入执行记录
+}
+
+// Func 任务函数，ctx 在超时或调度器停止时取消
+type Func func(ctx context.Context) (Result, error)
+
+// Run 执行记录
+type Run struct {
+	Id           int64     `db:"id" json:"id" gorm:"column:id;primaryKey;index:idx_job_run_name,priority:2"`
+	JobName      string    `db:"job_name" json:"job_name" gorm:"column:job_name;type:varchar(100);not null;index:idx_job_run_name,priority:1"`
+	TriggerType  string    `db:"trigger_type" json:"trigger_type" gorm:"column:trigger_type;type:varchar(20);not null"`
+	Status       string    `db:"status" json:"status" gorm:"column:status;type:varchar(20);not null"`
+	Host         string    `db:"host" json:"host" gorm:"column:host;type:varchar(100)"`
+	RowsAffected int64     `db:"rows_affected" json:"rows_affected" gorm:"column:rows_affected"`
+	Message      string    `db:"message" json:"message" gorm:"column:message;type:varchar(500)"`
+	Error        string    `db:"error" json:"error" gorm:"column:error;type:varchar(500)"`
+	StartedAt    time.Time `db:"started_at" json:"started_at" gorm:"column:started_at;index:idx_job_run_started_at"`
+	FinishedAt   time.Time `db:"finished_at" json:"finished_at" gorm:"column:finished_at"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Run) TableName() string {
+	return "job_run"
+}
+
+// Duration 执行耗时（执行中时为 0）
+func (r *Run) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
@@ -0,0 +1,28 @@
+package job
+
+import "github.com/zeromicro/go-zero/core/metric"
+
+// 跳过执行（只计入指标，不写执行记录）
+const (
+	statusSkipped = "skipped" // 本实例上次执行未结束
+	statusLocked  = "locked"  // 其他实例正在执行
+)
+
+var (
+	metricRuns = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: "idrm",
+		Subsystem: "job",
+		Name:      "runs_total",
+		Help:      "job runs by job and status",
+		Labels:    []string{"job", "status"},
+	})
+
+	metricDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: "idrm",
+		Subsystem: "job",
+		Name:      "run_duration_ms",
+		Help:      "job run duration in milliseconds",
+		Labels:    []string{"job"},
+		Buckets:   []float64{100, 1000, 10000, 60000, 300000, 1800000, 3600000},
+	})
+)
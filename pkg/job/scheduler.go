@@ -0,0 +1,436 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"idrm/pkg/config"
+	"idrm/pkg/lock"
+	"idrm/pkg/telemetry/trace"
+
+	"github.com/robfig/cron/v3"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/sysx"
+)
+
+// finishTimeout 写入执行结果的超时时间（任务 context 可能已超时或取消）
+const finishTimeout = 10 * time.Second
+
+// 分布式锁名称
+const (
+	leaderLockKey = "job:leader"
+	jobLockPrefix = "job:run:"
+)
+
+// Info 任务信息
+type Info struct {
+	Name    string    `json:"name"`
+	Cron    string    `json:"cron"`
+	Enabled bool      `json:"enabled"`
+	Timeout int       `json:"timeout"` // 秒，0 表示不限
+	Running bool      `json:"running"`
+	NextRun time.Time `json:"next_run,omitempty"` // 未启用时为零值
+}
+
+// entry 已注册的任务
+type entry struct {
+	name     string
+	conf     config.JobItemConfig
+	fn       Func
+	schedule cron.Schedule // 未启用时为 nil
+	running  atomic.Bool
+}
+
+// Scheduler 定时任务调度器，实现 go-zero service.Service
+type Scheduler struct {
+	cron    *cron.Cron
+	history *History
+	host    string
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+	stopped bool // 受 mu 保护，停止后不再开始新的执行
+
+	// 分布式锁，nil 表示单实例：始终为 leader，不获取任务锁
+	locker    lock.Locker
+	lockTTL   time.Duration
+	leader    atomic.Bool
+	electOnce sync.Once
+	electDone chan struct{} // 选举结束（已释放 leader 锁）
+
+	ctx      context.Context // Stop 时取消：执行中的任务收到取消
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup // 执行中的任务（含手动触发）
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Option 调度器选项
+type Option func(s *Scheduler)
+
+// WithLocker 多实例部署时使用分布式锁：leader 选举及任务互斥，ttl 为租约时长
+func WithLocker(locker lock.Locker, ttl time.Duration) Option {
+	return func(s *Scheduler) {
+		s.locker = locker
+		s.lockTTL = ttl
+	}
+}
+
+// NewScheduler 创建调度器，history 为 nil 时不记录执行记录
+func NewScheduler(history *History, opts ...Option) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		cron:      cron.New(),
+		history:   history,
+		host:      sysx.Hostname(),
+		entries:   make(map[string]*entry),
+		electDone: make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.locker == nil {
+		s.leader.Store(true)
+	}
+	return s
+}
+
+// Register 注册任务：Enabled 时按 Cron 定时执行，未启用的任务仍可手动触发
+func (s *Scheduler) Register(name string, c config.JobItemConfig, fn Func) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, name)
+	}
+	e := &entry{name: name, conf: c, fn: fn}
+	if c.Enabled {
+		schedule, err := cron.ParseStandard(c.Cron)
+		if err != nil {
+			return fmt.Errorf("job %s: invalid cron %q: %w", name, c.Cron, err)
+		}
+		e.schedule = schedule
+		s.cron.Schedule(schedule, cron.FuncJob(func() {
+			// 多实例时只有 leader 调度
+			if !s.IsLeader() {
+				return
+			}
+			if _, err := s.run(s.ctx, e, TriggerCron); err != nil &&
+				!errors.Is(err, ErrJobRunning) && !errors.Is(err, ErrJobLocked) {
+				logx.Errorf("任务 %s 执行失败: %v", name, err)
+			}
+		}))
+	}
+	s.entries[name] = e
+	return nil
+}
+
+// Start 启动调度，阻塞直到 Stop
+func (s *Scheduler) Start() {
+	s.electOnce.Do(func() {
+		if s.locker == nil {
+			close(s.electDone)
+			return
+		}
+		go s.elect()
+	})
+	s.cron.Start()
+	for _, info := range s.Jobs() {
+		if info.Enabled {
+			logx.Infof("任务 %s 已调度: cron=%q, next=%s", info.Name, info.Cron, info.NextRun.Format(time.DateTime))
+		} else {
+			logx.Infof("任务 %s 未启用定时执行（可手动触发）", info.Name)
+		}
+	}
+	<-s.done
+}
+
+// Stop 停止调度，取消执行中的任务并等待其结束
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		s.mu.Lock()
+		s.stopped = true
+		s.mu.Unlock()
+
+		s.cancel()
+		<-s.cron.Stop().Done()
+		s.wg.Wait()
+		// 未调用 Start 时选举未启动
+		s.electOnce.Do(func() { close(s.electDone) })
+		<-s.electDone
+		close(s.done)
+		logx.Info("定时任务调度器已停止")
+	})
+}
+
+// IsLeader 是否为 leader（未配置分布式锁时始终为 true）
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// Jobs 已注册的任务，按名称排序
+func (s *Scheduler) Jobs() []Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]Info, 0, len(s.entries))
+	for _, e := range s.entries {
+		info := Info{
+			Name:    e.name,
+			Cron:    e.conf.Cron,
+			Enabled: e.conf.Enabled,
+			Timeout: e.conf.Timeout,
+			Running: e.running.Load(),
+		}
+		if e.schedule != nil {
+			info.NextRun = e.schedule.Next(time.Now())
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Run 立即执行任务并等待结束（手动触发），返回执行记录
+// 任务执行失败时同时返回执行记录及错误
+func (s *Scheduler) Run(ctx context.Context, name string) (*Run, error) {
+	e, err := s.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	// 调用方取消或调度器停止时均取消任务
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(s.ctx, cancel)
+	defer stop()
+
+	return s.run(ctx, e, TriggerManual)
+}
+
+// Trigger 在后台执行任务（手动触发），不等待结束；任务执行中时返回 ErrJobRunning
+func (s *Scheduler) Trigger(name string) error {
+	e, err := s.entry(name)
+	if err != nil {
+		return err
+	}
+	lease, err := s.begin(e, TriggerManual)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer s.wg.Done()
+		if _, err := s.execute(s.ctx, e, TriggerManual, lease); err != nil {
+			logx.Errorf("任务 %s 执行失败: %v", name, err)
+		}
+	}()
+	return nil
+}
+
+// entry 获取已注册的任务
+func (s *Scheduler) entry(name string) (*entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	return e, nil
+}
+
+// run 任务未在执行时执行并等待结束，否则跳过
+func (s *Scheduler) run(ctx context.Context, e *entry, trigger string) (*Run, error) {
+	lease, err := s.begin(e, trigger)
+	if err != nil {
+		return nil, err
+	}
+	defer s.wg.Done()
+	return s.execute(ctx, e, trigger, lease)
+}
+
+// begin 标记任务开始执行并获取任务锁（配置分布式锁时）：
+// 本实例执行中时返回 ErrJobRunning，其他实例执行中时返回 ErrJobLocked，调度器已停止时返回 ErrStopped。
+// 成功时调用方须在执行结束后调用 s.wg.Done
+func (s *Scheduler) begin(e *entry, trigger string) (lock.Lease, error) {
+	if !e.running.CompareAndSwap(false, true) {
+		metricRuns.Inc(e.name, statusSkipped)
+		logx.Infof("任务 %s 上次执行未结束，跳过本次执行（%s）", e.name, trigger)
+		return nil, fmt.Errorf("%w: %s", ErrJobRunning, e.name)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		e.running.Store(false)
+		return nil, ErrStopped
+	}
+
+	var lease lock.Lease
+	if s.locker != nil {
+		var err error
+		lease, err = s.locker.Acquire(s.ctx, jobLockPrefix+e.name, s.lockTTL)
+		if errors.Is(err, lock.ErrNotAcquired) {
+			e.running.Store(false)
+			metricRuns.Inc(e.name, statusLocked)
+			logx.Infof("任务 %s 正在其他实例执行，跳过本次执行（%s）", e.name, trigger)
+			return nil, fmt.Errorf("%w: %s", ErrJobLocked, e.name)
+		}
+		if err != nil {
+			e.running.Store(false)
+			return nil, err
+		}
+	}
+	s.wg.Add(1)
+	return lease, nil
+}
+
+// execute 执行任务并写入执行记录，调用前须已调用 begin；lease 非 nil 时执行期间续期，结束后释放
+func (s *Scheduler) execute(ctx context.Context, e *entry, trigger string, lease lock.Lease) (run *Run, err error) {
+	defer e.running.Store(false)
+
+	ctx, span := trace.StartInternal(ctx)
+	span.SetAttributes(trace.WithAttributes("job.name", e.name, "job.trigger", trigger, "job.host", s.host)...)
+	defer func() { trace.End(span, err) }()
+
+	if lease != nil {
+		// 任务锁失效（续期失败）时取消任务，避免与其他实例同时执行
+		var release func() error
+		ctx, release = lock.Hold(ctx, lease, s.lockTTL)
+		defer release()
+		span.SetAttributes(trace.WithAttributes("job.lock_token", lease.Token())...)
+	}
+
+	if e.conf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(e.conf.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	run = &Run{
+		JobName:     e.name,
+		TriggerType: trigger,
+		Status:      StatusRunning,
+		Host:        s.host,
+		StartedAt:   time.Now(),
+	}
+	run.FinishedAt = run.StartedAt
+	if s.history != nil {
+		// 记录失败不影响执行，只是缺少该次记录
+		if err := s.history.start(ctx, run); err != nil {
+			logx.WithContext(ctx).Errorf("任务 %s 写入执行记录失败: %v", e.name, err)
+		}
+	}
+	logx.WithContext(ctx).Infof("任务 %s 开始执行（%s）", e.name, trigger)
+
+	result, err := s.call(ctx, e)
+	if err != nil && errors.Is(context.Cause(ctx), lock.ErrLost) {
+		err = fmt.Errorf("%w: %v", lock.ErrLost, err)
+	}
+	run.FinishedAt = time.Now()
+	run.RowsAffected = result.Rows
+	run.Message = truncate(result.Message)
+	switch {
+	case err == nil:
+		run.Status = StatusSuccess
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		run.Status = StatusTimeout
+	default:
+		run.Status = StatusFailed
+	}
+	if err != nil {
+		run.Error = truncate(err.Error())
+	}
+
+	if s.history != nil && run.Id > 0 {
+		finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finishTimeout)
+		if err := s.history.finish(finishCtx, run); err != nil {
+			logx.WithContext(ctx).Errorf("任务 %s 更新执行记录失败: %v", e.name, err)
+		}
+		cancel()
+	}
+
+	metricRuns.Inc(e.name, run.Status)
+	metricDuration.Observe(run.Duration().Milliseconds(), e.name)
+	span.SetAttributes(trace.WithAttributes("job.status", run.Status, "job.rows_affected", run.RowsAffected)...)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("任务 %s 执行失败(%s): 耗时=%s, err=%v", e.name, run.Status, run.Duration(), err)
+		return run, err
+	}
+	logx.WithContext(ctx).Infof("任务 %s 执行完成: 耗时=%s, rows=%d %s", e.name, run.Duration(), run.RowsAffected, run.Message)
+	return run, nil
+}
+
+// call 调用任务函数，panic 转为错误
+func (s *Scheduler) call(ctx context.Context, e *entry) (result Result, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job %s panic: %v", e.name, p)
+			logx.WithContext(ctx).Errorf("任务 %s panic: %v\n%s", e.name, p, debug.Stack())
+		}
+	}()
+	return e.fn(ctx)
+}
+
+// elect leader 选举：未持有时每 TTL/3 尝试获取，持有时续期；超过 TTL 未续期成功视为失去 leader
+func (s *Scheduler) elect() {
+	defer close(s.electDone)
+
+	var (
+		lease     lock.Lease
+		renewedAt time.Time
+	)
+	ticker := time.NewTicker(s.lockTTL / 3)
+	defer ticker.Stop()
+	for {
+		switch {
+		case lease == nil:
+			l, err := s.locker.Acquire(s.ctx, leaderLockKey, s.lockTTL)
+			if err == nil {
+				lease, renewedAt = l, time.Now()
+				s.leader.Store(true)
+				logx.Infof("本实例 %s 成为定时任务 leader (token=%d)", s.host, l.Token())
+			} else if !errors.Is(err, lock.ErrNotAcquired) && s.ctx.Err() == nil {
+				logx.Errorf("获取定时任务 leader 锁失败: %v", err)
+			}
+		default:
+			err := lease.Renew(s.ctx, s.lockTTL)
+			if err == nil {
+				renewedAt = time.Now()
+				break
+			}
+			if s.ctx.Err() != nil {
+				break
+			}
+			logx.Errorf("定时任务 leader 锁续期失败: %v", err)
+			if errors.Is(err, lock.ErrLost) || time.Since(renewedAt) >= s.lockTTL {
+				lease = nil
+				s.leader.Store(false)
+				logx.Errorf("本实例 %s 不再是定时任务 leader", s.host)
+			}
+		}
+
+		select {
+		case <-s.ctx.Done():
+			s.leader.Store(false)
+			if lease != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), finishTimeout)
+				if err := lease.Release(ctx); err != nil {
+					logx.Errorf("释放定时任务 leader 锁失败: %v", err)
+				}
+				cancel()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
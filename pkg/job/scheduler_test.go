@@ -0,0 +1,263 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"idrm/migrations"
+	"idrm/pkg/config"
+	"idrm/pkg/db"
+	"idrm/pkg/lock"
+	"idrm/pkg/testkit"
+)
+
+func newScheduler(t *testing.T) (*Scheduler, *History) {
+	t.Helper()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	history := NewHistory(conn)
+	s := NewScheduler(history)
+	t.Cleanup(s.Stop)
+	return s, history
+}
+
+func TestScheduler_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeout    int
+		fn         Func
+		wantStatus string
+		wantRows   int64
+		wantError  string
+	}{
+		{
+			name:       "执行成功",
+			fn:         func(ctx context.Context) (Result, error) { return Result{Rows: 3, Message: "category=3"}, nil },
+			wantStatus: StatusSuccess,
+			wantRows:   3,
+		},
+		{
+			name:       "执行失败",
+			fn:         func(ctx context.Context) (Result, error) { return Result{Rows: 1}, errors.New("boom") },
+			wantStatus: StatusFailed,
+			wantRows:   1,
+			wantError:  "boom",
+		},
+		{
+			name: "执行超时",
+			// 超时只能以秒配置，等待 1 秒
+			timeout: 1,
+			fn: func(ctx context.Context) (Result, error) {
+				<-ctx.Done()
+				return Result{}, ctx.Err()
+			},
+			wantStatus: StatusTimeout,
+			wantError:  context.DeadlineExceeded.Error(),
+		},
+		{
+			name:       "panic",
+			fn:         func(ctx context.Context) (Result, error) { panic("bad job") },
+			wantStatus: StatusFailed,
+			wantError:  "job test panic: bad job",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, history := newScheduler(t)
+			if err := s.Register("test", config.JobItemConfig{Timeout: tt.timeout}, tt.fn); err != nil {
+				t.Fatal(err)
+			}
+
+			run, err := s.Run(ctx, "test")
+			if (err != nil) != (tt.wantError != "") {
+				t.Fatalf("Run() error = %v, want %q", err, tt.wantError)
+			}
+			if run == nil || run.Id == 0 {
+				t.Fatalf("Run() = %+v, want persisted run", run)
+			}
+
+			got, err := history.FindOne(ctx, run.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus || got.RowsAffected != tt.wantRows || got.Error != tt.wantError ||
+				got.TriggerType != TriggerManual || got.FinishedAt.Before(got.StartedAt) {
+				t.Errorf("history = %+v, want status %s rows %d error %q", got, tt.wantStatus, tt.wantRows, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestScheduler_Overlap(t *testing.T) {
+	ctx := context.Background()
+	s, history := newScheduler(t)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	if err := s.Register("slow", config.JobItemConfig{}, func(ctx context.Context) (Result, error) {
+		close(started)
+		<-release
+		return Result{}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Trigger("slow"); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := s.Trigger("slow"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("Trigger() while running error = %v, want ErrJobRunning", err)
+	}
+	if _, err := s.Run(ctx, "slow"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("Run() while running error = %v, want ErrJobRunning", err)
+	}
+	if jobs := s.Jobs(); len(jobs) != 1 || !jobs[0].Running {
+		t.Errorf("Jobs() = %+v, want slow running", jobs)
+	}
+
+	close(release)
+	s.Stop()
+	runs, err := history.Recent(ctx, "slow", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != StatusSuccess {
+		t.Errorf("Recent() = %+v, want one successful run", runs)
+	}
+}
+
+func TestScheduler_Locker(t *testing.T) {
+	ctx := context.Background()
+	locker := lock.NewLocal()
+	a := NewScheduler(nil, WithLocker(locker, time.Second))
+	b := NewScheduler(nil, WithLocker(locker, time.Second))
+	t.Cleanup(a.Stop)
+	t.Cleanup(b.Stop)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := func(ctx context.Context) (Result, error) {
+		close(started)
+		<-release
+		return Result{}, nil
+	}
+	noop := func(ctx context.Context) (Result, error) { return Result{}, nil }
+	if err := a.Register("sync", config.JobItemConfig{}, slow); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Register("sync", config.JobItemConfig{}, noop); err != nil {
+		t.Fatal(err)
+	}
+
+	// 任务锁：其他实例执行中时跳过
+	if err := a.Trigger("sync"); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if _, err := b.Run(ctx, "sync"); !errors.Is(err, ErrJobLocked) {
+		t.Errorf("Run() on other instance error = %v, want ErrJobLocked", err)
+	}
+	close(release)
+	a.Stop()
+	if _, err := b.Run(ctx, "sync"); err != nil {
+		t.Errorf("Run() after release error = %v", err)
+	}
+
+	// leader 选举：只有一个实例为 leader，leader 停止后由另一个实例接管
+	c := NewScheduler(nil, WithLocker(locker, 300*time.Millisecond))
+	d := NewScheduler(nil, WithLocker(locker, 300*time.Millisecond))
+	t.Cleanup(d.Stop)
+	go c.Start()
+	waitFor(t, c.IsLeader)
+	go d.Start()
+	time.Sleep(200 * time.Millisecond)
+	if d.IsLeader() {
+		t.Fatal("IsLeader() = true on both instances")
+	}
+	c.Stop()
+	if c.IsLeader() {
+		t.Error("IsLeader() = true after Stop")
+	}
+	waitFor(t, d.IsLeader)
+}
+
+// waitFor 等待 cond 成立（最多 2 秒）
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 2s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	ctx := context.Background()
+	s, history := newScheduler(t)
+	started := make(chan struct{})
+	if err := s.Register("long", config.JobItemConfig{}, func(ctx context.Context) (Result, error) {
+		close(started)
+		<-ctx.Done()
+		return Result{}, ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Trigger("long"); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	// Stop 取消执行中的任务并等待其写入执行记录
+	s.Stop()
+
+	runs, err := history.Recent(ctx, "long", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Status != StatusFailed || runs[0].Error != context.Canceled.Error() {
+		t.Errorf("Recent() = %+v, want one canceled run", runs)
+	}
+	if err := s.Trigger("long"); !errors.Is(err, ErrStopped) {
+		t.Errorf("Trigger() after Stop error = %v, want ErrStopped", err)
+	}
+}
+
+func TestScheduler_Register(t *testing.T) {
+	noop := func(ctx context.Context) (Result, error) { return Result{}, nil }
+	s := NewScheduler(nil)
+	t.Cleanup(s.Stop)
+	if err := s.Register("a", config.JobItemConfig{Cron: "0 2 * * *", Enabled: true}, noop); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		job     string
+		conf    config.JobItemConfig
+		wantErr error
+	}{
+		{name: "未启用时不校验cron", job: "b", conf: config.JobItemConfig{Cron: "bad"}},
+		{name: "重复注册", job: "a", wantErr: ErrDuplicateJob},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Register(tt.job, tt.conf, noop); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := s.Register("c", config.JobItemConfig{Cron: "bad", Enabled: true}, noop); err == nil {
+		t.Error("Register() with invalid cron error = nil")
+	}
+	if err := s.Trigger("missing"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("Trigger() unknown job error = %v, want ErrUnknownJob", err)
+	}
+	if jobs := s.Jobs(); len(jobs) != 2 || jobs[0].NextRun.IsZero() || !jobs[1].NextRun.IsZero() {
+		t.Errorf("Jobs() = %+v, want a scheduled and b manual-only", jobs)
+	}
+}
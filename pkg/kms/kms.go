@@ -0,0 +1,58 @@
+// Package kms 密钥管理：加密存储敏感配置（如数据源密码）
+//
+// KeyManager 抽象密钥来源，目前提供本地密钥文件实现（AES-256-GCM）。
+// 密文以密钥ID为前缀（<id>:<base64>），轮换密钥后使用旧密钥加密的数据仍可解密。
+package kms
+
+import (
+	"context"
+	"errors"
+)
+
+// 密钥管理类型
+const (
+	TypeLocal = "local" // 本地密钥文件
+)
+
+var (
+	ErrNotConfigured = errors.New("kms: key file not configured")
+	ErrKeyNotFound   = errors.New("kms: key not found")
+	ErrCiphertext    = errors.New("kms: malformed ciphertext")
+	ErrUnknownType   = errors.New("kms: unknown type")
+)
+
+// KeyManager 加解密敏感数据，实现须并发安全
+type KeyManager interface {
+	// Encrypt 使用当前密钥加密，返回可直接存储的文本
+	Encrypt(ctx context.Context, plaintext []byte) (string, error)
+	// Decrypt 按密文中的密钥ID解密，密钥不存在时返回 ErrKeyNotFound
+	Decrypt(ctx context.Context, ciphertext string) ([]byte, error)
+}
+
+// Config 密钥管理配置
+type Config struct {
+	Type       string `json:",default=local,options=local"`
+	KeyFile    string `json:",optional"`      // 密钥文件路径，为空时加解密返回 ErrNotConfigured
+	AutoCreate bool   `json:",default=false"` // 密钥文件不存在时生成（仅开发环境使用，生产环境应妥善备份密钥文件）
+}
+
+// New 按配置创建密钥管理
+func New(c Config) (KeyManager, error) {
+	switch c.Type {
+	case TypeLocal, "":
+		if c.KeyFile == "" {
+			return Disabled, nil
+		}
+		return NewLocal(c.KeyFile, c.AutoCreate)
+	default:
+		return nil, ErrUnknownType
+	}
+}
+
+// Disabled 未配置密钥，加解密均返回 ErrNotConfigured
+var Disabled KeyManager = disabled{}
+
+type disabled struct{}
+
+func (disabled) Encrypt(context.Context, []byte) (string, error) { return "", ErrNotConfigured }
+func (disabled) Decrypt(context.Context, string) ([]byte, error) { return nil, ErrNotConfigured }
@@ -0,0 +1,83 @@
+package kms
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "secret.key")
+	if _, err := NewLocal(path, false); err == nil {
+		t.Fatal("NewLocal() without key file should fail")
+	}
+	old, err := NewLocal(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("key file mode = %v, err = %v, want 0600", info.Mode().Perm(), err)
+	}
+	oldCiphertext, err := old.Encrypt(ctx, []byte("idrm@2024"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 轮换：新密钥插入到文件开头
+	line, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, []byte(line+"\n"+string(data)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := NewLocal(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCiphertext, err := rotated.Encrypt(ctx, []byte("idrm@2024"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(newCiphertext, strings.Fields(line)[0]+":") {
+		t.Errorf("Encrypt() = %s, want prefix of new key id", newCiphertext)
+	}
+
+	tampered := []byte(oldCiphertext)
+	tampered[len(tampered)-2] ^= 1
+	tests := []struct {
+		name       string
+		ciphertext string
+		want       string
+		wantErr    error
+	}{
+		{"新密钥加密", newCiphertext, "idrm@2024", nil},
+		{"旧密钥加密", oldCiphertext, "idrm@2024", nil},
+		{"未知密钥", "deadbeef:" + strings.SplitN(oldCiphertext, ":", 2)[1], "", ErrKeyNotFound},
+		{"缺少密钥ID", "abc", "", ErrCiphertext},
+		{"密文被篡改", string(tampered), "", ErrCiphertext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rotated.Decrypt(ctx, tt.ciphertext)
+			if !errors.Is(err, tt.wantErr) || string(got) != tt.want {
+				t.Errorf("Decrypt() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_NotConfigured(t *testing.T) {
+	km, err := New(Config{Type: TypeLocal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := km.Encrypt(context.Background(), []byte("x")); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Encrypt() error = %v, want ErrNotConfigured", err)
+	}
+}
@@ -0,0 +1,151 @@
+package kms
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// keySize AES-256
+const keySize = 32
+
+// Local 本地密钥文件（AES-256-GCM）
+//
+// 文件每行一个密钥：<id> <base64 编码的 32 字节密钥>，# 开头的行为注释。
+// 第一个密钥用于加密，其余密钥只用于解密；轮换时在文件开头插入新密钥，
+// 旧密文在下次保存时使用新密钥重新加密。
+type Local struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+// NewLocal 读取密钥文件，autoCreate 时文件不存在则生成（权限 0600）
+func NewLocal(path string, autoCreate bool) (*Local, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && autoCreate {
+		if data, err = createKeyFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kms: read key file: %w", err)
+	}
+	return parseKeys(data)
+}
+
+// GenerateKey 生成一行密钥（写入密钥文件开头即为轮换）
+func GenerateKey() (string, error) {
+	id := make([]byte, 4)
+	key := make([]byte, keySize)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id) + " " + base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (l *Local) Encrypt(_ context.Context, plaintext []byte) (string, error) {
+	aead := l.aeads[l.active]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	// 密钥ID作为附加数据，防止密文被替换到其他密钥ID下
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(l.active))
+	return l.active + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (l *Local) Decrypt(_ context.Context, ciphertext string) ([]byte, error) {
+	id, encoded, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return nil, ErrCiphertext
+	}
+	aead, ok := l.aeads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrCiphertext
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCiphertext, err)
+	}
+	return plaintext, nil
+}
+
+// parseKeys 解析密钥文件内容
+func parseKeys(data []byte) (*Local, error) {
+	l := &Local{aeads: make(map[string]cipher.AEAD)}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.Contains(fields[0], ":") {
+			return nil, fmt.Errorf("kms: key file line %d: expected \"<id> <base64 key>\"", n)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("kms: key file line %d: key must be %d bytes base64", n, keySize)
+		}
+		if _, ok := l.aeads[fields[0]]; ok {
+			return nil, fmt.Errorf("kms: key file line %d: duplicate key id %s", n, fields[0])
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if l.aeads[fields[0]], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+		if l.active == "" {
+			l.active = fields[0]
+		}
+	}
+	if l.active == "" {
+		return nil, errors.New("kms: key file contains no key")
+	}
+	return l, nil
+}
+
+// createKeyFile 生成只含一个密钥的密钥文件
+func createKeyFile(path string) ([]byte, error) {
+	line, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	data := []byte("# idrm 密钥文件：第一个密钥用于加密，请妥善备份，丢失后已加密的数据无法解密\n" + line + "\n")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("kms: create key file: %w", err)
+	}
+	// O_EXCL：多个实例同时启动时只有一个生成密钥，其余读取
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kms: create key file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("kms: create key file: %w", err)
+	}
+	return data, f.Close()
+}
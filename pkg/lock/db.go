@@ -0,0 +1,125 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ Locker = (*DB)(nil)
+
+// DB 数据库租约锁：每个锁一行，过期时间为 Unix 毫秒（应用时钟，各实例需时间同步）
+// 获取：条件更新已过期的行（token+1），行不存在时插入（唯一键冲突即已被持有）；
+// 释放只清空持有者，不删除行，保证 token 单调递增
+type DB struct {
+	conn    sqlx.SqlConn // 主库
+	dialect dialect.Dialect
+}
+
+// NewDB 创建数据库租约锁（读写均使用主库）
+func NewDB(conn *db.Conn) *DB {
+	return &DB{conn: conn.SqlConn(), dialect: conn.Dialect()}
+}
+
+// Acquire 获取锁
+func (l *DB) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	owner := newOwner()
+	now := time.Now()
+	expires := now.Add(ttl).UnixMilli()
+
+	res, err := l.conn.ExecCtx(ctx, l.dialect.Rebind(
+		"UPDATE distributed_lock SET owner = ?, token = token + 1, expires_at = ? WHERE name = ? AND (owner = '' OR expires_at <= ?)"),
+		owner, expires, key, now.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("lock: acquire %s: %w", key, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, fmt.Errorf("lock: acquire %s: %w", key, err)
+	} else if n == 0 {
+		// 行不存在（首次获取）或仍被持有
+		_, err := l.conn.ExecCtx(ctx, l.dialect.Rebind(
+			"INSERT INTO distributed_lock (name, owner, token, expires_at) VALUES (?, ?, 1, ?)"),
+			key, owner, expires)
+		if db.IsDuplicateKey(err) {
+			return nil, ErrNotAcquired
+		}
+		if err != nil {
+			return nil, fmt.Errorf("lock: acquire %s: %w", key, err)
+		}
+		return &dbLease{locker: l, key: key, owner: owner, token: 1}, nil
+	}
+
+	var token int64
+	if err := l.conn.QueryRowCtx(ctx, &token, l.dialect.Rebind(
+		"SELECT token FROM distributed_lock WHERE name = ? AND owner = ?"), key, owner); err != nil {
+		return nil, fmt.Errorf("lock: read token %s: %w", key, err)
+	}
+	return &dbLease{locker: l, key: key, owner: owner, token: token}, nil
+}
+
+type dbLease struct {
+	locker *DB
+	key    string
+	owner  string
+	token  int64
+}
+
+func (l *dbLease) Key() string  { return l.key }
+func (l *dbLease) Token() int64 { return l.token }
+
+// Renew 续期
+func (l *dbLease) Renew(ctx context.Context, ttl time.Duration) error {
+	return l.exec(ctx, "UPDATE distributed_lock SET expires_at = ? WHERE name = ? AND owner = ? AND token = ?",
+		time.Now().Add(ttl).UnixMilli(), l.key, l.owner, l.token)
+}
+
+// Release 释放
+func (l *dbLease) Release(ctx context.Context) error {
+	return l.exec(ctx, "UPDATE distributed_lock SET owner = '', expires_at = 0 WHERE name = ? AND owner = ? AND token = ?",
+		l.key, l.owner, l.token)
+}
+
+// exec 执行条件更新，不再持有时返回 ErrLost
+func (l *dbLease) exec(ctx context.Context, query string, args ...interface{}) error {
+	res, err := l.locker.conn.ExecCtx(ctx, l.locker.dialect.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("lock: %s: %w", l.key, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("lock: %s: %w", l.key, err)
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// MySQL 默认返回实际修改的行数，值未变化（同一毫秒内续期）时为 0，需再确认是否仍持有
+	var held int64
+	if err := l.locker.conn.QueryRowCtx(ctx, &held, l.locker.dialect.Rebind(
+		"SELECT COUNT(*) FROM distributed_lock WHERE name = ? AND owner = ? AND token = ?"),
+		l.key, l.owner, l.token); err != nil {
+		return fmt.Errorf("lock: %s: %w", l.key, err)
+	}
+	if held == 0 {
+		return ErrLost
+	}
+	return nil
+}
+
+// lockRow 租约表结构（仅用于表结构一致性检查）
+type lockRow struct {
+	Name      string `gorm:"column:name;type:varchar(191);primaryKey"`
+	Owner     string `gorm:"column:owner;type:varchar(100);not null"`
+	Token     int64  `gorm:"column:token;not null"`
+	ExpiresAt int64  `gorm:"column:expires_at;not null"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (lockRow) TableName() string {
+	return "distributed_lock"
+}
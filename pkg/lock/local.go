@@ -0,0 +1,83 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Locker = (*Local)(nil)
+
+// Local 进程内锁（单实例部署及测试），语义与分布式实现一致
+type Local struct {
+	mu    sync.Mutex
+	locks map[string]*localState
+}
+
+type localState struct {
+	owner   string // 空表示已释放
+	token   int64
+	expires time.Time
+}
+
+// NewLocal 创建进程内锁
+func NewLocal() *Local {
+	return &Local{locks: make(map[string]*localState)}
+}
+
+// Acquire 获取锁
+func (l *Local) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	st, ok := l.locks[key]
+	if !ok {
+		st = &localState{}
+		l.locks[key] = st
+	}
+	if st.owner != "" && now.Before(st.expires) {
+		return nil, ErrNotAcquired
+	}
+
+	st.owner = newOwner()
+	st.token++
+	st.expires = now.Add(ttl)
+	return &localLease{locker: l, key: key, owner: st.owner, token: st.token}, nil
+}
+
+type localLease struct {
+	locker *Local
+	key    string
+	owner  string
+	token  int64
+}
+
+func (l *localLease) Key() string  { return l.key }
+func (l *localLease) Token() int64 { return l.token }
+
+// Renew 续期
+func (l *localLease) Renew(ctx context.Context, ttl time.Duration) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	st := l.locker.locks[l.key]
+	if st.owner != l.owner || st.token != l.token {
+		return ErrLost
+	}
+	st.expires = time.Now().Add(ttl)
+	return nil
+}
+
+// Release 释放
+func (l *localLease) Release(ctx context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	st := l.locker.locks[l.key]
+	if st.owner != l.owner || st.token != l.token {
+		return ErrLost
+	}
+	st.owner = ""
+	return nil
+}
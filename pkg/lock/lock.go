@@ -0,0 +1,195 @@
+// Package lock 分布式锁（租约）：获取时指定 TTL，持有期间需续期，超时未续期自动失效
+//
+// 每次获取返回单调递增的 fencing token：锁因进程暂停、网络分区等原因失效后，
+// 旧持有者可能仍在写入，下游可按 token 拒绝来自旧持有者的写入。
+//
+// 实现：
+//   - Local 进程内（单实例部署及测试）
+//   - DB 数据库租约表 distributed_lock（MySQL/PostgreSQL/SQLite，需执行 000004 迁移）
+//   - Redis SET NX PX，token 由独立计数器生成
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"idrm/pkg/db"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/sysx"
+)
+
+// 锁类型
+const (
+	TypeLocal = "local"
+	TypeDB    = "db"
+	TypeRedis = "redis"
+)
+
+// retryInterval Do 等待锁时的重试间隔
+const retryInterval = 100 * time.Millisecond
+
+var (
+	ErrNotAcquired = errors.New("lock: already held by another owner")
+	ErrLost        = errors.New("lock: lease lost")
+	ErrUnknownType = errors.New("lock: unknown type")
+)
+
+// Config 分布式锁配置
+type Config struct {
+	Type      string          `json:",default=local,options=local|db|redis"` // 多实例部署时使用 db 或 redis
+	TTL       int             `json:",default=30"`                           // 租约时长(秒)，持有期间每 TTL/3 续期
+	KeyPrefix string          `json:",default=idrm:lock:"`                   // Redis key 前缀
+	Redis     redis.RedisConf `json:",optional"`                             // Type 为 redis 时使用
+}
+
+// Locker 获取锁
+type Locker interface {
+	// Acquire 立即尝试获取锁，已被其他持有者持有（且未过期）时返回 ErrNotAcquired
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+}
+
+// Lease 已获取的锁
+type Lease interface {
+	// Key 锁名称
+	Key() string
+	// Token fencing token，同一 key 每次获取递增
+	Token() int64
+	// Renew 续期，锁已过期并被其他持有者获取（或已释放）时返回 ErrLost
+	Renew(ctx context.Context, ttl time.Duration) error
+	// Release 释放锁，已失效时返回 ErrLost
+	Release(ctx context.Context) error
+}
+
+// New 按配置创建 Locker，Type 为 db 时使用 conn（资源目录数据库）
+func New(c Config, conn *db.Conn) (Locker, error) {
+	switch c.Type {
+	case TypeLocal:
+		return NewLocal(), nil
+	case TypeDB:
+		if conn == nil {
+			return nil, errors.New("lock: db type requires a database connection")
+		}
+		return NewDB(conn), nil
+	case TypeRedis:
+		rds, err := redis.NewRedis(c.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("lock: create redis client: %w", err)
+		}
+		return NewRedis(rds, c.KeyPrefix), nil
+	default:
+		return nil, fmt.Errorf("%w: %q (expected local, db or redis)", ErrUnknownType, c.Type)
+	}
+}
+
+// Do 获取锁后执行 fn，执行期间自动续期，结束后释放
+// wait 为 0 时不等待，锁被持有时返回 ErrNotAcquired；否则在 wait 内重试获取。
+// 续期失败（锁已失效）时取消 fn 的 ctx，fn 应尽快返回；此时 Do 返回 fn 的错误或 ErrLost。
+func Do(ctx context.Context, locker Locker, key string, ttl, wait time.Duration, fn func(ctx context.Context) error) error {
+	lease, err := acquire(ctx, locker, key, ttl, wait)
+	if err != nil {
+		return err
+	}
+
+	ctx, release := Hold(ctx, lease, ttl)
+	err = fn(ctx)
+	if lost := release(); err == nil && lost != nil {
+		return lost
+	}
+	return err
+}
+
+// Hold 在后台按 ttl/3 续期，返回的 ctx 在锁失效时取消（携带 lease，可通过 FromContext 获取 token）
+// 调用返回的 release 停止续期并释放锁，锁在持有期间失效时返回 ErrLost
+func Hold(ctx context.Context, lease Lease, ttl time.Duration) (context.Context, func() error) {
+	ctx, cancel := context.WithCancelCause(context.WithValue(ctx, leaseKey{}, lease))
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := lease.Renew(ctx, ttl); err != nil {
+					// 非 ErrLost 的错误（如网络抖动）下次重试，锁仍可能有效
+					if errors.Is(err, ErrLost) {
+						logx.WithContext(ctx).Errorf("锁 %s 已失效 (token=%d)", lease.Key(), lease.Token())
+						cancel(err)
+						return
+					}
+					logx.WithContext(ctx).Errorf("锁 %s 续期失败: %v", lease.Key(), err)
+				}
+			}
+		}
+	}()
+
+	return ctx, func() error {
+		close(stop)
+		<-done
+		lost := context.Cause(ctx)
+		cancel(nil)
+		if errors.Is(lost, ErrLost) {
+			return ErrLost
+		}
+
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancelRelease()
+		if err := lease.Release(releaseCtx); err != nil {
+			if errors.Is(err, ErrLost) {
+				return err
+			}
+			// 释放失败时锁在 TTL 后过期
+			logx.WithContext(ctx).Errorf("锁 %s 释放失败: %v", lease.Key(), err)
+		}
+		return nil
+	}
+}
+
+// releaseTimeout 释放锁的超时时间
+const releaseTimeout = 5 * time.Second
+
+type leaseKey struct{}
+
+// FromContext 获取 Hold/Do 放入 context 的锁（用于读取 fencing token）
+func FromContext(ctx context.Context) (Lease, bool) {
+	lease, ok := ctx.Value(leaseKey{}).(Lease)
+	return lease, ok
+}
+
+// acquire 在 wait 内重试获取锁
+func acquire(ctx context.Context, locker Locker, key string, ttl, wait time.Duration) (Lease, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		lease, err := locker.Acquire(ctx, key, ttl)
+		if !errors.Is(err, ErrNotAcquired) || !time.Now().Before(deadline) {
+			return lease, err
+		}
+
+		timer := time.NewTimer(retryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// newOwner 持有者标识：主机名 + 随机数，同一进程内每次获取不同
+func newOwner() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return sysx.Hostname() + "-" + hex.EncodeToString(b)
+}
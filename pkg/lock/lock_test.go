@@ -0,0 +1,154 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/testkit"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+// shortTTL 测试使用的租约时长，expire 使其过期
+const shortTTL = 50 * time.Millisecond
+
+type lockerCase struct {
+	name   string
+	locker Locker
+	expire func() // 使 shortTTL 的租约过期
+}
+
+func lockers(t *testing.T) []lockerCase {
+	t.Helper()
+	sleep := func() { time.Sleep(2 * shortTTL) }
+
+	mr := miniredis.RunT(t)
+	rds := redis.New(mr.Addr())
+
+	return []lockerCase{
+		{name: "Local", locker: NewLocal(), expire: sleep},
+		{name: "DB", locker: NewDB(testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)), expire: sleep},
+		{name: "Redis", locker: NewRedis(rds, "test:lock:"), expire: func() { mr.FastForward(2 * shortTTL) }},
+	}
+}
+
+func TestLocker(t *testing.T) {
+	for _, tc := range lockers(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			first, err := tc.locker.Acquire(ctx, "job:a", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tc.locker.Acquire(ctx, "job:a", time.Minute); !errors.Is(err, ErrNotAcquired) {
+				t.Errorf("Acquire() held lock error = %v, want ErrNotAcquired", err)
+			}
+			if other, err := tc.locker.Acquire(ctx, "job:b", time.Minute); err != nil || other.Token() != 1 {
+				t.Errorf("Acquire() other key = %v, %v, want token 1", other, err)
+			}
+			if err := first.Renew(ctx, time.Minute); err != nil {
+				t.Errorf("Renew() error = %v", err)
+			}
+			if err := first.Release(ctx); err != nil {
+				t.Fatalf("Release() error = %v", err)
+			}
+			if err := first.Release(ctx); !errors.Is(err, ErrLost) {
+				t.Errorf("Release() twice error = %v, want ErrLost", err)
+			}
+
+			// 释放后重新获取：token 递增
+			second, err := tc.locker.Acquire(ctx, "job:a", shortTTL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if second.Token() <= first.Token() {
+				t.Errorf("Token() = %d, want > %d", second.Token(), first.Token())
+			}
+
+			// 过期后被其他持有者获取：旧租约续期、释放均失败，token 递增
+			tc.expire()
+			third, err := tc.locker.Acquire(ctx, "job:a", time.Minute)
+			if err != nil {
+				t.Fatalf("Acquire() after expiry error = %v", err)
+			}
+			if third.Token() <= second.Token() {
+				t.Errorf("Token() = %d, want > %d", third.Token(), second.Token())
+			}
+			if err := second.Renew(ctx, time.Minute); !errors.Is(err, ErrLost) {
+				t.Errorf("Renew() expired lease error = %v, want ErrLost", err)
+			}
+			if err := second.Release(ctx); !errors.Is(err, ErrLost) {
+				t.Errorf("Release() expired lease error = %v, want ErrLost", err)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	locker := NewLocal()
+
+	tests := []struct {
+		name    string
+		wait    time.Duration
+		held    bool // 执行前锁已被持有
+		wantErr error
+		wantRun bool
+	}{
+		{name: "获取成功", wantRun: true},
+		{name: "已被持有不等待", held: true, wantErr: ErrNotAcquired},
+		{name: "已被持有等待超时", held: true, wait: 3 * retryInterval, wantErr: ErrNotAcquired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.held {
+				lease, err := locker.Acquire(ctx, "do", time.Minute)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer lease.Release(ctx)
+			}
+
+			ran := false
+			err := Do(ctx, locker, "do", time.Minute, tt.wait, func(ctx context.Context) error {
+				lease, ok := FromContext(ctx)
+				ran = ok && lease.Token() > 0
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) || ran != tt.wantRun {
+				t.Errorf("Do() error = %v, ran = %v, want %v, %v", err, ran, tt.wantErr, tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestDo_Lost(t *testing.T) {
+	locker := NewLocal()
+
+	err := Do(context.Background(), locker, "lost", 30*time.Millisecond, 0, func(ctx context.Context) error {
+		// 模拟锁被强制获取（如进程暂停超过 TTL）：释放后由其他持有者获取
+		lease, _ := FromContext(ctx)
+		if err := lease.Release(ctx); err != nil {
+			return err
+		}
+		if _, err := locker.Acquire(ctx, "lost", time.Minute); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+			return errors.New("ctx not canceled after lease lost")
+		}
+	})
+	if !errors.Is(err, ErrLost) {
+		t.Errorf("Do() error = %v, want ErrLost", err)
+	}
+}
@@ -0,0 +1,97 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+var (
+	// acquireScript SET NX PX 成功后递增 fencing 计数器（计数器不过期）
+	acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`)
+
+	// renewScript 仍为持有者时续期
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	// releaseScript 仍为持有者时删除
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+var _ Locker = (*Redis)(nil)
+
+// Redis Redis 锁：key 值为持有者标识，{prefix}{key}:fence 为 fencing 计数器
+// key 使用 hash tag，集群模式下锁与计数器位于同一 slot
+type Redis struct {
+	rds    *redis.Redis
+	prefix string
+}
+
+// NewRedis 创建 Redis 锁
+func NewRedis(rds *redis.Redis, prefix string) *Redis {
+	return &Redis{rds: rds, prefix: prefix}
+}
+
+// Acquire 获取锁
+func (l *Redis) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	lockKey := l.prefix + "{" + key + "}"
+	owner := newOwner()
+
+	result, err := l.rds.ScriptRunCtx(ctx, acquireScript, []string{lockKey, lockKey + ":fence"},
+		owner, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return nil, fmt.Errorf("lock: acquire %s: %w", key, err)
+	}
+	token, _ := result.(int64)
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+	return &redisLease{locker: l, key: key, lockKey: lockKey, owner: owner, token: token}, nil
+}
+
+type redisLease struct {
+	locker  *Redis
+	key     string
+	lockKey string
+	owner   string
+	token   int64
+}
+
+func (l *redisLease) Key() string  { return l.key }
+func (l *redisLease) Token() int64 { return l.token }
+
+// Renew 续期
+func (l *redisLease) Renew(ctx context.Context, ttl time.Duration) error {
+	return l.run(ctx, renewScript, l.owner, strconv.FormatInt(ttl.Milliseconds(), 10))
+}
+
+// Release 释放
+func (l *redisLease) Release(ctx context.Context) error {
+	return l.run(ctx, releaseScript, l.owner)
+}
+
+// run 执行脚本，返回 0（不再持有）时返回 ErrLost
+func (l *redisLease) run(ctx context.Context, script *redis.Script, args ...any) error {
+	result, err := l.locker.rds.ScriptRunCtx(ctx, script, []string{l.lockKey}, args...)
+	if err != nil {
+		return fmt.Errorf("lock: %s: %w", l.key, err)
+	}
+	if n, _ := result.(int64); n == 0 {
+		return ErrLost
+	}
+	return nil
+}
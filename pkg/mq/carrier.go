@@ -0,0 +1,40 @@
+package mq
+
+import "go.opentelemetry.io/otel/propagation"
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+// headerCarrier 基于 Kafka 消息头的链路追踪上下文载体
+type headerCarrier struct {
+	headers *[]Header
+}
+
+// Get 获取消息头
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set 设置消息头（已存在时覆盖）
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, Header{Key: key, Value: []byte(value)})
+}
+
+// Keys 全部消息头名称
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
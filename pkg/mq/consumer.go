@@ -0,0 +1,308 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"idrm/pkg/config"
+	"idrm/pkg/telemetry/trace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.opentelemetry.io/otel"
+)
+
+// workerQueueSize 每个 worker 的待处理队列长度，队列满时暂停拉取
+const workerQueueSize = 16
+
+// commitTimeout 提交位移及写入死信的超时时间
+const commitTimeout = 10 * time.Second
+
+// Consumer 单个消费组（对应 Kafka.Consumers 中的一项），实现 go-zero service.Service
+type Consumer struct {
+	name   string
+	conf   config.ConsumerItemConfig
+	retry  config.RetryConfig
+	reader Reader
+	dlq    Writer
+	router *Router
+
+	tracker *offsetTracker
+
+	ctx    context.Context // Stop 时取消：停止拉取及重试等待
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewConsumer 创建消费组，配置中的每个 topic 须已在 router 中注册处理函数
+func NewConsumer(name string, c config.ConsumerItemConfig, retry config.RetryConfig, broker Broker, router *Router) (*Consumer, error) {
+	if len(c.Topics) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoTopics, name)
+	}
+	for _, topic := range c.Topics {
+		if _, err := router.handler(topic); err != nil {
+			return nil, fmt.Errorf("consumer %s: %w", name, err)
+		}
+	}
+	if c.Workers < 1 {
+		c.Workers = 1
+	}
+	if c.CommitInterval < 1 {
+		c.CommitInterval = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Consumer{
+		name:    name,
+		conf:    c,
+		retry:   retry,
+		reader:  broker.NewReader(c.Group, c.Topics),
+		dlq:     broker.NewWriter(),
+		router:  router,
+		tracker: newOffsetTracker(),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Start 拉取并处理消息，阻塞直到 Stop
+func (c *Consumer) Start() {
+	defer close(c.done)
+	logx.Infof("消费者 %s 启动: group=%s, topics=%v, workers=%d", c.name, c.conf.Group, c.conf.Topics, c.conf.Workers)
+
+	var wg sync.WaitGroup
+	queues := make([]chan Message, c.conf.Workers)
+	for i := range queues {
+		queues[i] = make(chan Message, workerQueueSize)
+		wg.Add(1)
+		go func(queue <-chan Message) {
+			defer wg.Done()
+			c.work(queue)
+		}(queues[i])
+	}
+
+	commitDone := make(chan struct{})
+	if c.conf.AutoCommit {
+		go func() {
+			defer close(commitDone)
+			c.commitLoop()
+		}()
+	} else {
+		close(commitDone)
+	}
+
+	c.fetch(queues)
+
+	// 排空：worker 完成正在处理的消息后退出，队列中未处理的消息不提交，重启后重新投递
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+	<-commitDone
+	c.flush()
+
+	if err := c.reader.Close(); err != nil {
+		logx.Errorf("消费者 %s 关闭 reader 失败: %v", c.name, err)
+	}
+	if err := c.dlq.Close(); err != nil {
+		logx.Errorf("消费者 %s 关闭死信 writer 失败: %v", c.name, err)
+	}
+	logx.Infof("消费者 %s 已停止", c.name)
+}
+
+// Stop 停止拉取，等待正在处理的消息完成并提交位移
+func (c *Consumer) Stop() {
+	c.cancel()
+	<-c.done
+}
+
+// fetch 拉取消息并按 key 分发到 worker，直到 Stop
+func (c *Consumer) fetch(queues []chan Message) {
+	var failures int
+	for {
+		msg, err := c.reader.FetchMessage(c.ctx)
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return
+			}
+			failures++
+			logx.Errorf("消费者 %s 拉取消息失败: %v", c.name, err)
+			if !c.sleep(c.backoff(failures)) {
+				return
+			}
+			continue
+		}
+		failures = 0
+
+		c.tracker.add(msg)
+		select {
+		case queues[c.worker(msg)] <- msg:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// worker 同一 key 固定分配到同一 worker，保证 key 内顺序
+func (c *Consumer) worker(msg Message) int {
+	if len(msg.Key) == 0 {
+		return int(msg.Partition) % c.conf.Workers
+	}
+	h := fnv.New32a()
+	h.Write(msg.Key)
+	return int(h.Sum32() % uint32(c.conf.Workers))
+}
+
+// work 依次处理队列中的消息；Stop 后不再处理新消息
+func (c *Consumer) work(queue <-chan Message) {
+	for msg := range queue {
+		if c.ctx.Err() != nil {
+			continue
+		}
+		if c.process(msg) {
+			c.complete(msg)
+		}
+	}
+}
+
+// process 处理消息（含重试及死信），返回是否可以提交位移
+func (c *Consumer) process(msg Message) bool {
+	// 处理函数使用独立的 context：Stop 时正在处理的消息可以完成
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier{&msg.Headers})
+	ctx, span := trace.StartConsumer(ctx, "mq.consume "+msg.Topic, trace.WithAttributes(
+		"messaging.system", "kafka",
+		"messaging.destination.name", msg.Topic,
+		"messaging.consumer.group.name", c.conf.Group,
+		"messaging.kafka.partition", msg.Partition,
+		"messaging.kafka.offset", msg.Offset,
+		"messaging.kafka.message.key", string(msg.Key),
+	)...)
+
+	handler, err := c.router.handler(msg.Topic)
+	attempts := 0
+	for err == nil {
+		attempts++
+		if err = handler.Consume(ctx, &msg); err == nil {
+			break
+		}
+		if attempts > c.retry.MaxRetries {
+			break
+		}
+		trace.AddEvent(span, "retry", trace.WithAttributes("attempt", attempts, "error", err.Error())...)
+		logx.WithContext(ctx).Errorf("消费者 %s 处理失败，稍后重试: topic=%s, offset=%d, attempt=%d, err=%v",
+			c.name, msg.Topic, msg.Offset, attempts, err)
+		if !c.sleep(c.backoff(attempts)) {
+			// 停止时放弃重试，不提交位移
+			trace.End(span, err)
+			return false
+		}
+		err = nil
+	}
+	trace.End(span, err)
+
+	if err == nil {
+		metricMessages.Inc(c.name, msg.Topic, resultSuccess)
+		return true
+	}
+	metricMessages.Inc(c.name, msg.Topic, resultDeadLetter)
+	logx.WithContext(ctx).Errorf("消费者 %s 重试耗尽，写入死信: topic=%s, offset=%d, attempts=%d, err=%v",
+		c.name, msg.Topic, msg.Offset, attempts, err)
+	return c.deadLetter(ctx, msg, attempts, err)
+}
+
+// deadLetter 写入死信 topic，失败时按最大间隔重试直到成功或 Stop
+func (c *Consumer) deadLetter(ctx context.Context, msg Message, attempts int, cause error) bool {
+	topic := c.conf.DeadLetterTopic
+	if topic == "" {
+		topic = msg.Topic + DeadLetterSuffix
+	}
+
+	headers := append([]Header(nil), msg.Headers...)
+	headers = append(headers,
+		Header{Key: HeaderOriginalTopic, Value: []byte(msg.Topic)},
+		Header{Key: HeaderOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		Header{Key: HeaderError, Value: []byte(cause.Error())},
+		Header{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+	)
+	dead := Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers}
+
+	for {
+		writeCtx, cancel := context.WithTimeout(ctx, commitTimeout)
+		err := c.dlq.WriteMessages(writeCtx, dead)
+		cancel()
+		if err == nil {
+			return true
+		}
+		logx.WithContext(ctx).Errorf("消费者 %s 写入死信 %s 失败: %v", c.name, topic, err)
+		if !c.sleep(time.Duration(c.retry.MaxInterval) * time.Millisecond) {
+			return false
+		}
+	}
+}
+
+// complete 标记完成；非 AutoCommit 时立即提交连续完成的位移
+func (c *Consumer) complete(msg Message) {
+	c.tracker.done(msg)
+	if !c.conf.AutoCommit {
+		c.flush()
+	}
+}
+
+// commitLoop 按 CommitInterval 定期提交
+func (c *Consumer) commitLoop() {
+	ticker := time.NewTicker(time.Duration(c.conf.CommitInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.flush()
+		}
+	}
+}
+
+// flush 提交各分区连续完成的最大位移
+func (c *Consumer) flush() {
+	c.tracker.commit(func(msgs []Message) error {
+		ctx, cancel := context.WithTimeout(context.Background(), commitTimeout)
+		defer cancel()
+
+		err := c.reader.CommitMessages(ctx, msgs...)
+		if err != nil {
+			logx.Errorf("消费者 %s 提交位移失败: %v", c.name, err)
+		}
+		return err
+	})
+}
+
+// backoff 第 attempts 次失败后的等待时间：InitialInterval * Multiplier^(attempts-1)，不超过 MaxInterval
+func (c *Consumer) backoff(attempts int) time.Duration {
+	interval := time.Duration(c.retry.InitialInterval) * time.Millisecond
+	max := time.Duration(c.retry.MaxInterval) * time.Millisecond
+	for i := 1; i < attempts && interval < max; i++ {
+		interval *= time.Duration(c.retry.Multiplier)
+	}
+	if interval > max {
+		interval = max
+	}
+	return interval
+}
+
+// sleep 等待 d，Stop 时返回 false
+func (c *Consumer) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-c.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
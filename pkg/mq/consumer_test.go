@@ -0,0 +1,255 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"idrm/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testGroup = "test-group"
+	testTopic = "test.events"
+)
+
+var testRetry = config.RetryConfig{MaxRetries: 2, InitialInterval: 1, MaxInterval: 5, Multiplier: 2}
+
+// startConsumer 启动消费者，测试结束时停止
+func startConsumer(t *testing.T, broker Broker, workers int, handler HandlerFunc) *Consumer {
+	t.Helper()
+	router := NewRouter()
+	router.Handle(testTopic, handler)
+	c, err := NewConsumer("test", config.ConsumerItemConfig{
+		Group:   testGroup,
+		Topics:  []string{testTopic},
+		Workers: workers,
+	}, testRetry, broker, router)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go c.Start()
+	t.Cleanup(c.Stop)
+	return c
+}
+
+// waitFor 等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNewConsumer(t *testing.T) {
+	router := NewRouter()
+	router.Handle(testTopic, HandlerFunc(func(ctx context.Context, msg *Message) error { return nil }))
+
+	tests := []struct {
+		name    string
+		topics  []string
+		wantErr error
+	}{
+		{name: "已注册", topics: []string{testTopic}},
+		{name: "未配置topic", topics: nil, wantErr: ErrNoTopics},
+		{name: "未注册处理函数", topics: []string{testTopic, "other"}, wantErr: ErrNoHandler},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConsumer("test", config.ConsumerItemConfig{Group: testGroup, Topics: tt.topics}, testRetry, NewMemoryBroker(), router)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewConsumer() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConsumer_Process(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int // 前 failures 次处理失败
+		wantAttempts int
+		wantDead     bool
+	}{
+		{name: "处理成功", failures: 0, wantAttempts: 1},
+		{name: "重试后成功", failures: 2, wantAttempts: 3},
+		{name: "重试耗尽写入死信", failures: 10, wantAttempts: 3, wantDead: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := NewMemoryBroker()
+			var mu sync.Mutex
+			attempts := 0
+			startConsumer(t, broker, 1, func(ctx context.Context, msg *Message) error {
+				mu.Lock()
+				defer mu.Unlock()
+				attempts++
+				if attempts <= tt.failures {
+					return errors.New("handler failed")
+				}
+				return nil
+			})
+
+			broker.Produce(testTopic, Message{Key: []byte("k"), Value: []byte("v")})
+			waitFor(t, func() bool { return broker.Committed(testGroup, testTopic) == 1 })
+
+			mu.Lock()
+			defer mu.Unlock()
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			dead := broker.Messages(testTopic + DeadLetterSuffix)
+			if (len(dead) == 1) != tt.wantDead {
+				t.Fatalf("dead letters = %d, want dead %v", len(dead), tt.wantDead)
+			}
+			if tt.wantDead {
+				carrier := headerCarrier{&dead[0].Headers}
+				if carrier.Get(HeaderOriginalTopic) != testTopic || carrier.Get(HeaderOriginalOffset) != "0" ||
+					carrier.Get(HeaderAttempts) != "3" || carrier.Get(HeaderError) != "handler failed" {
+					t.Errorf("dead letter headers = %v", dead[0].Headers)
+				}
+			}
+		})
+	}
+}
+
+func TestConsumer_KeyOrder(t *testing.T) {
+	broker := NewMemoryBroker()
+	var mu sync.Mutex
+	got := make(map[string][]int)
+	startConsumer(t, broker, 4, func(ctx context.Context, msg *Message) error {
+		n, _ := strconv.Atoi(string(msg.Value))
+		mu.Lock()
+		defer mu.Unlock()
+		got[string(msg.Key)] = append(got[string(msg.Key)], n)
+		return nil
+	})
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for i := 0; i < 100; i++ {
+		broker.Produce(testTopic, Message{Key: []byte(keys[i%len(keys)]), Value: []byte(strconv.Itoa(i))})
+	}
+	waitFor(t, func() bool { return broker.Committed(testGroup, testTopic) == 100 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	for key, values := range got {
+		for i := 1; i < len(values); i++ {
+			if values[i] < values[i-1] {
+				t.Errorf("key %s out of order: %v", key, values)
+				break
+			}
+		}
+	}
+}
+
+func TestConsumer_Drain(t *testing.T) {
+	broker := NewMemoryBroker()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []int64
+	c := startConsumer(t, broker, 1, func(ctx context.Context, msg *Message) error {
+		if msg.Offset == 0 {
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, msg.Offset)
+		return nil
+	})
+
+	broker.Produce(testTopic, Message{Value: []byte("0")}, Message{Value: []byte("1")}, Message{Value: []byte("2")})
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		c.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("Stop() returned before in-flight message finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-stopped
+
+	// 正在处理的消息完成并提交，队列中的消息不处理、不提交
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != 0 {
+		t.Errorf("handled = %v, want [0]", handled)
+	}
+	if got := broker.Committed(testGroup, testTopic); got != 1 {
+		t.Errorf("Committed() = %d, want 1", got)
+	}
+}
+
+func TestConsumer_TracePropagation(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	broker := NewMemoryBroker()
+	got := make(chan string, 1)
+	startConsumer(t, broker, 1, func(ctx context.Context, msg *Message) error {
+		got <- oteltrace.SpanContextFromContext(ctx).TraceID().String()
+		return nil
+	})
+
+	broker.Produce(testTopic, Message{Headers: []Header{
+		{Key: "traceparent", Value: []byte("00-" + traceId + "-00f067aa0ba902b7-01")},
+	}})
+	if id := <-got; id != traceId {
+		t.Errorf("trace id = %s, want %s", id, traceId)
+	}
+}
+
+func TestOffsetTracker(t *testing.T) {
+	tracker := newOffsetTracker()
+	msgs := make([]Message, 4)
+	for i := range msgs {
+		msgs[i] = Message{Topic: testTopic, Offset: int64(i)}
+		tracker.add(msgs[i])
+	}
+
+	var committed []int64
+	commit := func(msgs []Message) error {
+		for _, msg := range msgs {
+			committed = append(committed, msg.Offset)
+		}
+		return nil
+	}
+
+	// 1、2 先完成：0 未完成，不提交
+	tracker.done(msgs[1])
+	tracker.done(msgs[2])
+	tracker.commit(commit)
+	if len(committed) != 0 {
+		t.Fatalf("committed = %v, want none before offset 0 is done", committed)
+	}
+
+	// 0 完成：提交连续完成的最大位移 2，提交失败时下次重新提交
+	tracker.done(msgs[0])
+	tracker.commit(func([]Message) error { return errors.New("commit failed") })
+	tracker.commit(commit)
+	tracker.done(msgs[3])
+	tracker.commit(commit)
+	if len(committed) != 2 || committed[0] != 2 || committed[1] != 3 {
+		t.Errorf("committed = %v, want [2 3]", committed)
+	}
+}
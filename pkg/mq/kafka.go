@@ -0,0 +1,35 @@
+package mq
+
+import (
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var _ Broker = KafkaBroker{}
+
+// KafkaBroker 基于 kafka-go 的 Broker
+type KafkaBroker struct {
+	Brokers []string
+}
+
+// NewReader 创建消费组 Reader，位移由 Consumer 显式提交（CommitInterval 为 0，同步提交）
+func (b KafkaBroker) NewReader(group string, topics []string) Reader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     b.Brokers,
+		GroupID:     group,
+		GroupTopics: topics,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     time.Second,
+	})
+}
+
+// NewWriter 创建死信 Writer（按 key 哈希分区）
+func (b KafkaBroker) NewWriter() Writer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(b.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+	}
+}
@@ -0,0 +1,140 @@
+package mq
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Broker = (*MemoryBroker)(nil)
+
+// MemoryBroker 内存 Broker（测试用）：每个 topic 单分区，按消费组记录已提交位移
+type MemoryBroker struct {
+	mu        sync.Mutex
+	topics    map[string][]Message
+	committed map[string]int64 // group/topic -> 下一条待消费的位移
+	notify    chan struct{}    // 有新消息时关闭并替换
+}
+
+// NewMemoryBroker 创建内存 Broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics:    make(map[string][]Message),
+		committed: make(map[string]int64),
+		notify:    make(chan struct{}),
+	}
+}
+
+// Produce 写入消息（分区为 0，位移递增）
+func (b *MemoryBroker) Produce(topic string, msgs ...Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, msg := range msgs {
+		msg.Topic = topic
+		msg.Partition = 0
+		msg.Offset = int64(len(b.topics[topic]))
+		if msg.Time.IsZero() {
+			msg.Time = time.Now()
+		}
+		b.topics[topic] = append(b.topics[topic], msg)
+	}
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// Messages 获取 topic 中的全部消息
+func (b *MemoryBroker) Messages(topic string) []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Message(nil), b.topics[topic]...)
+}
+
+// Committed 消费组在 topic 上已提交的位移（下一条待消费的位移）
+func (b *MemoryBroker) Committed(group, topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.committed[group+"/"+topic]
+}
+
+// NewReader 创建 Reader，从消费组已提交的位移开始读取
+func (b *MemoryBroker) NewReader(group string, topics []string) Reader {
+	return &memoryReader{broker: b, group: group, topics: topics, positions: make(map[string]int64)}
+}
+
+// NewWriter 创建 Writer
+func (b *MemoryBroker) NewWriter() Writer {
+	return memoryWriter{broker: b}
+}
+
+type memoryReader struct {
+	broker    *MemoryBroker
+	group     string
+	topics    []string
+	positions map[string]int64 // 只由 FetchMessage 所在协程访问
+	inited    bool
+}
+
+// FetchMessage 阻塞直到有新消息或 ctx 结束
+func (r *memoryReader) FetchMessage(ctx context.Context) (Message, error) {
+	for {
+		r.broker.mu.Lock()
+		if !r.inited {
+			for _, topic := range r.topics {
+				r.positions[topic] = r.broker.committed[r.group+"/"+topic]
+			}
+			r.inited = true
+		}
+		for _, topic := range r.topics {
+			if pos := r.positions[topic]; pos < int64(len(r.broker.topics[topic])) {
+				r.positions[topic] = pos + 1
+				msg := r.broker.topics[topic][pos]
+				r.broker.mu.Unlock()
+				return msg, nil
+			}
+		}
+		notify := r.broker.notify
+		r.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// CommitMessages 提交位移（与 Kafka 一致，提交 offset+1）
+func (r *memoryReader) CommitMessages(ctx context.Context, msgs ...Message) error {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	for _, msg := range msgs {
+		key := r.group + "/" + msg.Topic
+		if next := msg.Offset + 1; next > r.broker.committed[key] {
+			r.broker.committed[key] = next
+		}
+	}
+	return nil
+}
+
+func (r *memoryReader) Close() error {
+	return nil
+}
+
+type memoryWriter struct {
+	broker *MemoryBroker
+}
+
+func (w memoryWriter) WriteMessages(ctx context.Context, msgs ...Message) error {
+	for _, msg := range msgs {
+		w.broker.Produce(msg.Topic, msg)
+	}
+	return nil
+}
+
+func (w memoryWriter) Close() error {
+	return nil
+}
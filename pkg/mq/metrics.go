@@ -0,0 +1,17 @@
+package mq
+
+import "github.com/zeromicro/go-zero/core/metric"
+
+// 处理结果
+const (
+	resultSuccess    = "success"
+	resultDeadLetter = "dead_letter"
+)
+
+var metricMessages = metric.NewCounterVec(&metric.CounterVecOpts{
+	Namespace: "idrm",
+	Subsystem: "mq",
+	Name:      "consumed_total",
+	Help:      "consumed messages by consumer, topic and result",
+	Labels:    []string{"consumer", "topic", "result"},
+})
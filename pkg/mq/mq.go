@@ -0,0 +1,109 @@
+// Package mq Kafka 消费运行时：按 topic 注册处理函数，worker 池并发处理，处理完成后提交位移
+//
+//   - 同一 key 的消息由同一 worker 按顺序处理，不同 key 并发
+//   - 位移只提交到连续处理完成的位置，进程退出后未完成的消息会重新投递（至少一次）
+//   - 处理失败按 RetryConfig 指数退避重试，重试耗尽后写入死信 topic 并提交位移
+//   - 按消息头中的链路追踪上下文创建 consumer span
+//
+// Broker 抽象了 Kafka 连接，测试使用 MemoryBroker 代替真实 Kafka。
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// 死信消息头（记录原始位置及失败原因）
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderError             = "x-error"
+	HeaderAttempts          = "x-attempts"
+)
+
+// DeadLetterSuffix 未配置 DeadLetterTopic 时死信 topic 为 {topic}{DeadLetterSuffix}
+const DeadLetterSuffix = ".dlq"
+
+var (
+	ErrNoHandler = errors.New("mq: no handler registered for topic")
+	ErrNoTopics  = errors.New("mq: consumer has no topics")
+)
+
+// Message Kafka 消息
+type Message = kafka.Message
+
+// Header 消息头
+type Header = kafka.Header
+
+// Handler 消息处理
+type Handler interface {
+	Consume(ctx context.Context, msg *Message) error
+}
+
+// HandlerFunc 函数形式的 Handler
+type HandlerFunc func(ctx context.Context, msg *Message) error
+
+// Consume 调用 f
+func (f HandlerFunc) Consume(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// Reader 消息来源（kafka.Reader 或 MemoryBroker 的 reader）
+type Reader interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	CommitMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Writer 消息写入（用于死信），消息须指定 Topic
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Broker 创建 Reader 与 Writer
+type Broker interface {
+	NewReader(group string, topics []string) Reader
+	NewWriter() Writer
+}
+
+// Router 按 topic 注册处理函数
+type Router struct {
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRouter 创建 Router
+func NewRouter() *Router {
+	return &Router{handlers: make(map[string]Handler)}
+}
+
+// Handle 注册 topic 的处理函数，重复注册时覆盖
+func (r *Router) Handle(topic string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[topic] = handler
+}
+
+// handler 获取 topic 的处理函数
+func (r *Router) handler(topic string) (Handler, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.handlers[topic]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoHandler, topic)
+	}
+	return handler, nil
+}
+
+// HeaderValue 获取消息头，不存在时返回空字符串
+func HeaderValue(msg *Message, key string) string {
+	return headerCarrier{&msg.Headers}.Get(key)
+}
@@ -0,0 +1,115 @@
+package mq
+
+import (
+	"sort"
+	"sync"
+)
+
+// partitionKey topic + 分区
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+// partitionOffsets 单个分区已拉取的位移
+type partitionOffsets struct {
+	pending   []int64        // 已拉取未提交的位移（拉取顺序即位移递增顺序）
+	done      map[int64]bool // 已处理完成
+	committed int64          // 已提交的最大位移，-1 表示未提交
+}
+
+// offsetTracker 跟踪各分区的处理进度，只提交连续完成的位移，避免跳过仍在处理中的消息
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionOffsets
+	commitMu   sync.Mutex // 串行提交，防止并发提交导致位移回退
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[partitionKey]*partitionOffsets)}
+}
+
+// add 记录拉取的消息
+func (t *offsetTracker) add(msg Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := partitionKey{msg.Topic, msg.Partition}
+	p, ok := t.partitions[key]
+	if !ok {
+		p = &partitionOffsets{done: make(map[int64]bool), committed: -1}
+		t.partitions[key] = p
+	}
+	p.pending = append(p.pending, msg.Offset)
+}
+
+// done 标记消息处理完成
+func (t *offsetTracker) done(msg Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if p, ok := t.partitions[partitionKey{msg.Topic, msg.Partition}]; ok {
+		p.done[msg.Offset] = true
+	}
+}
+
+// committable 各分区连续完成的最大位移（相对上次提交有推进的分区）
+func (t *offsetTracker) committable() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var msgs []Message
+	for key, p := range t.partitions {
+		n := 0
+		for n < len(p.pending) && p.done[p.pending[n]] {
+			delete(p.done, p.pending[n])
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		offset := p.pending[n-1]
+		p.pending = p.pending[n:]
+		if offset > p.committed {
+			msgs = append(msgs, Message{Topic: key.topic, Partition: key.partition, Offset: offset})
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if msgs[i].Topic != msgs[j].Topic {
+			return msgs[i].Topic < msgs[j].Topic
+		}
+		return msgs[i].Partition < msgs[j].Partition
+	})
+	return msgs
+}
+
+// commit 提交连续完成的位移，提交失败时下次重新提交
+func (t *offsetTracker) commit(fn func(msgs []Message) error) {
+	t.commitMu.Lock()
+	defer t.commitMu.Unlock()
+
+	msgs := t.committable()
+	if len(msgs) == 0 {
+		return
+	}
+	if err := fn(msgs); err != nil {
+		t.mu.Lock()
+		for _, msg := range msgs {
+			// 失败的位移保留在待提交位置，下次 committable 重新返回
+			p := t.partitions[partitionKey{msg.Topic, msg.Partition}]
+			p.pending = append([]int64{msg.Offset}, p.pending...)
+			p.done[msg.Offset] = true
+		}
+		t.mu.Unlock()
+		return
+	}
+
+	t.mu.Lock()
+	for _, msg := range msgs {
+		p := t.partitions[partitionKey{msg.Topic, msg.Partition}]
+		if msg.Offset > p.committed {
+			p.committed = msg.Offset
+		}
+	}
+	t.mu.Unlock()
+}
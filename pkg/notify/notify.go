@@ -0,0 +1,77 @@
+// Package notify 通知：将业务事件（如数据视图结构变化）发送给负责人
+//
+// Notifier 抽象通知渠道，目前提供日志及 Webhook（HTTP POST JSON，可对接企业微信、钉钉等网关）实现。
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 通知类型
+const (
+	TypeLog     = "log"     // 只记录日志
+	TypeWebhook = "webhook" // POST JSON 到 Webhook.URL
+)
+
+var (
+	ErrUnknownType = errors.New("notify: unknown type")
+	ErrNoURL       = errors.New("notify: webhook url not configured")
+)
+
+// Message 通知内容
+type Message struct {
+	To      string      `json:"to"` // 接收人（负责人），为空时由渠道发送到默认接收方
+	Subject string      `json:"subject"`
+	Content string      `json:"content"`
+	Level   string      `json:"level"`          // info 或 warning
+	Data    interface{} `json:"data,omitempty"` // 原始事件内容
+}
+
+// 通知级别
+const (
+	LevelInfo    = "info"
+	LevelWarning = "warning"
+)
+
+// Notifier 发送通知，实现须并发安全
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// Config 通知配置
+type Config struct {
+	Type    string `json:",default=log,options=log|webhook"`
+	Webhook struct {
+		URL     string            `json:",optional"`
+		Headers map[string]string `json:",optional"`  // 如鉴权头
+		Timeout int               `json:",default=5"` // 秒
+	} `json:",optional"`
+}
+
+// New 按配置创建通知渠道
+func New(c Config) (Notifier, error) {
+	switch c.Type {
+	case TypeLog, "":
+		return Log, nil
+	case TypeWebhook:
+		if c.Webhook.URL == "" {
+			return nil, ErrNoURL
+		}
+		return NewWebhook(c.Webhook.URL, c.Webhook.Headers, c.Webhook.Timeout), nil
+	default:
+		return nil, ErrUnknownType
+	}
+}
+
+// Log 只记录日志的通知渠道
+var Log Notifier = logNotifier{}
+
+type logNotifier struct{}
+
+func (logNotifier) Notify(ctx context.Context, msg *Message) error {
+	logx.WithContext(ctx).Infof("通知 %s [%s] %s: %s", msg.To, msg.Level, msg.Subject, msg.Content)
+	return nil
+}
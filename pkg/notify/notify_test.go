@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook(t *testing.T) {
+	var got Message
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			t.Errorf("X-Token = %q, want secret", r.Header.Get("X-Token"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	var c Config
+	c.Type = TypeWebhook
+	c.Webhook.URL = srv.URL
+	c.Webhook.Headers = map[string]string{"X-Token": "secret"}
+	n, err := New(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	msg := &Message{To: "张三", Subject: "结构变化", Content: "删除列 email", Level: LevelWarning}
+	if err := n.Notify(ctx, msg); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got.To != msg.To || got.Subject != msg.Subject || got.Level != LevelWarning {
+		t.Errorf("webhook received %+v, want %+v", got, *msg)
+	}
+
+	status = http.StatusBadGateway
+	if err := n.Notify(ctx, msg); err == nil {
+		t.Error("Notify() with 502 response error = nil, want error")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if n, err := New(Config{}); err != nil || n != Log {
+		t.Errorf("New(empty) = %v, %v, want Log", n, err)
+	}
+	if _, err := New(Config{Type: TypeWebhook}); err != ErrNoURL {
+		t.Errorf("New(webhook without url) error = %v, want ErrNoURL", err)
+	}
+	if _, err := New(Config{Type: "sms"}); err != ErrUnknownType {
+		t.Errorf("New(sms) error = %v, want ErrUnknownType", err)
+	}
+}
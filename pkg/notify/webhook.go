@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook 以 JSON 格式 POST 通知内容，非 2xx 响应视为失败
+type Webhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhook 创建 Webhook 通知渠道，timeout 为请求超时（秒），不大于 0 时为 5 秒
+func NewWebhook(url string, headers map[string]string, timeout int) *Webhook {
+	if timeout <= 0 {
+		timeout = 5
+	}
+	return &Webhook{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: time.Duration(timeout) * time.Second},
+	}
+}
+
+func (w *Webhook) Notify(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("notify: encode message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notify: webhook status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	return nil
+}
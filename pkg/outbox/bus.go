@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Handler 事件处理函数
+type Handler func(ctx context.Context, msg *Message) error
+
+var _ Publisher = (*Bus)(nil)
+
+// Bus 进程内事件总线：按聚合类型订阅，投递时同步调用处理函数
+// 任一处理函数失败时整条消息重试，已成功的处理函数会再次收到（至少一次）
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus 创建进程内事件总线
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe 订阅指定聚合类型的事件，aggregateType 为空时订阅全部
+func (b *Bus) Subscribe(aggregateType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[aggregateType] = append(b.handlers[aggregateType], handler)
+}
+
+// Publish 依次调用订阅者
+func (b *Bus) Publish(ctx context.Context, msg *Message) error {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[msg.AggregateType])+len(b.handlers[""]))
+	handlers = append(handlers, b.handlers[msg.AggregateType]...)
+	handlers = append(handlers, b.handlers[""]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("bus handle %s: %w", msg.EventType, err)
+	}
+	return nil
+}
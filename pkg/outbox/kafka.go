@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"idrm/pkg/config"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var _ Publisher = (*KafkaPublisher)(nil)
+
+// KafkaPublisher 投递到 Kafka：消息 key 为聚合 key（哈希分区，同一聚合有序），消息头包含事件元数据及链路追踪上下文
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher 创建 Kafka 投递目标（不建立连接）
+func NewKafkaPublisher(c config.KafkaProducerConfig) (*KafkaPublisher, error) {
+	if len(c.Brokers) == 0 || c.Topic == "" {
+		return nil, errors.New("outbox: kafka brokers and topic are required")
+	}
+
+	return &KafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(c.Brokers...),
+		Topic:        c.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Relay 逐条同步投递，不等待攒批
+		BatchSize:    1,
+		BatchTimeout: time.Millisecond,
+		WriteTimeout: time.Duration(c.WriteTimeout) * time.Second,
+	}}, nil
+}
+
+// Publish 同步写入，broker 确认后返回
+func (p *KafkaPublisher) Publish(ctx context.Context, msg *Message) error {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	headers := []kafka.Header{
+		{Key: HeaderEventId, Value: []byte(strconv.FormatInt(msg.Id, 10))},
+		{Key: HeaderEventType, Value: []byte(msg.EventType)},
+		{Key: HeaderAggregateType, Value: []byte(msg.AggregateType)},
+		{Key: HeaderAggregateId, Value: []byte(msg.AggregateId)},
+	}
+	for key, value := range carrier {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+
+	return p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(msg.Key()),
+		Value:   []byte(msg.Payload),
+		Headers: headers,
+		Time:    msg.CreatedAt,
+	})
+}
+
+// Close 关闭连接
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
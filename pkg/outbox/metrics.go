@@ -0,0 +1,17 @@
+package outbox
+
+import "github.com/zeromicro/go-zero/core/metric"
+
+// 投递结果
+const (
+	resultPublished = "published"
+	resultFailed    = "failed"
+)
+
+var metricMessages = metric.NewCounterVec(&metric.CounterVecOpts{
+	Namespace: "idrm",
+	Subsystem: "outbox",
+	Name:      "messages_total",
+	Help:      "outbox messages relayed by result",
+	Labels:    []string{"result"},
+})
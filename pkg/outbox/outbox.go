@@ -0,0 +1,90 @@
+// Package outbox 事务发件箱：业务数据变更时在同一事务中写入事件，由 Relay 异步投递到 Kafka 或进程内总线
+//
+// 写入与业务数据同一事务提交（无双写问题），Relay 按 ID 顺序投递，投递成功后标记为已投递：
+//   - 至少一次：投递成功但标记失败时会重复投递，消费方需按事件 ID 幂等处理
+//   - 同一聚合（AggregateType + AggregateId）按写入顺序投递，某条失败时该聚合后续事件等待重试，其他聚合不受影响
+//   - Kafka 消息 key 为聚合 key，同一聚合落在同一分区
+//   - 多实例部署时 Relay 使用分布式锁（WithLocker），同一时刻只有一个实例投递
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"idrm/pkg/config"
+)
+
+// ModelName 模型名称（表名）
+const ModelName = "outbox"
+
+// 投递状态
+const (
+	StatusPending   = 0
+	StatusPublished = 1
+)
+
+// 消息头
+const (
+	HeaderEventId       = "event-id"
+	HeaderEventType     = "event-type"
+	HeaderAggregateType = "aggregate-type"
+	HeaderAggregateId   = "aggregate-id"
+)
+
+var (
+	ErrNoTransaction    = errors.New("outbox: write must be inside a transaction")
+	ErrUnsupportedTx    = errors.New("outbox: unsupported transaction type")
+	ErrUnknownPublisher = errors.New("outbox: unknown publisher")
+)
+
+// Config 发件箱配置
+type Config struct {
+	Enabled          bool                       `json:",default=false"`                 // 写操作同时写入发件箱（需先执行 000002 迁移）
+	Relay            bool                       `json:",default=true"`                  // 本实例运行 Relay，多实例时只有持有投递租约（WithLocker）的实例投递
+	Publisher        string                     `json:",default=bus,options=bus|kafka"` // 投递目标：进程内总线或 Kafka
+	Kafka            config.KafkaProducerConfig `json:",optional"`
+	PollInterval     int                        `json:",default=1000"` // 轮询间隔(毫秒)
+	BatchSize        int                        `json:",default=100"`  // 每次轮询最多投递条数
+	RetryInterval    int                        `json:",default=1"`    // 首次重试间隔(秒)，之后指数退避
+	MaxRetryInterval int                        `json:",default=300"`  // 最大重试间隔(秒)
+}
+
+// Event 待写入的事件
+type Event struct {
+	AggregateType string      // 聚合类型，如 category
+	AggregateId   string      // 聚合ID
+	Type          string      // 事件类型，如 category.created
+	Payload       interface{} // 事件内容，JSON 编码后写入
+}
+
+// Writer 在当前事务中写入事件（事务对象通过 db.ContextWithTx 放入 context）
+type Writer interface {
+	Write(ctx context.Context, events ...Event) error
+}
+
+// Message 发件箱记录
+type Message struct {
+	Id            int64     `db:"id" gorm:"column:id;primaryKey;index:idx_outbox_status,priority:2"`
+	AggregateType string    `db:"aggregate_type" gorm:"column:aggregate_type;type:varchar(50);not null"`
+	AggregateId   string    `db:"aggregate_id" gorm:"column:aggregate_id;type:varchar(64);not null"`
+	EventType     string    `db:"event_type" gorm:"column:event_type;type:varchar(100);not null"`
+	Payload       string    `db:"payload" gorm:"column:payload;not null"`
+	Headers       string    `db:"headers" gorm:"column:headers;not null"` // JSON 对象，包含写入时的链路追踪上下文
+	Status        int       `db:"status" gorm:"column:status;index:idx_outbox_status,priority:1"`
+	Attempts      int       `db:"attempts" gorm:"column:attempts"`
+	LastError     string    `db:"last_error" gorm:"column:last_error;type:varchar(500)"`
+	NextAttemptAt time.Time `db:"next_attempt_at" gorm:"column:next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at" gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `db:"updated_at" gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName 表名（与 migrations 中的DDL保持一致）
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// Key 聚合 key（Kafka 消息 key，同一 key 按顺序投递）
+func (m *Message) Key() string {
+	return m.AggregateType + ":" + m.AggregateId
+}
@@ -0,0 +1,268 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/lock"
+	"idrm/pkg/testkit"
+
+	"gorm.io/gorm"
+)
+
+// fakePublisher 记录投递顺序，fail 中的事件ID投递失败
+type fakePublisher struct {
+	published []int64
+	fail      map[int64]bool
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, msg *Message) error {
+	if p.fail[msg.Id] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, msg.Id)
+	return nil
+}
+
+func TestStore_Write(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm)
+	store := NewStore(conn)
+	event := Event{AggregateType: "category", AggregateId: "1", Type: "category.created", Payload: map[string]int{"id": 1}}
+
+	if err := store.Write(ctx, event); !errors.Is(err, ErrNoTransaction) {
+		t.Errorf("Write() outside transaction error = %v, want ErrNoTransaction", err)
+	}
+
+	errRollback := errors.New("rollback")
+	err := conn.Gorm.Transaction(func(tx *gorm.DB) error {
+		if err := store.Write(db.ContextWithTx(ctx, tx), event); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Transaction() error = %v, want rollback", err)
+	}
+	if err := conn.Gorm.Transaction(func(tx *gorm.DB) error {
+		return store.Write(db.ContextWithTx(ctx, tx), event, event)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := store.pending(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[0].Payload != `{"id":1}` || msgs[0].Key() != "category:1" || msgs[0].Headers == "" {
+		t.Errorf("pending() = %+v, want 2 committed messages", msgs)
+	}
+}
+
+func TestStore_PurgePublished(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm)
+	store := NewStore(conn)
+	event := Event{AggregateType: "category", AggregateId: "1", Type: "category.updated", Payload: map[string]int{"id": 1}}
+	if err := conn.Gorm.Transaction(func(tx *gorm.DB) error {
+		return store.Write(db.ContextWithTx(ctx, tx), event, event, event, event)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := store.pending(ctx, time.Now(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 前 3 条已投递，最后一条待投递
+	for _, msg := range msgs[:3] {
+		msg.Status = StatusPublished
+		if err := store.update(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := store.PurgePublished(ctx, time.Now().Add(time.Minute), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("PurgePublished() = %d, want 3", n)
+	}
+	if pending, err := store.pending(ctx, time.Now(), 10); err != nil || len(pending) != 1 || pending[0].Id != msgs[3].Id {
+		t.Errorf("pending() after purge = %+v, %v, want the unpublished message", pending, err)
+	}
+}
+
+func TestRelay_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm)
+	store := NewStore(conn)
+
+	// 聚合 a: 1, 3；聚合 b: 2, 4
+	var events []Event
+	for _, id := range []string{"a", "b", "a", "b"} {
+		events = append(events, Event{AggregateType: "category", AggregateId: id, Type: "category.updated"})
+	}
+	if err := conn.Gorm.Transaction(func(tx *gorm.DB) error {
+		return store.Write(db.ContextWithTx(ctx, tx), events...)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := &fakePublisher{fail: map[int64]bool{1: true}}
+	relay := NewRelay(store, publisher, Config{BatchSize: 10, RetryInterval: 1, MaxRetryInterval: 60})
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		advance time.Duration // 本轮前时钟前进
+		fail    bool          // 事件 1 是否投递失败
+		want    int
+	}{
+		{"失败的聚合暂停，其他聚合继续", 0, true, 2},
+		{"未到重试时间", 0, false, 0},
+		{"重试成功后按顺序投递", 2 * time.Second, false, 2},
+		{"无待投递", 0, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			publisher.fail[1] = tt.fail
+			got, err := relay.RunOnce(ctx)
+			if err != nil || got != tt.want {
+				t.Errorf("RunOnce() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+
+	want := []int64{2, 4, 1, 3}
+	if len(publisher.published) != len(want) {
+		t.Fatalf("published = %v, want %v", publisher.published, want)
+	}
+	for i := range want {
+		if publisher.published[i] != want[i] {
+			t.Errorf("published = %v, want %v", publisher.published, want)
+		}
+	}
+}
+
+func TestRelay_RunOnce_StuckAggregate(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm)
+	store := NewStore(conn)
+	write := func(id string, n int) {
+		t.Helper()
+		events := make([]Event, n)
+		for i := range events {
+			events[i] = Event{AggregateType: "category", AggregateId: id, Type: "category.updated"}
+		}
+		if err := conn.Gorm.Transaction(func(tx *gorm.DB) error {
+			return store.Write(db.ContextWithTx(ctx, tx), events...)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 聚合 a 的首条（ID 1）持续失败，且待投递条数超过批次大小
+	write("a", 5)
+	write("b", 2)
+	publisher := &fakePublisher{fail: map[int64]bool{1: true}}
+	relay := NewRelay(store, publisher, Config{BatchSize: 3, RetryInterval: 60, MaxRetryInterval: 600})
+	now := time.Now()
+	relay.now = func() time.Time { return now }
+
+	if got, err := relay.RunOnce(ctx); err != nil || got != 0 {
+		t.Fatalf("RunOnce() = %d, %v, want 0 (batch filled by a)", got, err)
+	}
+	if got, err := relay.RunOnce(ctx); err != nil || got != 2 {
+		t.Errorf("RunOnce() = %d, %v, want 2 (b not blocked by a)", got, err)
+	}
+	write("b", 1)
+	now = time.Now()
+	if got, err := relay.RunOnce(ctx); err != nil || got != 1 {
+		t.Errorf("RunOnce() after new event = %d, %v, want 1", got, err)
+	}
+	if want := []int64{6, 7, 8}; len(publisher.published) != len(want) || publisher.published[2] != want[2] {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+
+	// 重试成功后聚合 a 按顺序继续
+	now = now.Add(2 * time.Minute)
+	publisher.fail[1] = false
+	if got, err := relay.RunOnce(ctx); err != nil || got != 3 {
+		t.Errorf("RunOnce() after retry = %d, %v, want 3", got, err)
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	relay := NewRelay(nil, nil, Config{RetryInterval: 1, MaxRetryInterval: 10})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{10, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRelay_Lease(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm)
+	store := NewStore(conn)
+	write := func(n int) {
+		t.Helper()
+		events := make([]Event, n)
+		for i := range events {
+			events[i] = Event{AggregateType: "category", AggregateId: "1", Type: "category.updated"}
+		}
+		if err := conn.Gorm.Transaction(func(tx *gorm.DB) error {
+			return store.Write(db.ContextWithTx(ctx, tx), events...)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 两个实例使用同一发件箱及同一数据库锁
+	locker := lock.NewDB(conn)
+	c := Config{BatchSize: 10, RetryInterval: 1, MaxRetryInterval: 60}
+	first, second := &fakePublisher{}, &fakePublisher{}
+	a := NewRelay(store, first, c, WithLocker(locker, time.Minute))
+	b := NewRelay(store, second, c, WithLocker(locker, time.Minute))
+
+	write(2)
+	if got, err := a.poll(ctx); err != nil || got != 2 {
+		t.Fatalf("a.poll() = %d, %v, want 2", got, err)
+	}
+	write(1)
+	if got, err := b.poll(ctx); err != nil || got != 0 {
+		t.Errorf("b.poll() while a holds the lease = %d, %v, want 0", got, err)
+	}
+	if got, err := a.poll(ctx); err != nil || got != 1 {
+		t.Errorf("a.poll() = %d, %v, want 1", got, err)
+	}
+
+	// a 停止时释放租约，b 接替投递
+	a.Stop()
+	write(1)
+	if got, err := b.poll(ctx); err != nil || got != 1 {
+		t.Errorf("b.poll() after a stopped = %d, %v, want 1", got, err)
+	}
+	b.Stop()
+	if len(first.published) != 3 || len(second.published) != 1 || second.published[0] != 4 {
+		t.Errorf("published = %v / %v, want [1 2 3] / [4]", first.published, second.published)
+	}
+}
@@ -0,0 +1,250 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"idrm/pkg/lock"
+	"idrm/pkg/telemetry/trace"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Publisher 投递目标
+// ctx 延续写入时的链路追踪上下文，实现方需将其注入消息头
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// NewPublisher 按配置创建投递目标（bus 时投递到传入的进程内总线）
+func NewPublisher(c Config, bus *Bus) (Publisher, error) {
+	switch c.Publisher {
+	case "kafka":
+		return NewKafkaPublisher(c.Kafka)
+	case "bus", "":
+		return bus, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (expected bus or kafka)", ErrUnknownPublisher, c.Publisher)
+	}
+}
+
+// relayLockKey 投递租约的锁名称，多实例时只有持有者投递
+const relayLockKey = "outbox:relay"
+
+// Relay 轮询发件箱并投递
+type Relay struct {
+	store     *Store
+	publisher Publisher
+	config    Config
+	now       func() time.Time
+
+	// 分布式锁，nil 表示单实例：每次轮询直接投递
+	locker    lock.Locker
+	lockTTL   time.Duration
+	lease     lock.Lease // 只在轮询协程中访问（Stop 等待轮询结束后释放）
+	renewedAt time.Time
+
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// RelayOption Relay 选项
+type RelayOption func(r *Relay)
+
+// WithLocker 多实例部署时使用分布式锁：只有持有投递租约的实例投递，保证聚合内顺序；ttl 为租约时长
+func WithLocker(locker lock.Locker, ttl time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.locker = locker
+		r.lockTTL = ttl
+	}
+}
+
+// NewRelay 创建 Relay
+func NewRelay(store *Store, publisher Publisher, c Config, opts ...RelayOption) *Relay {
+	r := &Relay{
+		store:     store,
+		publisher: publisher,
+		config:    c,
+		now:       time.Now,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start 启动后台轮询
+func (r *Relay) Start() {
+	r.started = true
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(time.Duration(r.config.PollInterval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if _, err := r.poll(context.Background()); err != nil {
+					logx.Errorf("发件箱投递失败: %v", err)
+				}
+			}
+		}
+	}()
+	logx.Infof("发件箱 Relay 已启动 (Publisher: %s)", r.config.Publisher)
+}
+
+// Stop 停止轮询，等待当前批次完成后释放投递租约并关闭投递目标
+func (r *Relay) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+		if r.started {
+			<-r.done
+		}
+		if r.lease != nil {
+			if err := r.lease.Release(context.Background()); err != nil {
+				logx.Errorf("释放发件箱投递锁失败: %v", err)
+			}
+			r.lease = nil
+		}
+		if closer, ok := r.publisher.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logx.Errorf("关闭发件箱投递目标失败: %v", err)
+			}
+		}
+	})
+}
+
+// poll 持有投递租约时投递一批待投递记录（未配置分布式锁时直接投递），未持有时返回 0
+func (r *Relay) poll(ctx context.Context) (int, error) {
+	if r.locker != nil && !r.hold(ctx) {
+		return 0, nil
+	}
+	return r.RunOnce(ctx)
+}
+
+// hold 未持有时尝试获取投递租约，持有时每 TTL/3 续期；续期失败时放弃租约，返回是否持有
+func (r *Relay) hold(ctx context.Context) bool {
+	if r.lease == nil {
+		l, err := r.locker.Acquire(ctx, relayLockKey, r.lockTTL)
+		if err != nil {
+			if !errors.Is(err, lock.ErrNotAcquired) {
+				logx.Errorf("获取发件箱投递锁失败: %v", err)
+			}
+			return false
+		}
+		r.lease, r.renewedAt = l, time.Now()
+		logx.Infof("本实例开始投递发件箱 (token=%d)", l.Token())
+		return true
+	}
+	if time.Since(r.renewedAt) < r.lockTTL/3 {
+		return true
+	}
+	if err := r.lease.Renew(ctx, r.lockTTL); err != nil {
+		logx.Errorf("发件箱投递锁续期失败，停止投递: %v", err)
+		r.lease = nil
+		return false
+	}
+	r.renewedAt = time.Now()
+	return true
+}
+
+// RunOnce 投递一批待投递记录，返回投递成功的条数（不检查投递租约）
+// 同一聚合中某条投递失败或未到重试时间时，跳过该聚合的后续记录，保证聚合内顺序
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	now := r.now()
+	msgs, err := r.store.pending(ctx, now, r.config.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("load pending: %w", err)
+	}
+
+	blocked := make(map[string]bool)
+	published := 0
+	for _, msg := range msgs {
+		key := msg.Key()
+		if blocked[key] {
+			continue
+		}
+		if msg.NextAttemptAt.After(now) {
+			blocked[key] = true
+			continue
+		}
+
+		if err := r.publish(ctx, msg); err != nil {
+			blocked[key] = true
+			metricMessages.Inc(resultFailed)
+			logx.WithContext(ctx).Errorf("发件箱投递失败: id=%d, key=%s, attempts=%d, err=%v", msg.Id, key, msg.Attempts+1, err)
+
+			msg.Attempts++
+			msg.LastError = truncate(err.Error(), maxErrorLength)
+			msg.NextAttemptAt = now.Add(r.backoff(msg.Attempts))
+			if err := r.store.update(ctx, msg); err != nil {
+				return published, fmt.Errorf("record failure of %d: %w", msg.Id, err)
+			}
+			continue
+		}
+
+		metricMessages.Inc(resultPublished)
+		published++
+		msg.Status = StatusPublished
+		msg.LastError = ""
+		// 标记失败时下次轮询会重复投递（至少一次）
+		if err := r.store.update(ctx, msg); err != nil {
+			return published, fmt.Errorf("mark %d published: %w", msg.Id, err)
+		}
+	}
+	return published, nil
+}
+
+// publish 延续写入时的链路追踪上下文投递
+func (r *Relay) publish(ctx context.Context, msg *Message) (err error) {
+	carrier := propagation.MapCarrier{}
+	if msg.Headers != "" {
+		if err := json.Unmarshal([]byte(msg.Headers), &carrier); err != nil {
+			logx.WithContext(ctx).Errorf("发件箱消息头解析失败: id=%d, err=%v", msg.Id, err)
+		}
+	}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
+
+	ctx, span := trace.StartProducer(ctx, "outbox.publish", trace.WithAttributes(
+		"messaging.message.id", msg.Id,
+		"messaging.event_type", msg.EventType,
+		"messaging.key", msg.Key(),
+	)...)
+	defer func() { trace.End(span, err) }()
+
+	return r.publisher.Publish(ctx, msg)
+}
+
+// backoff 第 attempts 次失败后的重试间隔
+func (r *Relay) backoff(attempts int) time.Duration {
+	interval := time.Duration(r.config.RetryInterval) * time.Second
+	max := time.Duration(r.config.MaxRetryInterval) * time.Second
+	for i := 1; i < attempts && interval < max; i++ {
+		interval *= 2
+	}
+	if interval > max {
+		interval = max
+	}
+	return interval
+}
+
+// truncate 按字符截断
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -0,0 +1,151 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+	"idrm/pkg/db/repo"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"gorm.io/gorm"
+)
+
+// maxErrorLength last_error 列长度
+const maxErrorLength = 500
+
+// pendingQuery 到达投递时间、且所属聚合中没有更早的未到重试时间的记录（失败的聚合不占用批次）
+const pendingQuery = `SELECT o.id, o.aggregate_type, o.aggregate_id, o.event_type, o.payload, o.headers, o.status,
+	o.attempts, o.last_error, o.next_attempt_at, o.created_at, o.updated_at
+FROM outbox o
+WHERE o.status = ? AND o.next_attempt_at <= ? AND NOT EXISTS (
+	SELECT 1 FROM outbox b
+	WHERE b.status = ? AND b.aggregate_type = o.aggregate_type AND b.aggregate_id = o.aggregate_id
+		AND b.id < o.id AND b.next_attempt_at > ?
+)
+ORDER BY o.id
+LIMIT ?`
+
+var _ Writer = (*Store)(nil)
+
+// Store 发件箱存储
+type Store struct {
+	// primary Relay 使用的仓储：读写均使用主库，避免从副本读到已投递的记录
+	primary repo.Repository[Message]
+	conn    sqlx.SqlConn // 主库连接，查询待投递记录
+	dialect dialect.Dialect
+}
+
+// NewStore 创建发件箱存储
+// 事务内写入按事务对象类型选择 gorm 或 sqlx，与业务模型使用的 ORM 无关
+func NewStore(conn *db.Conn) *Store {
+	return &Store{
+		primary: repo.NewSqlxDialect[Message](conn.SqlConn(), conn.SqlConn(), conn.Dialect()),
+		conn:    conn.SqlConn(),
+		dialect: conn.Dialect(),
+	}
+}
+
+// Write 在 context 中的事务内写入事件，不在事务中时返回 ErrNoTransaction
+func (s *Store) Write(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, ok := db.TxFromContext(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+	var r repo.Repository[Message]
+	switch conn := tx.(type) {
+	case *gorm.DB:
+		r = repo.NewGorm[Message](conn)
+	case sqlx.SqlConn, sqlx.Session:
+		r = s.primary.WithTx(conn)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedTx, tx)
+	}
+
+	msgs, err := newMessages(ctx, events, time.Now())
+	if err != nil {
+		return err
+	}
+	return r.BatchInsert(ctx, msgs)
+}
+
+// pending 按 ID 顺序获取 now 时可投递的记录
+// 跳过所属聚合中有更早的记录未到重试时间的记录，持续失败的聚合不会占满批次而阻塞其他聚合
+func (s *Store) pending(ctx context.Context, now time.Time, limit int) ([]*Message, error) {
+	var msgs []*Message
+	err := s.conn.QueryRowsCtx(ctx, &msgs, s.dialect.Rebind(pendingQuery), StatusPending, now, StatusPending, now, limit)
+	return msgs, err
+}
+
+// update 更新投递状态
+func (s *Store) update(ctx context.Context, msg *Message) error {
+	return s.primary.Update(ctx, msg)
+}
+
+// PurgePublished 分批删除 before 之前已投递的记录（每批 batchSize 条，单独提交），返回删除条数
+// 待投递的记录不删除
+func (s *Store) PurgePublished(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	var total int64
+	for {
+		msgs, err := s.primary.Find(ctx, repo.Query{
+			Conds:    []repo.Cond{repo.Eq("status", StatusPublished), repo.Lt("updated_at", before)},
+			Page:     1,
+			PageSize: batchSize,
+		})
+		if err != nil || len(msgs) == 0 {
+			return total, err
+		}
+
+		ids := make([]int64, len(msgs))
+		for i, msg := range msgs {
+			ids[i] = msg.Id
+		}
+		if err := s.primary.BatchDelete(ctx, ids); err != nil {
+			return total, err
+		}
+		total += int64(len(ids))
+		if len(msgs) < batchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// newMessages 编码事件，消息头记录当前链路追踪上下文，由 Relay 投递时延续
+func newMessages(ctx context.Context, events []Event, now time.Time) ([]*Message, error) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	headers, err := json.Marshal(carrier)
+	if err != nil {
+		return nil, fmt.Errorf("outbox: encode headers: %w", err)
+	}
+
+	msgs := make([]*Message, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("outbox: encode %s payload: %w", e.Type, err)
+		}
+		msgs[i] = &Message{
+			AggregateType: e.AggregateType,
+			AggregateId:   e.AggregateId,
+			EventType:     e.Type,
+			Payload:       string(payload),
+			Headers:       string(headers),
+			Status:        StatusPending,
+			NextAttemptAt: now,
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,98 @@
+package preview
+
+import (
+	"fmt"
+	"strings"
+
+	"idrm/pkg/db/dialect"
+	"idrm/pkg/sqlparse"
+)
+
+// allowedFunctions 查询中允许调用的函数（MySQL，大写）：聚合、窗口、字符串、数值、日期及空值处理函数；
+// 休眠、文件读取、加锁等函数及限定名的函数（存储函数）不在其中
+var allowedFunctions = map[string]bool{
+	// 聚合及窗口
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true, "GROUP_CONCAT": true,
+	"STD": true, "STDDEV": true, "STDDEV_POP": true, "STDDEV_SAMP": true, "VARIANCE": true, "VAR_POP": true, "VAR_SAMP": true,
+	"BIT_AND": true, "BIT_OR": true, "BIT_XOR": true,
+	"ROW_NUMBER": true, "RANK": true, "DENSE_RANK": true, "PERCENT_RANK": true, "CUME_DIST": true, "NTILE": true,
+	"LAG": true, "LEAD": true, "FIRST_VALUE": true, "LAST_VALUE": true, "NTH_VALUE": true,
+	// 空值及条件
+	"COALESCE": true, "IFNULL": true, "NULLIF": true, "IF": true, "ISNULL": true, "GREATEST": true, "LEAST": true,
+	// 字符串
+	"CONCAT": true, "CONCAT_WS": true, "UPPER": true, "LOWER": true, "UCASE": true, "LCASE": true,
+	"SUBSTRING": true, "SUBSTR": true, "MID": true, "SUBSTRING_INDEX": true, "LEFT": true, "RIGHT": true,
+	"TRIM": true, "LTRIM": true, "RTRIM": true, "LENGTH": true, "CHAR_LENGTH": true, "CHARACTER_LENGTH": true,
+	"REPLACE": true, "LPAD": true, "RPAD": true, "LOCATE": true, "INSTR": true, "POSITION": true,
+	"REVERSE": true, "FORMAT": true, "FIELD": true, "FIND_IN_SET": true, "STRCMP": true,
+	"JSON_EXTRACT": true, "JSON_UNQUOTE": true,
+	// 数值
+	"ABS": true, "CEIL": true, "CEILING": true, "FLOOR": true, "ROUND": true, "TRUNCATE": true, "MOD": true,
+	"SIGN": true, "POW": true, "POWER": true, "SQRT": true,
+	// 日期
+	"NOW": true, "CURDATE": true, "CURTIME": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"DATE": true, "TIME": true, "YEAR": true, "QUARTER": true, "MONTH": true, "WEEK": true, "DAY": true, "DAYOFMONTH": true,
+	"DAYOFWEEK": true, "DAYOFYEAR": true, "HOUR": true, "MINUTE": true, "SECOND": true, "LAST_DAY": true, "EXTRACT": true,
+	"DATE_FORMAT": true, "STR_TO_DATE": true, "DATE_ADD": true, "DATE_SUB": true, "ADDDATE": true, "SUBDATE": true,
+	"DATEDIFF": true, "TIMESTAMPDIFF": true, "UNIX_TIMESTAMP": true, "FROM_UNIXTIME": true,
+}
+
+// CheckSelect 检查查询为单条只读 SELECT（或 WITH ... SELECT）语句
+//
+// 只支持 MySQL：按 pkg/sqlparse 解析，只接受其语法内的单条查询（DML/DDL、SELECT ... INTO 及锁定读被拒绝），
+// 调用的函数须在 allowedFunctions 中；不允许注释（/*! */ 会被 MySQL 执行）及反斜杠
+// （字符串边界与 sql_mode NO_BACKSLASH_ESCAPES 无关）。
+// 其他方言没有可用的解析器，一律返回 ErrNotSelect。
+func CheckSelect(d dialect.Dialect, query string) error {
+	if d != dialect.MySQL {
+		return fmt.Errorf("%w: only MySQL queries can be checked, got %s", ErrNotSelect, d)
+	}
+	if err := scan(query); err != nil {
+		return err
+	}
+	result, err := sqlparse.Parse(query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotSelect, err)
+	}
+	for _, f := range result.Functions {
+		if !allowedFunctions[f] {
+			return fmt.Errorf("%w: function %s is not allowed", ErrNotSelect, f)
+		}
+	}
+	return nil
+}
+
+// scan 按 MySQL 引号规则（连续两个引号为转义）检查注释及反斜杠，引号内的内容不检查注释
+func scan(query string) error {
+	if strings.ContainsRune(query, '\\') {
+		return fmt.Errorf("%w: backslashes are not allowed", ErrNotSelect)
+	}
+	var quote rune // 当前所在引号，0 表示不在引号内
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			if r == quote {
+				if i+1 < len(runes) && runes[i+1] == quote {
+					i++
+					continue
+				}
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-',
+			r == '/' && i+1 < len(runes) && runes[i+1] == '*',
+			r == '#':
+			return fmt.Errorf("%w: comments are not allowed", ErrNotSelect)
+		}
+	}
+	if quote != 0 {
+		return fmt.Errorf("%w: unterminated quote", ErrNotSelect)
+	}
+	return nil
+}
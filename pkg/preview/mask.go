@@ -0,0 +1,183 @@
+package preview
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"idrm/pkg/validator"
+)
+
+// 脱敏方式
+const (
+	MaskFull     = "full"     // 全部替换为 ******
+	MaskMobile   = "mobile"   // 保留前 3 位及后 4 位：138****5678
+	MaskIDCard   = "idcard"   // 保留前 3 位及后 4 位：110***********1234
+	MaskEmail    = "email"    // 保留首字符及域名：z***@example.com
+	MaskName     = "name"     // 保留首字：张**
+	MaskBankCard = "bankcard" // 保留后 4 位
+	MaskAddress  = "address"  // 保留前 6 个字符
+)
+
+// fullMask 全部脱敏时的替换值
+const fullMask = "******"
+
+// Rule 脱敏规则：列名匹配 Columns 中任一模式（filepath.Match 规则，不区分大小写）时按 Type 脱敏
+type Rule struct {
+	Columns []string
+	Type    string `json:",options=full|mobile|idcard|email|name|bankcard|address"`
+}
+
+// DefaultRules 未配置 Masking 时使用的规则
+var DefaultRules = []Rule{
+	{Columns: []string{"*password*", "*passwd*", "*secret*", "*token*", "*salt*"}, Type: MaskFull},
+	{Columns: []string{"*mobile*", "*phone*", "*tel"}, Type: MaskMobile},
+	{Columns: []string{"*id_card*", "*idcard*", "*id_no", "*id_number"}, Type: MaskIDCard},
+	{Columns: []string{"*email*"}, Type: MaskEmail},
+	{Columns: []string{"*bank_card*", "*bankcard*", "*card_no"}, Type: MaskBankCard},
+	{Columns: []string{"*address*", "*addr"}, Type: MaskAddress},
+	{Columns: []string{"real_name", "*_real_name", "full_name", "user_name", "username", "contact_name"}, Type: MaskName},
+}
+
+// Sources 输出列名 → 计算该列引用的源列名（键不区分大小写），
+// 源列匹配脱敏规则时该输出列同样脱敏，避免通过别名或表达式绕过列名规则
+type Sources map[string][]string
+
+// Masker 按列名规则脱敏，可选按值识别手机号、身份证号
+type Masker struct {
+	rules        []Rule
+	detectValues bool
+}
+
+// NewMasker 创建脱敏器，rules 为空时使用 DefaultRules；规则类型或模式非法时返回错误
+func NewMasker(rules []Rule, detectValues bool) (*Masker, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	for _, r := range rules {
+		if maskFuncs[r.Type] == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownMask, r.Type)
+		}
+		for _, p := range r.Columns {
+			if _, err := filepath.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("mask column pattern %q: %w", p, err)
+			}
+		}
+	}
+	return &Masker{rules: rules, detectValues: detectValues}, nil
+}
+
+// Apply 对结果脱敏（原地修改），返回按列名规则脱敏的列；
+// 列名不匹配而引用的源列匹配时全部脱敏（表达式结果的格式不确定，不按源列的方式部分保留）
+func (m *Masker) Apply(r *Result, sources Sources) []string {
+	lower := make(map[string][]string, len(sources))
+	for k, v := range sources {
+		lower[strings.ToLower(k)] = v
+	}
+	masked := []string{}
+	types := make([]string, len(r.Columns))
+	for i, c := range r.Columns {
+		types[i] = m.typeOf(c)
+		for _, src := range lower[strings.ToLower(c)] {
+			if types[i] != "" {
+				break
+			}
+			if m.typeOf(src) != "" {
+				types[i] = MaskFull
+			}
+		}
+		if types[i] != "" {
+			masked = append(masked, c)
+		}
+	}
+	for _, row := range r.Rows {
+		for i, v := range row {
+			if v == nil {
+				continue
+			}
+			switch {
+			case types[i] != "":
+				row[i] = maskFuncs[types[i]](toString(v))
+			case m.detectValues:
+				if s, ok := v.(string); ok {
+					row[i] = detect(s)
+				}
+			}
+		}
+	}
+	return masked
+}
+
+// typeOf 列名匹配的第一条规则的脱敏方式，不匹配时返回空字符串
+func (m *Masker) typeOf(column string) string {
+	column = strings.ToLower(column)
+	for _, r := range m.rules {
+		for _, p := range r.Columns {
+			if ok, _ := filepath.Match(strings.ToLower(p), column); ok {
+				return r.Type
+			}
+		}
+	}
+	return ""
+}
+
+// detect 值为手机号或身份证号时脱敏
+func detect(s string) string {
+	switch {
+	case len(s) == 11 && validator.IsMobile(s):
+		return maskMobile(s)
+	case (len(s) == 18 || len(s) == 15) && validator.IsIDCard(s):
+		return maskIDCard(s)
+	}
+	return s
+}
+
+var maskFuncs = map[string]func(string) string{
+	MaskFull:     func(string) string { return fullMask },
+	MaskMobile:   maskMobile,
+	MaskIDCard:   maskIDCard,
+	MaskEmail:    maskEmail,
+	MaskName:     func(s string) string { return keep(s, 1, 0) },
+	MaskBankCard: func(s string) string { return keep(s, 0, 4) },
+	MaskAddress:  func(s string) string { return keep(s, 6, 0) },
+}
+
+func maskMobile(s string) string { return keep(s, 3, 4) }
+func maskIDCard(s string) string { return keep(s, 3, 4) }
+
+func maskEmail(s string) string {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 {
+		return fullMask
+	}
+	return keep(s[:at], 1, 0) + s[at:]
+}
+
+// keep 保留前 head 个及后 tail 个字符，其余替换为 *；值过短时减少保留的字符，至少替换一个字符
+func keep(s string, head, tail int) string {
+	runes := []rune(s)
+	n := len(runes)
+	if n == 0 {
+		return s
+	}
+	for head+tail >= n {
+		if tail > 0 {
+			tail--
+		} else {
+			head--
+		}
+	}
+	return string(runes[:head]) + strings.Repeat("*", n-head-tail) + string(runes[n-tail:])
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case time.Time:
+		return x.Format(time.DateTime)
+	default:
+		return fmt.Sprint(x)
+	}
+}
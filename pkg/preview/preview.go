@@ -0,0 +1,211 @@
+// Package preview 数据预览：对源数据库执行受限的只读查询，返回脱敏后的样例数据
+//
+// 每次预览：
+//   - Query 的查询须通过 CheckSelect（MySQL 单条 SELECT，函数白名单，无注释、写操作、锁定读）；
+//     Table 的查询由列名及表名（引用后）生成，不经过 CheckSelect
+//   - 外层包裹 LIMIT，行数不超过 MaxRows
+//   - 在只读事务中执行（SQLite 另开启 query_only），回滚结束
+//   - 语句超时：context 超时，PostgreSQL 另设置 statement_timeout，MySQL 使用 MAX_EXECUTION_TIME 提示
+//   - 按列名规则脱敏（Masking），可选按值识别手机号、身份证号
+package preview
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+)
+
+var (
+	ErrNotSelect   = errors.New("preview: only a single SELECT statement is allowed")
+	ErrTimeout     = errors.New("preview: query timed out")
+	ErrUnknownMask = errors.New("preview: unknown mask type")
+)
+
+// Config 数据预览配置
+type Config struct {
+	DefaultRows  int    `json:",default=20"`   // 未指定行数时返回的行数
+	MaxRows      int    `json:",default=100"`  // 最多返回的行数
+	Timeout      int    `json:",default=10"`   // 语句超时（秒）
+	DetectValues bool   `json:",default=true"` // 未匹配规则的列按值识别手机号、身份证号并脱敏
+	Masking      []Rule `json:",optional"`     // 脱敏规则，为空时使用 DefaultRules
+}
+
+// Result 预览结果
+type Result struct {
+	Columns []string
+	Rows    [][]interface{} // 字符串、数值、布尔或 nil；时间格式化为 2006-01-02 15:04:05
+	Masked  []string        // 按列名规则脱敏的列
+	HasMore bool            // 源中还有更多行
+	Elapsed time.Duration
+}
+
+// Previewer 执行数据预览，并发安全
+type Previewer struct {
+	c      Config
+	masker *Masker
+}
+
+// New 按配置创建 Previewer，脱敏规则非法时返回错误
+func New(c Config) (*Previewer, error) {
+	masker, err := NewMasker(c.Masking, c.DetectValues)
+	if err != nil {
+		return nil, err
+	}
+	if c.MaxRows <= 0 {
+		c.MaxRows = 100
+	}
+	if c.DefaultRows <= 0 || c.DefaultRows > c.MaxRows {
+		c.DefaultRows = c.MaxRows
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10
+	}
+	return &Previewer{c: c, masker: masker}, nil
+}
+
+// Table 预览表或视图的指定列，columns 为空时查询全部列；sources 为视图列的来源（可为 nil）
+func (p *Previewer) Table(ctx context.Context, conn *db.Conn, schema, table string, columns []string, sources Sources, limit int) (*Result, error) {
+	d := conn.Dialect()
+	cols := "*"
+	if len(columns) > 0 {
+		quoted := make([]string, len(columns))
+		for i, c := range columns {
+			quoted[i] = quote(d, c)
+		}
+		cols = strings.Join(quoted, ", ")
+	}
+	name := quote(d, table)
+	if schema != "" {
+		name = quote(d, schema) + "." + name
+	}
+	return p.run(ctx, conn, "SELECT "+cols+" FROM "+name, sources, limit)
+}
+
+// Query 执行只读查询（须通过 CheckSelect，即只支持 MySQL 源），sources 为输出列的来源（可为 nil）；
+// limit 不大于 0 时为 DefaultRows，超过 MaxRows 时为 MaxRows
+func (p *Previewer) Query(ctx context.Context, conn *db.Conn, query string, sources Sources, limit int) (*Result, error) {
+	if err := CheckSelect(conn.Dialect(), query); err != nil {
+		return nil, err
+	}
+	return p.run(ctx, conn, query, sources, limit)
+}
+
+// run 外层包裹 LIMIT 后执行并脱敏
+func (p *Previewer) run(ctx context.Context, conn *db.Conn, query string, sources Sources, limit int) (*Result, error) {
+	d := conn.Dialect()
+	if limit <= 0 {
+		limit = p.c.DefaultRows
+	}
+	limit = min(limit, p.c.MaxRows)
+
+	timeout := time.Duration(p.c.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := p.query(ctx, conn, wrap(d, query, limit+1, timeout), limit)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		return nil, err
+	}
+	result.Elapsed = time.Since(start)
+	result.Masked = p.masker.Apply(result, sources)
+	return result, nil
+}
+
+// query 在独占连接的只读事务中执行，多取一行用于判断 HasMore
+func (p *Previewer) query(ctx context.Context, conn *db.Conn, query string, limit int) (*Result, error) {
+	c, err := conn.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	d := conn.Dialect()
+	if d == dialect.SQLite {
+		// SQLite 的只读事务选项不生效，使用连接级 query_only
+		if _, err := c.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+			return nil, err
+		}
+		defer c.ExecContext(context.Background(), "PRAGMA query_only = OFF")
+	}
+	tx, err := c.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if d == dialect.Postgres {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", p.c.Timeout*1000)); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		if len(result.Rows) == limit {
+			result.HasMore = true
+			break
+		}
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = normalize(v)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
+
+// wrap 外层包裹 LIMIT，MySQL 添加执行时间提示
+func wrap(d dialect.Dialect, query string, limit int, timeout time.Duration) string {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	hint := ""
+	if d == dialect.MySQL {
+		hint = fmt.Sprintf("/*+ MAX_EXECUTION_TIME(%d) */ ", timeout.Milliseconds())
+	}
+	return fmt.Sprintf("SELECT %s* FROM (%s) preview_q LIMIT %d", hint, query, limit)
+}
+
+// quote 引用标识符
+func quote(d dialect.Dialect, name string) string {
+	if d == dialect.MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// normalize 驱动返回的值转换为可 JSON 编码的值
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.Format(time.DateTime)
+	default:
+		return v
+	}
+}
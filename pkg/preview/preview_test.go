@@ -0,0 +1,147 @@
+package preview
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"idrm/migrations"
+	"idrm/pkg/db"
+	"idrm/pkg/db/dialect"
+	"idrm/pkg/testkit"
+)
+
+func TestCheckSelect(t *testing.T) {
+	tests := []struct {
+		dialect dialect.Dialect
+		query   string
+		ok      bool
+	}{
+		{dialect.MySQL, "SELECT id, name FROM users WHERE name = 'delete me';", true},
+		{dialect.MySQL, "WITH t AS (SELECT 1 AS `update`) SELECT * FROM t", true},
+		{dialect.MySQL, "select `drop` from t where a = 'it''s' and b = \"--\"", true},
+		{dialect.MySQL, "SELECT c.id, UPPER(c.name), COUNT(*), IFNULL(MAX(o.amount), 0) FROM c JOIN o ON o.cid = c.id GROUP BY c.id, c.name", true},
+		{dialect.MySQL, "DELETE FROM users", false},
+		{dialect.MySQL, "SELECT 1; DROP TABLE users", false},
+		{dialect.MySQL, "SELECT 1 -- comment", false},
+		{dialect.MySQL, "SELECT 1 # comment", false},
+		{dialect.MySQL, "SELECT 1 /*!, SLEEP(100) */", false},
+		{dialect.MySQL, "SELECT * FROM users FOR UPDATE", false},
+		{dialect.MySQL, "SELECT * FROM users LOCK IN SHARE MODE", false},
+		{dialect.MySQL, "SELECT * INTO OUTFILE '/tmp/x' FROM users", false},
+		{dialect.MySQL, "SELECT 'unterminated", false},
+		// 函数白名单：休眠、文件读取、加锁及存储函数
+		{dialect.MySQL, "SELECT SLEEP(100)", false},
+		{dialect.MySQL, "SELECT a FROM t WHERE EXISTS (SELECT BENCHMARK(1000000000, MD5('x')))", false},
+		{dialect.MySQL, "SELECT LOAD_FILE('/etc/passwd')", false},
+		{dialect.MySQL, "SELECT GET_LOCK('x', 100)", false},
+		{dialect.MySQL, "SELECT shop.f(1)", false},
+		// 反斜杠转义引号（NO_BACKSLASH_ESCAPES 下边界不同），一律拒绝
+		{dialect.MySQL, `SELECT 'x\' , '; DROP TABLE t; -- '`, false},
+		{dialect.MySQL, `SELECT '\'', SLEEP(100), '\''`, false},
+		// PostgreSQL、SQLite 没有解析器，一律拒绝：E 字符串、U& 字符串及美元引用
+		{dialect.Postgres, "SELECT 1", false},
+		{dialect.Postgres, `SELECT E'\'', pg_sleep(100), E'\''`, false},
+		{dialect.Postgres, `SELECT e'\'', pg_read_file('/etc/passwd'), e'\''`, false},
+		{dialect.Postgres, `SELECT U&'\0027', dblink('host=x', 'DROP TABLE t'), U&'\0027'`, false},
+		{dialect.Postgres, "SELECT $$'$$, pg_sleep(100), $$'$$", false},
+		{dialect.Postgres, "SELECT $q$'$q$; DROP TABLE t; --'", false},
+		{dialect.SQLite, "SELECT id FROM users", false},
+	}
+	for _, tt := range tests {
+		err := CheckSelect(tt.dialect, tt.query)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckSelect(%s, %q) error = %v, want ok %t", tt.dialect, tt.query, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrNotSelect) {
+			t.Errorf("CheckSelect(%q) error = %v, want ErrNotSelect", tt.query, err)
+		}
+	}
+}
+
+func TestKeep(t *testing.T) {
+	tests := []struct {
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{maskMobile, "13812345678", "138****5678"},
+		{maskIDCard, "11010519491231002X", "110***********002X"},
+		{maskEmail, "zhangsan@example.com", "z*******@example.com"},
+		{maskFuncs[MaskName], "张三丰", "张**"},
+		{maskFuncs[MaskName], "张", "*"},
+		{maskFuncs[MaskBankCard], "1234", "*234"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.in); got != tt.want {
+			t.Errorf("mask(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPreviewer_Table(t *testing.T) {
+	ctx := context.Background()
+	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMSqlx)
+	testkit.Exec(t, conn, "CREATE TABLE person (id integer, real_name text, mobile text, remark text)")
+	for _, row := range [][]interface{}{
+		{1, "张三", "13812345678", "联系 13900001111"},
+		{2, "李四", "13987654321", "13900001111"},
+		{3, "王五", nil, "-"},
+	} {
+		testkit.Exec(t, conn, "INSERT INTO person VALUES (?, ?, ?, ?)", row...)
+	}
+
+	p, err := New(Config{DefaultRows: 2, MaxRows: 10, Timeout: 5, DetectValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := p.Table(ctx, conn, "main", "person", []string{"id", "real_name", "mobile", "remark"}, nil, 0)
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+	if !r.HasMore || len(r.Rows) != 2 {
+		t.Errorf("Table() rows = %d, has more %t, want 2 rows and more", len(r.Rows), r.HasMore)
+	}
+	want := [][]interface{}{
+		{int64(1), "张*", "138****5678", "联系 13900001111"},
+		{int64(2), "李*", "139****4321", "139****1111"},
+	}
+	if !reflect.DeepEqual(r.Rows, want) {
+		t.Errorf("Table() rows = %v, want %v", r.Rows, want)
+	}
+	if !reflect.DeepEqual(r.Masked, []string{"real_name", "mobile"}) {
+		t.Errorf("Table() masked = %v, want real_name, mobile", r.Masked)
+	}
+
+	r, err = p.Table(ctx, conn, "main", "person", nil, nil, 100)
+	if err != nil {
+		t.Fatalf("Table(all columns) error = %v", err)
+	}
+	if len(r.Rows) != 3 || r.HasMore || r.Rows[2][2] != nil {
+		t.Errorf("Table(all columns) = %v, has more %t, want 3 rows with nil mobile", r.Rows, r.HasMore)
+	}
+
+	// 视图的别名或表达式引用敏感列时按来源脱敏
+	testkit.Exec(t, conn, "CREATE VIEW person_alias AS SELECT id, mobile AS m, real_name || '' AS n FROM person ORDER BY id")
+	r, err = p.Table(ctx, conn, "main", "person_alias", nil, Sources{"M": {"mobile"}, "n": {"real_name"}, "id": {"id"}}, 1)
+	if err != nil {
+		t.Fatalf("Table(alias) error = %v", err)
+	}
+	if want := [][]interface{}{{int64(1), "******", "******"}}; !reflect.DeepEqual(r.Rows, want) || !reflect.DeepEqual(r.Masked, []string{"m", "n"}) {
+		t.Errorf("Table(alias) rows = %v, masked %v, want %v", r.Rows, r.Masked, want)
+	}
+
+	// 预览结束后连接恢复可写
+	testkit.Exec(t, conn, "INSERT INTO person (id) VALUES (4)")
+	if _, err := p.Query(ctx, conn, "DELETE FROM person", nil, 1); !errors.Is(err, ErrNotSelect) {
+		t.Errorf("Query(DELETE) error = %v, want ErrNotSelect", err)
+	}
+}
+
+func TestNew_UnknownMask(t *testing.T) {
+	_, err := New(Config{Masking: []Rule{{Columns: []string{"*"}, Type: "blur"}}})
+	if !errors.Is(err, ErrUnknownMask) {
+		t.Errorf("New() error = %v, want ErrUnknownMask", err)
+	}
+}
@@ -15,7 +15,8 @@ type CreateUserRequest struct {
 }
 
 func main() {
-	fmt.Println("=== Response 包使用示例 ===\n")
+	fmt.Println("=== Response 包使用示例 ===")
+	fmt.Println()
 
 	// 示例1: 成功响应
 	fmt.Println("1. 成功响应:")
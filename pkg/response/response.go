@@ -1,10 +1,13 @@
 package response
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"idrm/pkg/errorx"
+	"idrm/pkg/validator"
 )
 
 // HttpResponse 统一HTTP响应结构
@@ -113,14 +116,39 @@ func ErrorDetailed(w http.ResponseWriter, code string, description string, solut
 
 // ErrorValidation 验证错误响应
 func ErrorValidation(w http.ResponseWriter, validationErrors map[string]string) {
-	resp := &HttpError{
+	WriteJSON(w, http.StatusBadRequest, newValidationError(validationErrors))
+}
+
+// newValidationError 构建验证错误响应体
+func newValidationError(validationErrors map[string]string) *HttpError {
+	return &HttpError{
 		Code:        "idrm.common.validation_error",
 		Description: "参数验证失败",
 		Solution:    "请检查请求参数是否符合要求",
 		Cause:       "请求参数不符合验证规则",
 		Detail:      validationErrors,
 	}
-	WriteJSON(w, http.StatusBadRequest, resp)
+}
+
+// ErrorHandler go-zero 全局错误处理（通过 httpx.SetErrorHandlerCtx 注册）
+// - 验证错误: 400 + ErrorValidation 格式
+// - 业务错误(errorx.CodeError): 200 + 统一响应格式（与 Error 一致）
+// - 其他错误: 保持 go-zero 默认行为（400 + 纯文本）
+func ErrorHandler(ctx context.Context, err error) (int, interface{}) {
+	var validationErr *validator.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest, newValidationError(validationErr.Fields)
+	}
+
+	var codeErr *errorx.CodeError
+	if errors.As(err, &codeErr) {
+		return http.StatusOK, &HttpResponse{
+			Code: codeErr.GetCode(),
+			Msg:  codeErr.GetMsg(),
+		}
+	}
+
+	return http.StatusBadRequest, err
 }
 
 // NotFound 404错误响应
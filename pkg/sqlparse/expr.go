@@ -0,0 +1,517 @@
+package sqlparse
+
+import (
+	"slices"
+	"strings"
+)
+
+// binaryOp 当前位置的二元（及后缀）运算符与优先级，不是运算符时优先级为 0
+func (p *parser) binaryOp() (string, int) {
+	t := p.peek()
+	switch t.kind {
+	case tokOp:
+		switch t.text {
+		case "||":
+			return "OR", precOr
+		case "&&":
+			return "AND", precAnd
+		case "=", "<=>", "<", "<=", ">", ">=", "<>", "!=", ":=":
+			return t.text, precCompare
+		case "|":
+			return t.text, precBitOr
+		case "&":
+			return t.text, precBitAnd
+		case "<<", ">>":
+			return t.text, precShift
+		case "+", "-":
+			return t.text, precAdd
+		case "*", "/", "%":
+			return t.text, precMul
+		case "^":
+			return t.text, precBitXor
+		case "->", "->>":
+			return t.text, precJSON
+		}
+	case tokIdent:
+		if t.quoted {
+			return "", 0
+		}
+		switch op := strings.ToUpper(t.text); op {
+		case "OR":
+			return op, precOr
+		case "XOR":
+			return op, precXor
+		case "AND":
+			return op, precAnd
+		case "IS", "IN", "BETWEEN", "LIKE", "REGEXP", "RLIKE":
+			return op, precCompare
+		case "NOT":
+			if isKw(p.peekAt(1), "IN", "BETWEEN", "LIKE", "REGEXP", "RLIKE") {
+				return op, precCompare
+			}
+		case "SOUNDS":
+			if isKw(p.peekAt(1), "LIKE") {
+				return op, precCompare
+			}
+		case "DIV", "MOD":
+			return op, precMul
+		case "COLLATE":
+			return op, precCollate
+		}
+	}
+	return "", 0
+}
+
+// parseExpr 优先级不低于 minPrec 的表达式
+func (p *parser) parseExpr(minPrec int) (*expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, prec := p.binaryOp()
+		if prec == 0 || prec < minPrec {
+			return left, nil
+		}
+		e := &expr{start: left.start}
+		e.add(left)
+		p.next()
+		if op == "NOT" || op == "SOUNDS" {
+			op = strings.ToUpper(p.next().text)
+		}
+
+		switch op {
+		case "IS":
+			p.acceptKw("NOT")
+			if !p.acceptKw("NULL", "TRUE", "FALSE", "UNKNOWN") {
+				return nil, p.errorf("expected NULL, TRUE, FALSE or UNKNOWN")
+			}
+		case "IN":
+			if err := p.expectOp("("); err != nil {
+				return nil, err
+			}
+			if err := p.parseArgs(e); err != nil {
+				return nil, err
+			}
+		case "BETWEEN":
+			if err := p.parseOperand(e, precCompare+1); err != nil {
+				return nil, err
+			}
+			if err := p.expectKw("AND"); err != nil {
+				return nil, err
+			}
+			if err := p.parseOperand(e, precCompare+1); err != nil {
+				return nil, err
+			}
+		case "LIKE":
+			if err := p.parseOperand(e, precCompare+1); err != nil {
+				return nil, err
+			}
+			if p.acceptKw("ESCAPE") {
+				if err := p.parseOperand(e, precCompare+1); err != nil {
+					return nil, err
+				}
+			}
+		case "COLLATE":
+			if t := p.next(); t.kind != tokIdent && t.kind != tokString {
+				p.i--
+				return nil, p.errorf("expected collation name")
+			}
+		default:
+			if err := p.parseOperand(e, prec+1); err != nil {
+				return nil, err
+			}
+		}
+		e.end = p.lastEnd()
+		left = e
+	}
+}
+
+// parseOperand 解析子表达式并并入 e
+func (p *parser) parseOperand(e *expr, minPrec int) error {
+	child, err := p.parseExpr(minPrec)
+	if err != nil {
+		return err
+	}
+	e.add(child)
+	return nil
+}
+
+func (p *parser) parseUnary() (*expr, error) {
+	t := p.peek()
+	e := &expr{start: t.pos}
+	switch {
+	case isKw(t, "NOT"):
+		p.next()
+		if err := p.parseOperand(e, precNot); err != nil {
+			return nil, err
+		}
+	case isOp(t, "!"), isOp(t, "-"), isOp(t, "+"), isOp(t, "~"),
+		isKw(t, "BINARY") && !isOp(p.peekAt(1), "("):
+		p.next()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		e.add(child)
+	case isKw(t, "INTERVAL"):
+		p.next()
+		if err := p.parseOperand(e, precMul); err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokIdent {
+			return nil, p.errorf("expected interval unit")
+		}
+		p.next()
+	case isKw(t, "EXISTS"):
+		p.next()
+		sub, err := p.parseSubquery()
+		if err != nil {
+			return nil, err
+		}
+		e.subs = append(e.subs, sub)
+	default:
+		return p.parsePrimary()
+	}
+	e.end = p.lastEnd()
+	return e, nil
+}
+
+func (p *parser) parsePrimary() (*expr, error) {
+	t := p.peek()
+	e := &expr{start: t.pos}
+	switch t.kind {
+	case tokNumber, tokParam, tokVariable:
+		p.next()
+	case tokString:
+		for p.peek().kind == tokString {
+			p.next()
+		}
+	case tokOp:
+		if !isOp(t, "(") {
+			return nil, p.errorf("unexpected %q", t.text)
+		}
+		if startsQuery(p.peekAt(1)) {
+			sub, err := p.parseSubquery()
+			if err != nil {
+				return nil, err
+			}
+			e.subs = append(e.subs, sub)
+			break
+		}
+		p.next()
+		if err := p.parseArgs(e); err != nil {
+			return nil, err
+		}
+	case tokIdent:
+		if err := p.parseIdentExpr(e); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("expected expression")
+	}
+	e.end = p.lastEnd()
+	return e, nil
+}
+
+// parseIdentExpr 以标识符开始的表达式：字面量关键字、CASE、CAST、函数调用或列引用
+func (p *parser) parseIdentExpr(e *expr) error {
+	t := p.peek()
+	next := p.peekAt(1)
+	upper := strings.ToUpper(t.text)
+	if !t.quoted {
+		switch {
+		case upper == "NULL" || upper == "TRUE" || upper == "FALSE":
+			p.next()
+			return nil
+		case (upper == "DATE" || upper == "TIME" || upper == "TIMESTAMP") && next.kind == tokString:
+			p.next()
+			p.next()
+			return nil
+		case next.kind == tokString && next.pos == t.end &&
+			(strings.HasPrefix(t.text, "_") || upper == "N" || upper == "X" || upper == "B"):
+			// 字符集引导符及 N''、X''、B'' 字面量
+			p.next()
+			p.next()
+			return nil
+		case upper == "CASE":
+			return p.parseCase(e)
+		case (upper == "CAST" || upper == "CONVERT") && isOp(next, "("):
+			return p.parseCast(e)
+		case isOp(next, "(") && (!reserved[upper] || functionKeywords[upper]):
+			p.next()
+			return p.parseCall(e, upper)
+		case reserved[upper]:
+			return p.errorf("unexpected keyword %s", upper)
+		}
+	}
+
+	// 列引用 [schema.]table.column，或限定名的函数调用
+	path := []string{p.next().text}
+	for isOp(p.peek(), ".") && p.peekAt(1).kind == tokIdent {
+		p.next()
+		path = append(path, p.next().text)
+	}
+	if isOp(p.peek(), "(") && len(path) == 2 {
+		return p.parseCall(e, strings.ToUpper(path[0]+"."+path[1]))
+	}
+	if len(path) > 3 {
+		return newSyntaxError(p.src, t.pos, "too many qualifiers in column reference")
+	}
+	e.refs = append(e.refs, path)
+	e.column = true
+	return nil
+}
+
+// parseArgs 已读取 (，读取逗号分隔的表达式或子查询直到 )
+func (p *parser) parseArgs(e *expr) error {
+	if startsQuery(p.peek()) {
+		sub, err := p.parseQuery()
+		if err != nil {
+			return err
+		}
+		e.subs = append(e.subs, sub)
+		return p.expectOp(")")
+	}
+	for {
+		if err := p.parseOperand(e, 0); err != nil {
+			return err
+		}
+		if !p.acceptOp(",") {
+			return p.expectOp(")")
+		}
+	}
+}
+
+// parseCall 函数调用，当前位置为 (；name 为大写函数名，限定名为 SCHEMA.NAME
+func (p *parser) parseCall(e *expr, name string) error {
+	if !slices.Contains(p.funcs, name) {
+		p.funcs = append(p.funcs, name)
+	}
+	p.next()
+	switch {
+	case p.acceptOp(")"):
+	case isOp(p.peek(), "*") && isOp(p.peekAt(1), ")"):
+		p.next()
+		p.next()
+	case name == "EXTRACT":
+		// EXTRACT(unit FROM expr)
+		if p.peek().kind != tokIdent {
+			return p.errorf("expected unit")
+		}
+		p.next()
+		if err := p.expectKw("FROM"); err != nil {
+			return err
+		}
+		if err := p.parseOperand(e, 0); err != nil {
+			return err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return err
+		}
+	default:
+		if err := p.parseCallArgs(e, name); err != nil {
+			return err
+		}
+	}
+
+	if p.acceptKw("OVER") {
+		if isOp(p.peek(), "(") {
+			return p.parseWindowSpec(e)
+		}
+		_, err := p.parseIdent()
+		return err
+	}
+	return nil
+}
+
+// parseCallArgs 函数参数，支持 DISTINCT、子查询及 FROM/FOR/IN/SEPARATOR/ORDER BY/AS/USING 等关键字分隔
+func (p *parser) parseCallArgs(e *expr, name string) error {
+	argPrec := 0
+	if name == "POSITION" {
+		argPrec = precCompare + 1 // POSITION(substr IN str)
+	}
+	p.acceptKw("DISTINCT", "ALL")
+	p.acceptKw("LEADING", "TRAILING", "BOTH")
+	if startsQuery(p.peek()) {
+		return p.parseArgs(e)
+	}
+	for {
+		if !isKw(p.peek(), "FROM") {
+			if err := p.parseOperand(e, argPrec); err != nil {
+				return err
+			}
+		}
+		switch {
+		case p.acceptOp(")"):
+			return nil
+		case p.acceptOp(","), p.acceptKw("FROM", "FOR", "IN", "SEPARATOR"):
+		case isKw(p.peek(), "ORDER") && isKw(p.peekAt(1), "BY"):
+			p.next()
+			p.next()
+			list, err := p.parseOrderList()
+			if err != nil {
+				return err
+			}
+			for _, child := range list {
+				e.add(child)
+			}
+			if p.acceptOp(")") {
+				return nil
+			}
+			if err := p.expectKw("SEPARATOR"); err != nil {
+				return err
+			}
+		case p.acceptKw("AS"):
+			if err := p.skipType(); err != nil {
+				return err
+			}
+			return p.expectOp(")")
+		case p.acceptKw("USING"):
+			if _, err := p.parseIdent(); err != nil {
+				return err
+			}
+			return p.expectOp(")")
+		default:
+			return p.errorf("expected \",\" or \")\"")
+		}
+	}
+}
+
+// parseCast CAST(expr AS type)、CONVERT(expr, type)、CONVERT(expr USING charset)
+func (p *parser) parseCast(e *expr) error {
+	p.next()
+	p.next()
+	if err := p.parseOperand(e, 0); err != nil {
+		return err
+	}
+	switch {
+	case p.acceptKw("AS"), p.acceptOp(","):
+		if err := p.skipType(); err != nil {
+			return err
+		}
+	case p.acceptKw("USING"):
+		if _, err := p.parseIdent(); err != nil {
+			return err
+		}
+	default:
+		return p.errorf("expected AS")
+	}
+	return p.expectOp(")")
+}
+
+// skipType 跳过类型声明，如 DECIMAL(10, 2)、CHAR(10) CHARACTER SET utf8mb4、UNSIGNED INTEGER
+func (p *parser) skipType() error {
+	if p.peek().kind != tokIdent {
+		return p.errorf("expected type")
+	}
+	for p.peek().kind == tokIdent {
+		p.next()
+		if isOp(p.peek(), "(") {
+			if err := p.skipParens(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipParens 跳过成对的括号及其内容
+func (p *parser) skipParens() error {
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return p.errorf("expected \")\"")
+		case isOp(t, "("):
+			depth++
+		case isOp(t, ")"):
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// parseCase CASE [value] WHEN ... THEN ... [ELSE ...] END
+func (p *parser) parseCase(e *expr) error {
+	p.next()
+	if !isKw(p.peek(), "WHEN") {
+		if err := p.parseOperand(e, 0); err != nil {
+			return err
+		}
+	}
+	if !isKw(p.peek(), "WHEN") {
+		return p.errorf("expected WHEN")
+	}
+	for p.acceptKw("WHEN") {
+		if err := p.parseOperand(e, 0); err != nil {
+			return err
+		}
+		if err := p.expectKw("THEN"); err != nil {
+			return err
+		}
+		if err := p.parseOperand(e, 0); err != nil {
+			return err
+		}
+	}
+	if p.acceptKw("ELSE") {
+		if err := p.parseOperand(e, 0); err != nil {
+			return err
+		}
+	}
+	return p.expectKw("END")
+}
+
+// parseWindowSpec ([name] [PARTITION BY ...] [ORDER BY ...] [frame])，窗口帧原样跳过
+func (p *parser) parseWindowSpec(e *expr) error {
+	if err := p.expectOp("("); err != nil {
+		return err
+	}
+	if isIdent(p.peek()) && !isKw(p.peek(), "ROWS", "RANGE", "GROUPS") {
+		p.next()
+	}
+	if p.acceptKw("PARTITION") {
+		if err := p.expectKw("BY"); err != nil {
+			return err
+		}
+		for {
+			if err := p.parseOperand(e, 0); err != nil {
+				return err
+			}
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	if isKw(p.peek(), "ORDER") {
+		p.next()
+		if err := p.expectKw("BY"); err != nil {
+			return err
+		}
+		list, err := p.parseOrderList()
+		if err != nil {
+			return err
+		}
+		for _, child := range list {
+			e.add(child)
+		}
+	}
+	for depth := 0; ; {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return p.errorf("expected \")\"")
+		case isOp(t, "("):
+			depth++
+		case isOp(t, ")"):
+			if depth == 0 {
+				e.end = p.lastEnd()
+				return nil
+			}
+			depth--
+		}
+	}
+}
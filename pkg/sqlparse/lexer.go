@@ -0,0 +1,185 @@
+package sqlparse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 词法单元类型
+type tokenKind int
+
+const (
+	tokEOF      tokenKind = iota
+	tokIdent              // 标识符或关键字（反引号引用的标识符 quoted 为 true，不作为关键字）
+	tokNumber             // 数值
+	tokString             // 字符串（单引号或双引号）
+	tokVariable           // 用户变量或系统变量（@x、@@x）
+	tokParam              // 占位符 ?
+	tokOp                 // 运算符及标点
+)
+
+// token 词法单元，pos/end 为原文中的字节偏移
+type token struct {
+	kind   tokenKind
+	text   string // 标识符为去除引号后的名称，运算符为原文
+	quoted bool
+	pos    int
+	end    int
+}
+
+// multiOps 多字符运算符（长的在前）
+var multiOps = []string{"<=>", "->>", "<=", ">=", "<>", "!=", "||", "&&", "<<", ">>", ":=", "->"}
+
+// lex 将 SQL 切分为词法单元，跳过空白及注释（--、#、/* */）
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '#' || isDashComment(src[i:]):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, newSyntaxError(src, i, "unterminated comment")
+			}
+			i += end + 4
+		case r == '`':
+			name, n, ok := quoted(src[i:], '`', false)
+			if !ok {
+				return nil, newSyntaxError(src, i, "unterminated quoted identifier")
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: name, quoted: true, pos: i, end: i + n})
+			i += n
+		case r == '\'' || r == '"':
+			text, n, ok := quoted(src[i:], byte(r), true)
+			if !ok {
+				return nil, newSyntaxError(src, i, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, pos: i, end: i + n})
+			i += n
+		case isDigit(r) || (r == '.' && i+1 < len(src) && isDigit(rune(src[i+1]))):
+			n := scanNumber(src[i:])
+			tokens = append(tokens, token{kind: tokNumber, text: src[i : i+n], pos: i, end: i + n})
+			i += n
+		case r == '@':
+			n := 1
+			if strings.HasPrefix(src[i:], "@@") {
+				n = 2
+			}
+			n += scanWord(src[i+n:])
+			if n == 1 || (n == 2 && src[i+1] == '@') {
+				return nil, newSyntaxError(src, i, "invalid variable")
+			}
+			// 系统变量可带作用域，如 @@session.sql_mode
+			if src[i+1] == '@' && i+n+1 < len(src) && src[i+n] == '.' {
+				if m := scanWord(src[i+n+1:]); m > 0 {
+					n += 1 + m
+				}
+			}
+			tokens = append(tokens, token{kind: tokVariable, text: src[i : i+n], pos: i, end: i + n})
+			i += n
+		case isWordRune(r):
+			n := scanWord(src[i:])
+			tokens = append(tokens, token{kind: tokIdent, text: src[i : i+n], pos: i, end: i + n})
+			i += n
+		case r == '?':
+			tokens = append(tokens, token{kind: tokParam, text: "?", pos: i, end: i + 1})
+			i++
+		default:
+			op := string(r)
+			for _, m := range multiOps {
+				if strings.HasPrefix(src[i:], m) {
+					op = m
+					break
+				}
+			}
+			if !strings.Contains("=<>!|&+-*/%^~(),.;:", op[:1]) {
+				return nil, newSyntaxError(src, i, "unexpected character "+op)
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: i, end: i + len(op)})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src), end: len(src)}), nil
+}
+
+// quoted 读取引号内的内容，返回去除引号及转义后的文本和消耗的字节数
+// 连续两个引号为转义；backslash 为 true 时反斜杠转义下一个字符
+func quoted(s string, q byte, backslash bool) (string, int, bool) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case backslash && c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case c == q && i+1 < len(s) && s[i+1] == q:
+			i++
+			b.WriteByte(q)
+		case c == q:
+			return b.String(), i + 1, true
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, false
+}
+
+// scanNumber 整数、小数、科学计数法及十六进制（0x）
+func scanNumber(s string) int {
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		n := 2
+		for n < len(s) && strings.IndexByte("0123456789abcdefABCDEF", s[n]) >= 0 {
+			n++
+		}
+		return n
+	}
+	n := 0
+	for n < len(s) && (isDigit(rune(s[n])) || s[n] == '.') {
+		n++
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		if m < len(s) && isDigit(rune(s[m])) {
+			for m < len(s) && isDigit(rune(s[m])) {
+				m++
+			}
+			n = m
+		}
+	}
+	return n
+}
+
+// scanWord 标识符字符的字节数
+func scanWord(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !isWordRune(r) && !isDigit(r) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// isDashComment MySQL 中 -- 后须跟空白才是注释（a--1 为 a - (-1)）
+func isDashComment(s string) bool {
+	return strings.HasPrefix(s, "--") && (len(s) == 2 || unicode.IsSpace(rune(s[2])))
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || (r > unicode.MaxASCII && !unicode.IsSpace(r) && !unicode.IsPunct(r))
+}
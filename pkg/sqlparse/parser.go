@@ -0,0 +1,657 @@
+package sqlparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// query 查询：WITH 子句、集合运算的各分支及 ORDER BY/LIMIT
+type query struct {
+	ctes     []*cte
+	branches []*branch
+	extra    []*expr
+}
+
+// cte 公用表表达式
+type cte struct {
+	name    string
+	columns []string
+	q       *query
+}
+
+// branch 集合运算的一个分支：SELECT 或括号内的查询
+type branch struct {
+	sel *selectStmt
+	sub *query
+}
+
+type selectStmt struct {
+	items []*selectItem
+	from  []*source
+	extra []*expr // WHERE、GROUP BY、HAVING、JOIN ON 等不产生输出列的表达式
+}
+
+// selectItem 输出项，star 为 * 或 t.*
+type selectItem struct {
+	e         *expr
+	star      bool
+	qualifier []string
+	alias     string
+}
+
+// source FROM 中的表或派生表
+type source struct {
+	schema  string
+	name    string
+	alias   string
+	sub     *query
+	columns []string // 派生表的列名列表
+}
+
+// expr 表达式：原文范围、引用的列及其中的子查询
+type expr struct {
+	start, end int
+	refs       [][]string
+	subs       []*query
+	column     bool // 整个表达式为单个列引用
+}
+
+func (e *expr) add(child *expr) {
+	e.refs = append(e.refs, child.refs...)
+	e.subs = append(e.subs, child.subs...)
+}
+
+// dmlKeywords 非查询语句的起始关键字
+var dmlKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "MERGE": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+	"GRANT": true, "REVOKE": true, "SET": true, "CALL": true, "LOAD": true, "LOCK": true,
+	"UNLOCK": true, "DO": true, "HANDLER": true, "EXECUTE": true, "PREPARE": true, "USE": true,
+}
+
+// reserved 不能作为未引用标识符（列名、别名）的关键字
+var reserved = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true, "BY": true,
+	"LIMIT": true, "OFFSET": true, "UNION": true, "EXCEPT": true, "INTERSECT": true, "ALL": true, "DISTINCT": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "CROSS": true, "NATURAL": true, "OUTER": true,
+	"STRAIGHT_JOIN": true, "ON": true, "USING": true, "AS": true, "AND": true, "OR": true, "XOR": true, "NOT": true,
+	"IS": true, "IN": true, "BETWEEN": true, "LIKE": true, "REGEXP": true, "RLIKE": true, "CASE": true, "WHEN": true,
+	"THEN": true, "ELSE": true, "END": true, "NULL": true, "TRUE": true, "FALSE": true, "EXISTS": true, "INTO": true,
+	"FOR": true, "LOCK": true, "WINDOW": true, "WITH": true, "USE": true, "IGNORE": true, "FORCE": true,
+	"PARTITION": true, "DIV": true, "MOD": true, "INTERVAL": true, "ESCAPE": true, "COLLATE": true, "BINARY": true,
+	"LATERAL": true, "ASC": true, "DESC": true, "INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true,
+	"IF": true, "CHAR": true, "DATABASE": true, "SCHEMA": true, "VALUES": true, "OVER": true, "SEPARATOR": true,
+}
+
+// functionKeywords 可作为函数名的保留字
+var functionKeywords = map[string]bool{
+	"LEFT": true, "RIGHT": true, "IF": true, "MOD": true, "INSERT": true, "REPLACE": true, "CHAR": true,
+	"DATABASE": true, "SCHEMA": true, "VALUES": true,
+}
+
+// 运算符优先级（MySQL），数值越大结合越紧
+const (
+	precOr      = 1
+	precXor     = 2
+	precAnd     = 3
+	precNot     = 4
+	precCompare = 5
+	precBitOr   = 6
+	precBitAnd  = 7
+	precShift   = 8
+	precAdd     = 9
+	precMul     = 10
+	precBitXor  = 11
+	precUnary   = 12
+	precCollate = 13
+	precJSON    = 14
+)
+
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+	funcs  []string // 调用的函数名，见 Result.Functions
+}
+
+func (p *parser) peek() token        { return p.tokens[p.i] }
+func (p *parser) peekAt(n int) token { return p.tokens[min(p.i+n, len(p.tokens)-1)] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// lastEnd 最后一个已消耗的词法单元的结束位置
+func (p *parser) lastEnd() int {
+	if p.i == 0 {
+		return 0
+	}
+	return p.tokens[p.i-1].end
+}
+
+func isKw(t token, kws ...string) bool {
+	if t.kind != tokIdent || t.quoted {
+		return false
+	}
+	for _, kw := range kws {
+		if strings.EqualFold(t.text, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func isOp(t token, op string) bool { return t.kind == tokOp && t.text == op }
+
+func (p *parser) acceptKw(kws ...string) bool {
+	if isKw(p.peek(), kws...) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) acceptOp(op string) bool {
+	if isOp(p.peek(), op) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKw(kw string) error {
+	if !p.acceptKw(kw) {
+		return p.errorf("expected %s", kw)
+	}
+	return nil
+}
+
+func (p *parser) expectOp(op string) error {
+	if !p.acceptOp(op) {
+		return p.errorf("expected %q", op)
+	}
+	return nil
+}
+
+// errorf 当前位置的语法错误
+func (p *parser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	msg := fmt.Sprintf(format, args...)
+	if t.kind == tokEOF {
+		msg += ", got end of input"
+	}
+	return newSyntaxError(p.src, t.pos, msg)
+}
+
+// isIdent 当前词法单元可作为标识符（引用的标识符或非保留字）
+func isIdent(t token) bool {
+	return t.kind == tokIdent && (t.quoted || !reserved[strings.ToUpper(t.text)])
+}
+
+func (p *parser) parseIdent() (string, error) {
+	if !isIdent(p.peek()) {
+		return "", p.errorf("expected identifier")
+	}
+	return p.next().text, nil
+}
+
+// startsQuery 当前词法单元开始一个查询
+func startsQuery(t token) bool { return isKw(t, "SELECT", "WITH") }
+
+// parseStatement 单条查询语句，末尾可有一个分号
+func (p *parser) parseStatement() (*query, error) {
+	t := p.peek()
+	if t.kind == tokIdent && !t.quoted && dmlKeywords[strings.ToUpper(t.text)] {
+		return nil, fmt.Errorf("%w: %s statement", ErrNotSelect, strings.ToUpper(t.text))
+	}
+	if !startsQuery(t) && !isOp(t, "(") {
+		return nil, p.errorf("expected SELECT or WITH")
+	}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkTail(); err != nil {
+		return nil, err
+	}
+	if p.acceptOp(";") && p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("%w: multiple statements", ErrNotSelect)
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return q, nil
+}
+
+// checkTail 拒绝 SELECT ... INTO 及锁定读
+func (p *parser) checkTail() error {
+	switch t := p.peek(); {
+	case isKw(t, "INTO"):
+		return fmt.Errorf("%w: SELECT ... INTO", ErrNotSelect)
+	case isKw(t, "FOR") && isKw(p.peekAt(1), "UPDATE", "SHARE"), isKw(t, "LOCK") && isKw(p.peekAt(1), "IN"):
+		return fmt.Errorf("%w: locking read", ErrNotSelect)
+	}
+	return nil
+}
+
+func (p *parser) parseQuery() (*query, error) {
+	q := &query{}
+	if p.acceptKw("WITH") {
+		p.acceptKw("RECURSIVE")
+		for {
+			c, err := p.parseCTE()
+			if err != nil {
+				return nil, err
+			}
+			q.ctes = append(q.ctes, c)
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+
+	for {
+		b, err := p.parseBranch()
+		if err != nil {
+			return nil, err
+		}
+		q.branches = append(q.branches, b)
+		if !p.acceptKw("UNION", "EXCEPT", "INTERSECT") {
+			break
+		}
+		p.acceptKw("ALL", "DISTINCT")
+	}
+
+	extra, err := p.parseOrderLimit()
+	if err != nil {
+		return nil, err
+	}
+	q.extra = extra
+	return q, p.checkTail()
+}
+
+func (p *parser) parseCTE() (*cte, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	c := &cte{name: name}
+	if p.acceptOp("(") {
+		if c.columns, err = p.parseIdentList(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKw("AS"); err != nil {
+		return nil, err
+	}
+	if c.q, err = p.parseSubquery(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseSubquery ( query )
+func (p *parser) parseSubquery() (*query, error) {
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return q, p.expectOp(")")
+}
+
+// parseIdentList 已读取 (，读取到 ) 为止
+func (p *parser) parseIdentList() ([]string, error) {
+	var names []string
+	for {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	return names, p.expectOp(")")
+}
+
+func (p *parser) parseBranch() (*branch, error) {
+	if isOp(p.peek(), "(") {
+		sub, err := p.parseSubquery()
+		if err != nil {
+			return nil, err
+		}
+		return &branch{sub: sub}, nil
+	}
+	sel, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	return &branch{sel: sel}, nil
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	if err := p.expectKw("SELECT"); err != nil {
+		return nil, err
+	}
+	for p.acceptKw("ALL", "DISTINCT", "DISTINCTROW", "HIGH_PRIORITY", "STRAIGHT_JOIN", "SQL_SMALL_RESULT",
+		"SQL_BIG_RESULT", "SQL_BUFFER_RESULT", "SQL_NO_CACHE", "SQL_CALC_FOUND_ROWS") {
+	}
+
+	s := &selectStmt{}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		s.items = append(s.items, item)
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	if err := p.checkTail(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKw("FROM") {
+		if isKw(p.peek(), "DUAL") {
+			p.next()
+		} else if err := p.parseTableRefs(s); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKw("WHERE") {
+		if err := p.parseExtra(s); err != nil {
+			return nil, err
+		}
+	}
+	if isKw(p.peek(), "GROUP") {
+		p.next()
+		if err := p.expectKw("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			if err := p.parseExtra(s); err != nil {
+				return nil, err
+			}
+			p.acceptKw("ASC", "DESC")
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+		if isKw(p.peek(), "WITH") && isKw(p.peekAt(1), "ROLLUP") {
+			p.next()
+			p.next()
+		}
+	}
+	if p.acceptKw("HAVING") {
+		if err := p.parseExtra(s); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKw("WINDOW") {
+		for {
+			if _, err := p.parseIdent(); err != nil {
+				return nil, err
+			}
+			if err := p.expectKw("AS"); err != nil {
+				return nil, err
+			}
+			e := &expr{start: p.peek().pos}
+			if err := p.parseWindowSpec(e); err != nil {
+				return nil, err
+			}
+			s.extra = append(s.extra, e)
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) parseExtra(s *selectStmt) error {
+	e, err := p.parseExpr(0)
+	if err != nil {
+		return err
+	}
+	s.extra = append(s.extra, e)
+	return nil
+}
+
+func (p *parser) parseSelectItem() (*selectItem, error) {
+	if p.acceptOp("*") {
+		return &selectItem{star: true}, nil
+	}
+	// t.* 或 schema.t.*
+	for n := 1; n <= 2; n++ {
+		if isStarPath(p, n) {
+			item := &selectItem{star: true}
+			for k := 0; k < n; k++ {
+				item.qualifier = append(item.qualifier, p.next().text)
+				p.next() // .
+			}
+			p.next() // *
+			return item, nil
+		}
+	}
+
+	e, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+	item := &selectItem{e: e}
+	if p.acceptKw("AS") {
+		t := p.peek()
+		if t.kind != tokString && t.kind != tokIdent {
+			return nil, p.errorf("expected alias")
+		}
+		item.alias = p.next().text
+	} else if t := p.peek(); isIdent(t) || t.kind == tokString {
+		item.alias = p.next().text
+	}
+	return item, nil
+}
+
+// isStarPath 当前位置为 n 段限定名后跟 .*
+func isStarPath(p *parser, n int) bool {
+	for k := 0; k < n; k++ {
+		if p.peekAt(2*k).kind != tokIdent || !isOp(p.peekAt(2*k+1), ".") {
+			return false
+		}
+	}
+	return isOp(p.peekAt(2*n), "*")
+}
+
+func (p *parser) parseTableRefs(s *selectStmt) error {
+	for {
+		if err := p.parseTableRef(s); err != nil {
+			return err
+		}
+		if !p.acceptOp(",") {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseTableRef(s *selectStmt) error {
+	if err := p.parseTableFactor(s); err != nil {
+		return err
+	}
+	for {
+		switch {
+		case p.acceptKw("NATURAL"):
+			p.acceptKw("LEFT", "RIGHT")
+			p.acceptKw("OUTER")
+			if err := p.expectKw("JOIN"); err != nil {
+				return err
+			}
+		case p.acceptKw("INNER", "CROSS"):
+			if err := p.expectKw("JOIN"); err != nil {
+				return err
+			}
+		case p.acceptKw("LEFT", "RIGHT"):
+			p.acceptKw("OUTER")
+			if err := p.expectKw("JOIN"); err != nil {
+				return err
+			}
+		case p.acceptKw("JOIN", "STRAIGHT_JOIN"):
+		default:
+			return nil
+		}
+
+		if err := p.parseTableFactor(s); err != nil {
+			return err
+		}
+		switch {
+		case p.acceptKw("ON"):
+			if err := p.parseExtra(s); err != nil {
+				return err
+			}
+		case p.acceptKw("USING"):
+			if err := p.expectOp("("); err != nil {
+				return err
+			}
+			if _, err := p.parseIdentList(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *parser) parseTableFactor(s *selectStmt) error {
+	lateral := p.acceptKw("LATERAL")
+	if isOp(p.peek(), "(") {
+		if !lateral && !startsQuery(p.peekAt(1)) && !isOp(p.peekAt(1), "(") {
+			// 括号内的表引用
+			p.next()
+			if err := p.parseTableRefs(s); err != nil {
+				return err
+			}
+			return p.expectOp(")")
+		}
+		sub, err := p.parseSubquery()
+		if err != nil {
+			return err
+		}
+		src := &source{sub: sub}
+		p.acceptKw("AS")
+		if src.alias, err = p.parseIdent(); err != nil {
+			return err
+		}
+		if p.acceptOp("(") {
+			if src.columns, err = p.parseIdentList(); err != nil {
+				return err
+			}
+		}
+		s.from = append(s.from, src)
+		return nil
+	}
+	if lateral {
+		return p.errorf("expected subquery after LATERAL")
+	}
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+	src := &source{name: name}
+	if p.acceptOp(".") {
+		src.schema = name
+		if src.name, err = p.parseIdent(); err != nil {
+			return err
+		}
+	}
+	if p.acceptKw("PARTITION") {
+		if err := p.expectOp("("); err != nil {
+			return err
+		}
+		if _, err := p.parseIdentList(); err != nil {
+			return err
+		}
+	}
+	if p.acceptKw("AS") {
+		if src.alias, err = p.parseIdent(); err != nil {
+			return err
+		}
+	} else if isIdent(p.peek()) {
+		src.alias = p.next().text
+	}
+	s.from = append(s.from, src)
+
+	// 索引提示 USE|IGNORE|FORCE {INDEX|KEY} [FOR ...] (...)
+	for p.acceptKw("USE", "IGNORE", "FORCE") {
+		if !p.acceptKw("INDEX", "KEY") {
+			return p.errorf("expected INDEX or KEY")
+		}
+		if p.acceptKw("FOR") {
+			if p.acceptKw("ORDER", "GROUP") {
+				if err := p.expectKw("BY"); err != nil {
+					return err
+				}
+			} else if err := p.expectKw("JOIN"); err != nil {
+				return err
+			}
+		}
+		if err := p.expectOp("("); err != nil {
+			return err
+		}
+		if !p.acceptOp(")") {
+			if _, err := p.parseIdentList(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseOrderLimit ORDER BY 及 LIMIT
+func (p *parser) parseOrderLimit() ([]*expr, error) {
+	var extra []*expr
+	if isKw(p.peek(), "ORDER") {
+		p.next()
+		if err := p.expectKw("BY"); err != nil {
+			return nil, err
+		}
+		list, err := p.parseOrderList()
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, list...)
+	}
+	if p.acceptKw("LIMIT") {
+		for {
+			e, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			extra = append(extra, e)
+			if !p.acceptOp(",") && !p.acceptKw("OFFSET") {
+				break
+			}
+		}
+	}
+	return extra, nil
+}
+
+func (p *parser) parseOrderList() ([]*expr, error) {
+	var list []*expr
+	for {
+		e, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		p.acceptKw("ASC", "DESC")
+		if !p.acceptOp(",") {
+			return list, nil
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package sqlparse
+
+import "strings"
+
+// resolver 解析名称引用，收集物理表并追溯列血缘
+type resolver struct {
+	src    string
+	tables []Table
+	seen   map[Table]bool
+}
+
+// scope 查询块的名称空间，内层查询可引用外层的表（关联子查询）
+type scope struct {
+	parent  *scope
+	ctes    []*cteInfo
+	sources []*scopeSource
+}
+
+type cteInfo struct {
+	name    string
+	columns []Column
+}
+
+// scopeSource FROM 中的表：physical 为物理表，否则为派生表或 CTE，columns 为其输出列
+type scopeSource struct {
+	schema   string
+	name     string
+	alias    string
+	physical bool
+	columns  []Column
+}
+
+func (r *resolver) addTable(t Table) {
+	key := Table{Schema: strings.ToLower(t.Schema), Name: strings.ToLower(t.Name)}
+	if !r.seen[key] {
+		r.seen[key] = true
+		r.tables = append(r.tables, t)
+	}
+}
+
+// query 解析查询，返回输出列；集合运算各分支的源列按位置合并
+func (r *resolver) query(q *query, parent *scope) []Column {
+	s := &scope{parent: parent}
+	for _, c := range q.ctes {
+		// 先登记再解析，递归 CTE 引用自身时列未知
+		info := &cteInfo{name: c.name}
+		s.ctes = append(s.ctes, info)
+		info.columns = rename(r.query(c.q, s), c.columns)
+	}
+
+	var columns []Column
+	for i, b := range q.branches {
+		var cols []Column
+		if b.sub != nil {
+			cols = r.query(b.sub, s)
+		} else {
+			cols = r.selectStmt(b.sel, s)
+		}
+		if i == 0 {
+			columns = cols
+			continue
+		}
+		for k := range columns {
+			if k < len(cols) {
+				columns[k].Sources = appendRefs(append([]ColumnRef{}, columns[k].Sources...), cols[k].Sources...)
+				columns[k].Direct = columns[k].Direct && cols[k].Direct
+			}
+		}
+	}
+	for _, e := range q.extra {
+		r.subqueries(e, s)
+	}
+	return columns
+}
+
+func (r *resolver) selectStmt(sel *selectStmt, parent *scope) []Column {
+	s := &scope{parent: parent}
+	for _, src := range sel.from {
+		s.sources = append(s.sources, r.source(src, s))
+	}
+
+	var columns []Column
+	for _, item := range sel.items {
+		if item.star {
+			columns = append(columns, r.expandStar(item.qualifier, s)...)
+			continue
+		}
+		col := Column{Expr: r.text(item.e), Sources: r.refs(item.e, s)}
+		if item.e.column {
+			// 单个列引用：经派生表追溯到的也是列引用时为直接引用
+			_, col.Direct = r.resolve(item.e.refs[0], s)
+		}
+		switch {
+		case item.alias != "":
+			col.Name = item.alias
+		case item.e.column:
+			path := item.e.refs[0]
+			col.Name = path[len(path)-1]
+		default:
+			col.Name = col.Expr
+		}
+		columns = append(columns, col)
+	}
+	for _, e := range sel.extra {
+		r.subqueries(e, s)
+	}
+	return columns
+}
+
+// source 登记 FROM 中的表：派生表解析其查询，未限定的名称优先匹配 CTE
+func (r *resolver) source(src *source, s *scope) *scopeSource {
+	if src.sub != nil {
+		return &scopeSource{alias: src.alias, columns: rename(r.query(src.sub, s), src.columns)}
+	}
+	if src.schema == "" {
+		if c := s.cte(src.name); c != nil {
+			return &scopeSource{name: src.name, alias: src.alias, columns: c.columns}
+		}
+	}
+	r.addTable(Table{Schema: src.schema, Name: src.name})
+	return &scopeSource{schema: src.schema, name: src.name, alias: src.alias, physical: true}
+}
+
+func (s *scope) cte(name string) *cteInfo {
+	for cur := s; cur != nil; cur = cur.parent {
+		for i := len(cur.ctes) - 1; i >= 0; i-- {
+			if strings.EqualFold(cur.ctes[i].name, name) {
+				return cur.ctes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// expandStar 展开 * 或 t.*：物理表输出名为 * 的列，派生表展开为其各列
+func (r *resolver) expandStar(qualifier []string, s *scope) []Column {
+	var columns []Column
+	for _, src := range s.sources {
+		if len(qualifier) > 0 && !src.matches(qualifier) {
+			continue
+		}
+		if src.physical {
+			ref := ColumnRef{Schema: src.schema, Table: src.name, Column: "*"}
+			columns = append(columns, Column{Name: "*", Expr: src.qualifier() + ".*", Sources: []ColumnRef{ref}, Direct: true})
+			continue
+		}
+		for _, c := range src.columns {
+			columns = append(columns, Column{Name: c.Name, Expr: src.qualifier() + "." + c.Name, Sources: c.Sources, Direct: c.Direct})
+		}
+	}
+	return columns
+}
+
+// refs 表达式引用的源列，含标量子查询的输出列
+func (r *resolver) refs(e *expr, s *scope) []ColumnRef {
+	refs := []ColumnRef{}
+	for _, sub := range e.subs {
+		for _, c := range r.query(sub, s) {
+			refs = appendRefs(refs, c.Sources...)
+		}
+	}
+	for _, path := range e.refs {
+		resolved, _ := r.resolve(path, s)
+		refs = appendRefs(refs, resolved...)
+	}
+	return refs
+}
+
+// subqueries 解析不产生输出列的表达式中的子查询，只收集引用的表
+func (r *resolver) subqueries(e *expr, s *scope) {
+	for _, sub := range e.subs {
+		r.query(sub, s)
+	}
+}
+
+// resolve 解析列引用，由内向外查找；无法确定所属表时返回表名为空的源列，
+// 未找到（如引用输出列别名）时返回 nil；direct 表示最终指向物理表的列（而非派生表中的表达式）
+func (r *resolver) resolve(path []string, s *scope) (refs []ColumnRef, direct bool) {
+	col := path[len(path)-1]
+	qualifier := path[:len(path)-1]
+	for cur := s; cur != nil; cur = cur.parent {
+		if len(qualifier) > 0 {
+			for _, src := range cur.sources {
+				if src.matches(qualifier) {
+					refs, direct, _ := src.lookup(col)
+					return refs, direct
+				}
+			}
+			continue
+		}
+
+		var physical []*scopeSource
+		for _, src := range cur.sources {
+			if src.physical {
+				physical = append(physical, src)
+			} else if refs, direct, ok := src.lookup(col); ok {
+				return refs, direct
+			}
+		}
+		switch len(physical) {
+		case 0:
+			continue
+		case 1:
+			refs, direct, _ := physical[0].lookup(col)
+			return refs, direct
+		default:
+			return []ColumnRef{{Column: col}}, true
+		}
+	}
+	return nil, false
+}
+
+// matches 限定名是否指向该表：有别名时只匹配别名
+func (src *scopeSource) matches(qualifier []string) bool {
+	switch len(qualifier) {
+	case 1:
+		return strings.EqualFold(src.qualifier(), qualifier[0])
+	case 2:
+		return src.alias == "" && strings.EqualFold(src.schema, qualifier[0]) && strings.EqualFold(src.name, qualifier[1])
+	}
+	return false
+}
+
+func (src *scopeSource) qualifier() string {
+	if src.alias != "" {
+		return src.alias
+	}
+	return src.name
+}
+
+// lookup 表中的列；派生表中未显式列出但来自 * 展开的列追溯到对应物理表
+func (src *scopeSource) lookup(col string) (refs []ColumnRef, direct, ok bool) {
+	if src.physical {
+		return []ColumnRef{{Schema: src.schema, Table: src.name, Column: col}}, true, true
+	}
+	var stars []ColumnRef
+	for _, c := range src.columns {
+		if strings.EqualFold(c.Name, col) {
+			return c.Sources, c.Direct, true
+		}
+		if c.Name == "*" {
+			stars = append(stars, c.Sources...)
+		}
+	}
+	switch len(stars) {
+	case 0:
+		return nil, false, false
+	case 1:
+		return []ColumnRef{{Schema: stars[0].Schema, Table: stars[0].Table, Column: col}}, true, true
+	default:
+		return []ColumnRef{{Column: col}}, true, true
+	}
+}
+
+// text 表达式原文，连续空白规整为一个空格
+func (r *resolver) text(e *expr) string {
+	return strings.Join(strings.Fields(r.src[e.start:e.end]), " ")
+}
+
+// rename 按列名列表重命名输出列
+func rename(columns []Column, names []string) []Column {
+	if len(names) == 0 {
+		return columns
+	}
+	renamed := append([]Column{}, columns...)
+	for i := range renamed {
+		if i < len(names) {
+			renamed[i].Name = names[i]
+		}
+	}
+	return renamed
+}
+
+// appendRefs 追加源列并去重
+func appendRefs(refs []ColumnRef, more ...ColumnRef) []ColumnRef {
+	for _, ref := range more {
+		dup := false
+		for _, r := range refs {
+			if r == ref {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,173 @@
+package sqlparse
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseValid(t *testing.T) {
+	queries := []string{
+		"SELECT 1",
+		"select * from dual;",
+		"SELECT DISTINCT a, b AS c, d 'e' FROM t WHERE a > 1 AND b IS NOT NULL ORDER BY a DESC LIMIT 10, 20",
+		"SELECT a FROM t1 JOIN t2 USING (id) LEFT OUTER JOIN t3 ON t2.x = t3.x NATURAL JOIN t4",
+		"SELECT a FROM (t1, t2) CROSS JOIN t3 STRAIGHT_JOIN t4 ON 1 = 1",
+		"SELECT a FROM t FORCE INDEX FOR ORDER BY (idx_a) USE KEY () WHERE a LIKE 'x%' ESCAPE '!'",
+		"SELECT COUNT(*), COUNT(DISTINCT a), GROUP_CONCAT(DISTINCT b ORDER BY b SEPARATOR ',') FROM t GROUP BY c WITH ROLLUP HAVING COUNT(*) > 1",
+		"SELECT CASE WHEN a BETWEEN 1 AND 2 THEN 'x' ELSE 'y' END, CASE a WHEN 1 THEN 2 END FROM t",
+		"SELECT CAST(a AS DECIMAL(10, 2)), CONVERT(b, CHAR(10)), CONVERT(c USING utf8mb4), EXTRACT(YEAR FROM d) FROM t",
+		"SELECT TRIM(LEADING 'x' FROM a), SUBSTRING(b FROM 1 FOR 2), POSITION('a' IN c), IF(a, LEFT(b, 1), RIGHT(b, 1)) FROM t",
+		"SELECT a + INTERVAL 1 DAY, DATE '2024-01-01', _utf8mb4'x' COLLATE utf8mb4_bin, b -> '$.k', b ->> '$.k' FROM t",
+		"SELECT ROW_NUMBER() OVER (PARTITION BY a ORDER BY b ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM t",
+		"SELECT SUM(a) OVER w FROM t WINDOW w AS (PARTITION BY b)",
+		"SELECT a FROM t WHERE a IN (1, 2) AND b NOT IN (SELECT b FROM u) AND EXISTS (SELECT 1 FROM v WHERE v.id = t.id)",
+		"SELECT a FROM t WHERE a = ANY (SELECT a FROM u) AND NOT a <=> ? AND @x := 1 AND @@session.sql_mode",
+		"(SELECT a FROM t) UNION ALL (SELECT b FROM u) ORDER BY 1",
+		"WITH RECURSIVE r (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM r WHERE n < 10) SELECT n FROM r",
+		"SELECT `select`.`from` FROM `select` -- 注释\n# 注释\n/* 注释 */",
+		"SELECT a FROM t, LATERAL (SELECT b FROM u WHERE u.a = t.a) AS x",
+		"SELECT a--1 FROM t",
+		// information_schema.VIEWS 中的视图定义格式
+		"select `shop`.`orders`.`id` AS `id`,sum(`shop`.`orders`.`amount`) AS `total` from `shop`.`orders` group by `shop`.`orders`.`id`",
+	}
+	for _, q := range queries {
+		if _, err := Parse(q); err != nil {
+			t.Errorf("Parse(%q) error = %v", q, err)
+		}
+	}
+}
+
+func TestParseRejected(t *testing.T) {
+	notSelect := []string{
+		"INSERT INTO t VALUES (1)",
+		"update t set a = 1",
+		"DROP TABLE t",
+		"SELECT 1; DELETE FROM t",
+		"SELECT a INTO @x FROM t",
+		"SELECT a FROM t INTO OUTFILE '/tmp/x'",
+		"SELECT a FROM t FOR UPDATE",
+		"SELECT a FROM t LOCK IN SHARE MODE",
+	}
+	for _, q := range notSelect {
+		if _, err := Parse(q); !errors.Is(err, ErrNotSelect) {
+			t.Errorf("Parse(%q) error = %v, want ErrNotSelect", q, err)
+		}
+	}
+
+	syntax := []struct {
+		query        string
+		line, column int
+	}{
+		{"SELECT a FROM", 1, 14},
+		{"SELECT a,\nFROM t", 2, 1},
+		{"SELECT (a FROM t", 1, 11},
+		{"SELECT a FROM (SELECT 1)", 1, 25},
+		{"SELECT 'unterminated", 1, 8},
+		{"SHOW TABLES", 1, 1},
+		{"SELECT a FROM t WHERE", 1, 22},
+	}
+	for _, tt := range syntax {
+		_, err := Parse(tt.query)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q) error = %v, want SyntaxError", tt.query, err)
+			continue
+		}
+		if se.Line != tt.line || se.Column != tt.column {
+			t.Errorf("Parse(%q) position = %d:%d, want %d:%d", tt.query, se.Line, se.Column, tt.line, tt.column)
+		}
+	}
+}
+
+func TestParseLineage(t *testing.T) {
+	r, err := Parse(`
+		WITH paid AS (
+			SELECT order_id, SUM(amount) AS paid_amount FROM pay.payments GROUP BY order_id
+		)
+		SELECT o.id,
+		       u.name   AS user_name,
+		       CONCAT(u.first_name, ' ', o.remark) full_text,
+		       p.paid_amount,
+		       d.region,
+		       (SELECT MAX(l.created_at) FROM logs l WHERE l.order_id = o.id) AS last_log,
+		       status
+		FROM orders o
+		JOIN users u ON u.id = o.user_id
+		LEFT JOIN paid p ON p.order_id = o.id
+		JOIN (SELECT id, area AS region FROM shop.depts) d ON d.id = o.dept_id
+		WHERE o.id IN (SELECT order_id FROM refunds)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTables := []Table{
+		{Schema: "pay", Name: "payments"},
+		{Name: "orders"},
+		{Name: "users"},
+		{Schema: "shop", Name: "depts"},
+		{Name: "logs"},
+		{Name: "refunds"},
+	}
+	if !reflect.DeepEqual(r.Tables, wantTables) {
+		t.Errorf("Tables = %v, want %v", r.Tables, wantTables)
+	}
+
+	want := []Column{
+		{Name: "id", Expr: "o.id", Sources: []ColumnRef{{Table: "orders", Column: "id"}}, Direct: true},
+		{Name: "user_name", Expr: "u.name", Sources: []ColumnRef{{Table: "users", Column: "name"}}, Direct: true},
+		{Name: "full_text", Expr: "CONCAT(u.first_name, ' ', o.remark)", Sources: []ColumnRef{
+			{Table: "users", Column: "first_name"}, {Table: "orders", Column: "remark"},
+		}},
+		{Name: "paid_amount", Expr: "p.paid_amount", Sources: []ColumnRef{{Schema: "pay", Table: "payments", Column: "amount"}}},
+		{Name: "region", Expr: "d.region", Sources: []ColumnRef{{Schema: "shop", Table: "depts", Column: "area"}}, Direct: true},
+		{Name: "last_log", Expr: "(SELECT MAX(l.created_at) FROM logs l WHERE l.order_id = o.id)", Sources: []ColumnRef{
+			{Table: "logs", Column: "created_at"},
+		}},
+		// 多表查询中未限定的列无法确定所属表
+		{Name: "status", Expr: "status", Sources: []ColumnRef{{Column: "status"}}, Direct: true},
+	}
+	if !reflect.DeepEqual(r.Columns, want) {
+		t.Errorf("Columns =\n%+v\nwant\n%+v", r.Columns, want)
+	}
+}
+
+func TestParseStarAndUnion(t *testing.T) {
+	r, err := Parse("SELECT x.*, t.* FROM (SELECT a, b AS c FROM s1) x, t UNION SELECT 1, 2, u.* FROM u")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// UNION 中对应位置为常量的列不是直接引用
+	want := []Column{
+		{Name: "a", Expr: "x.a", Sources: []ColumnRef{{Table: "s1", Column: "a"}}},
+		{Name: "c", Expr: "x.c", Sources: []ColumnRef{{Table: "s1", Column: "b"}}},
+		{Name: "*", Expr: "t.*", Sources: []ColumnRef{{Table: "t", Column: "*"}, {Table: "u", Column: "*"}}, Direct: true},
+	}
+	if !reflect.DeepEqual(r.Columns, want) {
+		t.Errorf("Columns =\n%+v\nwant\n%+v", r.Columns, want)
+	}
+
+	// 派生表的 * 展开后按列名追溯到物理表
+	r, err = Parse("SELECT v.k, UPPER(v.name) AS n FROM (SELECT * FROM db1.items) v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Column{
+		{Name: "k", Expr: "v.k", Sources: []ColumnRef{{Schema: "db1", Table: "items", Column: "k"}}, Direct: true},
+		{Name: "n", Expr: "UPPER(v.name)", Sources: []ColumnRef{{Schema: "db1", Table: "items", Column: "name"}}},
+	}
+	if !reflect.DeepEqual(r.Columns, want) {
+		t.Errorf("Columns =\n%+v\nwant\n%+v", r.Columns, want)
+	}
+}
+
+func TestParseFunctions(t *testing.T) {
+	r, err := Parse("SELECT COUNT(*), upper(a), db1.f(b), CAST(c AS CHAR), IF(d, sleep(1), Upper(e)) FROM t WHERE EXISTS (SELECT load_file('x'))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"COUNT", "UPPER", "DB1.F", "IF", "SLEEP", "LOAD_FILE"}
+	if !reflect.DeepEqual(r.Functions, want) {
+		t.Errorf("Functions = %v, want %v", r.Functions, want)
+	}
+}
@@ -0,0 +1,91 @@
+package testkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"idrm/pkg/db"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFixtures 加载 YAML 测试数据，文件格式为 表名 -> 行列表：
+//
+//	category:
+//	  - id: 1
+//	    name: 数据资源
+//	    code: DR
+//
+// 按文件顺序、表名字母序逐行插入，未列出的列使用表默认值。
+// 可空且无默认值的列（如 description）映射到非指针字段时需显式给出，否则 sqlx 扫描 NULL 失败
+func LoadFixtures(t testing.TB, conn *db.Conn, files ...string) {
+	t.Helper()
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read fixture %s: %v", file, err)
+		}
+		if err := loadFixture(context.Background(), conn, content); err != nil {
+			t.Fatalf("load fixture %s: %v", file, err)
+		}
+	}
+}
+
+// loadFixture 插入单个文件中的测试数据
+func loadFixture(ctx context.Context, conn *db.Conn, content []byte) error {
+	var tables map[string][]map[string]interface{}
+	if err := yaml.Unmarshal(content, &tables); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, table := range names {
+		for i, row := range tables[table] {
+			if err := insertRow(ctx, conn, table, row); err != nil {
+				return fmt.Errorf("%s[%d]: %w", table, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// insertRow 插入一行（列按名称排序，保证语句稳定）
+func insertRow(ctx context.Context, conn *db.Conn, table string, row map[string]interface{}) error {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = row[column]
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+	_, err := conn.DB.ExecContext(ctx, conn.Dialect().Rebind(query), values...)
+	return err
+}
+
+// Exec 直接执行 SQL（占位符统一使用 ?），用于准备测试数据或断言
+func Exec(t testing.TB, conn *db.Conn, query string, args ...interface{}) sql.Result {
+	t.Helper()
+
+	result, err := conn.DB.ExecContext(context.Background(), conn.Dialect().Rebind(query), args...)
+	if err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+// Package testkit 测试工具：基于 SQLite 的临时数据库及测试数据加载
+//
+// 无需 MySQL 容器即可运行 model、logic 及 handler 测试：
+//
+//	conn := testkit.SQLite(t, migrations.ResourceCatalog, db.ORMGorm)
+//	testkit.LoadFixtures(t, conn, "testdata/category.yaml")
+package testkit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"idrm/pkg/db"
+	"idrm/pkg/db/migrate"
+
+	"github.com/zeromicro/go-zero/core/conf"
+)
+
+// Option 调整测试数据库配置
+type Option func(c *db.Config)
+
+// WithModels 按模型覆盖 ORM（对应配置 DB.*.Models）
+func WithModels(models map[string]string) Option {
+	return func(c *db.Config) {
+		c.Models = models
+	}
+}
+
+// SQLite 创建临时 SQLite 数据库并执行内置迁移脚本，测试结束时自动关闭
+// database 为迁移脚本目录（如 migrations.ResourceCatalog），orm 为 db.ORMGorm 或 db.ORMSqlx
+func SQLite(t testing.TB, database, orm string, opts ...Option) *db.Conn {
+	t.Helper()
+
+	var cfg db.Config
+	if err := conf.FillDefault(&cfg); err != nil {
+		t.Fatalf("fill db config: %v", err)
+	}
+	cfg.Driver = "sqlite"
+	cfg.Database = filepath.Join(t.TempDir(), database+".db")
+	cfg.ORM = orm
+	cfg.LogLevel = "silent"
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	conn, err := db.Open(cfg)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	migrator, err := migrate.NewEmbedded(conn.DB, string(conn.Dialect()), database)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrate %s: %v", database, err)
+	}
+
+	return conn
+}
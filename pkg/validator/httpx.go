@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ValidationError 请求参数验证错误
+// Fields 为 map[字段名]错误消息，可直接用于 response.ErrorValidation
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// Error 实现error接口
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for field, msg := range e.Fields {
+		msgs = append(msgs, field+": "+msg)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// HttpxValidator 实现 go-zero 的 httpx.Validator 接口
+// 通过 httpx.SetValidator 注册后，httpx.Parse 解析完请求会自动执行 validate tag 及结构体级规则
+type HttpxValidator struct{}
+
+// NewHttpxValidator 创建 httpx 验证钩子
+func NewHttpxValidator() HttpxValidator {
+	return HttpxValidator{}
+}
+
+// Validate 验证解析后的请求结构体，失败时返回 *ValidationError
+func (HttpxValidator) Validate(r *http.Request, data any) error {
+	// 仅验证结构体（数组等请求体交由业务逻辑处理）
+	t := reflect.TypeOf(data)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if err := ValidateCtx(r.Context(), data); err != nil {
+		return &ValidationError{Fields: GetErrorMsgLocale(err, requestLocale(r))}
+	}
+	return nil
+}
+
+// requestLocale 根据 Accept-Language 选择错误消息语言，默认中文
+func requestLocale(r *http.Request) string {
+	lang := strings.ToLower(strings.TrimSpace(r.Header.Get("Accept-Language")))
+	if strings.HasPrefix(lang, LocaleEn) {
+		return LocaleEn
+	}
+	return LocaleZh
+}
@@ -0,0 +1,275 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// customRule 自定义验证规则（验证函数 + 中英文错误消息）
+type customRule struct {
+	tag string
+	fn  func(s string) bool
+	zh  string
+	en  string
+}
+
+// customRules 已注册的自定义验证规则
+var customRules = []customRule{
+	{tag: "mobile", fn: IsMobile, zh: "{0}必须是有效的手机号码", en: "{0} must be a valid mobile number"},
+	{tag: "idcard", fn: IsIDCard, zh: "{0}必须是有效的身份证号码", en: "{0} must be a valid resident ID card number"},
+	{tag: "chinese", fn: IsChinese, zh: "{0}必须是中文", en: "{0} must contain only Chinese characters"},
+	{tag: "uscc", fn: IsUSCC, zh: "{0}必须是有效的统一社会信用代码", en: "{0} must be a valid unified social credit code"},
+	{tag: "orgcode", fn: IsOrgCode, zh: "{0}必须是有效的组织机构代码", en: "{0} must be a valid organization code"},
+	{tag: "catcode", fn: IsCategoryCode, zh: "{0}必须以字母开头，只能包含字母、数字，并以_或-分隔", en: "{0} must start with a letter and contain only letters and digits separated by _ or -"},
+	{tag: "sqlident", fn: IsSQLIdentifier, zh: "{0}必须是有效的SQL标识符", en: "{0} must be a valid SQL identifier"},
+	{tag: "tablename", fn: IsTableName, zh: "{0}必须是有效的表名（table或schema.table）", en: "{0} must be a valid table name (table or schema.table)"},
+}
+
+// ==================== 身份证号 ====================
+
+// idCardWeights 18位身份证前17位加权因子（GB 11643-1999）
+var idCardWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// idCardCheckCodes 校验码（按加权和 mod 11 取值）
+const idCardCheckCodes = "10X98765432"
+
+// provinceCodes 省级行政区划代码（身份证/信用代码前两位）
+var provinceCodes = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true,
+	"21": true, "22": true, "23": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "36": true, "37": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true,
+	"50": true, "51": true, "52": true, "53": true, "54": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true,
+	"71": true, "81": true, "82": true, "83": true,
+}
+
+// IsIDCard 验证居民身份证号码
+// 18位：地区码 + 出生日期 + 顺序码 + 校验码；15位（一代证）：地区码 + 六位出生日期 + 顺序码
+func IsIDCard(s string) bool {
+	switch len(s) {
+	case 18:
+		if !isDigits(s[:17]) || !provinceCodes[s[:2]] || !isValidBirthday(s[6:14]) {
+			return false
+		}
+		sum := 0
+		for i := 0; i < 17; i++ {
+			sum += int(s[i]-'0') * idCardWeights[i]
+		}
+		last := s[17]
+		if last == 'x' {
+			last = 'X'
+		}
+		return idCardCheckCodes[sum%11] == last
+	case 15:
+		return isDigits(s) && provinceCodes[s[:2]] && isValidBirthday("19"+s[6:12])
+	default:
+		return false
+	}
+}
+
+// isValidBirthday 验证 yyyyMMdd 格式出生日期（1900年起，不晚于今天）
+func isValidBirthday(s string) bool {
+	birthday, err := time.ParseInLocation("20060102", s, time.Local)
+	if err != nil {
+		return false
+	}
+	return birthday.Year() >= 1900 && !birthday.After(time.Now())
+}
+
+// ==================== 统一社会信用代码 / 组织机构代码 ====================
+
+// usccCharset 统一社会信用代码字符集（不使用 I、O、Z、S、V），下标即字符值
+const usccCharset = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+
+// usccWeights 统一社会信用代码前17位加权因子（GB 32100-2015）
+var usccWeights = [17]int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// IsUSCC 验证18位统一社会信用代码
+// 登记管理部门码(1) + 机构类别码(1) + 行政区划码(6) + 组织机构代码(9) + 校验码(1)
+func IsUSCC(s string) bool {
+	if len(s) != 18 || !isDigits(s[2:8]) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		v := strings.IndexByte(usccCharset, s[i])
+		if v < 0 {
+			return false
+		}
+		sum += v * usccWeights[i]
+	}
+	check := (31 - sum%31) % 31
+	return s[17] == usccCharset[check]
+}
+
+// orgCodeWeights 组织机构代码本体代码加权因子（GB 11714-1997）
+var orgCodeWeights = [8]int{3, 7, 9, 10, 5, 8, 4, 2}
+
+// IsOrgCode 验证组织机构代码，支持 "XXXXXXXX-X" 与 "XXXXXXXXX" 两种写法
+func IsOrgCode(s string) bool {
+	if len(s) == 10 && s[8] == '-' {
+		s = s[:8] + s[9:]
+	}
+	if len(s) != 9 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 8; i++ {
+		v := orgCodeCharValue(s[i])
+		if v < 0 {
+			return false
+		}
+		sum += v * orgCodeWeights[i]
+	}
+	var check byte
+	switch c := 11 - sum%11; c {
+	case 10:
+		check = 'X'
+	case 11:
+		check = '0'
+	default:
+		check = byte('0' + c)
+	}
+	return s[8] == check
+}
+
+// orgCodeCharValue 组织机构代码字符值：0-9 对应 0-9，A-Z 对应 10-35
+func orgCodeCharValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	default:
+		return -1
+	}
+}
+
+// ==================== 手机号 ====================
+
+// mobileCarriers 手机号号段（前三位）→ 运营商
+var mobileCarriers = map[string]string{}
+
+// 初始化运营商号段（不含物联网号段）
+func init() {
+	segments := map[string][]string{
+		"中国移动": {"134", "135", "136", "137", "138", "139", "147", "148", "150", "151", "152", "157", "158", "159",
+			"172", "178", "182", "183", "184", "187", "188", "195", "197", "198"},
+		"中国联通":  {"130", "131", "132", "145", "146", "155", "156", "166", "171", "175", "176", "185", "186", "196"},
+		"中国电信":  {"133", "149", "153", "173", "174", "177", "180", "181", "189", "190", "191", "193", "199"},
+		"中国广电":  {"192"},
+		"虚拟运营商": {"162", "165", "167", "170"},
+	}
+	for carrier, prefixes := range segments {
+		for _, prefix := range prefixes {
+			mobileCarriers[prefix] = carrier
+		}
+	}
+}
+
+// MobileCarrier 返回手机号所属运营商，无效号码返回空字符串
+// 支持 +86 / 86 国家码前缀
+func MobileCarrier(s string) string {
+	s = strings.TrimPrefix(s, "+")
+	if len(s) == 13 && strings.HasPrefix(s, "86") {
+		s = s[2:]
+	}
+	if len(s) != 11 || !isDigits(s) {
+		return ""
+	}
+	return mobileCarriers[s[:3]]
+}
+
+// IsMobile 验证中国大陆手机号（校验运营商号段）
+func IsMobile(s string) bool {
+	return MobileCarrier(s) != ""
+}
+
+// ==================== 中文 ====================
+
+// IsChinese 验证字符串仅包含汉字（含 CJK 扩展区），空字符串返回 false
+func IsChinese(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================== 类别编码 / SQL 标识符 ====================
+
+var (
+	categoryCodeRegexp  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*([_-][A-Za-z0-9]+)*$`)
+	sqlIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+)
+
+// maxIdentifierLength MySQL 标识符最大长度
+const maxIdentifierLength = 64
+
+// sqlReservedWords 不允许作为标识符的常用 SQL 保留字
+var sqlReservedWords = map[string]bool{
+	"ADD": true, "ALL": true, "ALTER": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true,
+	"CASE": true, "CHECK": true, "COLUMN": true, "CONSTRAINT": true, "CREATE": true, "CROSS": true,
+	"DATABASE": true, "DEFAULT": true, "DELETE": true, "DESC": true, "DISTINCT": true, "DROP": true,
+	"ELSE": true, "EXISTS": true, "FALSE": true, "FOR": true, "FOREIGN": true, "FROM": true, "GRANT": true,
+	"GROUP": true, "HAVING": true, "IN": true, "INDEX": true, "INNER": true, "INSERT": true, "INTO": true,
+	"IS": true, "JOIN": true, "KEY": true, "LEFT": true, "LIKE": true, "LIMIT": true, "NOT": true, "NULL": true,
+	"ON": true, "OR": true, "ORDER": true, "OUTER": true, "PRIMARY": true, "REFERENCES": true, "RIGHT": true,
+	"SELECT": true, "SET": true, "TABLE": true, "THEN": true, "TO": true, "TRUE": true, "UNION": true,
+	"UNIQUE": true, "UPDATE": true, "USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WITH": true,
+}
+
+// IsCategoryCode 验证类别编码：字母开头，字母数字组成，可用 _ 或 - 分段（如 ROOT、GOV_FIN-01）
+func IsCategoryCode(s string) bool {
+	return categoryCodeRegexp.MatchString(s)
+}
+
+// IsSQLIdentifier 验证SQL标识符（列名、库名等）：字母或下划线开头，不超过64个字符，且不是保留字
+func IsSQLIdentifier(s string) bool {
+	return len(s) <= maxIdentifierLength &&
+		sqlIdentifierRegexp.MatchString(s) &&
+		!sqlReservedWords[strings.ToUpper(s)]
+}
+
+// IsTableName 验证表名，支持 table 或 schema.table
+func IsTableName(s string) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, part := range parts {
+		if !IsSQLIdentifier(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits 是否全部为数字
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// stringRule 将字符串验证函数适配为 validator.Func
+func stringRule(fn func(s string) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return fn(fl.Field().String())
+	}
+}
@@ -1,21 +1,31 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
 	"sync"
 
+	"github.com/go-playground/locales/en"
 	"github.com/go-playground/locales/zh"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
 	zh_translations "github.com/go-playground/validator/v10/translations/zh"
 )
 
+// 支持的错误消息语言
+const (
+	LocaleZh = "zh"
+	LocaleEn = "en"
+)
+
 var (
 	once     sync.Once
 	validate *validator.Validate
-	trans    ut.Translator
+	uni      *ut.UniversalTranslator
+	trans    ut.Translator // 默认翻译器（中文）
 )
 
 // Init 初始化验证器（单例模式）
@@ -32,13 +42,15 @@ func Init() {
 			return name
 		})
 
-		// 初始化中文翻译器
+		// 初始化翻译器（默认中文，支持英文）
 		zhLocale := zh.New()
-		uni := ut.New(zhLocale, zhLocale)
-		trans, _ = uni.GetTranslator("zh")
+		uni = ut.New(zhLocale, zhLocale, en.New())
+		trans, _ = uni.GetTranslator(LocaleZh)
+		enTrans, _ := uni.GetTranslator(LocaleEn)
 
-		// 注册中文翻译
+		// 注册内置规则翻译
 		_ = zh_translations.RegisterDefaultTranslations(validate, trans)
+		_ = en_translations.RegisterDefaultTranslations(validate, enTrans)
 
 		// 注册自定义验证器
 		registerCustomValidators()
@@ -56,6 +68,14 @@ func Validate(data interface{}) error {
 	return validate.Struct(data)
 }
 
+// ValidateCtx 验证结构体（携带 context，供依赖数据访问的结构体级规则使用）
+func ValidateCtx(ctx context.Context, data interface{}) error {
+	if validate == nil {
+		Init()
+	}
+	return validate.StructCtx(ctx, data)
+}
+
 // ValidateVar 验证单个变量
 func ValidateVar(field interface{}, tag string) error {
 	if validate == nil {
@@ -64,9 +84,14 @@ func ValidateVar(field interface{}, tag string) error {
 	return validate.Var(field, tag)
 }
 
-// GetErrorMsg 获取友好的错误消息
+// GetErrorMsg 获取友好的错误消息（中文）
 // 返回格式: map[字段名]错误消息
 func GetErrorMsg(err error) map[string]string {
+	return GetErrorMsgLocale(err, LocaleZh)
+}
+
+// GetErrorMsgLocale 获取指定语言（zh/en）的错误消息
+func GetErrorMsgLocale(err error, locale string) map[string]string {
 	if err == nil {
 		return nil
 	}
@@ -74,9 +99,10 @@ func GetErrorMsg(err error) map[string]string {
 	errs := make(map[string]string)
 
 	if validationErrs, ok := err.(validator.ValidationErrors); ok {
+		t := translator(locale)
 		for _, e := range validationErrs {
 			// 使用翻译后的错误消息
-			errs[e.Field()] = e.Translate(trans)
+			errs[e.Field()] = e.Translate(t)
 		}
 	} else {
 		// 非验证错误
@@ -120,62 +146,61 @@ func GetErrorList(err error) []string {
 	return errList
 }
 
-// 注册自定义验证器
-func registerCustomValidators() {
-	// 示例：验证手机号
-	validate.RegisterValidation("mobile", func(fl validator.FieldLevel) bool {
-		mobile := fl.Field().String()
-		if len(mobile) != 11 {
-			return false
-		}
-		// 简单验证：1开头的11位数字
-		return mobile[0] == '1'
-	})
+// StructLevelFunc 结构体级验证函数（用于跨字段、依赖数据库的规则）
+// 通过 sl.ReportError 上报字段错误，tag 需通过 RegisterTranslation 注册中文消息
+type StructLevelFunc = validator.StructLevelFuncCtx
 
-	// 示例：验证身份证号（简化版）
-	validate.RegisterValidation("idcard", func(fl validator.FieldLevel) bool {
-		idcard := fl.Field().String()
-		// 15位或18位
-		return len(idcard) == 15 || len(idcard) == 18
-	})
+// StructLevel 结构体级验证上下文
+type StructLevel = validator.StructLevel
 
-	// 示例：验证中文
-	validate.RegisterValidation("chinese", func(fl validator.FieldLevel) bool {
-		str := fl.Field().String()
-		for _, r := range str {
-			if r < 0x4e00 || r > 0x9fa5 {
-				return false
-			}
-		}
-		return true
-	})
+// RegisterStructValidation 为指定类型注册结构体级验证规则
+// 示例: RegisterStructValidation(fn, types.CreateCategoryReq{})
+func RegisterStructValidation(fn StructLevelFunc, types ...interface{}) {
+	if validate == nil {
+		Init()
+	}
+	validate.RegisterStructValidationCtx(fn, types...)
 }
 
-// 注册自定义翻译
-func registerCustomTranslations() {
-	// 自定义 mobile 的翻译
-	validate.RegisterTranslation("mobile", trans, func(ut ut.Translator) error {
-		return ut.Add("mobile", "{0}必须是有效的手机号码", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("mobile", fe.Field())
-		return t
-	})
+// RegisterTranslation 为自定义 tag 注册中文错误消息，{0} 为字段名，{1} 为参数
+func RegisterTranslation(tag, message string) error {
+	return RegisterTranslationLocale(LocaleZh, tag, message)
+}
 
-	// 自定义 idcard 的翻译
-	validate.RegisterTranslation("idcard", trans, func(ut ut.Translator) error {
-		return ut.Add("idcard", "{0}必须是有效的身份证号码", true)
+// RegisterTranslationLocale 为自定义 tag 注册指定语言的错误消息
+func RegisterTranslationLocale(locale, tag, message string) error {
+	if validate == nil {
+		Init()
+	}
+	return validate.RegisterTranslation(tag, translator(locale), func(ut ut.Translator) error {
+		return ut.Add(tag, message, true)
 	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("idcard", fe.Field())
+		t, _ := ut.T(tag, fe.Field(), fe.Param())
 		return t
 	})
+}
 
-	// 自定义 chinese 的翻译
-	validate.RegisterTranslation("chinese", trans, func(ut ut.Translator) error {
-		return ut.Add("chinese", "{0}必须是中文", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("chinese", fe.Field())
+// translator 获取指定语言的翻译器，不支持的语言返回默认中文翻译器
+func translator(locale string) ut.Translator {
+	if t, found := uni.GetTranslator(locale); found {
 		return t
-	})
+	}
+	return trans
+}
+
+// 注册自定义验证器（规则定义见 rules.go）
+func registerCustomValidators() {
+	for _, rule := range customRules {
+		_ = validate.RegisterValidation(rule.tag, stringRule(rule.fn))
+	}
+}
+
+// 注册自定义翻译（中英文）
+func registerCustomTranslations() {
+	for _, rule := range customRules {
+		_ = RegisterTranslationLocale(LocaleZh, rule.tag, rule.zh)
+		_ = RegisterTranslationLocale(LocaleEn, rule.tag, rule.en)
+	}
 }
 
 // FormatError 格式化错误为字符串
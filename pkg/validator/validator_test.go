@@ -1,6 +1,11 @@
 package validator
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -154,3 +159,159 @@ func TestFormatError(t *testing.T) {
 		t.Error("未格式化错误消息")
 	}
 }
+
+type rangeStruct struct {
+	Min int `json:"min" validate:"gte=0"`
+	Max int `json:"max" validate:"gte=0"`
+}
+
+func TestHttpxValidator(t *testing.T) {
+	Init()
+
+	RegisterStructValidation(func(ctx context.Context, sl StructLevel) {
+		r := sl.Current().Interface().(rangeStruct)
+		if r.Max < r.Min {
+			sl.ReportError(r.Max, "max", "Max", "range_order", "")
+		}
+	}, rangeStruct{})
+	if err := RegisterTranslation("range_order", "{0}不能小于min"); err != nil {
+		t.Fatalf("RegisterTranslation() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       interface{}
+		wantFields []string
+	}{
+		{name: "有效数据", data: &rangeStruct{Min: 1, Max: 2}},
+		{name: "字段规则", data: &rangeStruct{Min: -1, Max: 2}, wantFields: []string{"min"}},
+		{name: "结构体级规则", data: &rangeStruct{Min: 3, Max: 2}, wantFields: []string{"max"}},
+		{name: "非结构体跳过", data: &[]rangeStruct{{Min: -1}}},
+	}
+
+	v := NewHttpxValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			err := v.Validate(r, tt.data)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := validationErr.Fields[field]; !ok {
+					t.Errorf("缺少字段 %s 的错误: %v", field, validationErr.Fields)
+				}
+			}
+			t.Logf("错误详情: %v", validationErr.Fields)
+		})
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	Init()
+
+	tests := []struct {
+		name  string
+		tag   string
+		value string
+		want  bool
+	}{
+		// 身份证号
+		{name: "身份证-有效", tag: "idcard", value: "11010519491231002X", want: true},
+		{name: "身份证-小写x", tag: "idcard", value: "11010519491231002x", want: true},
+		{name: "身份证-校验码错误", tag: "idcard", value: "110105194912310021", want: false},
+		{name: "身份证-地区码错误", tag: "idcard", value: "99010519491231002X", want: false},
+		{name: "身份证-日期非法", tag: "idcard", value: "110105194902301234", want: false},
+		{name: "身份证-15位", tag: "idcard", value: "110105491231002", want: true},
+		{name: "身份证-长度错误", tag: "idcard", value: "1101051949123", want: false},
+
+		// 统一社会信用代码
+		{name: "信用代码-有效", tag: "uscc", value: "91350100M000100Y43", want: true},
+		{name: "信用代码-校验码错误", tag: "uscc", value: "91350100M000100Y44", want: false},
+		{name: "信用代码-非法字符", tag: "uscc", value: "91350100I000100Y43", want: false},
+		{name: "信用代码-行政区划非数字", tag: "uscc", value: "9135A100M000100Y43", want: false},
+
+		// 组织机构代码
+		{name: "机构代码-有效", tag: "orgcode", value: "D2143569-X", want: true},
+		{name: "机构代码-无连字符", tag: "orgcode", value: "600037341", want: true},
+		{name: "机构代码-校验码错误", tag: "orgcode", value: "12345678-9", want: false},
+		{name: "机构代码-小写", tag: "orgcode", value: "d2143569-X", want: false},
+
+		// 手机号
+		{name: "手机号-移动", tag: "mobile", value: "13800138000", want: true},
+		{name: "手机号-广电", tag: "mobile", value: "19200001111", want: true},
+		{name: "手机号-国家码", tag: "mobile", value: "+8618612345678", want: true},
+		{name: "手机号-无效号段", tag: "mobile", value: "12000138000", want: false},
+		{name: "手机号-非数字", tag: "mobile", value: "1380013800a", want: false},
+
+		// 中文
+		{name: "中文-基本", tag: "chinese", value: "数据资源", want: true},
+		{name: "中文-扩展B区", tag: "chinese", value: "𠀀𪚥", want: true},
+		{name: "中文-含英文", tag: "chinese", value: "数据abc", want: false},
+
+		// 类别编码
+		{name: "类别编码-有效", tag: "catcode", value: "GOV_FIN-01", want: true},
+		{name: "类别编码-数字开头", tag: "catcode", value: "1ROOT", want: false},
+		{name: "类别编码-连续分隔符", tag: "catcode", value: "A__B", want: false},
+		{name: "类别编码-结尾分隔符", tag: "catcode", value: "ROOT_", want: false},
+
+		// SQL 标识符 / 表名
+		{name: "标识符-有效", tag: "sqlident", value: "user_name", want: true},
+		{name: "标识符-保留字", tag: "sqlident", value: "select", want: false},
+		{name: "标识符-含空格", tag: "sqlident", value: "user name", want: false},
+		{name: "标识符-超长", tag: "sqlident", value: strings.Repeat("a", 65), want: false},
+		{name: "表名-带库名", tag: "tablename", value: "idrm.category", want: true},
+		{name: "表名-注入", tag: "tablename", value: "category;drop", want: false},
+		{name: "表名-多级", tag: "tablename", value: "a.b.c", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVar(tt.value, tt.tag)
+			if (err == nil) != tt.want {
+				t.Errorf("ValidateVar(%q, %q) error = %v, want valid = %v", tt.value, tt.tag, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMobileCarrier(t *testing.T) {
+	tests := map[string]string{
+		"13800138000": "中国移动",
+		"18612345678": "中国联通",
+		"18912345678": "中国电信",
+		"12345678901": "",
+	}
+	for mobile, want := range tests {
+		if got := MobileCarrier(mobile); got != want {
+			t.Errorf("MobileCarrier(%q) = %q, want %q", mobile, got, want)
+		}
+	}
+}
+
+func TestGetErrorMsgLocale(t *testing.T) {
+	Init()
+
+	type idStruct struct {
+		IDCard string `json:"id_card" validate:"idcard"`
+	}
+	err := Validate(idStruct{IDCard: "123"})
+	if err == nil {
+		t.Fatal("期望有验证错误")
+	}
+
+	if got := GetErrorMsgLocale(err, LocaleZh)["id_card"]; got != "id_card必须是有效的身份证号码" {
+		t.Errorf("中文消息 = %q", got)
+	}
+	if got := GetErrorMsgLocale(err, LocaleEn)["id_card"]; got != "id_card must be a valid resident ID card number" {
+		t.Errorf("英文消息 = %q", got)
+	}
+}